@@ -1,32 +1,136 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"task-management-api/config"
+	"task-management-api/domain/entities"
+	"task-management-api/logging"
+	"task-management-api/notify"
 	"task-management-api/router"
+	"task-management-api/usecase"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// trashPurgeInterval is how often tasks past the trash retention period are
+// looked for.
+const trashPurgeInterval = time.Hour
+
+// newReminderNotifier returns the channels reminders are sent over, or nil
+// when none is configured.
+func newReminderNotifier(env config.Environment) entities.Notifier {
+	var notifiers notify.Multi
+	if env.GetSMTPAddr() != "" {
+		notifiers = append(notifiers, notify.NewSMTPNotifier(env.GetSMTPAddr(), env.GetSMTPFrom(), env.GetSMTPUsername(), env.GetSMTPPassword()))
+	}
+	if env.GetReminderWebhookURL() != "" {
+		notifiers = append(notifiers, notify.NewWebhookNotifier(env.GetReminderWebhookURL(), nil))
+	}
+
+	if len(notifiers) == 0 {
+		return nil
+	}
+	return notifiers
+}
+
 func main()  {
-	
-	env, db, err := config.Initialize()
+
+	env, repositories, err := config.Initialize(os.Args[1:])
 	if err !=  nil{
-		log.Fatal(err)
+		slog.Error("Startup failed", "error", err)
+		os.Exit(1)
+	}
+
+	slog.SetDefault(logging.New(os.Stdout, env.GetLogLevel()))
+
+	// Requests are logged by middleware.RequestLogger instead of gin's logger.
+	route := gin.New()
+	route.Use(gin.Recovery())
+
+	if err := router.NewRouter(env, time.Second * 5, repositories, route); err != nil {
+		slog.Error("Startup failed", "error", err)
+		os.Exit(1)
+	}
+
+	server := &http.Server{
+		Addr:         ":" + env.GetPort(),
+		Handler:      route,
+		ReadTimeout:  env.GetReadTimeout(),
+		WriteTimeout: env.GetWriteTimeout(),
+		IdleTimeout:  env.GetIdleTimeout(),
+		ErrorLog:     slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
 	}
 
-	route := gin.Default()
-	
-	router.NewRouter(env, time.Second * 5, db, route)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	log.Println("")
-	log.Println("-----------------------------------")
-	log.Println("Server is running on port: " + env.GetPort())
-	log.Println("Press CTRL + C to stop the server")
-	log.Println("-----------------------------------")
-	log.Println("")
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
 
-	route.Run(":" + env.GetPort())
+	purger := usecase.NewTrashPurger(repositories.Tasks, env.GetTrashRetention(), trashPurgeInterval, nil)
+	purgerDone := make(chan struct{})
+	go func() {
+		defer close(purgerDone)
+		purger.Run(ctx)
+	}()
+
+	// Without a channel, reminders are still scheduled but not sent.
+	schedulerDone := make(chan struct{})
+	if notifier := newReminderNotifier(env); notifier != nil {
+		scheduler := usecase.NewReminderScheduler(repositories.Reminders, repositories.Tasks, repositories.Users, notifier, env.GetReminderInterval(), nil)
+		go func() {
+			defer close(schedulerDone)
+			scheduler.Run(ctx)
+		}()
+	} else {
+		close(schedulerDone)
+		slog.Info("Reminders are not sent: neither smtp_addr nor reminder_webhook_url is set")
+	}
+
+	dispatcher := usecase.NewWebhookDispatcher(repositories.Webhooks, notify.NewWebhookSender(nil), env.GetWebhookInterval(), nil)
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		dispatcher.Run(ctx)
+	}()
+
+	slog.Info("Server is running", "port", env.GetPort())
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Server failed", "error", err)
+		}
+	case <-ctx.Done():
+		slog.Info("Shutting down the server")
+	}
+	stop()
+
+	// In-flight requests get until the shutdown timeout to finish; the
+	// database is only disconnected once they are done.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), env.GetShutdownTimeout())
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Server shutdown failed", "error", err)
+	}
+
+	<-purgerDone
+	<-schedulerDone
+	<-dispatcherDone
+
+	if err := repositories.Close(shutdownCtx); err != nil {
+		slog.Error("Database disconnect failed", "error", err)
+	}
 
-}
\ No newline at end of file
+	slog.Info("Server stopped")
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"task-management-api/config"
+	"task-management-api/usecase"
+	"task-management-api/utils"
+)
+
+// One-shot migration that replaces legacy plaintext passwords with hashes
+// produced by the configured password hasher.
+func main() {
+
+	env, repositories, err := config.Initialize(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	passwordHasher, err := utils.NewPasswordHasher(env.GetPasswordHasher(), env.GetBcryptCost())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	migrated, err := usecase.MigratePlaintextPasswords(context.Background(), repositories.Users, passwordHasher)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Migrated %d plaintext passwords", migrated)
+}
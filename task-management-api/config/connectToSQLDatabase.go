@@ -0,0 +1,69 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"task-management-api/repository"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// GetSQLDatabase opens the SQL database named by DbURL. SQLite URLs take the
+// form sqlite:///path/to/file.db or sqlite::memory:; Postgres URLs are passed
+// to the driver unchanged.
+func GetSQLDatabase(env Environment) (*repository.SQLDatabase, error) {
+	url := env.GetDbURL()
+
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	switch {
+	case strings.HasPrefix(url, "sqlite:"):
+		db, err = sql.Open("sqlite", SQLiteDSN(url))
+		if err != nil {
+			return nil, err
+		}
+		// SQLite allows a single writer; one connection also keeps an
+		// in-memory database alive for the lifetime of the pool.
+		db.SetMaxOpenConns(1)
+		err = db.Ping()
+		if err == nil {
+			slog.Info("Connected to SQLite")
+			return repository.NewSQLDatabase(db, repository.SQLite), nil
+		}
+	case strings.HasPrefix(url, "postgres://"), strings.HasPrefix(url, "postgresql://"):
+		db, err = sql.Open("pgx", url)
+		if err != nil {
+			return nil, err
+		}
+		err = db.Ping()
+		if err == nil {
+			slog.Info("Connected to Postgres")
+			return repository.NewSQLDatabase(db, repository.Postgres), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported SQL database URL %q", url)
+	}
+
+	db.Close()
+	return nil, fmt.Errorf("failed to connect to the SQL database: %w", err)
+}
+
+// SQLiteDSN converts a sqlite: URL into a data source name for the SQLite
+// driver, enabling foreign keys and waiting on locks instead of failing.
+func SQLiteDSN(url string) string {
+	path := strings.TrimPrefix(strings.TrimPrefix(url, "sqlite:"), "//")
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	return "file:" + path + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
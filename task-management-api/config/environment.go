@@ -1,25 +1,73 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"os"
-
-	"github.com/joho/godotenv"
+	"net"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"task-management-api/domain/entities"
+	"time"
 )
 
+// MinJwtKeyLength is the shortest JWT signing key accepted. HS256 keys should
+// carry at least 256 bits.
+const MinJwtKeyLength = 32
+
 type Environment interface {
 	GetJwtKey() string
 	GetDbURL() string
 	GetDbName() string
 	GetPort() string
+	GetPasswordHasher() string
+	GetBcryptCost() int
+	GetDbDriver() string
+	GetReadTimeout() time.Duration
+	GetWriteTimeout() time.Duration
+	GetIdleTimeout() time.Duration
+	GetShutdownTimeout() time.Duration
+	GetCorsAllowedOrigins() []string
+	GetTrustedProxies() []string
+	GetLogLevel() string
+	GetAuthRateLimit() entities.RateLimit
+	GetTaskRateLimit() entities.RateLimit
+	GetTrashRetention() time.Duration
+	GetReminderInterval() time.Duration
+	GetSMTPAddr() string
+	GetSMTPFrom() string
+	GetSMTPUsername() string
+	GetSMTPPassword() string
+	GetReminderWebhookURL() string
+	GetWebhookInterval() time.Duration
 }
 
 type environment struct {
-	jwtKey string
-	dbURL  string
-	dbName string
-	port   string
+	jwtKey             string
+	dbURL              string
+	dbName             string
+	port               string
+	passwordHasher     string
+	bcryptCost         int
+	dbDriver           string
+	readTimeout        time.Duration
+	writeTimeout       time.Duration
+	idleTimeout        time.Duration
+	shutdownTimeout    time.Duration
+	corsAllowedOrigins []string
+	trustedProxies     []string
+	logLevel           string
+	authRateLimit      entities.RateLimit
+	taskRateLimit      entities.RateLimit
+	trashRetention     time.Duration
+	reminderInterval   time.Duration
+	smtpAddr           string
+	smtpFrom           string
+	smtpUsername       string
+	smtpPassword       string
+	reminderWebhookURL string
+	webhookInterval    time.Duration
 }
 
 func (e *environment) GetJwtKey() string {
@@ -38,28 +86,243 @@ func (e *environment) GetPort() string {
 	return e.port
 }
 
-func NewEnvironment() (Environment, error) {
-		log.Println("Loading .env file")
-		err := godotenv.Load()
+func (e *environment) GetPasswordHasher() string {
+	return e.passwordHasher
+}
+
+func (e *environment) GetBcryptCost() int {
+	return e.bcryptCost
+}
+
+func (e *environment) GetDbDriver() string {
+	return e.dbDriver
+}
+
+func (e *environment) GetReadTimeout() time.Duration {
+	return e.readTimeout
+}
+
+func (e *environment) GetWriteTimeout() time.Duration {
+	return e.writeTimeout
+}
+
+func (e *environment) GetIdleTimeout() time.Duration {
+	return e.idleTimeout
+}
+
+func (e *environment) GetShutdownTimeout() time.Duration {
+	return e.shutdownTimeout
+}
+
+func (e *environment) GetCorsAllowedOrigins() []string {
+	return e.corsAllowedOrigins
+}
+
+func (e *environment) GetTrustedProxies() []string {
+	return e.trustedProxies
+}
+
+func (e *environment) GetLogLevel() string {
+	return e.logLevel
+}
+
+func (e *environment) GetAuthRateLimit() entities.RateLimit {
+	return e.authRateLimit
+}
+
+func (e *environment) GetTaskRateLimit() entities.RateLimit {
+	return e.taskRateLimit
+}
+
+func (e *environment) GetTrashRetention() time.Duration {
+	return e.trashRetention
+}
+
+func (e *environment) GetReminderInterval() time.Duration {
+	return e.reminderInterval
+}
+
+func (e *environment) GetSMTPAddr() string {
+	return e.smtpAddr
+}
+
+func (e *environment) GetSMTPFrom() string {
+	return e.smtpFrom
+}
+
+func (e *environment) GetSMTPUsername() string {
+	return e.smtpUsername
+}
+
+func (e *environment) GetSMTPPassword() string {
+	return e.smtpPassword
+}
+
+func (e *environment) GetReminderWebhookURL() string {
+	return e.reminderWebhookURL
+}
+
+func (e *environment) GetWebhookInterval() time.Duration {
+	return e.webhookInterval
+}
+
+// newEnvironment parses and validates the merged setting values. Every invalid
+// setting is reported, not just the first one.
+func newEnvironment(values map[string]string) (*environment, error) {
+	var errs []error
+	invalid := func(name, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("invalid %s: "+format, append([]interface{}{name}, args...)...))
+	}
+
+	duration := func(name string) time.Duration {
+		value, err := time.ParseDuration(values[name])
 		if err != nil {
-			log.Println(err)
-			return nil, fmt.Errorf("error loading .env file: %w", err)
+			invalid(name, "%v", err)
+		} else if value <= 0 {
+			invalid(name, "must be positive")
 		}
+		return value
+	}
+
+	env := &environment{
+		jwtKey:             values["jwt_key"],
+		dbURL:              values["db_url"],
+		dbName:             values["db_name"],
+		port:               values["port"],
+		passwordHasher:     strings.ToLower(values["password_hasher"]),
+		dbDriver:           strings.ToLower(values["db_driver"]),
+		readTimeout:        duration("read_timeout"),
+		writeTimeout:       duration("write_timeout"),
+		idleTimeout:        duration("idle_timeout"),
+		shutdownTimeout:    duration("shutdown_timeout"),
+		trashRetention:     duration("trash_retention"),
+		reminderInterval:   duration("reminder_interval"),
+		webhookInterval:    duration("webhook_interval"),
+		corsAllowedOrigins: splitList(values["cors_allowed_origins"]),
+		trustedProxies:     splitList(values["trusted_proxies"]),
+		logLevel:           strings.ToLower(values["log_level"]),
+		smtpAddr:           values["smtp_addr"],
+		smtpFrom:           values["smtp_from"],
+		smtpUsername:       values["smtp_username"],
+		smtpPassword:       values["smtp_password"],
+		reminderWebhookURL: values["reminder_webhook_url"],
+	}
+
+	rateLimit := func(name string) entities.RateLimit {
+		limit, err := parseRateLimit(values[name])
+		if err != nil {
+			invalid(name, "%v", err)
+		}
+		return limit
+	}
+	env.authRateLimit = rateLimit("auth_rate_limit")
+	env.taskRateLimit = rateLimit("task_rate_limit")
+
+	if len(env.jwtKey) < MinJwtKeyLength {
+		invalid("jwt_key", "must be at least %d characters long", MinJwtKeyLength)
+	}
+
+	if port, err := strconv.Atoi(env.port); err != nil || port < 1 || port > 65535 {
+		invalid("port", "%q is not a TCP port", env.port)
+	}
 
-	return &environment{
-		dbURL:  os.Getenv("DbURL"),
-		dbName: os.Getenv("DbName"),
-		port:   os.Getenv("Port"),
-		jwtKey: os.Getenv("jwtKey"),
-	}, nil
+	switch env.dbDriver {
+	case "":
+		if env.dbURL == "" {
+			invalid("db_url", "is required unless db_driver is memory")
+		}
+	case "memory":
+	default:
+		invalid("db_driver", "unsupported driver %q", env.dbDriver)
+	}
+
+	for _, proxy := range env.trustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				invalid("trusted_proxies", "%q is not an IP address or CIDR range", proxy)
+			}
+		}
+	}
+
+	switch env.passwordHasher {
+	case "bcrypt", "argon2id":
+	default:
+		invalid("password_hasher", "must be bcrypt or argon2id")
+	}
+
+	if value := values["bcrypt_cost"]; value != "" {
+		cost, err := strconv.Atoi(value)
+		if err != nil {
+			invalid("bcrypt_cost", "%v", err)
+		}
+		env.bcryptCost = cost
+	}
+
+	switch env.logLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		invalid("log_level", "must be one of debug, info, warn, error")
+	}
+
+	if env.smtpAddr != "" {
+		if _, _, err := net.SplitHostPort(env.smtpAddr); err != nil {
+			invalid("smtp_addr", "%q is not of the form host:port", env.smtpAddr)
+		}
+		if _, err := mail.ParseAddress(env.smtpFrom); err != nil {
+			invalid("smtp_from", "must be an email address when smtp_addr is set")
+		}
+	}
+
+	if env.reminderWebhookURL != "" {
+		webhookURL, err := url.Parse(env.reminderWebhookURL)
+		if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+			invalid("reminder_webhook_url", "%q is not an http or https URL", env.reminderWebhookURL)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// parseRateLimit reads a limit such as "20/m": 20 requests per minute, which
+// may also be made in a single burst. "off" disables the limit.
+func parseRateLimit(value string) (entities.RateLimit, error) {
+	if value == "off" {
+		return entities.RateLimit{}, nil
+	}
+
+	count, unit, _ := strings.Cut(value, "/")
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests < 1 {
+		return entities.RateLimit{}, fmt.Errorf("%q is not of the form <requests>/<s|m|h> or off", value)
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return entities.RateLimit{}, fmt.Errorf("%q is not of the form <requests>/<s|m|h> or off", value)
+	}
+
+	return entities.RateLimit{Rate: float64(requests) / period.Seconds(), Burst: requests}, nil
 }
 
-func GetJwtKey() []byte {
-	env, err := NewEnvironment()
-	if err != nil {
-		log.Println(err)
-		log.Fatal(err)
+// splitList splits a comma separated setting, dropping empty entries.
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
 	}
 
-	return []byte(env.GetJwtKey())
+	return list
 }
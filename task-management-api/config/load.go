@@ -0,0 +1,225 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// setting describes one configuration value and the names it is read under
+// in each source.
+type setting struct {
+	name      string // key in YAML and TOML files
+	env       string // environment variable, also used as the .env key
+	legacyEnv string // deprecated environment variable still honoured
+	usage     string
+	fallback  string
+}
+
+var settings = []setting{
+	{name: "port", env: "PORT", legacyEnv: "Port", usage: "HTTP port to listen on", fallback: "8080"},
+	{name: "db_url", env: "DB_URL", legacyEnv: "DbURL", usage: "database URL (mongodb://, sqlite: or postgres://)"},
+	{name: "db_name", env: "DB_NAME", legacyEnv: "DbName", usage: "MongoDB database name", fallback: "taskManagementDatabase"},
+	{name: "db_driver", env: "DB_DRIVER", usage: "set to memory to use the in-memory database"},
+	{name: "jwt_key", env: "JWT_KEY", legacyEnv: "jwtKey", usage: "key signing access tokens"},
+	{name: "password_hasher", env: "PASSWORD_HASHER", legacyEnv: "PasswordHasher", usage: "bcrypt or argon2id", fallback: "bcrypt"},
+	{name: "bcrypt_cost", env: "BCRYPT_COST", legacyEnv: "BcryptCost", usage: "bcrypt cost factor"},
+	{name: "read_timeout", env: "READ_TIMEOUT", legacyEnv: "ReadTimeout", usage: "maximum time to read a request", fallback: "10s"},
+	{name: "write_timeout", env: "WRITE_TIMEOUT", legacyEnv: "WriteTimeout", usage: "maximum time to write a response", fallback: "10s"},
+	{name: "idle_timeout", env: "IDLE_TIMEOUT", legacyEnv: "IdleTimeout", usage: "how long idle keep-alive connections stay open", fallback: "60s"},
+	{name: "shutdown_timeout", env: "SHUTDOWN_TIMEOUT", legacyEnv: "ShutdownTimeout", usage: "how long in-flight requests may run on shutdown", fallback: "15s"},
+	{name: "cors_allowed_origins", env: "CORS_ALLOWED_ORIGINS", usage: "comma separated origins allowed to make cross-origin requests"},
+	{name: "trusted_proxies", env: "TRUSTED_PROXIES", usage: "comma separated addresses or CIDR ranges of the proxies whose X-Forwarded-For is trusted"},
+	{name: "auth_rate_limit", env: "AUTH_RATE_LIMIT", usage: "requests per client IP to /auth, as <requests>/<s|m|h> or off", fallback: "20/m"},
+	{name: "task_rate_limit", env: "TASK_RATE_LIMIT", usage: "requests per user to /task, as <requests>/<s|m|h> or off", fallback: "300/m"},
+	{name: "trash_retention", env: "TRASH_RETENTION", usage: "how long deleted tasks stay in the trash before they are purged", fallback: "720h"},
+	{name: "log_level", env: "LOG_LEVEL", usage: "debug, info, warn or error", fallback: "info"},
+	{name: "reminder_interval", env: "REMINDER_INTERVAL", usage: "how often due task reminders are looked for", fallback: "1m"},
+	{name: "smtp_addr", env: "SMTP_ADDR", usage: "host:port of the SMTP server reminders are emailed through"},
+	{name: "smtp_from", env: "SMTP_FROM", usage: "sender address of reminder emails"},
+	{name: "smtp_username", env: "SMTP_USERNAME", usage: "SMTP username, if the server requires authentication"},
+	{name: "smtp_password", env: "SMTP_PASSWORD", usage: "SMTP password"},
+	{name: "reminder_webhook_url", env: "REMINDER_WEBHOOK_URL", usage: "URL reminders are posted to as JSON"},
+	{name: "webhook_interval", env: "WEBHOOK_INTERVAL", usage: "how often pending webhook deliveries are looked for", fallback: "5s"},
+}
+
+// defaultConfigFile is read, when it exists, if no file is named with
+// -config or CONFIG_FILE.
+const defaultConfigFile = ".env"
+
+// Load builds the configuration from, in increasing order of precedence,
+// built-in defaults, an optional config file, environment variables and the
+// command-line flags in args. The config file is named with -config or
+// CONFIG_FILE and may be YAML, TOML or a .env file.
+func Load(args []string) (Environment, error) {
+	flags := flag.NewFlagSet("task-management-api", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML, TOML or .env config file")
+	for _, s := range settings {
+		flags.String(flagName(s), "", s.usage)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(settings))
+	for _, s := range settings {
+		values[s.name] = s.fallback
+	}
+
+	fileValues, err := readConfigFile(*configFile)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range fileValues {
+		values[name] = value
+	}
+
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.env); ok {
+			values[s.name] = value
+		} else if value, ok := lookupLegacyEnv(s); ok {
+			values[s.name] = value
+		}
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		if s, ok := settingByFlag(f.Name); ok {
+			values[s.name] = f.Value.String()
+		}
+	})
+
+	env, err := newEnvironment(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func flagName(s setting) string {
+	return strings.ReplaceAll(s.name, "_", "-")
+}
+
+func settingByFlag(name string) (setting, bool) {
+	for _, s := range settings {
+		if flagName(s) == name {
+			return s, true
+		}
+	}
+
+	return setting{}, false
+}
+
+func lookupLegacyEnv(s setting) (string, bool) {
+	if s.legacyEnv == "" {
+		return "", false
+	}
+
+	value, ok := os.LookupEnv(s.legacyEnv)
+	if ok {
+		slog.Warn("Deprecated environment variable", "name", s.legacyEnv, "use", s.env)
+	}
+
+	return value, ok
+}
+
+// readConfigFile returns the settings in path keyed by setting name. Without a
+// path the default .env file is read if there is one.
+func readConfigFile(path string) (map[string]string, error) {
+	optional := path == ""
+	if optional {
+		path = defaultConfigFile
+	}
+
+	data, err := os.ReadFile(path)
+	if optional && errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		values, err = structuredValues(data, yaml.Unmarshal)
+	case ".toml":
+		values, err = structuredValues(data, toml.Unmarshal)
+	default:
+		values, err = dotenvValues(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// structuredValues decodes a YAML or TOML document of top-level settings. Lists
+// are accepted for list settings and joined with commas.
+func structuredValues(data []byte, unmarshal func([]byte, interface{}) error) (map[string]string, error) {
+	document := map[string]interface{}{}
+	if err := unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(document))
+	for key, value := range document {
+		if !isSetting(key) {
+			return nil, fmt.Errorf("unknown setting %q", key)
+		}
+
+		if list, ok := value.([]interface{}); ok {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+			continue
+		}
+
+		values[key] = fmt.Sprint(value)
+	}
+
+	return values, nil
+}
+
+// dotenvValues reads a .env file, which uses the environment variable names.
+// Unknown variables are ignored so that the file can be shared with other
+// tools.
+func dotenvValues(data []byte) (map[string]string, error) {
+	variables, err := godotenv.UnmarshalBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for _, s := range settings {
+		if value, ok := variables[s.env]; ok {
+			values[s.name] = value
+		} else if value, ok := variables[s.legacyEnv]; ok && s.legacyEnv != "" {
+			values[s.name] = value
+		}
+	}
+
+	return values, nil
+}
+
+func isSetting(name string) bool {
+	for _, s := range settings {
+		if s.name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,174 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"task-management-api/config"
+	"task-management-api/domain/entities"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJwtKey = "0123456789abcdef0123456789abcdef"
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("JWT_KEY", testJwtKey)
+		t.Setenv("DB_DRIVER", "memory")
+
+		env, err := config.Load(nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "8080", env.GetPort())
+		assert.Equal(t, "bcrypt", env.GetPasswordHasher())
+		assert.Equal(t, "info", env.GetLogLevel())
+		assert.Equal(t, 10*time.Second, env.GetReadTimeout())
+		assert.Equal(t, 15*time.Second, env.GetShutdownTimeout())
+		assert.Empty(t, env.GetCorsAllowedOrigins())
+		assert.Empty(t, env.GetTrustedProxies())
+		assert.Equal(t, entities.RateLimit{Rate: 20.0 / 60, Burst: 20}, env.GetAuthRateLimit())
+		assert.Equal(t, entities.RateLimit{Rate: 5, Burst: 300}, env.GetTaskRateLimit())
+		assert.Equal(t, 30*24*time.Hour, env.GetTrashRetention())
+		assert.Equal(t, time.Minute, env.GetReminderInterval())
+		assert.Empty(t, env.GetSMTPAddr())
+		assert.Empty(t, env.GetReminderWebhookURL())
+		assert.Equal(t, 5*time.Second, env.GetWebhookInterval())
+	})
+
+	t.Run("reminder channels", func(t *testing.T) {
+		t.Setenv("JWT_KEY", testJwtKey)
+		t.Setenv("DB_DRIVER", "memory")
+		t.Setenv("SMTP_ADDR", "mail.example.com:587")
+		t.Setenv("SMTP_FROM", "Tasks <tasks@example.com>")
+		t.Setenv("SMTP_USERNAME", "mailer")
+		t.Setenv("REMINDER_WEBHOOK_URL", "https://hooks.example.com/reminders")
+
+		env, err := config.Load([]string{"-reminder-interval", "30s"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 30*time.Second, env.GetReminderInterval())
+		assert.Equal(t, "mail.example.com:587", env.GetSMTPAddr())
+		assert.Equal(t, "Tasks <tasks@example.com>", env.GetSMTPFrom())
+		assert.Equal(t, "mailer", env.GetSMTPUsername())
+		assert.Equal(t, "https://hooks.example.com/reminders", env.GetReminderWebhookURL())
+	})
+
+	t.Run("rate limits", func(t *testing.T) {
+		t.Setenv("JWT_KEY", testJwtKey)
+		t.Setenv("DB_DRIVER", "memory")
+		t.Setenv("AUTH_RATE_LIMIT", "2/s")
+		t.Setenv("TASK_RATE_LIMIT", "off")
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.1, 192.168.0.0/16")
+
+		env, err := config.Load(nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, entities.RateLimit{Rate: 2, Burst: 2}, env.GetAuthRateLimit())
+		assert.False(t, env.GetTaskRateLimit().Enabled())
+		assert.Equal(t, []string{"10.0.0.1", "192.168.0.0/16"}, env.GetTrustedProxies())
+	})
+
+	t.Run("flags override the environment which overrides the file", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+port: 6000
+db_url: mongodb://file:27017
+jwt_key: `+testJwtKey+`
+read_timeout: 5s
+cors_allowed_origins:
+  - https://a.example.com
+  - https://b.example.com
+`)
+		t.Setenv("PORT", "7000")
+		t.Setenv("LOG_LEVEL", "debug")
+
+		env, err := config.Load([]string{"-config", path, "-log-level", "warn"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "7000", env.GetPort())
+		assert.Equal(t, "warn", env.GetLogLevel())
+		assert.Equal(t, "mongodb://file:27017", env.GetDbURL())
+		assert.Equal(t, 5*time.Second, env.GetReadTimeout())
+		assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, env.GetCorsAllowedOrigins())
+
+		env, err = config.Load([]string{"-config", path, "-port", "9000"})
+		require.NoError(t, err)
+		assert.Equal(t, "9000", env.GetPort())
+	})
+
+	t.Run("toml file named by CONFIG_FILE", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", writeConfigFile(t, "config.toml", `
+db_driver = "memory"
+jwt_key = "`+testJwtKey+`"
+bcrypt_cost = 12
+cors_allowed_origins = ["*"]
+`))
+
+		env, err := config.Load(nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "memory", env.GetDbDriver())
+		assert.Equal(t, 12, env.GetBcryptCost())
+		assert.Equal(t, []string{"*"}, env.GetCorsAllowedOrigins())
+	})
+
+	t.Run("dotenv file with legacy names", func(t *testing.T) {
+		path := writeConfigFile(t, "app.env", `
+DbURL="sqlite::memory:"
+jwtKey="`+testJwtKey+`"
+CORS_ALLOWED_ORIGINS="https://a.example.com, https://b.example.com"
+UNRELATED=1
+`)
+
+		env, err := config.Load([]string{"-config", path})
+		require.NoError(t, err)
+
+		assert.Equal(t, "sqlite::memory:", env.GetDbURL())
+		assert.Equal(t, testJwtKey, env.GetJwtKey())
+		assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, env.GetCorsAllowedOrigins())
+	})
+
+	t.Run("invalid settings are all reported", func(t *testing.T) {
+		t.Setenv("JWT_KEY", "short")
+		t.Setenv("READ_TIMEOUT", "soon")
+		t.Setenv("AUTH_RATE_LIMIT", "20/day")
+		t.Setenv("TRASH_RETENTION", "-1h")
+		t.Setenv("SMTP_ADDR", "mail.example.com")
+		t.Setenv("REMINDER_WEBHOOK_URL", "ftp://hooks.example.com")
+		t.Setenv("TRUSTED_PROXIES", "proxy.internal")
+
+		_, err := config.Load([]string{"-port", "http"})
+		require.Error(t, err)
+
+		assert.ErrorContains(t, err, "invalid jwt_key: must be at least 32 characters long")
+		assert.ErrorContains(t, err, "invalid read_timeout")
+		assert.ErrorContains(t, err, `invalid port: "http" is not a TCP port`)
+		assert.ErrorContains(t, err, "invalid db_url: is required unless db_driver is memory")
+		assert.ErrorContains(t, err, `invalid auth_rate_limit: "20/day" is not of the form <requests>/<s|m|h> or off`)
+		assert.ErrorContains(t, err, "invalid trash_retention: must be positive")
+		assert.ErrorContains(t, err, `invalid smtp_addr: "mail.example.com" is not of the form host:port`)
+		assert.ErrorContains(t, err, "invalid smtp_from: must be an email address when smtp_addr is set")
+		assert.ErrorContains(t, err, `invalid reminder_webhook_url: "ftp://hooks.example.com" is not an http or https URL`)
+		assert.ErrorContains(t, err, `invalid trusted_proxies: "proxy.internal" is not an IP address or CIDR range`)
+	})
+
+	t.Run("unknown setting in file", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yml", "jwt_secret: abc\n")
+
+		_, err := config.Load([]string{"-config", path})
+		assert.ErrorContains(t, err, `unknown setting "jwt_secret"`)
+	})
+
+	t.Run("missing named file", func(t *testing.T) {
+		_, err := config.Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")})
+		assert.Error(t, err)
+	})
+}
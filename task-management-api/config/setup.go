@@ -1,21 +1,70 @@
 package config
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"task-management-api/metrics"
 	"task-management-api/mongo"
+	"task-management-api/repository"
+	"time"
 )
 
-func Initialize() (Environment, mongo.Database, error){
-	env, err := NewEnvironment()
+// Initialize loads the configuration from the command-line arguments args and
+// the environment, then connects the repositories to the configured database.
+func Initialize(args []string) (Environment, *repository.Repositories, error){
+	env, err := Load(args)
 	if err != nil {
-		log.Println(err)
-		log.Fatal(err)
+		return nil, nil, fmt.Errorf("loading configuration: %w", err)
 	}
 
-	db, err := GetMongoClient(env)
+	repositories, err := newRepositories(env)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, err
 	}
 
-    return env, db ,nil
+    return env, repositories ,nil
+}
+
+// newRepositories picks the database from the scheme of the DB URL: mongodb:// and
+// mongodb+srv:// use MongoDB, sqlite: and postgres:// use the SQL
+// repositories. DB_DRIVER=memory overrides it with the in-memory database,
+// which keeps all data in the process and loses it on restart.
+func newRepositories(env Environment) (*repository.Repositories, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	switch env.GetDbDriver() {
+	case "":
+	case "memory":
+		slog.Info("Using the in-memory database")
+		return repository.NewMongoRepositories(ctx, instrument(mongo.NewMemoryClient().Database(env.GetDbName())))
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", env.GetDbDriver())
+	}
+
+	scheme, _, _ := strings.Cut(env.GetDbURL(), ":")
+	switch strings.ToLower(scheme) {
+	case "mongodb", "mongodb+srv":
+		db, err := GetMongoClient(env)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewMongoRepositories(ctx, instrument(db))
+	case "sqlite", "postgres", "postgresql":
+		db, err := GetSQLDatabase(env)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewSQLRepositories(ctx, db)
+	default:
+		return nil, fmt.Errorf("unsupported database URL scheme %q", scheme)
+	}
+}
+
+// instrument reports the operations of database's collections to the service
+// metrics.
+func instrument(database mongo.Database) mongo.Database {
+	return mongo.NewInstrumentedDatabase(database, metrics.Default)
 }
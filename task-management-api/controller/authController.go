@@ -1,10 +1,10 @@
 package controller
 
 import (
-	"log"
 	"net/http"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/model"
+	"task-management-api/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,21 +22,23 @@ func NewAuthController(authorizationUsecase entities.AuthUseCase) *Authcontrolle
 
 func (au *Authcontroller) Register(c *gin.Context){
 
-	var newUser *model.UserCreate
-	if err := c.BindJSON(&newUser); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Bad Request"})
+	var request model.UserRegister
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
-	_ , err := au.AuthorizationUsecase.Register(newUser)	
-	if err != nil {
+	newUser := &model.UserCreate{
+		Username: request.Username,
+		Password: request.Password,
+		Email:    request.Email,
+		Name:     request.Name,
+		Bio:      request.Bio,
+	}
 
-		if err.Error() == "username already exists" {
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
-			return
-		}
-		
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal Server Error"})
+	_ , err := au.AuthorizationUsecase.Register(c.Request.Context(), newUser)	
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -44,20 +46,106 @@ func (au *Authcontroller) Register(c *gin.Context){
 }
 
 func (uc *Authcontroller) Login(c *gin.Context){
-	var userLogin *model.UserLogin
+	var userLogin model.UserLogin
+
+	if err := bindJSON(c, &userLogin); err != nil {
+		c.Error(err)
+		return
+	}
+
+	tokens, err := uc.AuthorizationUsecase.Login(c.Request.Context(), &userLogin)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
 
-	if err := c.BindJSON(&userLogin); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Bad Request"})
+}
+
+func (au *Authcontroller) Refresh(c *gin.Context) {
+	var tokenRefresh model.TokenRefresh
+
+	if err := bindJSON(c, &tokenRefresh); err != nil {
+		c.Error(err)
 		return
 	}
 
-	token, err := uc.AuthorizationUsecase.Login(userLogin)
+	tokens, err := au.AuthorizationUsecase.Refresh(c.Request.Context(), tokenRefresh.RefreshToken)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (au *Authcontroller) Logout(c *gin.Context) {
+	currUser, exists := middleware.CurrentUser(c)
+	if !exists {
+		c.Error(errNotAuthenticated)
+		return
+	}
 
-}
\ No newline at end of file
+	// The refresh token is optional; without it only the access token is revoked.
+	var tokenRefresh model.TokenRefresh
+	if c.Request.ContentLength > 0 {
+		if err := bindJSON(c, &tokenRefresh); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	if err := au.AuthorizationUsecase.Logout(c.Request.Context(), currUser, tokenRefresh.RefreshToken); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func (au *Authcontroller) LogoutAll(c *gin.Context) {
+	currUser, exists := middleware.CurrentUser(c)
+	if !exists {
+		c.Error(errNotAuthenticated)
+		return
+	}
+
+	if err := au.AuthorizationUsecase.LogoutAll(c.Request.Context(), currUser); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all sessions"})
+}
+
+func (au *Authcontroller) AdminRegister(c *gin.Context) {
+	currUser, exists := middleware.CurrentUser(c)
+	if !exists {
+		c.Error(errNotAuthenticated)
+		return
+	}
+
+	var request model.AdminUserCreate
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	newUser := &model.UserCreate{
+		Username: request.Username,
+		Password: request.Password,
+		Email:    request.Email,
+		Name:     request.Name,
+		Bio:      request.Bio,
+		Role:     request.Role,
+	}
+
+	_, err := au.AuthorizationUsecase.AdminRegister(c.Request.Context(), currUser, newUser, nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "User created successfully"})
+}
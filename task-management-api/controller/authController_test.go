@@ -6,18 +6,23 @@ import (
 	"net/http/httptest"
 	"strings"
 	"task-management-api/controller"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
 	"task-management-api/domain/mocks"
 	"task-management-api/domain/model"
+	"task-management-api/middleware"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestRegister(t *testing.T) {
 	mockUsecase := new(mocks.AuthUseCase)
 	router := gin.Default()
+	router.Use(middleware.ErrorHandler())
 
 	ac := controller.NewAuthController(mockUsecase)
 
@@ -37,7 +42,7 @@ func TestRegister(t *testing.T) {
 			ID:     fixedID,
 		}
 		
-		mockUsecase.On("Register", newUser).Return(&model.UserInfo{}, nil).Once()
+		mockUsecase.On("Register", mock.Anything, newUser).Return(&model.UserInfo{}, nil).Once()
 
 		body := `{
 			"username": "newuser", 
@@ -66,7 +71,9 @@ func TestRegister(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.JSONEq(t, `{"message": "Bad Request"}`, w.Body.String())
+		assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid request body", "instance": "/register",
+			"errors": [{"field": "password", "message": "must be of type string"}]}`, w.Body.String())
 	})
 
 	t.Run("username already exists", func(t *testing.T) {
@@ -75,15 +82,15 @@ func TestRegister(t *testing.T) {
 			Password: "password",
 		}
 		
-		mockUsecase.On("Register", newUser).Return(nil, errors.New("username already exists")).Once()
+		mockUsecase.On("Register", mock.Anything, newUser).Return(nil, apperrors.Conflict("username already exists")).Once()
 
 		body := `{"username":"existinguser", "password":"password"}`
 		req, _ := http.NewRequest("POST", "/register", strings.NewReader(body))
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.JSONEq(t, `{"message": "username already exists"}`, w.Body.String())
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Conflict", "status": 409, "detail": "username already exists", "instance": "/register"}`, w.Body.String())
 
 		mockUsecase.AssertExpectations(t)
 	})
@@ -94,7 +101,7 @@ func TestRegister(t *testing.T) {
 			Password: "password",
 		}
 		
-		mockUsecase.On("Register", newUser).Return(nil,errors.New("unexpected error")).Once()
+		mockUsecase.On("Register", mock.Anything, newUser).Return(nil,errors.New("unexpected error")).Once()
 
 		body := `{"username":"erroruser", "password":"password"}`
 		req, _ := http.NewRequest("POST", "/register", strings.NewReader(body))
@@ -102,7 +109,7 @@ func TestRegister(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.JSONEq(t, `{"message": "Internal Server Error"}`, w.Body.String())
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/register"}`, w.Body.String())
 
 		mockUsecase.AssertExpectations(t)
 	})
@@ -111,6 +118,7 @@ func TestRegister(t *testing.T) {
 func TestLogin(t *testing.T) {
 	mockUsecase := new(mocks.AuthUseCase)
 	router := gin.Default()
+	router.Use(middleware.ErrorHandler())
 
 	ac := controller.NewAuthController(mockUsecase)
 
@@ -122,7 +130,8 @@ func TestLogin(t *testing.T) {
 			Password: "password",
 		}
 		
-		mockUsecase.On("Login", userLogin).Return("token", nil).Once()
+		tokens := &entities.TokenPair{AccessToken: "token", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 900}
+		mockUsecase.On("Login", mock.Anything, userLogin).Return(tokens, nil).Once()
 
 		body := `{"username":"newuser", "password":"password"}`
 		req, _ := http.NewRequest("POST", "/login", strings.NewReader(body))
@@ -130,7 +139,7 @@ func TestLogin(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.JSONEq(t, `{"token": "token"}`, w.Body.String())
+		assert.JSONEq(t, `{"access_token": "token", "refresh_token": "refresh", "token_type": "Bearer", "expires_in": 900}`, w.Body.String())
 
 		mockUsecase.AssertExpectations(t)
 	})
@@ -142,7 +151,8 @@ func TestLogin(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.JSONEq(t, `{"message": "Bad Request"}`, w.Body.String())
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid request body", "instance": "/login",
+			"errors": [{"field": "password", "message": "must be of type string"}]}`, w.Body.String())
 	})
 
 	t.Run("unauthorized", func(t *testing.T) {
@@ -151,7 +161,7 @@ func TestLogin(t *testing.T) {
 			Password: "password",
 		}
 		
-		mockUsecase.On("Login", userLogin).Return("", errors.New("unauthorized")).Once()
+		mockUsecase.On("Login", mock.Anything, userLogin).Return(nil, apperrors.Unauthorized("invalid username or password")).Once()
 
 		body := `{"username":"existinguser", "password":"password"}`
 		req, _ := http.NewRequest("POST", "/login", strings.NewReader(body))
@@ -159,8 +169,98 @@ func TestLogin(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.JSONEq(t, `{"message": "Unauthorized"}`, w.Body.String())
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Unauthorized", "status": 401, "detail": "invalid username or password", "instance": "/login"}`, w.Body.String())
+
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	mockUsecase := new(mocks.AuthUseCase)
+	router := gin.Default()
+	router.Use(middleware.ErrorHandler())
+
+	ac := controller.NewAuthController(mockUsecase)
+
+	router.POST("/refresh", ac.Refresh)
+
+	t.Run("success", func(t *testing.T) {
+		tokens := &entities.TokenPair{AccessToken: "newtoken", RefreshToken: "newrefresh", TokenType: "Bearer", ExpiresIn: 900}
+		mockUsecase.On("Refresh", mock.Anything, "refresh").Return(tokens, nil).Once()
+
+		body := `{"refresh_token": "refresh"}`
+		req, _ := http.NewRequest("POST", "/refresh", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"access_token": "newtoken", "refresh_token": "newrefresh", "token_type": "Bearer", "expires_in": 900}`, w.Body.String())
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("reused token", func(t *testing.T) {
+		mockUsecase.On("Refresh", mock.Anything, "reused").Return(nil, apperrors.Unauthorized("refresh token reuse detected")).Once()
+
+		body := `{"refresh_token": "reused"}`
+		req, _ := http.NewRequest("POST", "/refresh", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Unauthorized", "status": 401, "detail": "refresh token reuse detected", "instance": "/refresh"}`, w.Body.String())
+
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestLogout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currUser := entities.AuthenticatedUser{UserID: "user_id", Role: entities.RoleUser, TokenID: "jti"}
+
+	mockUsecase := new(mocks.AuthUseCase)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	ac := controller.NewAuthController(mockUsecase)
+	setUser := func(c *gin.Context) {
+		c.Set("user", currUser)
+		c.Next()
+	}
+	router.POST("/logout", setUser, ac.Logout)
+	router.POST("/logout-all", setUser, ac.LogoutAll)
+
+	t.Run("logout", func(t *testing.T) {
+		mockUsecase.On("Logout", mock.Anything, currUser, "refresh").Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/logout", strings.NewReader(`{"refresh_token": "refresh"}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
 
+	t.Run("logout without refresh token", func(t *testing.T) {
+		mockUsecase.On("Logout", mock.Anything, currUser, "").Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/logout", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("logout all", func(t *testing.T) {
+		mockUsecase.On("LogoutAll", mock.Anything, currUser).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/logout-all", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "Logged out of all sessions"}`, w.Body.String())
 		mockUsecase.AssertExpectations(t)
 	})
 }
@@ -171,3 +271,78 @@ func TestNewAuthController(t *testing.T) {
 
 	assert.NotNil(t, ac)
 }
+
+func TestAdminRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	admin := entities.AuthenticatedUser{UserID: "admin_id", Username: "admin", Role: entities.RoleAdmin}
+
+	newRouter := func(mockUsecase *mocks.AuthUseCase) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		ac := controller.NewAuthController(mockUsecase)
+		router.POST("/admin/users", func(c *gin.Context) {
+			c.Set("user", admin)
+			c.Next()
+		}, ac.AdminRegister)
+		return router
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUsecase := new(mocks.AuthUseCase)
+		router := newRouter(mockUsecase)
+
+		newUser := &model.UserCreate{Username: "newadmin", Password: "password", Role: entities.RoleAdmin}
+		mockUsecase.On("AdminRegister", mock.Anything, admin, newUser, nil).Return(&model.UserInfo{}, nil).Once()
+
+		body := `{"username": "newadmin", "password": "password", "role": "ADMIN"}`
+		req, _ := http.NewRequest(http.MethodPost, "/admin/users", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"message": "User created successfully"}`, w.Body.String())
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockUsecase := new(mocks.AuthUseCase)
+		router := newRouter(mockUsecase)
+
+		mockUsecase.On("AdminRegister", mock.Anything, admin, mock.Anything, nil).Return(nil, apperrors.Forbidden("admin role required")).Once()
+
+		body := `{"username": "newadmin", "password": "password"}`
+		req, _ := http.NewRequest(http.MethodPost, "/admin/users", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Forbidden", "status": 403, "detail": "admin role required", "instance": "/admin/users"}`, w.Body.String())
+	})
+}
+
+func TestRegisterValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUsecase := new(mocks.AuthUseCase)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+
+	ac := controller.NewAuthController(mockUsecase)
+	router.POST("/register", ac.Register)
+
+	body := `{"username": "ab", "password": "short", "email": "not-an-email"}`
+	req, _ := http.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid request body", "instance": "/register",
+		"errors": [
+			{"field": "username", "message": "must be at least 3 characters long"},
+			{"field": "password", "message": "must be at least 8 characters long"},
+			{"field": "email", "message": "must be a valid email address"}
+		]}`, w.Body.String())
+	mockUsecase.AssertNotCalled(t, "Register", mock.Anything)
+}
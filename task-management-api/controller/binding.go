@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/model"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// validate checks the `binding` tags of request bodies. Field errors are
+// reported with their JSON names.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	// Merge patch members are validated by the value they set; absent and
+	// null members are skipped by omitempty.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(interface{ ValueOrNil() interface{} }).ValueOrNil()
+	}, model.Patch[string]{}, model.Patch[[]string]{}, model.Patch[time.Time]{})
+
+	// notpast rejects timestamps before the time of the request.
+	v.RegisterValidation("notpast", func(fl validator.FieldLevel) bool {
+		value, ok := fl.Field().Interface().(time.Time)
+		return ok && !value.Before(time.Now())
+	})
+
+	return v
+}
+
+// bindJSON decodes the request body into obj and validates it. Unknown fields,
+// malformed JSON and values breaking a binding rule are all rejected with a
+// validation error naming the offending fields.
+func bindJSON(c *gin.Context, obj interface{}) error {
+	if c.Request.Body == nil {
+		return errEmptyRequestBody
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return decodeError(err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errInvalidRequestBody.Wrap(errors.New("unexpected data after the JSON body"))
+	}
+
+	if err := validate.Struct(obj); err != nil {
+		return validationError(err)
+	}
+
+	return nil
+}
+
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return errEmptyRequestBody
+	case errors.As(err, &typeErr):
+		return apperrors.Validation(errInvalidRequestBody.Detail,
+			apperrors.FieldError{Field: typeErr.Field, Message: "must be of type " + jsonType(typeErr.Type)}).Wrap(err)
+	case errors.As(err, &timeErr):
+		return apperrors.Validation("timestamps must use the RFC 3339 format").Wrap(err)
+	case errors.As(err, &syntaxErr):
+		return apperrors.Validation("request body is not valid JSON").Wrap(err)
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return apperrors.Validation(errInvalidRequestBody.Detail,
+			apperrors.FieldError{Field: strings.Trim(field, `"`), Message: "is not allowed"}).Wrap(err)
+	}
+
+	return errInvalidRequestBody.Wrap(err)
+}
+
+func validationError(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return errInvalidRequestBody.Wrap(err)
+	}
+
+	fields := make([]apperrors.FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		// The namespace starts with the name of the request struct.
+		_, field, _ := strings.Cut(fieldErr.Namespace(), ".")
+		fields = append(fields, apperrors.FieldError{Field: field, Message: validationMessage(fieldErr)})
+	}
+
+	return apperrors.Validation(errInvalidRequestBody.Detail, fields...).Wrap(err)
+}
+
+func validationMessage(fieldErr validator.FieldError) string {
+	isList := fieldErr.Kind() == reflect.Slice
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if isList {
+			return fmt.Sprintf("must have at least %s items", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at least %s characters long", fieldErr.Param())
+	case "max":
+		if isList {
+			return fmt.Sprintf("must have at most %s items", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at most %s characters long", fieldErr.Param())
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	case "notpast":
+		return "must not be in the past"
+	default:
+		return "is invalid"
+	}
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"net/http"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type commentcontroller struct {
+	CommentUsecase entities.CommentUsecase
+}
+
+func NewCommentController(commentUsecase entities.CommentUsecase) *commentcontroller {
+	return &commentcontroller{
+		CommentUsecase: commentUsecase,
+	}
+}
+
+func (cc *commentcontroller) GetComments(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	query, err := pageQueryFromRequest(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := cc.CommentUsecase.GetComments(c.Request.Context(), c.Param("id"), userID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (cc *commentcontroller) CreateComment(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var request model.CommentRequest
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	comment, err := cc.CommentUsecase.CreateComment(c.Request.Context(), c.Param("id"), userID, request.Body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment created successfully", "comment": comment})
+}
+
+func (cc *commentcontroller) UpdateComment(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var request model.CommentRequest
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	comment, err := cc.CommentUsecase.UpdateComment(c.Request.Context(), c.Param("id"), c.Param("commentId"), userID, request.Body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment updated successfully", "comment": comment})
+}
+
+func (cc *commentcontroller) DeleteComment(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := cc.CommentUsecase.DeleteComment(c.Request.Context(), c.Param("id"), c.Param("commentId"), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
@@ -0,0 +1,142 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-management-api/controller"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/domain/model"
+	"task-management-api/middleware"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newCommentRouter(mockUsecase *mocks.CommentUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", "test_user_id")
+		c.Next()
+	})
+
+	cc := controller.NewCommentController(mockUsecase)
+	router.GET("/tasks/:id/comments", cc.GetComments)
+	router.POST("/tasks/:id/comments", cc.CreateComment)
+	router.PATCH("/tasks/:id/comments/:commentId", cc.UpdateComment)
+	router.DELETE("/tasks/:id/comments/:commentId", cc.DeleteComment)
+	return router
+}
+
+func TestGetComments(t *testing.T) {
+	t.Run("Comments Retrieved Successfully", func(t *testing.T) {
+		mockUsecase := mocks.NewCommentUsecase(t)
+		page := &model.CommentPage{Comments: []*model.CommentInfo{{ID: "c1", Body: "Hello"}}, Next: "c1"}
+		mockUsecase.On("GetComments", mock.Anything, "1", "test_user_id", model.PageQuery{Cursor: "c0", Limit: 1}).Return(page, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/tasks/1/comments?limit=1&cursor=c0", nil)
+		w := httptest.NewRecorder()
+		newCommentRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response model.CommentPage
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "c1", response.Next)
+		assert.Len(t, response.Comments, 1)
+	})
+
+	t.Run("Invalid Limit", func(t *testing.T) {
+		mockUsecase := mocks.NewCommentUsecase(t)
+
+		req, _ := http.NewRequest(http.MethodGet, "/tasks/1/comments?limit=many", nil)
+		w := httptest.NewRecorder()
+		newCommentRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCreateComment(t *testing.T) {
+	t.Run("Comment Created Successfully", func(t *testing.T) {
+		mockUsecase := mocks.NewCommentUsecase(t)
+		mockUsecase.On("CreateComment", mock.Anything, "1", "test_user_id", "Hello").Return(&model.CommentInfo{ID: "c1", Body: "Hello"}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/tasks/1/comments", strings.NewReader(`{"body": "Hello"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newCommentRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "Comment created successfully")
+	})
+
+	t.Run("Missing Body", func(t *testing.T) {
+		mockUsecase := mocks.NewCommentUsecase(t)
+
+		req, _ := http.NewRequest(http.MethodPost, "/tasks/1/comments", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newCommentRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdateComment(t *testing.T) {
+	t.Run("Comment Updated Successfully", func(t *testing.T) {
+		mockUsecase := mocks.NewCommentUsecase(t)
+		mockUsecase.On("UpdateComment", mock.Anything, "1", "c1", "test_user_id", "Edited").Return(&model.CommentInfo{ID: "c1", Body: "Edited"}, nil)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/tasks/1/comments/c1", strings.NewReader(`{"body": "Edited"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newCommentRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Comment updated successfully")
+	})
+
+	t.Run("Not The Author", func(t *testing.T) {
+		mockUsecase := mocks.NewCommentUsecase(t)
+		mockUsecase.On("UpdateComment", mock.Anything, "1", "c1", "test_user_id", "Edited").Return(nil, entities.ErrCommentNotAuthor)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/tasks/1/comments/c1", strings.NewReader(`{"body": "Edited"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newCommentRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	t.Run("Comment Deleted Successfully", func(t *testing.T) {
+		mockUsecase := mocks.NewCommentUsecase(t)
+		mockUsecase.On("DeleteComment", mock.Anything, "1", "c1", "test_user_id").Return(nil)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/tasks/1/comments/c1", nil)
+		w := httptest.NewRecorder()
+		newCommentRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "Comment deleted successfully"}`, w.Body.String())
+	})
+
+	t.Run("Comment Not Found", func(t *testing.T) {
+		mockUsecase := mocks.NewCommentUsecase(t)
+		mockUsecase.On("DeleteComment", mock.Anything, "1", "c1", "test_user_id").Return(entities.ErrCommentNotFound)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/tasks/1/comments/c1", nil)
+		w := httptest.NewRecorder()
+		newCommentRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
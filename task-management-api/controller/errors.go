@@ -0,0 +1,13 @@
+package controller
+
+import "task-management-api/domain/apperrors"
+
+// errInvalidRequestBody is returned when a request body is not valid JSON or
+// does not match the expected shape.
+var errInvalidRequestBody = apperrors.Validation("invalid request body")
+
+var errEmptyRequestBody = apperrors.Validation("request body is required")
+
+// errNotAuthenticated is returned by handlers behind AuthMiddleware when no
+// authenticated user was set on the context.
+var errNotAuthenticated = apperrors.Unauthorized("authentication required")
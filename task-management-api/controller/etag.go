@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+)
+
+// errIfMatchRequired is returned when a task is changed without saying which
+// version of it the client last read.
+var errIfMatchRequired = apperrors.PreconditionRequired("If-Match header with the task's ETag is required")
+
+// taskETag is the entity tag of a task at version.
+func taskETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// taskVersionFromIfMatch reads the task version from an If-Match header,
+// which must hold either "*" or a single strong ETag returned by the API. An
+// ETag that cannot belong to any task version fails the precondition.
+func taskVersionFromIfMatch(header string) (int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, errIfMatchRequired
+	}
+
+	if header == "*" {
+		return entities.AnyTaskVersion, nil
+	}
+
+	// Weak tags never match: If-Match uses the strong comparison.
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, entities.ErrTaskModified
+	}
+
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil || version < 0 {
+		return 0, entities.ErrTaskModified
+	}
+
+	return version, nil
+}
+
+// etagListMatches reports whether an If-None-Match header matches etag. The
+// header may list several tags; weak tags compare equal to strong ones.
+func etagListMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
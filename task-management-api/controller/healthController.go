@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds how long a readiness probe waits for the database.
+const readinessTimeout = 2 * time.Second
+
+var errDatabaseUnavailable = apperrors.Unavailable("database unavailable")
+
+type healthcontroller struct {
+	HealthChecker entities.HealthChecker
+}
+
+func NewHealthController(healthChecker entities.HealthChecker) *healthcontroller {
+	return &healthcontroller{
+		HealthChecker: healthChecker,
+	}
+}
+
+// Liveness reports that the process is up and serving requests. It does not
+// touch the database, so a database outage does not get the process restarted.
+func (hc *healthcontroller) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness reports whether the service can handle traffic, which requires a
+// reachable database.
+func (hc *healthcontroller) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := hc.HealthChecker.Ping(ctx); err != nil {
+		c.Error(errDatabaseUnavailable.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,70 @@
+package controller_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task-management-api/controller"
+	"task-management-api/domain/mocks"
+	"task-management-api/middleware"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(healthChecker *mocks.HealthChecker) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+
+		hc := controller.NewHealthController(healthChecker)
+		router.GET("/healthz", hc.Liveness)
+		router.GET("/readyz", hc.Readiness)
+		return router
+	}
+
+	t.Run("liveness does not ping the database", func(t *testing.T) {
+		mockHealthChecker := new(mocks.HealthChecker)
+		router := newRouter(mockHealthChecker)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+		mockHealthChecker.AssertNotCalled(t, "Ping", mock.Anything)
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		mockHealthChecker := new(mocks.HealthChecker)
+		mockHealthChecker.On("Ping", mock.Anything).Return(nil).Once()
+		router := newRouter(mockHealthChecker)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+		mockHealthChecker.AssertExpectations(t)
+	})
+
+	t.Run("database unreachable", func(t *testing.T) {
+		mockHealthChecker := new(mocks.HealthChecker)
+		mockHealthChecker.On("Ping", mock.Anything).Return(errors.New("connection refused")).Once()
+		router := newRouter(mockHealthChecker)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Service Unavailable", "status": 503, "detail": "database unavailable", "instance": "/readyz"}`, w.Body.String())
+		mockHealthChecker.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// projectcontroller serves /projects. The membership of the user in the
+// project in the path is checked by middleware.RequireProjectRole before any
+// handler taking a project ID runs.
+type projectcontroller struct {
+	ProjectUsecase entities.ProjectUsecase
+	TaskUsecase    entities.TaskUsecase
+}
+
+func NewProjectController(projectUsecase entities.ProjectUsecase, taskUsecase entities.TaskUsecase) *projectcontroller {
+	return &projectcontroller{
+		ProjectUsecase: projectUsecase,
+		TaskUsecase:    taskUsecase,
+	}
+}
+
+// GetProjects lists the projects the user owns or is a member of. Archived
+// projects are only listed with ?archived=true.
+func (pc *projectcontroller) GetProjects(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	archived := false
+	if value := c.Query("archived"); value != "" {
+		archived, err = strconv.ParseBool(value)
+		if err != nil {
+			c.Error(apperrors.Validation("invalid archived", apperrors.FieldError{Field: "archived", Message: "must be true or false"}))
+			return
+		}
+	}
+
+	projects, err := pc.ProjectUsecase.GetProjects(c.Request.Context(), userID, archived)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"projects": projects})
+}
+
+func (pc *projectcontroller) CreateProject(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var request model.ProjectRequest
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	project, err := pc.ProjectUsecase.CreateProject(c.Request.Context(), request.Name, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Project created successfully", "project": project})
+}
+
+func (pc *projectcontroller) GetProjectByID(c *gin.Context) {
+	project, err := pc.ProjectUsecase.GetProjectByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
+func (pc *projectcontroller) RenameProject(c *gin.Context) {
+	var request model.ProjectRequest
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pc.changeProject(c, "Project renamed successfully", func(ctx context.Context, id string) (*model.ProjectInfo, error) {
+		return pc.ProjectUsecase.RenameProject(ctx, id, request.Name)
+	})
+}
+
+func (pc *projectcontroller) ArchiveProject(c *gin.Context) {
+	pc.changeProject(c, "Project archived successfully", func(ctx context.Context, id string) (*model.ProjectInfo, error) {
+		return pc.ProjectUsecase.ArchiveProject(ctx, id, true)
+	})
+}
+
+func (pc *projectcontroller) UnarchiveProject(c *gin.Context) {
+	pc.changeProject(c, "Project unarchived successfully", func(ctx context.Context, id string) (*model.ProjectInfo, error) {
+		return pc.ProjectUsecase.ArchiveProject(ctx, id, false)
+	})
+}
+
+func (pc *projectcontroller) AddProjectMember(c *gin.Context) {
+	var request model.MemberRequest
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pc.changeProject(c, "Member added successfully", func(ctx context.Context, id string) (*model.ProjectInfo, error) {
+		return pc.ProjectUsecase.AddProjectMember(ctx, id, c.Param("username"), request.Role)
+	})
+}
+
+func (pc *projectcontroller) RemoveProjectMember(c *gin.Context) {
+	pc.changeProject(c, "Member removed successfully", func(ctx context.Context, id string) (*model.ProjectInfo, error) {
+		return pc.ProjectUsecase.RemoveProjectMember(ctx, id, c.Param("username"))
+	})
+}
+
+// changeProject runs a change to the project in the path and answers with the
+// changed project.
+func (pc *projectcontroller) changeProject(c *gin.Context, message string, change func(ctx context.Context, id string) (*model.ProjectInfo, error)) {
+	project, err := change(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "project": project})
+}
+
+// GetProjectTasks lists the tasks of a project. It accepts the same query
+// parameters as GET /task/.
+func (pc *projectcontroller) GetProjectTasks(c *gin.Context) {
+	query, err := taskQueryFromRequest(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := pc.TaskUsecase.GetProjectTasks(c.Request.Context(), c.Param("id"), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (pc *projectcontroller) CreateProjectTask(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var request model.TaskCreate
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	task := taskFromCreate(request, userID)
+	task.ProjectID = c.Param("id")
+
+	if err := pc.TaskUsecase.CreateTask(c.Request.Context(), task); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Task created successfully"})
+}
+
+func (pc *projectcontroller) MoveTask(c *gin.Context) {
+	pc.changeProjectTask(c, "Task moved successfully", pc.TaskUsecase.MoveTask)
+}
+
+func (pc *projectcontroller) RemoveProjectTask(c *gin.Context) {
+	pc.changeProjectTask(c, "Task removed from project successfully", pc.TaskUsecase.RemoveTaskFromProject)
+}
+
+// changeProjectTask runs a change to the project of the task in the path and
+// answers with the changed task and its new ETag.
+func (pc *projectcontroller) changeProjectTask(c *gin.Context, message string, change func(ctx context.Context, id string, projectID string, userID string) (*model.TaskInfo, error)) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	task, err := change(c.Request.Context(), c.Param("taskId"), c.Param("id"), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", taskETag(task.Version))
+	c.JSON(http.StatusOK, gin.H{"message": message, "task": task})
+}
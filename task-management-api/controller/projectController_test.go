@@ -0,0 +1,227 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-management-api/controller"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/domain/model"
+	"task-management-api/middleware"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newProjectRouter(projectUsecase *mocks.ProjectUsecase, taskUsecase *mocks.TaskUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", "test_user_id")
+		c.Next()
+	})
+
+	pc := controller.NewProjectController(projectUsecase, taskUsecase)
+	router.GET("/projects", pc.GetProjects)
+	router.POST("/projects", pc.CreateProject)
+	router.GET("/projects/:id", pc.GetProjectByID)
+	router.PATCH("/projects/:id", pc.RenameProject)
+	router.POST("/projects/:id/archive", pc.ArchiveProject)
+	router.POST("/projects/:id/unarchive", pc.UnarchiveProject)
+	router.PUT("/projects/:id/members/:username", pc.AddProjectMember)
+	router.DELETE("/projects/:id/members/:username", pc.RemoveProjectMember)
+	router.GET("/projects/:id/tasks", pc.GetProjectTasks)
+	router.POST("/projects/:id/tasks", pc.CreateProjectTask)
+	router.PUT("/projects/:id/tasks/:taskId", pc.MoveTask)
+	router.DELETE("/projects/:id/tasks/:taskId", pc.RemoveProjectTask)
+	return router
+}
+
+func serveProject(router *gin.Engine, method string, path string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetProjects(t *testing.T) {
+	projects := []*model.ProjectInfo{{ID: "1", Name: "Launch", OwnerID: "test_user_id", Members: []model.ProjectMember{}}}
+
+	t.Run("success", func(t *testing.T) {
+		projectUsecase := mocks.NewProjectUsecase(t)
+		projectUsecase.On("GetProjects", mock.Anything, "test_user_id", true).Return(projects, nil)
+
+		w := serveProject(newProjectRouter(projectUsecase, mocks.NewTaskUsecase(t)), http.MethodGet, "/projects?archived=true", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		expectedResponse, _ := json.Marshal(gin.H{"projects": projects})
+		assert.JSONEq(t, string(expectedResponse), w.Body.String())
+	})
+
+	t.Run("invalid archived", func(t *testing.T) {
+		w := serveProject(newProjectRouter(mocks.NewProjectUsecase(t), mocks.NewTaskUsecase(t)), http.MethodGet, "/projects?archived=maybe", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCreateProject(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		project := &model.ProjectInfo{ID: "1", Name: "Launch", OwnerID: "test_user_id", Members: []model.ProjectMember{}}
+
+		projectUsecase := mocks.NewProjectUsecase(t)
+		projectUsecase.On("CreateProject", mock.Anything, "Launch", "test_user_id").Return(project, nil)
+
+		w := serveProject(newProjectRouter(projectUsecase, mocks.NewTaskUsecase(t)), http.MethodPost, "/projects", `{"name": "Launch"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		expectedResponse, _ := json.Marshal(gin.H{"message": "Project created successfully", "project": project})
+		assert.JSONEq(t, string(expectedResponse), w.Body.String())
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		w := serveProject(newProjectRouter(mocks.NewProjectUsecase(t), mocks.NewTaskUsecase(t)), http.MethodPost, "/projects", `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestChangeProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		setup   func(projectUsecase *mocks.ProjectUsecase, project *model.ProjectInfo)
+		message string
+	}{
+		{
+			name: "rename", method: http.MethodPatch, path: "/projects/1", body: `{"name": "Relaunch"}`, message: "Project renamed successfully",
+			setup: func(projectUsecase *mocks.ProjectUsecase, project *model.ProjectInfo) {
+				projectUsecase.On("RenameProject", mock.Anything, "1", "Relaunch").Return(project, nil)
+			},
+		},
+		{
+			name: "archive", method: http.MethodPost, path: "/projects/1/archive", message: "Project archived successfully",
+			setup: func(projectUsecase *mocks.ProjectUsecase, project *model.ProjectInfo) {
+				projectUsecase.On("ArchiveProject", mock.Anything, "1", true).Return(project, nil)
+			},
+		},
+		{
+			name: "unarchive", method: http.MethodPost, path: "/projects/1/unarchive", message: "Project unarchived successfully",
+			setup: func(projectUsecase *mocks.ProjectUsecase, project *model.ProjectInfo) {
+				projectUsecase.On("ArchiveProject", mock.Anything, "1", false).Return(project, nil)
+			},
+		},
+		{
+			name: "add member", method: http.MethodPut, path: "/projects/1/members/bob", body: `{"role": "editor"}`, message: "Member added successfully",
+			setup: func(projectUsecase *mocks.ProjectUsecase, project *model.ProjectInfo) {
+				projectUsecase.On("AddProjectMember", mock.Anything, "1", "bob", entities.ProjectRoleEditor).Return(project, nil)
+			},
+		},
+		{
+			name: "remove member", method: http.MethodDelete, path: "/projects/1/members/bob", message: "Member removed successfully",
+			setup: func(projectUsecase *mocks.ProjectUsecase, project *model.ProjectInfo) {
+				projectUsecase.On("RemoveProjectMember", mock.Anything, "1", "bob").Return(project, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectUsecase := mocks.NewProjectUsecase(t)
+			project := &model.ProjectInfo{ID: "1", Name: "Launch", OwnerID: "test_user_id", Members: []model.ProjectMember{}}
+			tt.setup(projectUsecase, project)
+
+			w := serveProject(newProjectRouter(projectUsecase, mocks.NewTaskUsecase(t)), tt.method, tt.path, tt.body)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			expectedResponse, _ := json.Marshal(gin.H{"message": tt.message, "project": project})
+			assert.JSONEq(t, string(expectedResponse), w.Body.String())
+		})
+	}
+
+	t.Run("owner role cannot be given", func(t *testing.T) {
+		w := serveProject(newProjectRouter(mocks.NewProjectUsecase(t), mocks.NewTaskUsecase(t)), http.MethodPut, "/projects/1/members/bob", `{"role": "owner"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("archived project", func(t *testing.T) {
+		projectUsecase := mocks.NewProjectUsecase(t)
+		projectUsecase.On("RenameProject", mock.Anything, "1", "Relaunch").Return(nil, entities.ErrProjectArchived)
+
+		w := serveProject(newProjectRouter(projectUsecase, mocks.NewTaskUsecase(t)), http.MethodPatch, "/projects/1", `{"name": "Relaunch"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestProjectTasks(t *testing.T) {
+	t.Run("list", func(t *testing.T) {
+		page := &model.TaskPage{Tasks: []*model.TaskInfo{{ID: "2", Title: "In project", ProjectID: "1"}}}
+
+		taskUsecase := mocks.NewTaskUsecase(t)
+		taskUsecase.On("GetProjectTasks", mock.Anything, "1", model.TaskQuery{Status: entities.StatusTodo}).Return(page, nil)
+
+		w := serveProject(newProjectRouter(mocks.NewProjectUsecase(t), taskUsecase), http.MethodGet, "/projects/1/tasks?status=todo", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		expectedResponse, _ := json.Marshal(page)
+		assert.JSONEq(t, string(expectedResponse), w.Body.String())
+	})
+
+	t.Run("create", func(t *testing.T) {
+		taskUsecase := mocks.NewTaskUsecase(t)
+		taskUsecase.On("CreateTask", mock.Anything, mock.MatchedBy(func(task entities.Task) bool {
+			return task.Title == "In project" && task.ProjectID == "1" && task.UserID == "test_user_id"
+		})).Return(nil)
+
+		w := serveProject(newProjectRouter(mocks.NewProjectUsecase(t), taskUsecase), http.MethodPost, "/projects/1/tasks", `{"title": "In project"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"message": "Task created successfully"}`, w.Body.String())
+	})
+
+	tests := []struct {
+		name    string
+		method  string
+		setup   func(taskUsecase *mocks.TaskUsecase, task *model.TaskInfo)
+		message string
+	}{
+		{
+			name: "move", method: http.MethodPut, message: "Task moved successfully",
+			setup: func(taskUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+				taskUsecase.On("MoveTask", mock.Anything, "2", "1", "test_user_id").Return(task, nil)
+			},
+		},
+		{
+			name: "remove", method: http.MethodDelete, message: "Task removed from project successfully",
+			setup: func(taskUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+				taskUsecase.On("RemoveTaskFromProject", mock.Anything, "2", "1", "test_user_id").Return(task, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskUsecase := mocks.NewTaskUsecase(t)
+			task := &model.TaskInfo{ID: "2", Title: "Moved", Version: 5}
+			tt.setup(taskUsecase, task)
+
+			w := serveProject(newProjectRouter(mocks.NewProjectUsecase(t), taskUsecase), tt.method, "/projects/1/tasks/2", "")
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+			expectedResponse, _ := json.Marshal(gin.H{"message": tt.message, "task": task})
+			assert.JSONEq(t, string(expectedResponse), w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"net/http"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tagcontroller struct {
+	TagUsecase entities.TagUsecase
+}
+
+func NewTagController(tagUsecase entities.TagUsecase) *tagcontroller {
+	return &tagcontroller{
+		TagUsecase: tagUsecase,
+	}
+}
+
+func (tc *tagcontroller) GetTags(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	tags, err := tc.TagUsecase.GetTags(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+func (tc *tagcontroller) RenameTag(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var request model.TagRename
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	updated, err := tc.TagUsecase.RenameTag(c.Request.Context(), userID, c.Param("name"), request.Name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag renamed successfully", "tasks_updated": updated})
+}
+
+func (tc *tagcontroller) DeleteTag(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	updated, err := tc.TagUsecase.DeleteTag(c.Request.Context(), userID, c.Param("name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully", "tasks_updated": updated})
+}
@@ -0,0 +1,102 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-management-api/controller"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/domain/model"
+	"task-management-api/middleware"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTagRouter(mockUsecase *mocks.TagUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", "test_user_id")
+		c.Next()
+	})
+
+	tc := controller.NewTagController(mockUsecase)
+	router.GET("/tags", tc.GetTags)
+	router.PATCH("/tags/:name", tc.RenameTag)
+	router.DELETE("/tags/:name", tc.DeleteTag)
+	return router
+}
+
+func TestGetTags(t *testing.T) {
+	mockUsecase := mocks.NewTagUsecase(t)
+	tags := []*model.TagInfo{{Name: "home", Count: 1}, {Name: "work", Count: 2}}
+	mockUsecase.On("GetTags", mock.Anything, "test_user_id").Return(tags, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tags", nil)
+	w := httptest.NewRecorder()
+	newTagRouter(mockUsecase).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"tags": [{"name": "home", "count": 1}, {"name": "work", "count": 2}]}`, w.Body.String())
+}
+
+func TestRenameTag(t *testing.T) {
+	t.Run("Tag Renamed Successfully", func(t *testing.T) {
+		mockUsecase := mocks.NewTagUsecase(t)
+		mockUsecase.On("RenameTag", mock.Anything, "test_user_id", "work", "office").Return(int64(2), nil)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/tags/work", strings.NewReader(`{"name": "office"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newTagRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "Tag renamed successfully", "tasks_updated": 2}`, w.Body.String())
+	})
+
+	t.Run("Missing Name", func(t *testing.T) {
+		mockUsecase := mocks.NewTagUsecase(t)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/tags/work", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newTagRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var problem map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, "invalid request body", problem["detail"])
+	})
+
+	t.Run("Tag Not Found", func(t *testing.T) {
+		mockUsecase := mocks.NewTagUsecase(t)
+		mockUsecase.On("RenameTag", mock.Anything, "test_user_id", "missing", "office").Return(int64(0), entities.ErrTagNotFound)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/tags/missing", strings.NewReader(`{"name": "office"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newTagRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "tag not found", "instance": "/tags/missing"}`, w.Body.String())
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	mockUsecase := mocks.NewTagUsecase(t)
+	mockUsecase.On("DeleteTag", mock.Anything, "test_user_id", "two words").Return(int64(3), nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/tags/two%20words", nil)
+	w := httptest.NewRecorder()
+	newTagRouter(mockUsecase).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message": "Tag deleted successfully", "tasks_updated": 3}`, w.Body.String())
+}
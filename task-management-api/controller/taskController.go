@@ -1,9 +1,15 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"task-management-api/config"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,47 +27,197 @@ func NewTaskController(newEnvironment config.Environment,taskUsecase entities.Ta
 }
 
 func (tc *taskcontroller) GetTasks(c *gin.Context) {
-    userID , exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Please log in to acess tasks"})
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	userIDStr, ok := userID.(string)
-    if !ok {
-        c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal server error"})
+    query, err := taskQueryFromRequest(c)
+    if err != nil {
+        c.Error(err)
         return
     }
 
-    tasks, err := tc.TaskUsecase.GetTasks(userIDStr)
+    page, err := tc.TaskUsecase.GetTasks(c.Request.Context(), userID, query)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"message": "error retrieving tasks"})
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+    c.JSON(http.StatusOK, page)
 }
 
+// GetTrash lists the user's deleted tasks that have not been purged yet. It
+// accepts the same query parameters as GetTasks.
+func (tc *taskcontroller) GetTrash(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-func (tc *taskcontroller) GetTaskByID(c *gin.Context){
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Please log in to acess tasks"})
+	query, err := taskQueryFromRequest(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	id := c.Param("id")
+	page, err := tc.TaskUsecase.GetTrash(c.Request.Context(), userID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetAssignedTasks lists the tasks assigned to the user, whoever owns them. It
+// accepts the same query parameters as GetTasks.
+func (tc *taskcontroller) GetAssignedTasks(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	query, err := taskQueryFromRequest(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := tc.TaskUsecase.GetAssignedTasks(c.Request.Context(), userID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	task, err := tc.TaskUsecase.GetTaskByID(id, userID.(string))
+	c.JSON(http.StatusOK, page)
+}
+
+// taskQueryFromRequest reads the filters, sort order and page position of
+// GET /task/ from the query string.
+func taskQueryFromRequest(c *gin.Context) (model.TaskQuery, error) {
+	query := model.TaskQuery{
+		Status:   c.Query("status"),
+		Priority: c.Query("priority"),
+		TagMatch: c.Query("tag_match"),
+		Text:     c.Query("q"),
+		Sort:     c.Query("sort"),
+		Cursor:   c.Query("cursor"),
+	}
+
+	for _, tag := range c.QueryArray("tag") {
+		if tag != "" {
+			query.Tags = append(query.Tags, tag)
+		}
+	}
 
+	limit, err := limitFromRequest(c)
 	if err != nil {
+		return query, err
+	}
+	query.Limit = limit
+
+	if dueBefore := c.Query("due_before"); dueBefore != "" {
+		parsed, err := time.Parse(time.RFC3339, dueBefore)
+		if err != nil {
+			return query, apperrors.Validation("invalid due_before", apperrors.FieldError{Field: "due_before", Message: "must be an RFC 3339 timestamp"})
+		}
+		query.DueBefore = &parsed
+	}
 
-		if err.Error() == "mongo: no documents in result" {
-			c.JSON(http.StatusNotFound, gin.H{"message": "Task not found"})
-			return
+	if dueAfter := c.Query("due_after"); dueAfter != "" {
+		parsed, err := time.Parse(time.RFC3339, dueAfter)
+		if err != nil {
+			return query, apperrors.Validation("invalid due_after", apperrors.FieldError{Field: "due_after", Message: "must be an RFC 3339 timestamp"})
 		}
+		query.DueAfter = &parsed
+	}
+
+	return query, nil
+}
+
+
+// pageQueryFromRequest reads the page position of the comments or activity of
+// a task from the query string.
+func pageQueryFromRequest(c *gin.Context) (model.PageQuery, error) {
+	limit, err := limitFromRequest(c)
+	if err != nil {
+		return model.PageQuery{}, err
+	}
 
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "error retrieving task"})
+	return model.PageQuery{Cursor: c.Query("cursor"), Limit: limit}, nil
+}
+
+// limitFromRequest reads the page size from the query string. It is 0 when
+// the request leaves it to the default.
+func limitFromRequest(c *gin.Context) (int, error) {
+	limit := c.Query("limit")
+	if limit == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(limit)
+	if err != nil || parsed < 1 {
+		return 0, apperrors.Validation("invalid limit", apperrors.FieldError{Field: "limit", Message: "must be a positive integer"})
+	}
+
+	return parsed, nil
+}
+
+func (tc *taskcontroller) GetTaskActivity(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	query, err := pageQueryFromRequest(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := tc.TaskUsecase.GetTaskActivity(c.Request.Context(), c.Param("id"), userID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (tc *taskcontroller) GetTaskByID(c *gin.Context){
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	id := c.Param("id")
+
+	// The ETag only covers the task itself, so a tree, which also changes
+	// with its subtasks, is never answered with 304.
+	var task *model.TaskInfo
+	switch expand := c.Query("expand"); expand {
+	case "":
+		task, err = tc.TaskUsecase.GetTaskByID(c.Request.Context(), id, userID)
+	case "subtasks":
+		task, err = tc.TaskUsecase.GetTaskTree(c.Request.Context(), id, userID)
+	default:
+		err = apperrors.Validation("invalid expand", apperrors.FieldError{Field: "expand", Message: "must be subtasks"})
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	etag := taskETag(task.Version)
+	c.Header("ETag", etag)
+	if task.Progress == nil && etagListMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
@@ -69,90 +225,259 @@ func (tc *taskcontroller) GetTaskByID(c *gin.Context){
 }
 
 func (tc *taskcontroller) UpdateTask(c *gin.Context){
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Please log in to acess tasks"})
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	id := c.Param("id")
 
-	var updatedTask entities.Task
+	version, err := taskVersionFromIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	if err := c.BindJSON(&updatedTask); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Bad Request"})
+	var patch model.TaskPatch
+
+	if err := bindJSON(c, &patch); err != nil {
+		c.Error(err)
 		return
 	}
 
-    err := tc.TaskUsecase.UpdateTask(id, updatedTask, userID.(string))
-    if err != nil {
-        if err.Error() == "no documents updated" {
-            c.JSON(http.StatusNotFound, gin.H{"message": "Task not found"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal Server Error"})
-        return
+	task, err := tc.TaskUsecase.UpdateTask(c.Request.Context(), id, patch, userID, version)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Task updated successfully"})
+	c.Header("ETag", taskETag(task.Version))
+	c.JSON(http.StatusOK, gin.H{"message": "Task updated successfully", "task": task})
 }
 
 func (tc *taskcontroller) DeleteTask(c *gin.Context){
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Please log in to acess tasks"})
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	id := c.Param("id")
 
-	err := tc.TaskUsecase.DeleteTask(id, userID.(string))
+	version, err := taskVersionFromIfMatch(c.GetHeader("If-Match"))
 	if err != nil {
-		if err.Error() == "no documents deleted"{
-			c.JSON(http.StatusNotFound, gin.H{"message": "Task not found"})
-			return
-		}
+		c.Error(err)
+		return
+	}
 
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal Server Error"})
+	if err := tc.TaskUsecase.DeleteTask(c.Request.Context(), id, userID, version); err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
 }
-func (tc *taskcontroller) CreateTask(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists || userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "Please sign up to create a task"})
+
+func (tc *taskcontroller) RestoreTask(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	task, err := tc.TaskUsecase.RestoreTask(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	var newTask entities.Task
+	c.Header("ETag", taskETag(task.Version))
+	c.JSON(http.StatusOK, gin.H{"message": "Task restored successfully", "task": task})
+}
+func (tc *taskcontroller) SetTaskParent(c *gin.Context) {
+	tc.linkTask(c, "Subtask linked successfully", func(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+		return tc.TaskUsecase.SetTaskParent(ctx, id, c.Param("parentId"), userID)
+	})
+}
+
+func (tc *taskcontroller) RemoveTaskParent(c *gin.Context) {
+	tc.linkTask(c, "Subtask unlinked successfully", func(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+		return tc.TaskUsecase.SetTaskParent(ctx, id, "", userID)
+	})
+}
+
+func (tc *taskcontroller) AddTaskBlocker(c *gin.Context) {
+	tc.linkTask(c, "Blocker added successfully", func(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+		return tc.TaskUsecase.AddTaskBlocker(ctx, id, c.Param("blockerId"), userID)
+	})
+}
+
+func (tc *taskcontroller) RemoveTaskBlocker(c *gin.Context) {
+	tc.linkTask(c, "Blocker removed successfully", func(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+		return tc.TaskUsecase.RemoveTaskBlocker(ctx, id, c.Param("blockerId"), userID)
+	})
+}
+
+func (tc *taskcontroller) ShareTask(c *gin.Context) {
+	tc.linkTask(c, "Task shared successfully", func(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+		var request model.ShareRequest
+		if err := bindJSON(c, &request); err != nil {
+			return nil, err
+		}
+
+		return tc.TaskUsecase.ShareTask(ctx, id, c.Param("username"), request.Role, userID)
+	})
+}
+
+func (tc *taskcontroller) UnshareTask(c *gin.Context) {
+	tc.linkTask(c, "Task unshared successfully", func(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+		return tc.TaskUsecase.UnshareTask(ctx, id, c.Param("username"), userID)
+	})
+}
+
+func (tc *taskcontroller) SetTaskAssignee(c *gin.Context) {
+	tc.linkTask(c, "Task assigned successfully", func(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+		return tc.TaskUsecase.SetTaskAssignee(ctx, id, c.Param("username"), userID)
+	})
+}
+
+func (tc *taskcontroller) RemoveTaskAssignee(c *gin.Context) {
+	tc.linkTask(c, "Task unassigned successfully", func(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+		return tc.TaskUsecase.SetTaskAssignee(ctx, id, "", userID)
+	})
+}
+
+// linkTask runs a change to the links or sharing of the task in the path and
+// answers with the changed task and its new ETag.
+func (tc *taskcontroller) linkTask(c *gin.Context, message string, link func(ctx context.Context, id string, userID string) (*model.TaskInfo, error)) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	if err := c.BindJSON(&newTask); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Bad Request"})
+	task, err := link(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	newTask.UserID = userID.(string)
+	c.Header("ETag", taskETag(task.Version))
+	c.JSON(http.StatusOK, gin.H{"message": message, "task": task})
+}
 
-	err := tc.TaskUsecase.CreateTask(newTask)
+// GetTaskSeries lists the occurrences of a recurring task, oldest first.
+func (tc *taskcontroller) GetTaskSeries(c *gin.Context) {
+	userID, err := taskUserID(c)
 	if err != nil {
-		switch err.Error() {
-		case "Please sign up to create a task":
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "Please sign up to create a task"})
-			return
-		case "Bad Request":
-			c.JSON(http.StatusBadRequest, gin.H{"message": "Bad Request"})
-			return
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal Server Error"})
-		}
+		c.Error(err)
+		return
+	}
+
+	tasks, err := tc.TaskUsecase.GetTaskSeries(c.Request.Context(), c.Param("seriesId"), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
+
+// UpdateTaskSeries applies a merge patch to the open occurrences of a
+// recurring task.
+func (tc *taskcontroller) UpdateTaskSeries(c *gin.Context) {
+	var patch model.SeriesPatch
+	if err := bindJSON(c, &patch); err != nil {
+		c.Error(err)
+		return
+	}
+
+	tc.changeTaskSeries(c, "Series updated successfully", patch)
+}
+
+// StopTaskSeries removes the recurrence rule from the open occurrences of a
+// recurring task, so that no further occurrence is created.
+func (tc *taskcontroller) StopTaskSeries(c *gin.Context) {
+	tc.changeTaskSeries(c, "Series stopped successfully", model.SeriesPatch{
+		Recurrence: model.Patch[string]{Set: true, Null: true},
+	})
+}
+
+func (tc *taskcontroller) changeTaskSeries(c *gin.Context, message string, patch model.SeriesPatch) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	tasks, err := tc.TaskUsecase.UpdateTaskSeries(c.Request.Context(), c.Param("seriesId"), patch, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "tasks": tasks})
+}
+
+func (tc *taskcontroller) CreateTask(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var request model.TaskCreate
+
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := tc.TaskUsecase.CreateTask(c.Request.Context(), taskFromCreate(request, userID)); err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"message": "Task created successfully"})
 }
 
+// taskFromCreate builds the task stored for a validated POST /task/ body.
+func taskFromCreate(request model.TaskCreate, userID string) entities.Task {
+	// The priority name was checked by the binding rules.
+	priority, _ := entities.ParsePriority(request.Priority)
+
+	task := entities.Task{
+		UserID:      userID,
+		Title:       request.Title,
+		Description: request.Description,
+		Status:      request.Status,
+		Priority:    priority,
+		Tags:        request.Tags,
+		Recurrence:  request.Recurrence,
+		Reminders:   request.Reminders,
+	}
+	if request.DueDate != nil {
+		task.DueDate = *request.DueDate
+	}
+
+	return task
+}
+
+// taskUserID returns the id AuthMiddleware stored for the authenticated user.
+func taskUserID(c *gin.Context) (string, error) {
+	userID, exists := c.Get("user_id")
+	if !exists || userID == "" {
+		return "", apperrors.Unauthorized("Please log in to access tasks")
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		return "", errors.New("user_id is not a string")
+	}
+
+	return userIDStr, nil
+}
 
 func (tc *taskcontroller) GetEnvironment(c *gin.Context){
 	c.JSON(http.StatusOK, gin.H{"environment": tc.newEnvironment})
@@ -9,10 +9,13 @@ import (
 	"net/http/httptest"
 	"strings"
 	"task-management-api/controller"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/mocks"
 	"task-management-api/domain/model"
+	"task-management-api/middleware"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -28,13 +31,15 @@ func TestCreateTask(t *testing.T) {
 	
 		router := gin.Default()
 	
+		router.Use(middleware.ErrorHandler())
+	
 		tc := controller.NewTaskController(mockEnvironment, mockUsecase)
 	
 		router.POST("/tasks", tc.CreateTask)
 
-		mockUsecase.On("CreateTask", mock.AnythingOfType("entities.Task")).Return(nil).Once()
+		mockUsecase.On("CreateTask", mock.Anything, mock.AnythingOfType("entities.Task")).Return(nil).Once()
 
-		newTask := entities.Task{
+		newTask := model.TaskCreate{
 			Title:       "Test Task",
 			Description: "This is a test task",
 		}
@@ -54,101 +59,113 @@ func TestCreateTask(t *testing.T) {
 		assert.Equal(t, http.StatusCreated, w.Code)
 		assert.JSONEq(t, `{"message": "Task created successfully"}`, w.Body.String())
 
-		mockUsecase.AssertCalled(t, "CreateTask", mock.MatchedBy(func(task entities.Task) bool {
+		mockUsecase.AssertCalled(t, "CreateTask", mock.Anything, mock.MatchedBy(func(task entities.Task) bool {
 			return task.Title == newTask.Title && task.Description == newTask.Description && task.UserID == "test_user_id"
 		}))
 	})
+	t.Run("with reminders", func(t *testing.T) {
+		mockUsecase := new(mocks.TaskUsecase)
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", "test_user_id")
+			c.Next()
+		})
+		tc := controller.NewTaskController(new(mocks.Environment), mockUsecase)
+		router.POST("/tasks", tc.CreateTask)
+
+		mockUsecase.On("CreateTask", mock.Anything, mock.MatchedBy(func(task entities.Task) bool {
+			return strings.Join(task.Reminders, ",") == "P1D,PT30M"
+		})).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"title": "Test Task", "reminders": ["P1D", "PT30M"]}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
 	t.Run("error", func(t *testing.T) {
-		t.Run("unauthorized", func(t *testing.T) {
-			mockUsecase := new(mocks.TaskUsecase)
-			mockEnvironment := new(mocks.Environment)
-		
+		newRouter := func(mockUsecase *mocks.TaskUsecase, userID string) *gin.Engine {
 			router := gin.Default()
-		
-			tc := controller.NewTaskController(mockEnvironment, mockUsecase)
-		
+			router.Use(middleware.ErrorHandler())
+			router.Use(func(c *gin.Context) {
+				if userID != "" {
+					c.Set("user_id", userID)
+				}
+				c.Next()
+			})
+
+			tc := controller.NewTaskController(new(mocks.Environment), mockUsecase)
 			router.POST("/tasks", tc.CreateTask)
+			return router
+		}
 
-			newTask := entities.Task{
-				Title:       "Test Task",
-				Description: "This is a test task",
-			}
-			
-			mockUsecase.On("CreateTask", mock.AnythingOfType("entities.Task")).Return(errors.New("Please sign up to create a task")).Once()
+		newTask := model.TaskCreate{
+			Title:       "Test Task",
+			Description: "This is a test task",
+		}
+		taskJSON, _ := json.Marshal(newTask)
 
-			taskJSON, _ := json.Marshal(newTask)
+		t.Run("unauthorized", func(t *testing.T) {
+			mockUsecase := new(mocks.TaskUsecase)
+			router := newRouter(mockUsecase, "")
 
 			req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(taskJSON))
 			req.Header.Set("Content-Type", "application/json")
 			w := httptest.NewRecorder()
-			c, _ := gin.CreateTestContext(w)
-			c.Request = req
-
-			tc.CreateTask(c)
+			router.ServeHTTP(w, req)
 
 			assert.Equal(t, http.StatusUnauthorized, w.Code)
-			assert.JSONEq(t, `{"message": "Please sign up to create a task"}`, w.Body.String())
+			assert.JSONEq(t, `{"type": "about:blank", "title": "Unauthorized", "status": 401, "detail": "Please log in to access tasks", "instance": "/tasks"}`, w.Body.String())
+			mockUsecase.AssertNotCalled(t, "CreateTask", mock.Anything)
 		})
 
 		t.Run("bad_request", func(t *testing.T) {
 			mockUsecase := new(mocks.TaskUsecase)
-			mockEnvironment := new(mocks.Environment)
-		
-			router := gin.Default()
-		
-			tc := controller.NewTaskController(mockEnvironment, mockUsecase)
-		
-			router.POST("/tasks", tc.CreateTask)
+			router := newRouter(mockUsecase, "test_user_id")
 
-			mockUsecase.On("CreateTask", mock.AnythingOfType("entities.Task")).Return(errors.New("Bad Request")).Once()
+			req, _ := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"title": 1}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
 
-			invalidTaskJSON := `{"ttle": ""}`
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+			assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid request body", "instance": "/tasks",
+				"errors": [{"field": "title", "message": "must be of type string"}]}`, w.Body.String())
+			mockUsecase.AssertNotCalled(t, "CreateTask", mock.Anything)
+		})
 
-			req, _ := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(invalidTaskJSON))
-			req.Header.Set("Content-Type", "application/json")
+		t.Run("validation_error", func(t *testing.T) {
+			mockUsecase := new(mocks.TaskUsecase)
+			router := newRouter(mockUsecase, "test_user_id")
 
-			w := httptest.NewRecorder()
-			c, _ := gin.CreateTestContext(w)
-			c.Request = req
-			c.Set("user_id", "test_user_id")
+			mockUsecase.On("CreateTask", mock.Anything, mock.AnythingOfType("entities.Task")).Return(entities.ErrInvalidStatus).Once()
 
-			tc.CreateTask(c)
+			req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(taskJSON))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
 
 			assert.Equal(t, http.StatusBadRequest, w.Code)
-			assert.JSONEq(t, `{"message": "Bad Request"}`, w.Body.String())
+			assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid status", "instance": "/tasks",
+				"errors": [{"field": "status", "message": "must be one of todo, in_progress, done"}]}`, w.Body.String())
 		})
 
 		t.Run("internal_server_error", func(t *testing.T) {
 			mockUsecase := new(mocks.TaskUsecase)
-			mockEnvironment := new(mocks.Environment)
-		
-			router := gin.Default()
-		
-			tc := controller.NewTaskController(mockEnvironment, mockUsecase)
-		
-			router.POST("/tasks", tc.CreateTask)
-
-			newTask := entities.Task{
-				Title:       "Test Task",
-				Description: "This is a test task",
-			}
-
-			mockUsecase.On("CreateTask", mock.AnythingOfType("entities.Task")).Return(errors.New("Internal Server Error")).Once()
+			router := newRouter(mockUsecase, "test_user_id")
 
-			taskJSON, _ := json.Marshal(newTask)
+			mockUsecase.On("CreateTask", mock.Anything, mock.AnythingOfType("entities.Task")).Return(errors.New("connection refused")).Once()
 
 			req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(taskJSON))
 			req.Header.Set("Content-Type", "application/json")
-
 			w := httptest.NewRecorder()
-			c, _ := gin.CreateTestContext(w)
-			c.Request = req
-			c.Set("user_id", "test_user_id")
+			router.ServeHTTP(w, req)
 
-			tc.CreateTask(c)
-			
 			assert.Equal(t, http.StatusInternalServerError, w.Code)
-			assert.JSONEq(t, `{"message": "Internal Server Error"}`, w.Body.String())
+			assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/tasks"}`, w.Body.String())
 		})
 	})
 }
@@ -160,6 +177,7 @@ func TestGetTasks(t *testing.T) {
 		
 		gin.SetMode(gin.TestMode)
 		router := gin.Default()
+		router.Use(middleware.ErrorHandler())
 	
 		tc := controller.NewTaskController(mockEnvironment, mockUsecase)
 	
@@ -169,7 +187,7 @@ func TestGetTasks(t *testing.T) {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.JSONEq(t, `{"error": "Please log in to acess tasks"}`, w.Body.String())
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Unauthorized", "status": 401, "detail": "Please log in to access tasks", "instance": "/tasks"}`, w.Body.String())
 	})
 
 	t.Run("internal server error - GetTasks failure", func(t *testing.T) {
@@ -178,12 +196,13 @@ func TestGetTasks(t *testing.T) {
 	
 		gin.SetMode(gin.TestMode)
 		router := gin.Default()
+		router.Use(middleware.ErrorHandler())
 
 		testUserID := "123"
 	
 		tc := controller.NewTaskController(mockEnvironment, mockUsecase)
 
-		mockUsecase.On("GetTasks", testUserID).Return(nil, errors.New("error retrieving tasks"))
+		mockUsecase.On("GetTasks", mock.Anything, testUserID, model.TaskQuery{}).Return(nil, errors.New("error retrieving tasks"))
 
 		req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
 		w := httptest.NewRecorder()
@@ -200,7 +219,7 @@ func TestGetTasks(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.JSONEq(t, `{"message": "error retrieving tasks"}`, w.Body.String())
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/tasks"}`, w.Body.String())
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -209,6 +228,7 @@ func TestGetTasks(t *testing.T) {
 
 		gin.SetMode(gin.TestMode)
 		router := gin.Default()
+		router.Use(middleware.ErrorHandler())
 		
 		router.Use(func(c *gin.Context) {
 			c.Set("user_id", "test_user_id")
@@ -219,12 +239,15 @@ func TestGetTasks(t *testing.T) {
 	
 		router.GET("/tasks", tc.GetTasks)
 
+		today := time.Now().UTC().Truncate(time.Second)
+		tomorrow := today.Add(24 * time.Hour)
+
 		mockTasks := []*model.TaskInfo{
-			{Title: "Task 1", Description: "Description 1", DueDate: "Today"},
-			{Title: "Task 2", Description: "Description 2", DueDate: "Tomorrow"},
+			{ID: primitive.NewObjectID().Hex(), Title: "Task 1", Description: "Description 1", Status: "todo", Priority: "medium", DueDate: &today},
+			{ID: primitive.NewObjectID().Hex(), Title: "Task 2", Description: "Description 2", Status: "done", Priority: "high", DueDate: &tomorrow},
 		}
 		
-		mockUsecase.On("GetTasks", "test_user_id").Return(mockTasks, nil)
+		mockUsecase.On("GetTasks", mock.Anything, "test_user_id", model.TaskQuery{}).Return(&model.TaskPage{Tasks: mockTasks, Next: "next-token"}, nil)
 
 		req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
 		w := httptest.NewRecorder()
@@ -234,11 +257,104 @@ func TestGetTasks(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		expectedResponse, _ := json.Marshal(gin.H{"tasks": mockTasks})
+		expectedResponse, _ := json.Marshal(gin.H{"tasks": mockTasks, "next": "next-token"})
 		log.Println("Response: ", w.Body.String())
 		log.Println("Expected Response: ", string(expectedResponse))
 		assert.JSONEq(t, string(expectedResponse), w.Body.String())
 	})
+
+	t.Run("query parameters", func(t *testing.T) {
+		mockUsecase := new(mocks.TaskUsecase)
+		mockEnvironment := new(mocks.Environment)
+
+		gin.SetMode(gin.TestMode)
+		router := gin.Default()
+		router.Use(middleware.ErrorHandler())
+
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", "test_user_id")
+			c.Next()
+		})
+
+		tc := controller.NewTaskController(mockEnvironment, mockUsecase)
+		router.GET("/tasks", tc.GetTasks)
+
+		dueAfter := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+		dueBefore := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		expectedQuery := model.TaskQuery{
+			Status:    "todo",
+			Priority:  "high",
+			Tags:      []string{"work", "home"},
+			TagMatch:  "all",
+			DueBefore: &dueBefore,
+			DueAfter:  &dueAfter,
+			Text:      "report",
+			Sort:      "-due_date",
+			Cursor:    "abc",
+			Limit:     10,
+		}
+
+		mockUsecase.On("GetTasks", mock.Anything, "test_user_id", expectedQuery).Return(&model.TaskPage{Tasks: []*model.TaskInfo{}}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/tasks?status=todo&priority=high&tag=work&tag=home&tag_match=all&due_before=2024-10-01T00:00:00Z&due_after=2024-09-01T00:00:00Z&q=report&sort=-due_date&cursor=abc&limit=10", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"tasks": []}`, w.Body.String())
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid query parameters", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			url      string
+			usecase  error
+			field    string
+			detail   string
+		}{
+			{name: "limit", url: "/tasks?limit=abc", field: "limit", detail: "invalid limit"},
+			{name: "due_before", url: "/tasks?due_before=tomorrow", field: "due_before", detail: "invalid due_before"},
+			{name: "due_after", url: "/tasks?due_after=2024-13-01", field: "due_after", detail: "invalid due_after"},
+			{name: "sort", url: "/tasks?sort=title", usecase: entities.ErrInvalidTaskSort, field: "sort", detail: "invalid sort"},
+			{name: "cursor", url: "/tasks?cursor=abc", usecase: entities.ErrInvalidTaskCursor, field: "cursor", detail: "invalid cursor"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockUsecase := new(mocks.TaskUsecase)
+				mockEnvironment := new(mocks.Environment)
+
+				router := gin.Default()
+
+				router.Use(middleware.ErrorHandler())
+				router.Use(func(c *gin.Context) {
+					c.Set("user_id", "test_user_id")
+					c.Next()
+				})
+
+				tc := controller.NewTaskController(mockEnvironment, mockUsecase)
+				router.GET("/tasks", tc.GetTasks)
+
+				if tt.usecase != nil {
+					mockUsecase.On("GetTasks", mock.Anything, "test_user_id", mock.AnythingOfType("model.TaskQuery")).Return(nil, tt.usecase)
+				}
+
+				req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				var problem middleware.Problem
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, tt.detail, problem.Detail)
+				assert.Len(t, problem.Errors, 1)
+				assert.Equal(t, tt.field, problem.Errors[0].Field)
+				mockUsecase.AssertExpectations(t)
+			})
+		}
+	})
 }
 
 func TestGetTaskByID(t *testing.T) {
@@ -250,13 +366,15 @@ func TestGetTaskByID(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
 
         router := gin.Default() 
+
+        router.Use(middleware.ErrorHandler())
         router.GET("/tasks/:id", tc.GetTaskByID)
 
         req, _ := http.NewRequest(http.MethodGet, "/tasks/1", nil)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusUnauthorized, w.Code)
-        assert.JSONEq(t, `{"error": "Please log in to acess tasks"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Unauthorized", "status": 401, "detail": "Please log in to access tasks", "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("task not found", func(t *testing.T) {
@@ -265,19 +383,21 @@ func TestGetTaskByID(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
 
         router := gin.Default() // Create a new router for each sub-test
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", testUserID)
             c.Next()
         })
         router.GET("/tasks/:id", tc.GetTaskByID)
 
-        mockUsecase.On("GetTaskByID", "1", "test_user_id").Return(nil, errors.New("mongo: no documents in result"))
+        mockUsecase.On("GetTaskByID", mock.Anything, "1", "test_user_id").Return(nil, apperrors.NotFound("task not found"))
 
         req, _ := http.NewRequest(http.MethodGet, "/tasks/1", nil)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusNotFound, w.Code)
-        assert.JSONEq(t, `{"message": "Task not found"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "task not found", "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("internal server error", func(t *testing.T) {
@@ -286,18 +406,20 @@ func TestGetTaskByID(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
 
         router := gin.Default() // Create a new router for each sub-test
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", testUserID)
             c.Next()
         })
         router.GET("/tasks/:id", tc.GetTaskByID)
 
-        mockUsecase.On("GetTaskByID", "1", "test_user_id").Return(nil, errors.New("some error"))
+        mockUsecase.On("GetTaskByID", mock.Anything, "1", "test_user_id").Return(nil, errors.New("some error"))
         req, _ := http.NewRequest(http.MethodGet, "/tasks/1", nil)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusInternalServerError, w.Code)
-        assert.JSONEq(t, `{"message": "error retrieving task"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("success", func(t *testing.T) {
@@ -306,6 +428,8 @@ func TestGetTaskByID(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
 
         router := gin.Default() // Create a new router for each sub-test
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", testUserID)
             c.Next()
@@ -315,16 +439,48 @@ func TestGetTaskByID(t *testing.T) {
 
         taskID := primitive.NewObjectID()
         mockTask := &model.TaskInfo{Title: "Task 1", Description: "Description 1"}
-        mockUsecase.On("GetTaskByID", taskID.Hex(), "test_user_id").Return(mockTask, nil)
+        mockUsecase.On("GetTaskByID", mock.Anything, taskID.Hex(), "test_user_id").Return(mockTask, nil)
 
         req, _ := http.NewRequest(http.MethodGet, "/tasks/"+taskID.Hex(), nil)
         w := httptest.NewRecorder()
 
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusOK, w.Code)
+        assert.Equal(t, `"0"`, w.Header().Get("ETag"))
         expectedResponse, _ := json.Marshal(gin.H{"task": mockTask})
         assert.JSONEq(t, string(expectedResponse), w.Body.String())
     })
+
+    t.Run("not modified", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        router := gin.New()
+        router.Use(middleware.ErrorHandler())
+        router.Use(func(c *gin.Context) {
+            c.Set("user_id", testUserID)
+            c.Next()
+        })
+        router.GET("/tasks/:id", controller.NewTaskController(new(mocks.Environment), mockUsecase).GetTaskByID)
+
+        mockUsecase.On("GetTaskByID", mock.Anything, "1", "test_user_id").Return(&model.TaskInfo{ID: "1", Version: 3}, nil)
+
+        for header, status := range map[string]int{
+            `"3"`:        http.StatusNotModified,
+            `"2", W/"3"`: http.StatusNotModified,
+            "*":          http.StatusNotModified,
+            `"2"`:        http.StatusOK,
+        } {
+            req, _ := http.NewRequest(http.MethodGet, "/tasks/1", nil)
+            req.Header.Set("If-None-Match", header)
+            w := httptest.NewRecorder()
+            router.ServeHTTP(w, req)
+
+            assert.Equal(t, status, w.Code, header)
+            assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+            if status == http.StatusNotModified {
+                assert.Empty(t, w.Body.String())
+            }
+        }
+    })
 }
 func TestUpdateTask(t *testing.T) {
 
@@ -335,6 +491,8 @@ func TestUpdateTask(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
         router.PUT("/tasks/:id", tc.UpdateTask)
 
@@ -343,7 +501,7 @@ func TestUpdateTask(t *testing.T) {
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusUnauthorized, w.Code)
-        assert.JSONEq(t, `{"error": "Please log in to acess tasks"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Unauthorized", "status": 401, "detail": "Please log in to access tasks", "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("Bad Request", func(t *testing.T) {
@@ -351,6 +509,8 @@ func TestUpdateTask(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", "test_user_id")
             c.Next()
@@ -361,11 +521,12 @@ func TestUpdateTask(t *testing.T) {
 
         req, _ := http.NewRequest(http.MethodPut, "/tasks/1", bytes.NewBuffer([]byte("invalid json")))
         req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("If-Match", `"1"`)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusBadRequest, w.Code)
-        assert.JSONEq(t, `{"message": "Bad Request"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "request body is not valid JSON", "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("Task Not Found", func(t *testing.T) {
@@ -373,6 +534,8 @@ func TestUpdateTask(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", "test_user_id")
             c.Next()
@@ -381,27 +544,17 @@ func TestUpdateTask(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
         router.PUT("/tasks/:id", tc.UpdateTask)
 
-        mockTaskID := primitive.NewObjectID()
-        mockTask := entities.Task{
-            ID:          mockTaskID,
-            UserID:      "test_user_id",
-            UserName:    "test_user",
-            Password:    "test_password",
-            Title:       "Test Task",
-            Description: "This is a test task",
-            Status:      "Pending",
-        }
-
-        taskJSON, _ := json.Marshal(mockTask)
-        mockUsecase.On("UpdateTask", "1", mock.AnythingOfType("entities.Task"), "test_user_id").Return(errors.New("no documents updated"))
+        taskJSON := []byte(`{"title": "Test Task", "description": "This is a test task", "status": "in_progress"}`)
+        mockUsecase.On("UpdateTask", mock.Anything, "1", mock.AnythingOfType("model.TaskPatch"), "test_user_id", int64(1)).Return(nil, apperrors.NotFound("task not found"))
 
         req, _ := http.NewRequest(http.MethodPut, "/tasks/1", bytes.NewBuffer(taskJSON))
         req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("If-Match", `"1"`)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusNotFound, w.Code)
-        assert.JSONEq(t, `{"message": "Task not found"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "task not found", "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("Internal Server Error", func(t *testing.T) {
@@ -409,6 +562,8 @@ func TestUpdateTask(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", "test_user_id")
             c.Next()
@@ -417,34 +572,26 @@ func TestUpdateTask(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
         router.PUT("/tasks/:id", tc.UpdateTask)
 
-        mockTaskID := primitive.NewObjectID()
-        mockTask := entities.Task{
-            ID:          mockTaskID,
-            UserID:      "test_user_id",
-            UserName:    "test_user",
-            Password:    "test_password",
-            Title:       "Test Task",
-            Description: "This is a test task",
-            Status:      "Pending",
-        }
-
-        taskJSON, _ := json.Marshal(mockTask)
-        mockUsecase.On("UpdateTask", "1", mock.AnythingOfType("entities.Task"), "test_user_id").Return(errors.New("some internal error"))
+        taskJSON := []byte(`{"title": "Test Task", "description": "This is a test task", "status": "in_progress"}`)
+        mockUsecase.On("UpdateTask", mock.Anything, "1", mock.AnythingOfType("model.TaskPatch"), "test_user_id", int64(1)).Return(nil, errors.New("some internal error"))
 
         req, _ := http.NewRequest(http.MethodPut, "/tasks/1", bytes.NewBuffer(taskJSON))
         req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("If-Match", `"1"`)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusInternalServerError, w.Code)
-        assert.JSONEq(t, `{"message": "Internal Server Error"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/tasks/1"}`, w.Body.String())
     })
 
-    t.Run("Task Updated Successfully", func(t *testing.T) {
+    t.Run("Invalid Status Transition", func(t *testing.T) {
         mockUsecase := new(mocks.TaskUsecase)
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", "test_user_id")
             c.Next()
@@ -453,27 +600,160 @@ func TestUpdateTask(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
         router.PUT("/tasks/:id", tc.UpdateTask)
 
-        mockTaskID := primitive.NewObjectID()
-        mockTask := entities.Task{
-            ID:          mockTaskID,
-            UserID:      "test_user_id",
-            UserName:    "test_user",
-            Password:    "test_password",
-            Title:       "Test Task",
-            Description: "This is a test task",
-            Status:      "Pending",
-        }
+        mockUsecase.On("UpdateTask", mock.Anything, "1", mock.AnythingOfType("model.TaskPatch"), "test_user_id", int64(1)).Return(nil, entities.ErrInvalidStatusTransition)
+
+        req, _ := http.NewRequest(http.MethodPut, "/tasks/1", strings.NewReader(`{"status": "done"}`))
+        req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("If-Match", `"1"`)
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusConflict, w.Code)
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Conflict", "status": 409, "detail": "invalid status transition", "instance": "/tasks/1"}`, w.Body.String())
+    })
+
+    t.Run("Task Updated Successfully", func(t *testing.T) {
+        mockUsecase := new(mocks.TaskUsecase)
+        mockEnvironment := new(mocks.Environment)
+
+        router := gin.Default()
 
-        taskJSON, _ := json.Marshal(mockTask)
-        mockUsecase.On("UpdateTask", "1", mock.AnythingOfType("entities.Task"), "test_user_id").Return(nil)
+        router.Use(middleware.ErrorHandler())
+        router.Use(func(c *gin.Context) {
+            c.Set("user_id", "test_user_id")
+            c.Next()
+        })
+
+        tc := controller.NewTaskController(mockEnvironment, mockUsecase)
+        router.PUT("/tasks/:id", tc.UpdateTask)
+
+        taskJSON := []byte(`{"title": "Test Task", "description": "This is a test task", "status": "in_progress"}`)
+        updated := &model.TaskInfo{ID: "1", Title: "Test Task", Status: "in_progress", Version: 2}
+        mockUsecase.On("UpdateTask", mock.Anything, "1", mock.MatchedBy(func(patch model.TaskPatch) bool {
+            return patch.Title.Value == "Test Task" && !patch.Priority.Set
+        }), "test_user_id", int64(1)).Return(updated, nil)
 
         req, _ := http.NewRequest(http.MethodPut, "/tasks/1", bytes.NewBuffer(taskJSON))
         req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("If-Match", `"1"`)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusOK, w.Code)
-        assert.JSONEq(t, `{"message": "Task updated successfully"}`, w.Body.String())
+        assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+        expectedResponse, _ := json.Marshal(gin.H{"message": "Task updated successfully", "task": updated})
+        assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    })
+
+    t.Run("Merge Patch", func(t *testing.T) {
+        tests := []struct {
+            name        string
+            contentType string
+            body        string
+            matches     func(patch model.TaskPatch) bool
+            status      int
+            detail      string
+        }{
+            {
+                name:        "null clears a member",
+                contentType: model.MergePatchContentType,
+                body:        `{"description": null, "due_date": null}`,
+                matches: func(patch model.TaskPatch) bool {
+                    return patch.Description.Null && patch.DueDate.Null && !patch.Title.Set
+                },
+                status: http.StatusOK,
+            },
+            {
+                name:        "unknown member",
+                contentType: model.MergePatchContentType,
+                body:        `{"userid": "someone-else"}`,
+                status:      http.StatusBadRequest,
+                detail:      `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid request body", "instance": "/tasks/1", "errors": [{"field": "userid", "message": "is not allowed"}]}`,
+            },
+            {
+                name:        "invalid value",
+                contentType: "application/json",
+                body:        `{"priority": "urgent"}`,
+                status:      http.StatusBadRequest,
+                detail:      `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid request body", "instance": "/tasks/1", "errors": [{"field": "priority", "message": "must be one of low, medium, high"}]}`,
+            },
+        }
+
+        for _, tt := range tests {
+            t.Run(tt.name, func(t *testing.T) {
+                mockUsecase := mocks.NewTaskUsecase(t)
+                router := gin.New()
+                router.Use(middleware.ErrorHandler())
+                router.Use(func(c *gin.Context) {
+                    c.Set("user_id", "test_user_id")
+                    c.Next()
+                })
+                router.PATCH("/tasks/:id", controller.NewTaskController(new(mocks.Environment), mockUsecase).UpdateTask)
+
+                if tt.matches != nil {
+                    mockUsecase.On("UpdateTask", mock.Anything, "1", mock.MatchedBy(tt.matches), "test_user_id", int64(1)).
+                        Return(&model.TaskInfo{ID: "1", Version: 2}, nil)
+                }
+
+                req, _ := http.NewRequest(http.MethodPatch, "/tasks/1", strings.NewReader(tt.body))
+                req.Header.Set("Content-Type", tt.contentType)
+                req.Header.Set("If-Match", `"1"`)
+                w := httptest.NewRecorder()
+                router.ServeHTTP(w, req)
+
+                assert.Equal(t, tt.status, w.Code)
+                if tt.detail != "" {
+                    assert.JSONEq(t, tt.detail, w.Body.String())
+                }
+            })
+        }
+    })
+
+    t.Run("Preconditions", func(t *testing.T) {
+        tests := []struct {
+            name     string
+            ifMatch  string
+            version  int64
+            err      error
+            status   int
+        }{
+            {name: "missing If-Match", status: http.StatusPreconditionRequired},
+            {name: "weak ETag", ifMatch: `W/"1"`, status: http.StatusPreconditionFailed},
+            {name: "malformed ETag", ifMatch: `"abc"`, status: http.StatusPreconditionFailed},
+            {name: "stale version", ifMatch: `"1"`, version: 1, err: entities.ErrTaskModified, status: http.StatusPreconditionFailed},
+            {name: "any version", ifMatch: "*", version: entities.AnyTaskVersion, status: http.StatusOK},
+        }
+
+        for _, tt := range tests {
+            t.Run(tt.name, func(t *testing.T) {
+                mockUsecase := mocks.NewTaskUsecase(t)
+                router := gin.New()
+                router.Use(middleware.ErrorHandler())
+                router.Use(func(c *gin.Context) {
+                    c.Set("user_id", "test_user_id")
+                    c.Next()
+                })
+                router.PATCH("/tasks/:id", controller.NewTaskController(new(mocks.Environment), mockUsecase).UpdateTask)
+
+                if tt.version != 0 {
+                    var task *model.TaskInfo
+                    if tt.err == nil {
+                        task = &model.TaskInfo{ID: "1", Version: 8}
+                    }
+                    mockUsecase.On("UpdateTask", mock.Anything, "1", mock.AnythingOfType("model.TaskPatch"), "test_user_id", tt.version).Return(task, tt.err)
+                }
+
+                req, _ := http.NewRequest(http.MethodPatch, "/tasks/1", strings.NewReader(`{"title": "Renamed"}`))
+                req.Header.Set("Content-Type", "application/json")
+                if tt.ifMatch != "" {
+                    req.Header.Set("If-Match", tt.ifMatch)
+                }
+                w := httptest.NewRecorder()
+                router.ServeHTTP(w, req)
+
+                assert.Equal(t, tt.status, w.Code)
+            })
+        }
     })
 }
 
@@ -485,15 +765,18 @@ func TestDeleteTask(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
         router.DELETE("/tasks/:id", tc.DeleteTask)
 
         req, _ := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+        req.Header.Set("If-Match", `"1"`)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
-        assert.Equal(t, http.StatusNotFound, w.Code)
-        assert.JSONEq(t, `{"error": "Please log in to acess tasks"}`, w.Body.String())
+        assert.Equal(t, http.StatusUnauthorized, w.Code)
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Unauthorized", "status": 401, "detail": "Please log in to access tasks", "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("Task Not Found", func(t *testing.T) {
@@ -501,6 +784,8 @@ func TestDeleteTask(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", "test_user_id")
             c.Next()
@@ -509,14 +794,15 @@ func TestDeleteTask(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
         router.DELETE("/tasks/:id", tc.DeleteTask)
 
-        mockUsecase.On("DeleteTask", "1", "test_user_id").Return(errors.New("no documents deleted"))
+        mockUsecase.On("DeleteTask", mock.Anything, "1", "test_user_id", int64(1)).Return(apperrors.NotFound("task not found"))
 
         req, _ := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+        req.Header.Set("If-Match", `"1"`)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusNotFound, w.Code)
-        assert.JSONEq(t, `{"message": "Task not found"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "task not found", "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("Internal Server Error", func(t *testing.T) {
@@ -524,6 +810,8 @@ func TestDeleteTask(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", "test_user_id")
             c.Next()
@@ -532,14 +820,15 @@ func TestDeleteTask(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
         router.DELETE("/tasks/:id", tc.DeleteTask)
 
-        mockUsecase.On("DeleteTask", "1", "test_user_id").Return(errors.New("some internal error"))
+        mockUsecase.On("DeleteTask", mock.Anything, "1", "test_user_id", int64(1)).Return(errors.New("some internal error"))
 
         req, _ := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+        req.Header.Set("If-Match", `"1"`)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusInternalServerError, w.Code)
-        assert.JSONEq(t, `{"message": "Internal Server Error"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/tasks/1"}`, w.Body.String())
     })
 
     t.Run("Task Deleted Successfully", func(t *testing.T) {
@@ -547,6 +836,8 @@ func TestDeleteTask(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         router.Use(func(c *gin.Context) {
             c.Set("user_id", "test_user_id")
             c.Next()
@@ -555,13 +846,527 @@ func TestDeleteTask(t *testing.T) {
         tc := controller.NewTaskController(mockEnvironment, mockUsecase)
         router.DELETE("/tasks/:id", tc.DeleteTask)
 
-        mockUsecase.On("DeleteTask", "1", "test_user_id").Return(nil)
+        mockUsecase.On("DeleteTask", mock.Anything, "1", "test_user_id", int64(1)).Return(nil)
 
         req, _ := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+        req.Header.Set("If-Match", `"1"`)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusOK, w.Code)
         assert.JSONEq(t, `{"message": "Task deleted successfully"}`, w.Body.String())
     })
-}
\ No newline at end of file
+
+    t.Run("If-Match is required", func(t *testing.T) {
+        router := gin.New()
+        router.Use(middleware.ErrorHandler())
+        router.Use(func(c *gin.Context) {
+            c.Set("user_id", "test_user_id")
+            c.Next()
+        })
+        router.DELETE("/tasks/:id", controller.NewTaskController(new(mocks.Environment), mocks.NewTaskUsecase(t)).DeleteTask)
+
+        req, _ := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+    })
+}
+
+func TestGetTrash(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    mockUsecase := mocks.NewTaskUsecase(t)
+    router := gin.New()
+    router.Use(middleware.ErrorHandler())
+    router.Use(func(c *gin.Context) {
+        c.Set("user_id", "test_user_id")
+        c.Next()
+    })
+    router.GET("/tasks/trash", controller.NewTaskController(new(mocks.Environment), mockUsecase).GetTrash)
+
+    deletedAt := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+    page := &model.TaskPage{Tasks: []*model.TaskInfo{{ID: "1", Title: "Deleted", DeletedAt: &deletedAt}}, Next: "next"}
+    mockUsecase.On("GetTrash", mock.Anything, "test_user_id", model.TaskQuery{Sort: "priority", Limit: 1}).Return(page, nil)
+
+    req, _ := http.NewRequest(http.MethodGet, "/tasks/trash?sort=priority&limit=1", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    expectedResponse, _ := json.Marshal(page)
+    assert.JSONEq(t, string(expectedResponse), w.Body.String())
+}
+
+func TestGetTaskActivity(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    mockUsecase := mocks.NewTaskUsecase(t)
+    router := gin.New()
+    router.Use(middleware.ErrorHandler())
+    router.Use(func(c *gin.Context) {
+        c.Set("user_id", "test_user_id")
+        c.Next()
+    })
+    router.GET("/tasks/:id/activity", controller.NewTaskController(new(mocks.Environment), mockUsecase).GetTaskActivity)
+
+    page := &model.ActivityPage{Activity: []*model.ActivityInfo{{
+        ID:     "a1",
+        TaskID: "1",
+        Action: "status_changed",
+        Changes: []model.FieldChange{{Field: "status", From: json.RawMessage(`"todo"`), To: json.RawMessage(`"done"`)}},
+    }}, Next: "a1"}
+    mockUsecase.On("GetTaskActivity", mock.Anything, "1", "test_user_id", model.PageQuery{Cursor: "a2", Limit: 1}).Return(page, nil)
+
+    req, _ := http.NewRequest(http.MethodGet, "/tasks/1/activity?limit=1&cursor=a2", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    expectedResponse, _ := json.Marshal(page)
+    assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    assert.Contains(t, w.Body.String(), `"from":"todo"`)
+}
+
+func TestRestoreTask(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    newRouter := func(mockUsecase *mocks.TaskUsecase) *gin.Engine {
+        router := gin.New()
+        router.Use(middleware.ErrorHandler())
+        router.Use(func(c *gin.Context) {
+            c.Set("user_id", "test_user_id")
+            c.Next()
+        })
+        router.POST("/tasks/:id/restore", controller.NewTaskController(new(mocks.Environment), mockUsecase).RestoreTask)
+        return router
+    }
+
+    t.Run("Task Restored Successfully", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        restored := &model.TaskInfo{ID: "1", Title: "Restored", Version: 4}
+        mockUsecase.On("RestoreTask", mock.Anything, "1", "test_user_id").Return(restored, nil)
+
+        req, _ := http.NewRequest(http.MethodPost, "/tasks/1/restore", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusOK, w.Code)
+        assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+        expectedResponse, _ := json.Marshal(gin.H{"message": "Task restored successfully", "task": restored})
+        assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    })
+
+    t.Run("Task Not In Trash", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        mockUsecase.On("RestoreTask", mock.Anything, "1", "test_user_id").Return(nil, apperrors.NotFound("task not found in trash"))
+
+        req, _ := http.NewRequest(http.MethodPost, "/tasks/1/restore", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusNotFound, w.Code)
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "task not found in trash", "instance": "/tasks/1/restore"}`, w.Body.String())
+    })
+}
+
+func TestGetTaskTree(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    newRouter := func(mockUsecase *mocks.TaskUsecase) *gin.Engine {
+        router := gin.New()
+        router.Use(middleware.ErrorHandler())
+        router.Use(func(c *gin.Context) {
+            c.Set("user_id", "test_user_id")
+            c.Next()
+        })
+        router.GET("/tasks/:id", controller.NewTaskController(new(mocks.Environment), mockUsecase).GetTaskByID)
+        return router
+    }
+
+    t.Run("Tree Fetched Successfully", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        done, half := 100, 50
+        tree := &model.TaskInfo{ID: "1", Title: "Parent", Version: 2, Progress: &half, Subtasks: []*model.TaskInfo{
+            {ID: "2", Title: "Child", Status: entities.StatusDone, ParentID: "1", Progress: &done},
+        }}
+        mockUsecase.On("GetTaskTree", mock.Anything, "1", "test_user_id").Return(tree, nil)
+
+        // A tree also depends on its subtasks, so it is sent even when the
+        // task itself has not changed.
+        req, _ := http.NewRequest(http.MethodGet, "/tasks/1?expand=subtasks", nil)
+        req.Header.Set("If-None-Match", `"2"`)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusOK, w.Code)
+        assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+        expectedResponse, _ := json.Marshal(gin.H{"task": tree})
+        assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    })
+
+    t.Run("Invalid Expand", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+
+        req, _ := http.NewRequest(http.MethodGet, "/tasks/1?expand=comments", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusBadRequest, w.Code)
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid expand", "instance": "/tasks/1", "errors": [{"field": "expand", "message": "must be subtasks"}]}`, w.Body.String())
+    })
+}
+
+func TestTaskLinks(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    newRouter := func(mockUsecase *mocks.TaskUsecase) *gin.Engine {
+        router := gin.New()
+        router.Use(middleware.ErrorHandler())
+        router.Use(func(c *gin.Context) {
+            c.Set("user_id", "test_user_id")
+            c.Next()
+        })
+        tc := controller.NewTaskController(new(mocks.Environment), mockUsecase)
+        router.PUT("/tasks/:id/parent/:parentId", tc.SetTaskParent)
+        router.DELETE("/tasks/:id/parent", tc.RemoveTaskParent)
+        router.PUT("/tasks/:id/blocked_by/:blockerId", tc.AddTaskBlocker)
+        router.DELETE("/tasks/:id/blocked_by/:blockerId", tc.RemoveTaskBlocker)
+        return router
+    }
+
+    tests := []struct {
+        name    string
+        method  string
+        path    string
+        setup   func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo)
+        message string
+    }{
+        {
+            name: "link subtask", method: http.MethodPut, path: "/tasks/2/parent/1", message: "Subtask linked successfully",
+            setup: func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+                mockUsecase.On("SetTaskParent", mock.Anything, "2", "1", "test_user_id").Return(task, nil)
+            },
+        },
+        {
+            name: "unlink subtask", method: http.MethodDelete, path: "/tasks/2/parent", message: "Subtask unlinked successfully",
+            setup: func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+                mockUsecase.On("SetTaskParent", mock.Anything, "2", "", "test_user_id").Return(task, nil)
+            },
+        },
+        {
+            name: "add blocker", method: http.MethodPut, path: "/tasks/2/blocked_by/3", message: "Blocker added successfully",
+            setup: func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+                mockUsecase.On("AddTaskBlocker", mock.Anything, "2", "3", "test_user_id").Return(task, nil)
+            },
+        },
+        {
+            name: "remove blocker", method: http.MethodDelete, path: "/tasks/2/blocked_by/3", message: "Blocker removed successfully",
+            setup: func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+                mockUsecase.On("RemoveTaskBlocker", mock.Anything, "2", "3", "test_user_id").Return(task, nil)
+            },
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            mockUsecase := mocks.NewTaskUsecase(t)
+            task := &model.TaskInfo{ID: "2", Title: "Linked", Version: 5}
+            tt.setup(mockUsecase, task)
+
+            req, _ := http.NewRequest(tt.method, tt.path, nil)
+            w := httptest.NewRecorder()
+            newRouter(mockUsecase).ServeHTTP(w, req)
+
+            assert.Equal(t, http.StatusOK, w.Code)
+            assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+            expectedResponse, _ := json.Marshal(gin.H{"message": tt.message, "task": task})
+            assert.JSONEq(t, string(expectedResponse), w.Body.String())
+        })
+    }
+
+    t.Run("cycle", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        mockUsecase.On("AddTaskBlocker", mock.Anything, "2", "3", "test_user_id").Return(nil, entities.ErrTaskCycle)
+
+        req, _ := http.NewRequest(http.MethodPut, "/tasks/2/blocked_by/3", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusConflict, w.Code)
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Conflict", "status": 409, "detail": "link would create a cycle", "instance": "/tasks/2/blocked_by/3"}`, w.Body.String())
+    })
+}
+
+func TestTaskSharing(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    newRouter := func(mockUsecase *mocks.TaskUsecase) *gin.Engine {
+        router := gin.New()
+        router.Use(middleware.ErrorHandler())
+        router.Use(func(c *gin.Context) {
+            c.Set("user_id", "test_user_id")
+            c.Next()
+        })
+        tc := controller.NewTaskController(new(mocks.Environment), mockUsecase)
+        router.GET("/tasks/assigned", tc.GetAssignedTasks)
+        router.PUT("/tasks/:id/collaborators/:username", tc.ShareTask)
+        router.DELETE("/tasks/:id/collaborators/:username", tc.UnshareTask)
+        router.PUT("/tasks/:id/assignee/:username", tc.SetTaskAssignee)
+        router.DELETE("/tasks/:id/assignee", tc.RemoveTaskAssignee)
+        return router
+    }
+
+    tests := []struct {
+        name    string
+        method  string
+        path    string
+        body    string
+        setup   func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo)
+        message string
+    }{
+        {
+            name: "share", method: http.MethodPut, path: "/tasks/2/collaborators/bob", body: `{"role": "editor"}`, message: "Task shared successfully",
+            setup: func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+                mockUsecase.On("ShareTask", mock.Anything, "2", "bob", "editor", "test_user_id").Return(task, nil)
+            },
+        },
+        {
+            name: "unshare", method: http.MethodDelete, path: "/tasks/2/collaborators/bob", message: "Task unshared successfully",
+            setup: func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+                mockUsecase.On("UnshareTask", mock.Anything, "2", "bob", "test_user_id").Return(task, nil)
+            },
+        },
+        {
+            name: "assign", method: http.MethodPut, path: "/tasks/2/assignee/bob", message: "Task assigned successfully",
+            setup: func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+                mockUsecase.On("SetTaskAssignee", mock.Anything, "2", "bob", "test_user_id").Return(task, nil)
+            },
+        },
+        {
+            name: "unassign", method: http.MethodDelete, path: "/tasks/2/assignee", message: "Task unassigned successfully",
+            setup: func(mockUsecase *mocks.TaskUsecase, task *model.TaskInfo) {
+                mockUsecase.On("SetTaskAssignee", mock.Anything, "2", "", "test_user_id").Return(task, nil)
+            },
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            mockUsecase := mocks.NewTaskUsecase(t)
+            task := &model.TaskInfo{ID: "2", Title: "Shared", Version: 5}
+            tt.setup(mockUsecase, task)
+
+            req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+            req.Header.Set("Content-Type", "application/json")
+            w := httptest.NewRecorder()
+            newRouter(mockUsecase).ServeHTTP(w, req)
+
+            assert.Equal(t, http.StatusOK, w.Code)
+            assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+            expectedResponse, _ := json.Marshal(gin.H{"message": tt.message, "task": task})
+            assert.JSONEq(t, string(expectedResponse), w.Body.String())
+        })
+    }
+
+    t.Run("invalid role", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+
+        req, _ := http.NewRequest(http.MethodPut, "/tasks/2/collaborators/bob", strings.NewReader(`{"role": "owner"}`))
+        req.Header.Set("Content-Type", "application/json")
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusBadRequest, w.Code)
+    })
+
+    t.Run("forbidden", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        mockUsecase.On("UnshareTask", mock.Anything, "2", "bob", "test_user_id").Return(nil, entities.ErrTaskForbidden)
+
+        req, _ := http.NewRequest(http.MethodDelete, "/tasks/2/collaborators/bob", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusForbidden, w.Code)
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Forbidden", "status": 403, "detail": "your role on this task does not allow this", "instance": "/tasks/2/collaborators/bob"}`, w.Body.String())
+    })
+
+    t.Run("assigned tasks", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        page := &model.TaskPage{Tasks: []*model.TaskInfo{{ID: "1", Title: "Assigned", AssigneeID: "test_user_id"}}}
+        mockUsecase.On("GetAssignedTasks", mock.Anything, "test_user_id", model.TaskQuery{Status: entities.StatusTodo}).Return(page, nil)
+
+        req, _ := http.NewRequest(http.MethodGet, "/tasks/assigned?status=todo", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusOK, w.Code)
+        expectedResponse, _ := json.Marshal(page)
+        assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    })
+}
+
+func TestTaskSeries(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    newRouter := func(mockUsecase *mocks.TaskUsecase) *gin.Engine {
+        router := gin.New()
+        router.Use(middleware.ErrorHandler())
+        router.Use(func(c *gin.Context) {
+            c.Set("user_id", "test_user_id")
+            c.Next()
+        })
+        tc := controller.NewTaskController(new(mocks.Environment), mockUsecase)
+        router.GET("/tasks/series/:seriesId", tc.GetTaskSeries)
+        router.PATCH("/tasks/series/:seriesId", tc.UpdateTaskSeries)
+        router.POST("/tasks/series/:seriesId/stop", tc.StopTaskSeries)
+        return router
+    }
+
+    tasks := []*model.TaskInfo{{ID: "2", Title: "Gym", Recurrence: "FREQ=DAILY", SeriesID: "1", Occurrence: 2}}
+
+    t.Run("list", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        mockUsecase.On("GetTaskSeries", mock.Anything, "1", "test_user_id").Return(tasks, nil)
+
+        req, _ := http.NewRequest(http.MethodGet, "/tasks/series/1", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusOK, w.Code)
+        expectedResponse, _ := json.Marshal(gin.H{"tasks": tasks})
+        assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    })
+
+    t.Run("update", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        mockUsecase.On("UpdateTaskSeries", mock.Anything, "1", model.SeriesPatch{
+            Title:      model.Patch[string]{Set: true, Value: "Gym"},
+            Recurrence: model.Patch[string]{Set: true, Value: "FREQ=DAILY"},
+        }, "test_user_id").Return(tasks, nil)
+
+        req, _ := http.NewRequest(http.MethodPatch, "/tasks/series/1", strings.NewReader(`{"title": "Gym", "recurrence": "FREQ=DAILY"}`))
+        req.Header.Set("Content-Type", "application/json")
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusOK, w.Code)
+        expectedResponse, _ := json.Marshal(gin.H{"message": "Series updated successfully", "tasks": tasks})
+        assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    })
+
+    t.Run("status cannot be patched", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+
+        req, _ := http.NewRequest(http.MethodPatch, "/tasks/series/1", strings.NewReader(`{"status": "done"}`))
+        req.Header.Set("Content-Type", "application/json")
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusBadRequest, w.Code)
+    })
+
+    t.Run("stop", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        mockUsecase.On("UpdateTaskSeries", mock.Anything, "1", model.SeriesPatch{
+            Recurrence: model.Patch[string]{Set: true, Null: true},
+        }, "test_user_id").Return(tasks, nil)
+
+        req, _ := http.NewRequest(http.MethodPost, "/tasks/series/1/stop", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusOK, w.Code)
+        expectedResponse, _ := json.Marshal(gin.H{"message": "Series stopped successfully", "tasks": tasks})
+        assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    })
+
+    t.Run("unknown series", func(t *testing.T) {
+        mockUsecase := mocks.NewTaskUsecase(t)
+        mockUsecase.On("GetTaskSeries", mock.Anything, "1", "test_user_id").Return(nil, entities.ErrSeriesNotFound)
+
+        req, _ := http.NewRequest(http.MethodGet, "/tasks/series/1", nil)
+        w := httptest.NewRecorder()
+        newRouter(mockUsecase).ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusNotFound, w.Code)
+    })
+}
+
+func TestCreateTaskValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name   string
+		body   string
+		field  string
+		detail string
+	}{
+		{name: "missing title", body: `{"description": "no title"}`, field: "title", detail: "is required"},
+		{name: "long title", body: `{"title": "` + strings.Repeat("a", 201) + `"}`, field: "title", detail: "must be at most 200 characters long"},
+		{name: "status", body: `{"title": "Task", "status": "blocked"}`, field: "status", detail: "must be one of todo, in_progress, done"},
+		{name: "priority", body: `{"title": "Task", "priority": "urgent"}`, field: "priority", detail: "must be one of low, medium, high"},
+		{name: "past due date", body: `{"title": "Task", "due_date": "` + past + `"}`, field: "due_date", detail: "must not be in the past"},
+		{name: "empty tag", body: `{"title": "Task", "tags": ["work", ""]}`, field: "tags[1]", detail: "is required"},
+		{name: "too many reminders", body: `{"title": "Task", "reminders": ["PT1M", "PT2M", "PT3M", "PT4M", "PT5M", "PT6M"]}`, field: "reminders", detail: "must have at most 5 items"},
+		{name: "unknown field", body: `{"title": "Task", "password": "secret"}`, field: "password", detail: "is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(mocks.TaskUsecase)
+
+			router := gin.New()
+			router.Use(middleware.ErrorHandler())
+			router.Use(func(c *gin.Context) {
+				c.Set("user_id", "test_user_id")
+				c.Next()
+			})
+
+			tc := controller.NewTaskController(new(mocks.Environment), mockUsecase)
+			router.POST("/tasks", tc.CreateTask)
+
+			req, _ := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var problem middleware.Problem
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, []apperrors.FieldError{{Field: tt.field, Message: tt.detail}}, problem.Errors)
+			mockUsecase.AssertNotCalled(t, "CreateTask", mock.Anything)
+		})
+	}
+
+	t.Run("valid task", func(t *testing.T) {
+		mockUsecase := new(mocks.TaskUsecase)
+
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", "test_user_id")
+			c.Next()
+		})
+
+		tc := controller.NewTaskController(new(mocks.Environment), mockUsecase)
+		router.POST("/tasks", tc.CreateTask)
+
+		mockUsecase.On("CreateTask", mock.Anything, mock.MatchedBy(func(task entities.Task) bool {
+			return task.UserID == "test_user_id" && task.Title == "Task" && task.Priority == entities.PriorityHigh &&
+				task.DueDate.Format(time.RFC3339) == future && len(task.Tags) == 1
+		})).Return(nil).Once()
+
+		body := `{"title": "Task", "priority": "high", "tags": ["work"], "due_date": "` + future + `"}`
+		req, _ := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
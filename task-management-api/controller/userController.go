@@ -1,11 +1,10 @@
 package controller
 
 import (
-	"context"
-	"log"
 	"task-management-api/config"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/model"
+	"task-management-api/middleware"
 
 	"net/http"
 
@@ -26,9 +25,9 @@ func NewUserController(newEnvironment config.Environment, userUsecase entities.U
 
 func (uc *usercontroller) GetUsers(c *gin.Context) {
 
-	users, err := uc.UserUsecase.GetUsers(context.Background(), c.Query("param"))
+	users, err := uc.UserUsecase.GetUsers(c.Request.Context(), c.Query("param"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "error retrieving users"})
+		c.Error(err)
 		return
 	}
 
@@ -39,10 +38,9 @@ func (uc *usercontroller) GetUserByID(c *gin.Context) {
 	
 	id := c.Param("id")
 
-	user, err := uc.UserUsecase.GetUserByID(context.TODO(), id)
+	user, err := uc.UserUsecase.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "error retrieving user"})
+		c.Error(err)
 		return
 	}
 
@@ -51,47 +49,69 @@ func (uc *usercontroller) GetUserByID(c *gin.Context) {
 
 func (uc *usercontroller) UpdateUser(c *gin.Context) {
 
-	ctx := context.Background()
+	currUser, exists := middleware.CurrentUser(c)
+	if !exists {
+		c.Error(errNotAuthenticated)
+		return
+	}
+
+	ctx := c.Request.Context()
 	id := c.Param("id")
-	var updatedUser entities.User
-	if err := c.BindJSON(&updatedUser); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Bad Request"})
+	var patch model.UserPatch
+	if err := bindJSON(c, &patch); err != nil {
+		c.Error(err)
 		return
 	}
 
-	err := uc.UserUsecase.UpdateUser(ctx, id, updatedUser)
+	user, err := uc.UserUsecase.UpdateUser(ctx, currUser, id, patch)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"message": "Not Found"})
+		c.Error(err)
 		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
 }
 
 func (uc *usercontroller) DeleteUser(c *gin.Context) {
 	
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	id := c.Param("id")
 	err := uc.UserUsecase.DeleteUser(ctx, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal Server Error"})
+		c.Error(err)
 		return
 	}
 }
 
 func (uc *usercontroller) CreateUser(c *gin.Context) {
 	
-	var newUser model.UserCreate
-	ctx := context.Background()
+	var request model.UserRegister
+	ctx := c.Request.Context()
 
-	if err := c.BindJSON(&newUser); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Bad Request"})
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
+	newUser := model.UserCreate{
+		Username: request.Username,
+		Password: request.Password,
+		Email:    request.Email,
+		Name:     request.Name,
+		Bio:      request.Bio,
+	}
+
 	err := uc.UserUsecase.CreateUser(ctx, newUser)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal Server Error"})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "User created successfully", "user": newUser})
+	userInfo := model.UserInfo{
+		Username: newUser.Username,
+		Email:    newUser.Email,
+		Name:     newUser.Name,
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "User created successfully", "user": userInfo})
 }
\ No newline at end of file
@@ -4,15 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"task-management-api/controller"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/mocks"
 	"task-management-api/domain/model"
+	"task-management-api/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -28,6 +30,8 @@ func TestGetUsers(t *testing.T) {
 		mockEnvironment := new(mocks.Environment)
 	
 		router := gin.Default()
+	
+		router.Use(middleware.ErrorHandler())
 		uc := controller.NewUserController(mockEnvironment, mockUsecase)
 		router.GET("/users", uc.GetUsers)
 
@@ -37,7 +41,7 @@ func TestGetUsers(t *testing.T) {
         router.ServeHTTP(w, req)
 
         assert.Equal(t, http.StatusInternalServerError, w.Code)
-        assert.JSONEq(t, `{"message": "error retrieving users"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/users"}`, w.Body.String())
     })
 
 	t.Run("successful retrieval", func(t *testing.T) {
@@ -45,6 +49,8 @@ func TestGetUsers(t *testing.T) {
 		mockEnvironment := new(mocks.Environment)
 	
 		router := gin.Default()
+	
+		router.Use(middleware.ErrorHandler())
 		uc := controller.NewUserController(mockEnvironment, mockUsecase)
 		router.GET("/users", uc.GetUsers)
 
@@ -62,7 +68,7 @@ func TestGetUsers(t *testing.T) {
 		router.ServeHTTP(w, req)
 	
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.JSONEq(t, `{"users": [{"ID": "`+useID1.Hex()+`", "username": "John Doe", "password": ""}, {"ID": "`+useID2.Hex()+`", "username": "Jane Doe", "password": ""}]}`, w.Body.String())
+		assert.JSONEq(t, `{"users": [{"ID": "`+useID1.Hex()+`", "username": "John Doe", "role": ""}, {"ID": "`+useID2.Hex()+`", "username": "Jane Doe", "role": ""}]}`, w.Body.String())
 	})
 }
 
@@ -73,6 +79,8 @@ func TestGetUserByID(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
     
         router := gin.Default()
+    
+        router.Use(middleware.ErrorHandler())
         uc := controller.NewUserController(mockEnvironment, mockUsecase)
         router.GET("/users/:id", uc.GetUserByID)
 
@@ -81,7 +89,25 @@ func TestGetUserByID(t *testing.T) {
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusInternalServerError, w.Code)
-        assert.JSONEq(t, `{"message": "error retrieving user"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/users/id_value"}`, w.Body.String())
+    })
+
+    t.Run("not found", func(t *testing.T) {
+        mockUsecase := new(mocks.UserUsecase)
+        mockEnvironment := new(mocks.Environment)
+
+        router := gin.Default()
+        router.Use(middleware.ErrorHandler())
+        uc := controller.NewUserController(mockEnvironment, mockUsecase)
+        router.GET("/users/:id", uc.GetUserByID)
+
+        mockUsecase.On("GetUserByID", mock.Anything, "id_value").Return(nil, apperrors.NotFound("user not found"))
+        req, _ := http.NewRequest(http.MethodGet, "/users/id_value", nil)
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, req)
+        assert.Equal(t, http.StatusNotFound, w.Code)
+        assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "user not found", "instance": "/users/id_value"}`, w.Body.String())
     })
 
     t.Run("success", func(t *testing.T) {
@@ -89,6 +115,8 @@ func TestGetUserByID(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
     
         router := gin.Default()
+    
+        router.Use(middleware.ErrorHandler())
         uc := controller.NewUserController(mockEnvironment, mockUsecase)
         router.GET("/users/:id", uc.GetUserByID)
         
@@ -103,58 +131,104 @@ func TestGetUserByID(t *testing.T) {
     })
 }
 
-func TestUpdateUser(t *testing.T) {
+// asUser authenticates the requests of a test router as user.
+func asUser(user entities.AuthenticatedUser) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Set("user", user)
+        c.Next()
+    }
+}
 
-    t.Run("bad request", func(t *testing.T) {
-        mockUsecase := new(mocks.UserUsecase)
-        mockEnvironment := new(mocks.Environment)
+func TestUpdateUser(t *testing.T) {
+    owner := entities.AuthenticatedUser{UserID: "id_value", Username: "User 1", Role: entities.RoleUser}
 
+    newUpdateRouter := func(mockUsecase *mocks.UserUsecase, user entities.AuthenticatedUser) *gin.Engine {
         router := gin.Default()
-        uc := controller.NewUserController(mockEnvironment, mockUsecase)
+
+        router.Use(middleware.ErrorHandler())
+        router.Use(asUser(user))
+        uc := controller.NewUserController(new(mocks.Environment), mockUsecase)
         router.PUT("/users/:id", uc.UpdateUser)
+        return router
+    }
+
+    t.Run("bad request", func(t *testing.T) {
+        router := newUpdateRouter(new(mocks.UserUsecase), owner)
 
         req, _ := http.NewRequest(http.MethodPut, "/users/id_value", nil)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusBadRequest, w.Code)
-        assert.JSONEq(t, `{"message": "Bad Request"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "request body is required", "instance": "/users/id_value"}`, w.Body.String())
     })
 
     t.Run("not found", func(t *testing.T) {
         mockUsecase := new(mocks.UserUsecase)
-        mockEnvironment := new(mocks.Environment)
-
-        router := gin.Default()
-        uc := controller.NewUserController(mockEnvironment, mockUsecase)
-        router.PUT("/users/:id", uc.UpdateUser)
+        router := newUpdateRouter(mockUsecase, owner)
 
-        mockUser := entities.User{ID: primitive.NewObjectID(), UserName: "User 1"}
-        mockUsecase.On("UpdateUser", mock.Anything, "id_value", mockUser).Return(errors.New("some error"))
+        mockUsecase.On("UpdateUser", mock.Anything, owner, "id_value", mock.AnythingOfType("model.UserPatch")).Return(nil, apperrors.NotFound("user not found"))
 
-        userJSON, _ := json.Marshal(mockUser)
+        userJSON := []byte(`{"username": "User 1"}`)
         req, _ := http.NewRequest(http.MethodPut, "/users/id_value", bytes.NewBuffer(userJSON))
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusNotFound, w.Code)
-        assert.JSONEq(t, `{"message": "Not Found"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "user not found", "instance": "/users/id_value"}`, w.Body.String())
     })
 
     t.Run("success", func(t *testing.T) {
         mockUsecase := new(mocks.UserUsecase)
-        mockEnvironment := new(mocks.Environment)
+        router := newUpdateRouter(mockUsecase, owner)
 
-        router := gin.Default()
-        uc := controller.NewUserController(mockEnvironment, mockUsecase)
-        router.PUT("/users/:id", uc.UpdateUser)
+        mockUser := &entities.User{UserName: "Updated User", Name: "Updated"}
+        mockUsecase.On("UpdateUser", mock.Anything, owner, "id_value", mock.MatchedBy(func(patch model.UserPatch) bool {
+            return patch.Username.Value == "Updated User" && patch.Bio.Null && !patch.Password.Set
+        })).Return(mockUser, nil)
 
-        mockUser := entities.User{UserName: "Updated User"}
-        mockUsecase.On("UpdateUser", mock.Anything, "id_value", mockUser).Return(nil)
-
-        userJSON, _ := json.Marshal(mockUser)
+        userJSON := []byte(`{"username": "Updated User", "bio": null}`)
         req, _ := http.NewRequest(http.MethodPut, "/users/id_value", bytes.NewBuffer(userJSON))
+        req.Header.Set("Content-Type", model.MergePatchContentType)
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusOK, w.Code)
+        expectedResponse, _ := json.Marshal(gin.H{"user": mockUser})
+        assert.JSONEq(t, string(expectedResponse), w.Body.String())
+    })
+
+    t.Run("other user", func(t *testing.T) {
+        other := entities.AuthenticatedUser{UserID: "other_id", Username: "User 2", Role: entities.RoleUser}
+        mockUsecase := mocks.NewUserUsecase(t)
+        router := newUpdateRouter(mockUsecase, other)
+
+        mockUsecase.On("UpdateUser", mock.Anything, other, "id_value", mock.AnythingOfType("model.UserPatch")).Return(nil, entities.ErrUserForbidden)
+
+        req, _ := http.NewRequest(http.MethodPut, "/users/id_value", strings.NewReader(`{"password": "password123"}`))
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, req)
+        assert.Equal(t, http.StatusForbidden, w.Code)
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Forbidden", "status": 403, "detail": "you can only change your own account", "instance": "/users/id_value"}`, w.Body.String())
+    })
+
+    t.Run("role in body is not applied", func(t *testing.T) {
+        router := newUpdateRouter(mocks.NewUserUsecase(t), owner)
+
+        req, _ := http.NewRequest(http.MethodPut, "/users/id_value", strings.NewReader(`{"name": "User", "role": "ADMIN"}`))
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, req)
+        assert.Equal(t, http.StatusBadRequest, w.Code)
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid request body", "instance": "/users/id_value", "errors": [{"field": "role", "message": "is not allowed"}]}`, w.Body.String())
+    })
+
+    t.Run("not authenticated", func(t *testing.T) {
+        router := gin.Default()
+        router.Use(middleware.ErrorHandler())
+        uc := controller.NewUserController(new(mocks.Environment), mocks.NewUserUsecase(t))
+        router.PUT("/users/:id", uc.UpdateUser)
+
+        req, _ := http.NewRequest(http.MethodPut, "/users/id_value", strings.NewReader(`{"name": "User"}`))
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, req)
+        assert.Equal(t, http.StatusUnauthorized, w.Code)
     })
 }
 
@@ -166,6 +240,8 @@ func TestDeleteUser(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         uc := controller.NewUserController(mockEnvironment, mockUsecase)
         router.DELETE("/users/:id", uc.DeleteUser)
 
@@ -175,7 +251,7 @@ func TestDeleteUser(t *testing.T) {
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusInternalServerError, w.Code)
-        assert.JSONEq(t, `{"message": "Internal Server Error"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/users/id_value"}`, w.Body.String())
     })
 
     t.Run("success", func(t *testing.T) {
@@ -183,6 +259,8 @@ func TestDeleteUser(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         uc := controller.NewUserController(mockEnvironment, mockUsecase)
         router.DELETE("/users/:id", uc.DeleteUser)
 
@@ -203,6 +281,8 @@ func TestCreateUser(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         uc := controller.NewUserController(mockEnvironment, mockUsecase)
 
         router.POST("/users", uc.CreateUser)
@@ -213,7 +293,7 @@ func TestCreateUser(t *testing.T) {
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusBadRequest, w.Code)
-        assert.JSONEq(t, `{"message": "Bad Request"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "request body is not valid JSON", "instance": "/users"}`, w.Body.String())
     })
 
     t.Run("internal server error", func(t *testing.T) {
@@ -221,6 +301,8 @@ func TestCreateUser(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         uc := controller.NewUserController(mockEnvironment, mockUsecase)
         router.POST("/users", uc.CreateUser)
 		
@@ -232,14 +314,20 @@ func TestCreateUser(t *testing.T) {
             Bio:      "This is a test user",
         }
 
-        reqBody, _ := json.Marshal(newUser)
+        reqBody, _ := json.Marshal(model.UserRegister{
+            Username: newUser.Username,
+            Password: newUser.Password,
+            Email:    newUser.Email,
+            Name:     newUser.Name,
+            Bio:      newUser.Bio,
+        })
         mockUsecase.On("CreateUser", mock.Anything, newUser).Return(errors.New("some error"))
 
         req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(reqBody))
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
         assert.Equal(t, http.StatusInternalServerError, w.Code)
-        assert.JSONEq(t, `{"message": "Internal Server Error"}`, w.Body.String())
+        assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/users"}`, w.Body.String())
     })
 
     t.Run("success", func(t *testing.T) {
@@ -247,6 +335,8 @@ func TestCreateUser(t *testing.T) {
         mockEnvironment := new(mocks.Environment)
 
         router := gin.Default()
+
+        router.Use(middleware.ErrorHandler())
         uc := controller.NewUserController(mockEnvironment, mockUsecase)
         router.POST("/users", uc.CreateUser)
 
@@ -258,16 +348,20 @@ func TestCreateUser(t *testing.T) {
             Bio:      "This is a test user",
         }
 
-        reqBody, _ := json.Marshal(newUser)
+        reqBody, _ := json.Marshal(model.UserRegister{
+            Username: newUser.Username,
+            Password: newUser.Password,
+            Email:    newUser.Email,
+            Name:     newUser.Name,
+            Bio:      newUser.Bio,
+        })
         mockUsecase.On("CreateUser", mock.Anything, newUser).Return(nil)
 
         req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(reqBody))
         w := httptest.NewRecorder()
         router.ServeHTTP(w, req)
 
-        newUserJSON, _ := json.Marshal(newUser)
-
-        expectedResponse := fmt.Sprintf(`{"message": "User created successfully", "user": %s}`, newUserJSON)
+        expectedResponse := `{"message": "User created successfully", "user": {"id": "", "username": "testuser", "email": "testuser@example.com", "name": "Test User"}}`
 
         assert.Equal(t, http.StatusCreated, w.Code)
         assert.JSONEq(t, expectedResponse, w.Body.String())
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"net/http"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webhookcontroller serves /webhooks. Users only see their own webhooks.
+type webhookcontroller struct {
+	WebhookUsecase entities.WebhookUsecase
+}
+
+func NewWebhookController(webhookUsecase entities.WebhookUsecase) *webhookcontroller {
+	return &webhookcontroller{
+		WebhookUsecase: webhookUsecase,
+	}
+}
+
+func (wc *webhookcontroller) GetWebhooks(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	webhooks, err := wc.WebhookUsecase.GetWebhooks(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+// CreateWebhook answers with the secret of the new webhook, which is not
+// shown again.
+func (wc *webhookcontroller) CreateWebhook(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var request model.WebhookRequest
+	if err := bindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	webhook, err := wc.WebhookUsecase.CreateWebhook(c.Request.Context(), request.URL, request.Events, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Webhook created successfully", "webhook": webhook})
+}
+
+func (wc *webhookcontroller) GetWebhookByID(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	webhook, err := wc.WebhookUsecase.GetWebhookByID(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhook": webhook})
+}
+
+func (wc *webhookcontroller) DeleteWebhook(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := wc.WebhookUsecase.DeleteWebhook(c.Request.Context(), c.Param("id"), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// GetDeliveries lists the delivery log of a webhook, newest first. It accepts
+// the same page parameters as the activity of a task.
+func (wc *webhookcontroller) GetDeliveries(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	query, err := pageQueryFromRequest(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := wc.WebhookUsecase.GetDeliveries(c.Request.Context(), c.Param("id"), userID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// TestWebhook answers 200 with the logged delivery even when the receiver
+// failed; its status tells whether it succeeded.
+func (wc *webhookcontroller) TestWebhook(c *gin.Context) {
+	userID, err := taskUserID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	delivery, err := wc.WebhookUsecase.TestWebhook(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"delivery": delivery})
+}
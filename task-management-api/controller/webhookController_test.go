@@ -0,0 +1,119 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-management-api/controller"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/domain/model"
+	"task-management-api/middleware"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newWebhookRouter(webhookUsecase *mocks.WebhookUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", "test_user_id")
+		c.Next()
+	})
+
+	wc := controller.NewWebhookController(webhookUsecase)
+	router.GET("/webhooks", wc.GetWebhooks)
+	router.POST("/webhooks", wc.CreateWebhook)
+	router.GET("/webhooks/:id", wc.GetWebhookByID)
+	router.DELETE("/webhooks/:id", wc.DeleteWebhook)
+	router.GET("/webhooks/:id/deliveries", wc.GetDeliveries)
+	router.POST("/webhooks/:id/test", wc.TestWebhook)
+	return router
+}
+
+func serveWebhook(router *gin.Engine, method string, path string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateWebhook(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		webhook := &model.WebhookInfo{ID: "1", URL: "https://hooks.example.com/tasks", Events: []string{entities.EventTaskCreated},
+			Secret: "whsec_test", CreatedAt: time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)}
+
+		webhookUsecase := mocks.NewWebhookUsecase(t)
+		webhookUsecase.On("CreateWebhook", mock.Anything, "https://hooks.example.com/tasks", []string{entities.EventTaskCreated}, "test_user_id").Return(webhook, nil)
+
+		w := serveWebhook(newWebhookRouter(webhookUsecase), http.MethodPost, "/webhooks",
+			`{"url": "https://hooks.example.com/tasks", "events": ["task.created"]}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		expectedResponse, _ := json.Marshal(gin.H{"message": "Webhook created successfully", "webhook": webhook})
+		assert.JSONEq(t, string(expectedResponse), w.Body.String())
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		w := serveWebhook(newWebhookRouter(mocks.NewWebhookUsecase(t)), http.MethodPost, "/webhooks",
+			`{"url": "https://hooks.example.com/tasks", "events": ["task.archived"]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		webhookUsecase := mocks.NewWebhookUsecase(t)
+		webhookUsecase.On("CreateWebhook", mock.Anything, "hooks.example.com", []string{entities.EventTaskCreated}, "test_user_id").
+			Return(nil, entities.ErrInvalidWebhookURL)
+
+		w := serveWebhook(newWebhookRouter(webhookUsecase), http.MethodPost, "/webhooks", `{"url": "hooks.example.com", "events": ["task.created"]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"url"`)
+	})
+}
+
+func TestGetWebhookNotFound(t *testing.T) {
+	webhookUsecase := mocks.NewWebhookUsecase(t)
+	webhookUsecase.On("GetWebhookByID", mock.Anything, "1", "test_user_id").Return(nil, entities.ErrWebhookNotFound)
+
+	w := serveWebhook(newWebhookRouter(webhookUsecase), http.MethodGet, "/webhooks/1", "")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	page := &model.WebhookDeliveryPage{Deliveries: []*model.WebhookDeliveryInfo{{ID: "d1", WebhookID: "1", Event: entities.EventTaskCreated,
+		Payload: json.RawMessage(`{"event":"task.created"}`), Status: entities.WebhookDeliverySucceeded, Attempts: 1, ResponseStatus: 200}}}
+
+	webhookUsecase := mocks.NewWebhookUsecase(t)
+	webhookUsecase.On("GetDeliveries", mock.Anything, "1", "test_user_id", model.PageQuery{Limit: 10}).Return(page, nil)
+
+	w := serveWebhook(newWebhookRouter(webhookUsecase), http.MethodGet, "/webhooks/1/deliveries?limit=10", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	expectedResponse, _ := json.Marshal(page)
+	assert.JSONEq(t, string(expectedResponse), w.Body.String())
+}
+
+func TestTestWebhook(t *testing.T) {
+	delivery := &model.WebhookDeliveryInfo{ID: "d1", WebhookID: "1", Event: entities.EventWebhookTest, Payload: json.RawMessage(`{"event":"webhook.test"}`),
+		Status: entities.WebhookDeliveryFailed, Attempts: 1, ResponseStatus: 500, Error: "webhook answered 500 Internal Server Error"}
+
+	webhookUsecase := mocks.NewWebhookUsecase(t)
+	webhookUsecase.On("TestWebhook", mock.Anything, "1", "test_user_id").Return(delivery, nil)
+
+	w := serveWebhook(newWebhookRouter(webhookUsecase), http.MethodPost, "/webhooks/1/test", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	expectedResponse, _ := json.Marshal(gin.H{"delivery": delivery})
+	assert.JSONEq(t, string(expectedResponse), w.Body.String())
+}
@@ -0,0 +1,113 @@
+// Package apperrors defines the errors repositories and usecases return so
+// that the HTTP layer can map them to status codes without inspecting error
+// strings.
+package apperrors
+
+import (
+	"errors"
+	"time"
+)
+
+// Sentinel errors identifying the kind of an *Error. Check them with
+// errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnavailable  = errors.New("unavailable")
+	ErrRateLimited  = errors.New("rate limited")
+
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required")
+)
+
+// FieldError describes why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error of one of the sentinel kinds. Detail is safe to show
+// to clients; Err is the underlying cause, if any, and is not. RetryAfter
+// tells rate limited clients when to try again.
+type Error struct {
+	Kind       error
+	Detail     string
+	Fields     []FieldError
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	message := e.Detail
+	if message == "" {
+		message = e.Kind.Error()
+	}
+
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+
+	return message
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// Wrap returns a copy of e with err recorded as its cause.
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+func NotFound(detail string) *Error {
+	return &Error{Kind: ErrNotFound, Detail: detail}
+}
+
+func Conflict(detail string) *Error {
+	return &Error{Kind: ErrConflict, Detail: detail}
+}
+
+func Validation(detail string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Detail: detail, Fields: fields}
+}
+
+func Unauthorized(detail string) *Error {
+	return &Error{Kind: ErrUnauthorized, Detail: detail}
+}
+
+func Forbidden(detail string) *Error {
+	return &Error{Kind: ErrForbidden, Detail: detail}
+}
+
+func Unavailable(detail string) *Error {
+	return &Error{Kind: ErrUnavailable, Detail: detail}
+}
+
+func RateLimited(detail string, retryAfter time.Duration) *Error {
+	return &Error{Kind: ErrRateLimited, Detail: detail, RetryAfter: retryAfter}
+}
+
+func PreconditionFailed(detail string) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Detail: detail}
+}
+
+func PreconditionRequired(detail string) *Error {
+	return &Error{Kind: ErrPreconditionRequired, Detail: detail}
+}
+
+// As returns the *Error in err's chain, if there is one.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actions recorded in the activity log of a task. An update that changes the
+// status is recorded as a status change.
+const (
+	ActivityCreated       = "created"
+	ActivityUpdated       = "updated"
+	ActivityStatusChanged = "status_changed"
+	ActivityDeleted       = "deleted"
+	ActivityRestored      = "restored"
+)
+
+// Activity is one entry of the append-only log of what happened to a task and
+// who did it.
+type Activity struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID    string             `bson:"taskId"`
+	ActorID   string             `bson:"actorId"`
+	Action    string             `bson:"action"`
+	Changes   []FieldChange      `bson:"changes"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// FieldChange holds the JSON encoding of a task field before and after an
+// update, as it appears in task responses.
+type FieldChange struct {
+	Field string `json:"field" bson:"field"`
+	From  string `json:"from" bson:"from"`
+	To    string `json:"to" bson:"to"`
+}
+
+func (a *Activity) Info() *model.ActivityInfo {
+	return &model.ActivityInfo{
+		ID:        a.ID.Hex(),
+		TaskID:    a.TaskID,
+		ActorID:   a.ActorID,
+		Action:    a.Action,
+		Changes:   FieldChangesInfo(a.Changes),
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+// FieldChangesInfo returns the response representation of field changes.
+func FieldChangesInfo(changes []FieldChange) []model.FieldChange {
+	var infos []model.FieldChange
+	for _, change := range changes {
+		infos = append(infos, model.FieldChange{
+			Field: change.Field,
+			From:  json.RawMessage(change.From),
+			To:    json.RawMessage(change.To),
+		})
+	}
+
+	return infos
+}
+
+// ActivityRepository appends to and reads the activity log of tasks.
+// GetActivity returns the newest entries first.
+type ActivityRepository interface {
+	AddActivity(ctx context.Context, activity Activity) error
+	GetActivity(ctx context.Context, taskID string, query model.PageQuery) (*model.ActivityPage, error)
+}
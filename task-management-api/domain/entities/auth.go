@@ -1,21 +1,36 @@
 package entities
 
-import "task-management-api/domain/model"
+import (
+	"context"
+	"task-management-api/domain/model"
+	"time"
+)
+
+const (
+	RoleAdmin = "ADMIN"
+	RoleUser  = "USER"
+)
 
 type AuthenticatedUser struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Role     string `json:"role"`
-}
 
-type Token struct {
-	Token string `json:"token"`
+	TokenID        string    `json:"-"`
+	TokenExpiresAt time.Time `json:"-"`
 }
 
+// LoginMetrics counts login attempts by whether the credentials were accepted.
+type LoginMetrics interface {
+	RecordLogin(success bool)
+}
 
 type AuthUseCase interface {
-	Register(userCreate *model.UserCreate) (*model.UserInfo,error)
-	Login(userLogin *model.UserLogin) (string,error)
-	AdminRegister(currUser AuthenticatedUser, userCreate *model.UserCreate, param any) (*model.UserInfo,error)
+	Register(ctx context.Context, userCreate *model.UserCreate) (*model.UserInfo,error)
+	Login(ctx context.Context, userLogin *model.UserLogin) (*TokenPair,error)
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair,error)
+	Logout(ctx context.Context, currUser AuthenticatedUser, refreshToken string) error
+	LogoutAll(ctx context.Context, currUser AuthenticatedUser) error
+	AdminRegister(ctx context.Context, currUser AuthenticatedUser, userCreate *model.UserCreate, param any) (*model.UserInfo,error)
 }
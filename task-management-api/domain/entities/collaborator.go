@@ -0,0 +1,83 @@
+package entities
+
+import "task-management-api/domain/apperrors"
+
+// Roles a user can have on a task. The owner created the task; other users
+// get a role when the task is shared with them, when it is in a project they
+// are a member of, or when it is assigned to them. Viewers can read the task
+// and comment on it, editors can also change it, and only the owner can
+// delete, restore, share and move it.
+const (
+	TaskRoleViewer = "viewer"
+	TaskRoleEditor = "editor"
+	TaskRoleOwner  = "owner"
+)
+
+// roleRanks orders the roles on tasks and projects, which share their names.
+var roleRanks = map[string]int{
+	TaskRoleViewer: 1,
+	TaskRoleEditor: 2,
+	TaskRoleOwner:  3,
+}
+
+// RoleAllows reports whether role is at least the required role. The empty
+// role allows nothing.
+func RoleAllows(role string, required string) bool {
+	rank, ok := roleRanks[role]
+	return ok && rank >= roleRanks[required]
+}
+
+// HigherRole returns the higher of two roles.
+func HigherRole(a string, b string) string {
+	if roleRanks[b] > roleRanks[a] {
+		return b
+	}
+
+	return a
+}
+
+var (
+	ErrTaskForbidden        = apperrors.Forbidden("your role on this task does not allow this")
+	ErrInvalidTaskRole      = apperrors.Validation("invalid role", apperrors.FieldError{Field: "role", Message: "must be one of viewer, editor"})
+	ErrShareWithOwner       = apperrors.Conflict("the task already belongs to this user")
+	ErrCollaboratorNotFound = apperrors.NotFound("collaborator not found")
+	ErrAssigneeCannotAccess = apperrors.Conflict("the task is not shared with this user")
+)
+
+// IsValidCollaboratorRole reports whether a task can be shared with the given
+// role. Ownership cannot be shared.
+func IsValidCollaboratorRole(role string) bool {
+	return role == TaskRoleViewer || role == TaskRoleEditor
+}
+
+// Collaborator is a user a task is shared with.
+type Collaborator struct {
+	UserID string `bson:"userId"`
+	Role   string `bson:"role"`
+}
+
+// RoleOf returns the role of a user on the task itself, or "" if the task is
+// neither shared with nor assigned to them. An assignee can at least read the
+// task. Roles from the task's project are not included.
+func (t *Task) RoleOf(userID string) string {
+	if t.UserID == userID {
+		return TaskRoleOwner
+	}
+
+	for _, collaborator := range t.Collaborators {
+		if collaborator.UserID == userID {
+			return collaborator.Role
+		}
+	}
+
+	if t.AssigneeID != "" && t.AssigneeID == userID {
+		return TaskRoleViewer
+	}
+
+	return ""
+}
+
+// Allows reports whether a user has at least the given role on the task.
+func (t *Task) Allows(userID string, role string) bool {
+	return RoleAllows(t.RoleOf(userID), role)
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"context"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrCommentNotFound  = apperrors.NotFound("comment not found")
+	ErrCommentNotAuthor = apperrors.Forbidden("only the author can change a comment")
+)
+
+type Comment struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID    string             `bson:"taskId"`
+	AuthorID  string             `bson:"authorId"`
+	Body      string             `bson:"body"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+func (c *Comment) Info() *model.CommentInfo {
+	return &model.CommentInfo{
+		ID:        c.ID.Hex(),
+		TaskID:    c.TaskID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// CommentRepository stores the comments of tasks. GetComments returns them
+// oldest first.
+type CommentRepository interface {
+	GetComments(ctx context.Context, taskID string, query model.PageQuery) (*model.CommentPage, error)
+	GetCommentByID(ctx context.Context, taskID string, id string) (*Comment, error)
+	CreateComment(ctx context.Context, comment Comment) error
+	UpdateComment(ctx context.Context, comment Comment) error
+	DeleteComment(ctx context.Context, taskID string, id string) error
+}
+
+// CommentUsecase lets users comment on the tasks they can read. Only the
+// author of a comment can edit or delete it.
+type CommentUsecase interface {
+	GetComments(ctx context.Context, taskID string, userID string, query model.PageQuery) (*model.CommentPage, error)
+	CreateComment(ctx context.Context, taskID string, userID string, body string) (*model.CommentInfo, error)
+	UpdateComment(ctx context.Context, taskID string, id string, userID string, body string) (*model.CommentInfo, error)
+	DeleteComment(ctx context.Context, taskID string, id string, userID string) error
+}
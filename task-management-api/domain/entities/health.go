@@ -0,0 +1,9 @@
+package entities
+
+import "context"
+
+// HealthChecker reports whether the database the service depends on is
+// reachable.
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,138 @@
+package entities
+
+import (
+	"context"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Roles a user can have in a project. The owner created the project and is
+// the only one who can rename, archive it and manage its members. Editors can
+// add tasks to the project, and viewers can list its tasks.
+const (
+	ProjectRoleViewer = TaskRoleViewer
+	ProjectRoleEditor = TaskRoleEditor
+	ProjectRoleOwner  = TaskRoleOwner
+)
+
+var (
+	// ErrProjectNotFound is also returned for projects the user is not a
+	// member of, so the two cases cannot be told apart.
+	ErrProjectNotFound      = apperrors.NotFound("project not found")
+	ErrProjectForbidden     = apperrors.Forbidden("your role in this project does not allow this")
+	ErrProjectArchived      = apperrors.Conflict("project is archived")
+	ErrInvalidProjectRole   = apperrors.Validation("invalid role", apperrors.FieldError{Field: "role", Message: "must be one of viewer, editor"})
+	ErrAddProjectOwner      = apperrors.Conflict("the project already belongs to this user")
+	ErrProjectMemberMissing = apperrors.NotFound("member not found")
+	ErrTaskNotInProject     = apperrors.NotFound("task is not in this project")
+	ErrProjectModified      = apperrors.Conflict("project was changed by another request, try again")
+)
+
+// IsValidProjectMemberRole reports whether a user can be added to a project
+// with the given role. Ownership cannot be shared.
+func IsValidProjectMemberRole(role string) bool {
+	return role == ProjectRoleViewer || role == ProjectRoleEditor
+}
+
+// ProjectMember is a user added to a project.
+type ProjectMember struct {
+	UserID string `bson:"userId"`
+	Role   string `bson:"role"`
+}
+
+// Project groups tasks and the users who work on them. Tasks refer to their
+// project by ID.
+type Project struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty"`
+	Name    string             `bson:"name"`
+	OwnerID string             `bson:"ownerId"`
+	Members []ProjectMember    `bson:"members"`
+	// ArchivedAt is set while the project is archived. Tasks cannot be added
+	// to or moved out of an archived project.
+	ArchivedAt *time.Time `bson:"archivedAt"`
+	CreatedAt  time.Time  `bson:"createdAt"`
+	UpdatedAt  time.Time  `bson:"updatedAt"`
+	// Version is incremented on every update. Projects stored before
+	// versions were introduced are at version 0.
+	Version int64 `bson:"version"`
+}
+
+// RoleOf returns the role of a user in the project, or "" if they are not a
+// member.
+func (p *Project) RoleOf(userID string) string {
+	if p.OwnerID == userID {
+		return ProjectRoleOwner
+	}
+
+	for _, member := range p.Members {
+		if member.UserID == userID {
+			return member.Role
+		}
+	}
+
+	return ""
+}
+
+// TaskRoleOf returns the role a user has on the tasks of the project. The
+// owner of the project can edit its tasks but, unlike their owners, cannot
+// delete or share them.
+func (p *Project) TaskRoleOf(userID string) string {
+	switch p.RoleOf(userID) {
+	case ProjectRoleOwner, ProjectRoleEditor:
+		return TaskRoleEditor
+	case ProjectRoleViewer:
+		return TaskRoleViewer
+	default:
+		return ""
+	}
+}
+
+func (p *Project) Info() *model.ProjectInfo {
+	info := &model.ProjectInfo{
+		ID:         p.ID.Hex(),
+		Name:       p.Name,
+		OwnerID:    p.OwnerID,
+		Members:    []model.ProjectMember{},
+		ArchivedAt: p.ArchivedAt,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+	}
+
+	for _, member := range p.Members {
+		info.Members = append(info.Members, model.ProjectMember{UserID: member.UserID, Role: member.Role})
+	}
+
+	return info
+}
+
+// ProjectRepository stores projects. GetProjects returns the projects a user
+// owns or is a member of, oldest first, leaving archived projects out unless
+// archived is set. UpdateProject writes the name, members and archive time of
+// a project while it is still at project.Version, which it increments, and
+// fails with ErrProjectModified when another update came first.
+type ProjectRepository interface {
+	GetProjects(ctx context.Context, userID string, archived bool) ([]*Project, error)
+	GetProjectByID(ctx context.Context, id string) (*Project, error)
+	CreateProject(ctx context.Context, project Project) error
+	UpdateProject(ctx context.Context, project Project) error
+}
+
+// ProjectUsecase manages projects and their members. Methods taking a project
+// ID expect the caller to have checked the role of the user in the project,
+// see middleware.RequireProjectRole. The tasks of a project are managed by the
+// TaskUsecase.
+//
+// AddProjectMember adds the user with the given username to a project or
+// changes their role, and RemoveProjectMember takes them off it again.
+type ProjectUsecase interface {
+	GetProjects(ctx context.Context, userID string, archived bool) ([]*model.ProjectInfo, error)
+	GetProjectByID(ctx context.Context, id string) (*model.ProjectInfo, error)
+	CreateProject(ctx context.Context, name string, userID string) (*model.ProjectInfo, error)
+	RenameProject(ctx context.Context, id string, name string) (*model.ProjectInfo, error)
+	ArchiveProject(ctx context.Context, id string, archived bool) (*model.ProjectInfo, error)
+	AddProjectMember(ctx context.Context, id string, username string, role string) (*model.ProjectInfo, error)
+	RemoveProjectMember(ctx context.Context, id string, username string) (*model.ProjectInfo, error)
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit is a token bucket: up to Burst requests may be made at once and
+// the bucket refills at Rate requests per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled reports whether the limit restricts anything. The zero RateLimit
+// disables rate limiting.
+func (rl RateLimit) Enabled() bool {
+	return rl.Burst > 0 && rl.Rate > 0
+}
+
+// RateLimitDecision is the outcome of taking a token from a bucket.
+// ResetAfter is the time until the bucket is full again and RetryAfter the
+// time until the next request would be allowed.
+type RateLimitDecision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps the token buckets. Implementations backed by a shared
+// store let several instances of the service enforce one limit.
+type RateLimitStore interface {
+	Allow(ctx context.Context, key string, limit RateLimit) (RateLimitDecision, error)
+}
+
+// LoginFailures counts the consecutive failed logins for a username.
+type LoginFailures struct {
+	Count       int
+	LastFailure time.Time
+}
+
+// LoginAttemptStore tracks failed logins per username. Failures older than the
+// store's retention are forgotten.
+//
+// RecordAttempt counts a login attempt as failed before its password is
+// checked, unless lockedUntil, given the failures so far, is still ahead; it
+// then returns that time and counts nothing. The check and the count are one
+// atomic operation, so parallel attempts cannot all get past the check before
+// any of them is counted. A successful login calls ResetFailures.
+type LoginAttemptStore interface {
+	GetFailures(ctx context.Context, username string) (LoginFailures, error)
+	RecordAttempt(ctx context.Context, username string, lockedUntil func(LoginFailures) time.Time) (LoginFailures, time.Time, error)
+	ResetFailures(ctx context.Context, username string) error
+}
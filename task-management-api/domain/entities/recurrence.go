@@ -0,0 +1,313 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"task-management-api/domain/apperrors"
+	"time"
+)
+
+// Recurrence frequencies, as in the FREQ part of an RRULE.
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// MaxRecurrenceLength is the longest recurrence rule accepted, in characters.
+const MaxRecurrenceLength = 200
+
+// recurrenceUntilLayout is the UTC date-time form of UNTIL. UNTIL may also be
+// a plain date, which includes the whole day.
+const recurrenceUntilLayout = "20060102T150405Z"
+
+// maxRecurrenceSearch bounds the periods searched for the next occurrence, so
+// that rules such as BYMONTHDAY=31 with INTERVAL=12 from February end.
+const maxRecurrenceSearch = 1000
+
+var (
+	ErrRecurrenceDueDate = apperrors.Validation("invalid recurrence", apperrors.FieldError{Field: "due_date", Message: "is required for a recurring task"})
+	ErrSeriesNotFound    = apperrors.NotFound("series not found")
+)
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var recurrenceWeekdayNames = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func invalidRecurrence(message string) error {
+	return apperrors.Validation("invalid recurrence", apperrors.FieldError{Field: "recurrence", Message: message})
+}
+
+// Recurrence is a parsed recurrence rule, a subset of the RRULE of RFC 5545:
+// FREQ is DAILY, WEEKLY or MONTHLY, optionally with an INTERVAL, BYDAY for
+// weekly rules, BYMONTHDAY for monthly ones, and either a COUNT or an UNTIL.
+// Weeks start on Monday, and negative month days count from the end of the
+// month.
+//
+// Occurrences are computed from the due date of the previous one, in UTC, and
+// keep its time of day. A COUNT includes the first occurrence.
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+// ParseRecurrence parses a rule such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+// A leading "RRULE:" is ignored.
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	if len(rule) > MaxRecurrenceLength {
+		return nil, invalidRecurrence(fmt.Sprintf("must be at most %d characters long", MaxRecurrenceLength))
+	}
+
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	recurrence := &Recurrence{Interval: 1}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, invalidRecurrence(fmt.Sprintf("part %q must be NAME=VALUE", part))
+		}
+		if seen[name] {
+			return nil, invalidRecurrence(name + " is repeated")
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			recurrence.Freq = value
+			if value != FreqDaily && value != FreqWeekly && value != FreqMonthly {
+				err = invalidRecurrence("FREQ must be one of DAILY, WEEKLY, MONTHLY")
+			}
+		case "INTERVAL":
+			recurrence.Interval, err = parseRecurrenceNumber(name, value)
+		case "COUNT":
+			recurrence.Count, err = parseRecurrenceNumber(name, value)
+		case "UNTIL":
+			recurrence.Until, err = parseRecurrenceUntil(value)
+		case "BYDAY":
+			recurrence.ByDay, err = parseRecurrenceWeekdays(value)
+		case "BYMONTHDAY":
+			recurrence.ByMonthDay, err = parseRecurrenceMonthDays(value)
+		case "WKST":
+			if value != "MO" {
+				err = invalidRecurrence("only WKST=MO is supported")
+			}
+		default:
+			err = invalidRecurrence("unsupported part " + name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case recurrence.Freq == "":
+		return nil, invalidRecurrence("FREQ is required")
+	case recurrence.Count > 0 && recurrence.Until != nil:
+		return nil, invalidRecurrence("COUNT and UNTIL cannot both be set")
+	case recurrence.ByDay != nil && recurrence.Freq != FreqWeekly:
+		return nil, invalidRecurrence("BYDAY is only supported with FREQ=WEEKLY")
+	case recurrence.ByMonthDay != nil && recurrence.Freq != FreqMonthly:
+		return nil, invalidRecurrence("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+
+	return recurrence, nil
+}
+
+func parseRecurrenceNumber(name string, value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 || number > 1000 {
+		return 0, invalidRecurrence(name + " must be a number from 1 to 1000")
+	}
+
+	return number, nil
+}
+
+func parseRecurrenceUntil(value string) (*time.Time, error) {
+	until, err := time.Parse(recurrenceUntilLayout, value)
+	if err != nil {
+		until, err = time.Parse("20060102", value)
+		until = until.Add(24*time.Hour - time.Second)
+	}
+	if err != nil {
+		return nil, invalidRecurrence("UNTIL must be a date such as 20241231 or a UTC time such as 20241231T235959Z")
+	}
+
+	return &until, nil
+}
+
+func parseRecurrenceWeekdays(value string) ([]time.Weekday, error) {
+	seen := map[time.Weekday]bool{}
+	var weekdays []time.Weekday
+
+	for _, name := range strings.Split(value, ",") {
+		weekday, ok := recurrenceWeekdays[name]
+		if !ok {
+			return nil, invalidRecurrence("BYDAY must list days among MO, TU, WE, TH, FR, SA, SU")
+		}
+		if !seen[weekday] {
+			seen[weekday] = true
+			weekdays = append(weekdays, weekday)
+		}
+	}
+
+	sort.Slice(weekdays, func(i, j int) bool {
+		return daysSinceMonday(weekdays[i]) < daysSinceMonday(weekdays[j])
+	})
+
+	return weekdays, nil
+}
+
+func parseRecurrenceMonthDays(value string) ([]int, error) {
+	seen := map[int]bool{}
+	var days []int
+
+	for _, text := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(text)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, invalidRecurrence("BYMONTHDAY must list days from 1 to 31 or -31 to -1")
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+
+	sort.Ints(days)
+	return days, nil
+}
+
+// String formats the rule in a canonical form, with its parts in a fixed
+// order and INTERVAL left out when it is 1.
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		names := make([]string, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			names = append(names, recurrenceWeekdayNames[weekday])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(names, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(recurrenceUntilLayout))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence following the one due at current, which is
+// occurrence number occurrence of its series. It returns false when the rule
+// has no further occurrence.
+func (r *Recurrence) Next(current time.Time, occurrence int) (time.Time, bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	current = current.UTC()
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var next time.Time
+	var ok bool
+	switch r.Freq {
+	case FreqDaily:
+		next, ok = current.AddDate(0, 0, interval), true
+	case FreqWeekly:
+		next, ok = r.nextWeekly(current, interval), true
+	case FreqMonthly:
+		next, ok = r.nextMonthly(current, interval)
+	}
+
+	if !ok || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+func (r *Recurrence) nextWeekly(current time.Time, interval int) time.Time {
+	weekdays := r.ByDay
+	if len(weekdays) == 0 {
+		weekdays = []time.Weekday{current.Weekday()}
+	}
+
+	weekStart := current.AddDate(0, 0, -daysSinceMonday(current.Weekday()))
+	for _, weekday := range weekdays {
+		if daysSinceMonday(weekday) > daysSinceMonday(current.Weekday()) {
+			return weekStart.AddDate(0, 0, daysSinceMonday(weekday))
+		}
+	}
+
+	return weekStart.AddDate(0, 0, 7*interval+daysSinceMonday(weekdays[0]))
+}
+
+func (r *Recurrence) nextMonthly(current time.Time, interval int) (time.Time, bool) {
+	monthDays := r.ByMonthDay
+	if len(monthDays) == 0 {
+		monthDays = []int{current.Day()}
+	}
+
+	year, month, _ := current.Date()
+	hour, minute, second := current.Clock()
+
+	// Months lacking a day are skipped, as in RFC 5545.
+	for i := 0; i < maxRecurrenceSearch; i++ {
+		monthStart := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).AddDate(0, i*interval, 0)
+		length := monthStart.AddDate(0, 1, -1).Day()
+
+		var days []int
+		for _, day := range monthDays {
+			if day < 0 {
+				day += length + 1
+			}
+			if day >= 1 && day <= length {
+				days = append(days, day)
+			}
+		}
+		sort.Ints(days)
+
+		for _, day := range days {
+			next := time.Date(monthStart.Year(), monthStart.Month(), day, hour, minute, second, current.Nanosecond(), time.UTC)
+			if next.After(current) {
+				return next, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func daysSinceMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
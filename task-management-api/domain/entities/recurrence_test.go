@@ -0,0 +1,103 @@
+package entities_test
+
+import (
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	valid := []struct {
+		rule      string
+		canonical string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"rrule:freq=weekly;byday=we,mo;interval=2", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{"FREQ=WEEKLY;BYDAY=SU,MO;WKST=MO", "FREQ=WEEKLY;BYDAY=MO,SU"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1,15;COUNT=12", "FREQ=MONTHLY;BYMONTHDAY=-1,15;COUNT=12"},
+		{"FREQ=DAILY;INTERVAL=1;UNTIL=20241231", "FREQ=DAILY;UNTIL=20241231T235959Z"},
+		{"FREQ=DAILY;UNTIL=20241231T120000Z", "FREQ=DAILY;UNTIL=20241231T120000Z"},
+	}
+
+	for _, tt := range valid {
+		t.Run(tt.rule, func(t *testing.T) {
+			recurrence, err := entities.ParseRecurrence(tt.rule)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.canonical, recurrence.String())
+		})
+	}
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=two",
+		"FREQ=DAILY;COUNT=3;UNTIL=20241231",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=WEEKLY;WKST=SU",
+	}
+
+	for _, rule := range invalid {
+		t.Run("invalid "+rule, func(t *testing.T) {
+			_, err := entities.ParseRecurrence(rule)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		rule       string
+		current    time.Time
+		occurrence int
+		next       time.Time
+		ok         bool
+	}{
+		{"daily", "FREQ=DAILY", date(2024, 2, 28), 1, date(2024, 2, 29), true},
+		{"every third day", "FREQ=DAILY;INTERVAL=3", date(2024, 12, 30), 1, date(2025, 1, 2), true},
+		{"weekly on the same day", "FREQ=WEEKLY", date(2024, 9, 4), 1, date(2024, 9, 11), true},
+		{"weekly later in the week", "FREQ=WEEKLY;BYDAY=MO,WE,FR", date(2024, 9, 4), 1, date(2024, 9, 6), true},
+		{"weekly into the next week", "FREQ=WEEKLY;BYDAY=MO,WE,FR", date(2024, 9, 6), 1, date(2024, 9, 9), true},
+		{"fortnightly into a later week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU", date(2024, 9, 8), 1, date(2024, 9, 17), true},
+		{"monthly on the same day", "FREQ=MONTHLY", date(2024, 1, 15), 1, date(2024, 2, 15), true},
+		{"monthly skips short months", "FREQ=MONTHLY", date(2024, 1, 31), 1, date(2024, 3, 31), true},
+		{"monthly on several days", "FREQ=MONTHLY;BYMONTHDAY=1,15", date(2024, 1, 15), 1, date(2024, 2, 1), true},
+		{"monthly on the last day", "FREQ=MONTHLY;BYMONTHDAY=-1", date(2024, 1, 31), 1, date(2024, 2, 29), true},
+		{"quarterly", "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1", date(2024, 11, 1), 1, date(2025, 2, 1), true},
+		{"count not reached", "FREQ=DAILY;COUNT=3", date(2024, 9, 1), 2, date(2024, 9, 2), true},
+		{"count reached", "FREQ=DAILY;COUNT=3", date(2024, 9, 1), 3, time.Time{}, false},
+		{"until not reached", "FREQ=DAILY;UNTIL=20240902", date(2024, 9, 1), 1, date(2024, 9, 2), true},
+		{"until reached", "FREQ=DAILY;UNTIL=20240902T000000Z", date(2024, 9, 1), 1, time.Time{}, false},
+		{"no such day", "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30", date(2024, 2, 1), 1, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurrence, err := entities.ParseRecurrence(tt.rule)
+			require.NoError(t, err)
+
+			next, ok := recurrence.Next(tt.current, tt.occurrence)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.next, next)
+		})
+	}
+}
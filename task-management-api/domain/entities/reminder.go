@@ -0,0 +1,207 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"task-management-api/domain/apperrors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxTaskReminders is the most reminders a task can have.
+	MaxTaskReminders = 5
+	// MaxReminderOffset is the longest a reminder can be sent before the
+	// due date.
+	MaxReminderOffset = 30 * 24 * time.Hour
+	// MaxReminderAttempts is how many times sending a reminder is tried
+	// before it is given up.
+	MaxReminderAttempts = 5
+)
+
+// NotificationTaskReminder is the event of the notifications sent for
+// reminders.
+const NotificationTaskReminder = "task.reminder"
+
+// reminderOffsetPattern matches the ISO 8601 durations reminder offsets are
+// written as, in weeks, days, hours and minutes.
+var reminderOffsetPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?)?$`)
+
+func invalidReminder(message string) error {
+	return apperrors.Validation("invalid reminders", apperrors.FieldError{Field: "reminders", Message: message})
+}
+
+// ParseReminderOffset parses how long before the due date a reminder is sent,
+// as an ISO 8601 duration such as "P1D", "PT2H30M" or "P1W".
+func ParseReminderOffset(offset string) (time.Duration, error) {
+	parts := reminderOffsetPattern.FindStringSubmatch(offset)
+	if parts == nil || offset == "P" || offset[len(offset)-1] == 'T' {
+		return 0, invalidReminder(fmt.Sprintf("%q is not a duration such as P1D, PT2H or PT30M", offset))
+	}
+
+	var duration time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute} {
+		if parts[i+1] == "" {
+			continue
+		}
+		count, err := strconv.Atoi(parts[i+1])
+		if err != nil || time.Duration(count) > MaxReminderOffset/unit {
+			return 0, invalidReminder(fmt.Sprintf("%q is longer than 30 days", offset))
+		}
+		duration += time.Duration(count) * unit
+	}
+
+	if duration <= 0 {
+		return 0, invalidReminder(fmt.Sprintf("%q must be longer than zero", offset))
+	}
+	if duration > MaxReminderOffset {
+		return 0, invalidReminder(fmt.Sprintf("%q is longer than 30 days", offset))
+	}
+
+	return duration, nil
+}
+
+// FormatReminderOffset writes an offset in the canonical form accepted by
+// ParseReminderOffset: whole weeks as weeks, anything else as days, hours and
+// minutes. Seconds are dropped.
+func FormatReminderOffset(offset time.Duration) string {
+	minutes := int64(offset / time.Minute)
+	if minutes%(7*24*60) == 0 {
+		return "P" + strconv.FormatInt(minutes/(7*24*60), 10) + "W"
+	}
+
+	formatted := "P"
+	if days := minutes / (24 * 60); days > 0 {
+		formatted += strconv.FormatInt(days, 10) + "D"
+	}
+	hours, minutes := minutes%(24*60)/60, minutes%60
+	if hours > 0 || minutes > 0 {
+		formatted += "T"
+	}
+	if hours > 0 {
+		formatted += strconv.FormatInt(hours, 10) + "H"
+	}
+	if minutes > 0 {
+		formatted += strconv.FormatInt(minutes, 10) + "M"
+	}
+
+	return formatted
+}
+
+// NormalizeReminders validates the reminder offsets of a task and returns
+// them in canonical form, without repeats, earliest reminder first.
+func NormalizeReminders(offsets []string) ([]string, error) {
+	seen := map[time.Duration]bool{}
+	var durations []time.Duration
+
+	for _, offset := range offsets {
+		duration, err := ParseReminderOffset(offset)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[duration] {
+			seen[duration] = true
+			durations = append(durations, duration)
+		}
+	}
+
+	if len(durations) > MaxTaskReminders {
+		return nil, invalidReminder(fmt.Sprintf("must hold at most %d reminders", MaxTaskReminders))
+	}
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] > durations[j]
+	})
+
+	var normalized []string
+	for _, duration := range durations {
+		normalized = append(normalized, FormatReminderOffset(duration))
+	}
+
+	return normalized, nil
+}
+
+// Reminder is one notification scheduled for a task, sent at RemindAt: the
+// due date of the task less Offset. Reminders are claimed by one scheduler at
+// a time, until ClaimedUntil, and SentAt is set once they have been sent or
+// found to be stale. Attempts counts the claims.
+type Reminder struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID       string             `bson:"taskId"`
+	Offset       string             `bson:"offset"`
+	RemindAt     time.Time          `bson:"remindAt"`
+	ClaimedBy    string             `bson:"claimedBy"`
+	ClaimedUntil *time.Time         `bson:"claimedUntil"`
+	Attempts     int                `bson:"attempts"`
+	SentAt       *time.Time         `bson:"sentAt"`
+}
+
+// TaskReminders returns the reminders to schedule for task at now: one per
+// offset whose time has not passed yet. Tasks that are done or have no due
+// date get none.
+func TaskReminders(task Task, now time.Time) []Reminder {
+	if task.Status == StatusDone || task.DueDate.IsZero() {
+		return nil
+	}
+
+	var reminders []Reminder
+	for _, offset := range task.Reminders {
+		duration, err := ParseReminderOffset(offset)
+		if err != nil {
+			continue
+		}
+
+		remindAt := task.DueDate.Add(-duration)
+		if remindAt.After(now) {
+			reminders = append(reminders, Reminder{
+				ID:       primitive.NewObjectID(),
+				TaskID:   task.ID.Hex(),
+				Offset:   offset,
+				RemindAt: remindAt,
+			})
+		}
+	}
+
+	return reminders
+}
+
+// ReminderRepository stores the reminders scheduled for tasks. Several
+// replicas of the service may share it, so reminders are claimed before they
+// are sent.
+//
+// ReplaceReminders replaces the reminders of a task that were neither sent nor
+// claimed by a scheduler until after now. Reminders kept for the same offset
+// and time are not scheduled again.
+//
+// ClaimDueReminders claims up to limit reminders due at now that are neither
+// sent, claimed by another scheduler until after now, nor tried
+// MaxReminderAttempts times already. A claim lasts for lease, after which the
+// reminder can be claimed again if it still was not marked sent.
+type ReminderRepository interface {
+	ReplaceReminders(ctx context.Context, taskID string, reminders []Reminder, now time.Time) error
+	ClaimDueReminders(ctx context.Context, now time.Time, claimant string, lease time.Duration, limit int) ([]*Reminder, error)
+	MarkReminderSent(ctx context.Context, id string, sentAt time.Time) error
+}
+
+// Notification is a message for one user, such as a reminder that a task is
+// due soon.
+type Notification struct {
+	Event    string    `json:"event"`
+	UserID   string    `json:"user_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email,omitempty"`
+	TaskID   string    `json:"task_id"`
+	Title    string    `json:"title"`
+	DueDate  time.Time `json:"due_date"`
+	Subject  string    `json:"subject"`
+	Message  string    `json:"message"`
+}
+
+// Notifier delivers notifications over one channel, such as email.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
@@ -0,0 +1,107 @@
+package entities_test
+
+import (
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseReminderOffset(t *testing.T) {
+	valid := []struct {
+		offset    string
+		duration  time.Duration
+		canonical string
+	}{
+		{"P1D", 24 * time.Hour, "P1D"},
+		{"PT30M", 30 * time.Minute, "PT30M"},
+		{"PT2H30M", 150 * time.Minute, "PT2H30M"},
+		{"P1DT12H", 36 * time.Hour, "P1DT12H"},
+		{"P1W", 7 * 24 * time.Hour, "P1W"},
+		{"P14D", 14 * 24 * time.Hour, "P2W"},
+		{"PT48H", 48 * time.Hour, "P2D"},
+		{"PT90M", 90 * time.Minute, "PT1H30M"},
+		{"P30D", 30 * 24 * time.Hour, "P30D"},
+	}
+
+	for _, tt := range valid {
+		t.Run(tt.offset, func(t *testing.T) {
+			duration, err := entities.ParseReminderOffset(tt.offset)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.duration, duration)
+			assert.Equal(t, tt.canonical, entities.FormatReminderOffset(duration))
+		})
+	}
+
+	invalid := []string{"", "P", "PT", "P1DT", "1D", "P1H", "PT1D", "P-1D", "PT0M", "P31D", "P5W", "P99999999999999999999D", "p1d"}
+
+	for _, offset := range invalid {
+		t.Run("invalid "+offset, func(t *testing.T) {
+			_, err := entities.ParseReminderOffset(offset)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestNormalizeReminders(t *testing.T) {
+	t.Run("sorts and deduplicates", func(t *testing.T) {
+		reminders, err := entities.NormalizeReminders([]string{"PT30M", "P1D", "PT24H", "P1W"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P1W", "P1D", "PT30M"}, reminders)
+	})
+
+	t.Run("no reminders", func(t *testing.T) {
+		reminders, err := entities.NormalizeReminders(nil)
+
+		require.NoError(t, err)
+		assert.Nil(t, reminders)
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		_, err := entities.NormalizeReminders([]string{"PT10M", "PT20M", "PT30M", "PT40M", "PT50M", "PT60M"})
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+}
+
+func TestTaskReminders(t *testing.T) {
+	now := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	task := entities.Task{
+		ID:        primitive.NewObjectID(),
+		Status:    entities.StatusTodo,
+		DueDate:   now.Add(6 * time.Hour),
+		Reminders: []string{"P1D", "PT2H", "PT30M"},
+	}
+
+	t.Run("skips reminders whose time has passed", func(t *testing.T) {
+		reminders := entities.TaskReminders(task, now)
+
+		require.Len(t, reminders, 2)
+		assert.Equal(t, task.ID.Hex(), reminders[0].TaskID)
+		assert.Equal(t, "PT2H", reminders[0].Offset)
+		assert.Equal(t, now.Add(4*time.Hour), reminders[0].RemindAt)
+		assert.Equal(t, "PT30M", reminders[1].Offset)
+		assert.Equal(t, now.Add(330*time.Minute), reminders[1].RemindAt)
+	})
+
+	t.Run("done tasks", func(t *testing.T) {
+		doneTask := task
+		doneTask.Status = entities.StatusDone
+
+		assert.Empty(t, entities.TaskReminders(doneTask, now))
+	})
+
+	t.Run("tasks without a due date", func(t *testing.T) {
+		undatedTask := task
+		undatedTask.DueDate = time.Time{}
+
+		assert.Empty(t, entities.TaskReminders(undatedTask, now))
+	})
+}
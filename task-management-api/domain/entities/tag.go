@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"context"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/model"
+)
+
+// MaxTagLength is the longest tag accepted, in characters.
+const MaxTagLength = 50
+
+// A task query with several tags matches tasks carrying any or all of them.
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+var (
+	ErrTagNotFound     = apperrors.NotFound("tag not found")
+	ErrInvalidTagName  = apperrors.Validation("invalid tag", apperrors.FieldError{Field: "name", Message: "must be 1 to 50 characters long"})
+	ErrInvalidTagMatch = apperrors.Validation("invalid tag_match", apperrors.FieldError{Field: "tag_match", Message: "must be one of any, all"})
+)
+
+// TagRepository manages the tags of a user's tasks. Tags only exist on tasks,
+// so renaming and deleting a tag change every task carrying it, including
+// tasks in the trash, and return the tasks changed as they were before. GetTags
+// counts only tasks outside the trash.
+type TagRepository interface {
+	GetTags(ctx context.Context, userID string) ([]*model.TagInfo, error)
+	RenameTag(ctx context.Context, userID string, name string, newName string) ([]*Task, error)
+	DeleteTag(ctx context.Context, userID string, name string) ([]*Task, error)
+}
+
+type TagUsecase interface {
+	GetTags(ctx context.Context, userID string) ([]*model.TagInfo, error)
+	RenameTag(ctx context.Context, userID string, name string, newName string) (int64, error)
+	DeleteTag(ctx context.Context, userID string, name string) (int64, error)
+}
@@ -2,33 +2,342 @@ package entities
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/model"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusDone       = "done"
+)
+
+var (
+	ErrInvalidStatus           = apperrors.Validation("invalid status", apperrors.FieldError{Field: "status", Message: "must be one of todo, in_progress, done"})
+	ErrInvalidStatusTransition = apperrors.Conflict("invalid status transition")
+	ErrInvalidPriority         = apperrors.Validation("invalid priority", apperrors.FieldError{Field: "priority", Message: "must be one of low, medium, high"})
+	ErrInvalidTaskSort         = apperrors.Validation("invalid sort", apperrors.FieldError{Field: "sort", Message: "must be one of due_date, priority, created_at, optionally prefixed with -"})
+	ErrInvalidTaskCursor       = apperrors.Validation("invalid cursor", apperrors.FieldError{Field: "cursor", Message: "must be a cursor returned by a previous page"})
+	ErrTaskModified            = apperrors.PreconditionFailed("task was modified since it was read")
+	ErrTaskTitleRequired       = apperrors.Validation("invalid task", apperrors.FieldError{Field: "title", Message: "is required"})
+	ErrTaskCycle               = apperrors.Conflict("link would create a cycle")
+	ErrTaskBlocked             = apperrors.Conflict("task is blocked by open tasks")
+	ErrTaskParentNotFound      = apperrors.NotFound("parent task not found")
+	ErrTaskBlockerNotFound     = apperrors.NotFound("blocker not found")
+	// ErrTaskNotFound is also returned for tasks the user has no role on, so
+	// the two cases cannot be told apart.
+	ErrTaskNotFound            = apperrors.NotFound("task not found")
+)
+
+// Task fields an update can change, named as in JSON. Changing the status
+// also changes completed_at, and changing the recurrence also changes
+// series_id and occurrence.
+const (
+	TaskFieldTitle         = "title"
+	TaskFieldDescription   = "description"
+	TaskFieldStatus        = "status"
+	TaskFieldPriority      = "priority"
+	TaskFieldTags          = "tags"
+	TaskFieldDueDate       = "due_date"
+	TaskFieldParentID      = "parent_id"
+	TaskFieldBlockedBy     = "blocked_by"
+	TaskFieldCollaborators = "collaborators"
+	TaskFieldAssigneeID    = "assignee_id"
+	TaskFieldProjectID     = "project_id"
+	TaskFieldRecurrence    = "recurrence"
+	TaskFieldReminders     = "reminders"
+)
+
+// AnyTaskVersion is passed instead of a task version to update or delete the
+// task whatever its current version is.
+const AnyTaskVersion int64 = -1
+
+// Tasks can be sorted by any of these keys. Prefixing a key with "-" sorts in
+// descending order.
+const (
+	TaskSortDueDate   = "due_date"
+	TaskSortPriority  = "priority"
+	TaskSortCreatedAt = "created_at"
+
+	DefaultTaskSort = "-" + TaskSortCreatedAt
+)
+
+// ParseTaskSort splits a sort parameter into its key and direction.
+func ParseTaskSort(sort string) (key string, descending bool, err error) {
+	if sort == "" {
+		sort = DefaultTaskSort
+	}
+
+	key = strings.TrimPrefix(sort, "-")
+	descending = key != sort
+
+	switch key {
+	case TaskSortDueDate, TaskSortPriority, TaskSortCreatedAt:
+		return key, descending, nil
+	default:
+		return "", false, ErrInvalidTaskSort
+	}
+}
+
+// taskStatusTransitions lists the statuses a task may move to from each
+// status. Done tasks can be reopened.
+var taskStatusTransitions = map[string][]string{
+	StatusTodo:       {StatusInProgress},
+	StatusInProgress: {StatusTodo, StatusDone},
+	StatusDone:       {StatusTodo, StatusInProgress},
+}
+
+func IsValidTaskStatus(status string) bool {
+	_, ok := taskStatusTransitions[status]
+	return ok
+}
+
+// CanTransitionTask reports whether a task may move from one status to
+// another. Tasks stored with a status outside the workflow are treated as todo.
+func CanTransitionTask(from string, to string) bool {
+	if !IsValidTaskStatus(from) {
+		from = StatusTodo
+	}
+
+	if from == to {
+		return true
+	}
+
+	for _, allowed := range taskStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Priority is stored as a number so tasks can be sorted by it and is exposed
+// as its name in JSON.
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityMedium
+	PriorityHigh
+)
+
+var priorityNames = map[Priority]string{
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+}
+
+func ParsePriority(name string) (Priority, error) {
+	if name == "" {
+		return 0, nil
+	}
+
+	for priority, priorityName := range priorityNames {
+		if strings.EqualFold(priorityName, name) {
+			return priority, nil
+		}
+	}
+
+	return 0, ErrInvalidPriority
+}
+
+func (p Priority) IsValid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	priority, err := ParsePriority(name)
+	if err != nil {
+		return err
+	}
+
+	*p = priority
+	return nil
+}
+
 type Task struct {
     ID          primitive.ObjectID `bson:"_id,omitempty"`
 	UserID 		string `json:"user_id"`
-    UserName    string `json:"username"`
-    Password    string `json:"password"`
     Title       string `json:"title"`
     Description string `json:"description"`
     Status      string `json:"status"`
+	Priority    Priority   `json:"priority" bson:"priority"`
+	Tags        []string   `json:"tags" bson:"tags"`
+	DueDate     time.Time  `json:"due_date" bson:"dueDate"`
+	CreatedAt   time.Time  `json:"created_at" bson:"createdAt"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updatedAt"`
+	CompletedAt *time.Time `json:"completed_at" bson:"completedAt"`
+	// Version is incremented on every update. Tasks stored before versions
+	// were introduced read as version 0.
+	Version     int64      `json:"version" bson:"version"`
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt   *time.Time `json:"deleted_at" bson:"deletedAt"`
+	// ParentID is the ID of the task this one is a subtask of, and BlockedBy
+	// the IDs of the tasks that must be done before this one. Both refer to
+	// tasks of the same user.
+	ParentID    string     `json:"parent_id" bson:"parentId"`
+	BlockedBy   []string   `json:"blocked_by" bson:"blockedBy"`
+	// UserID above is the owner of the task. Collaborators are the other
+	// users it is shared with, and AssigneeID is the owner or one of them.
+	Collaborators []Collaborator `json:"collaborators" bson:"collaborators"`
+	AssigneeID    string         `json:"assignee_id" bson:"assigneeId"`
+	// ProjectID is the project the task belongs to, if any. Members of the
+	// project get a role on the task, see Project.TaskRoleOf.
+	ProjectID     string         `json:"project_id" bson:"projectId"`
+	// Recurrence is the canonical form of the recurrence rule of the task, see
+	// Recurrence. The occurrences of a recurring task share a SeriesID, the ID
+	// of the first one, and are numbered from 1 by Occurrence.
+	Recurrence    string         `json:"recurrence" bson:"recurrence"`
+	SeriesID      string         `json:"series_id" bson:"seriesId"`
+	Occurrence    int            `json:"occurrence" bson:"occurrence"`
+	// Reminders are how long before the due date reminders are sent, in the
+	// canonical form of FormatReminderOffset, earliest reminder first.
+	Reminders     []string       `json:"reminders" bson:"reminders"`
+}
+
+// Info converts the stored task into the representation returned to clients.
+func (t *Task) Info() *model.TaskInfo {
+	info := &model.TaskInfo{
+		ID:          t.ID.Hex(),
+		OwnerID:     t.UserID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		Priority:    t.Priority.String(),
+		Tags:        t.Tags,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		CompletedAt: t.CompletedAt,
+		Version:     t.Version,
+		DeletedAt:   t.DeletedAt,
+		ParentID:    t.ParentID,
+		BlockedBy:   t.BlockedBy,
+		AssigneeID:  t.AssigneeID,
+		ProjectID:   t.ProjectID,
+		Recurrence:  t.Recurrence,
+		SeriesID:    t.SeriesID,
+		Occurrence:  t.Occurrence,
+		Reminders:   t.Reminders,
+	}
+
+	for _, collaborator := range t.Collaborators {
+		info.Collaborators = append(info.Collaborators, model.Collaborator{UserID: collaborator.UserID, Role: collaborator.Role})
+	}
+
+	if !t.DueDate.IsZero() {
+		dueDate := t.DueDate
+		info.DueDate = &dueDate
+	}
+
+	return info
 }
 
+// TaskRepository reads and writes tasks by ID whoever they belong to; the
+// usecase checks the role of the user first. GetTasks lists the tasks a user
+// owns, collaborates on or is assigned, or all the tasks of query.ProjectID
+// when it is set, while the trash only holds the tasks a user owns.
+//
+// TaskRepository updates and deletes tasks only while they are still at the
+// given version, failing with ErrTaskModified otherwise. UpdateTask writes the
+// listed fields of updatedTask, reads the expected version from
+// updatedTask.Version and increments it.
+//
+// DeleteTask moves a task to the trash. Tasks in the trash are left out of
+// every other method until RestoreTask takes them back out, and are removed
+// for good by PurgeDeletedTasks, which also unlinks them from the remaining
+// tasks. GetTaskIncludingDeleted reads a task whether or not it is in the
+// trash, for following links that come back when a task is restored.
+//
+// GetSubtasks returns the tasks whose parent is parentID, oldest first, and
+// GetSeriesTasks the occurrences of a recurring task in order.
 type TaskRepository interface {
-	GetTasks(ctx context.Context, userID string) ([]*model.TaskInfo, error)
-	GetTaskByID(ctx context.Context, id string, userID string) (*Task, error)
-	UpdateTask(ctx context.Context, id string, updatedTask Task, userID string) error
-	DeleteTask(ctx context.Context, id string, userID string) error
+	GetTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error)
+	GetTaskByID(ctx context.Context, id string) (*Task, error)
+	GetTaskIncludingDeleted(ctx context.Context, id string) (*Task, error)
+	UpdateTask(ctx context.Context, id string, updatedTask Task, fields []string) error
+	DeleteTask(ctx context.Context, id string, version int64) error
 	CreateTask(ctx context.Context, newTask Task) error
+	GetDeletedTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error)
+	RestoreTask(ctx context.Context, id string, userID string) error
+	PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) (int64, error)
+	GetSubtasks(ctx context.Context, parentID string) ([]*Task, error)
+	GetSeriesTasks(ctx context.Context, seriesID string) ([]*Task, error)
 }
 
+// TaskUsecase takes the version of the task the client last read, or
+// AnyTaskVersion, to update and delete it. Every method checks the role of the
+// user on the task: tasks the user has no role on fail with ErrTaskNotFound,
+// and changes their role does not allow with ErrTaskForbidden.
+//
+// ShareTask gives the user with the given username a role on a task, or
+// changes it, and UnshareTask takes it away again, unassigning them.
+// SetTaskAssignee assigns a task to its owner or a collaborator, or unassigns
+// it when username is empty. GetAssignedTasks lists the tasks assigned to the
+// user.
+//
+// CreateTask adds the task to newTask.ProjectID if it is set. MoveTask moves a
+// task the user owns into a project, out of the one it was in, and
+// RemoveTaskFromProject makes it a task of its owner alone again. Neither
+// works with archived projects. GetProjectTasks lists the tasks of a project.
+// Like the ProjectUsecase, these expect the caller to have checked the role of
+// the user in the project.
+//
+// SetTaskParent makes a task a subtask of parentID, or a top-level task when
+// parentID is empty. AddTaskBlocker and RemoveTaskBlocker change the tasks a
+// task is blocked by. Links that would make a task its own ancestor or its own
+// blocker fail with ErrTaskCycle, and a task cannot be marked done while a
+// task it is blocked by is open. GetTaskTree returns a task with its subtasks
+// nested and the progress of each rolled up from its subtasks.
+//
+// A task with a recurrence rule needs a due date. When UpdateTask marks the
+// latest occurrence of a series done, the next occurrence is created as a copy
+// of it, due at the next date of the rule. GetTaskSeries lists the
+// occurrences of a series the user can read, and UpdateTaskSeries applies a
+// patch to those that are not done; a null recurrence stops the series.
+//
+// Every change to the due date, status or reminder offsets of a task
+// schedules its reminders again in the ReminderRepository. Tasks that are
+// done, have no due date or are in the trash have no pending reminders.
 type TaskUsecase interface {
-	GetTasks(userID string) ([]*model.TaskInfo, error)
-	GetTaskByID(id string, userID string) (*model.TaskInfo, error)
-	UpdateTask(id string, updatedTask Task, userID string) error
-	DeleteTask(id string, userID string) error
-	CreateTask(newTask Task) error
-}
\ No newline at end of file
+	GetTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error)
+	GetTaskByID(ctx context.Context, id string, userID string) (*model.TaskInfo, error)
+	UpdateTask(ctx context.Context, id string, patch model.TaskPatch, userID string, version int64) (*model.TaskInfo, error)
+	DeleteTask(ctx context.Context, id string, userID string, version int64) error
+	CreateTask(ctx context.Context, newTask Task) error
+	GetTrash(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error)
+	RestoreTask(ctx context.Context, id string, userID string) (*model.TaskInfo, error)
+	GetTaskTree(ctx context.Context, id string, userID string) (*model.TaskInfo, error)
+	SetTaskParent(ctx context.Context, id string, parentID string, userID string) (*model.TaskInfo, error)
+	AddTaskBlocker(ctx context.Context, id string, blockerID string, userID string) (*model.TaskInfo, error)
+	RemoveTaskBlocker(ctx context.Context, id string, blockerID string, userID string) (*model.TaskInfo, error)
+	GetTaskActivity(ctx context.Context, id string, userID string, query model.PageQuery) (*model.ActivityPage, error)
+	ShareTask(ctx context.Context, id string, username string, role string, userID string) (*model.TaskInfo, error)
+	UnshareTask(ctx context.Context, id string, username string, userID string) (*model.TaskInfo, error)
+	SetTaskAssignee(ctx context.Context, id string, username string, userID string) (*model.TaskInfo, error)
+	GetAssignedTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error)
+	GetProjectTasks(ctx context.Context, projectID string, query model.TaskQuery) (*model.TaskPage, error)
+	MoveTask(ctx context.Context, id string, projectID string, userID string) (*model.TaskInfo, error)
+	RemoveTaskFromProject(ctx context.Context, id string, projectID string, userID string) (*model.TaskInfo, error)
+	GetTaskSeries(ctx context.Context, seriesID string, userID string) ([]*model.TaskInfo, error)
+	UpdateTaskSeries(ctx context.Context, seriesID string, patch model.SeriesPatch, userID string) ([]*model.TaskInfo, error)
+}
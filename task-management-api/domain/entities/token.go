@@ -1,9 +1,64 @@
 package entities
 
-import "github.com/golang-jwt/jwt"
+import (
+	"context"
+	"time"
 
+	"github.com/golang-jwt/jwt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Claims carries the access token payload. The token ID is stored in the
+// standard "jti" claim and is what revocation is keyed by.
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
 	jwt.StandardClaims
-	
-}
\ No newline at end of file
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// RefreshToken is the server side record of an opaque refresh token. Only the
+// SHA-256 hash of the token is stored. Tokens obtained from the same login
+// share a FamilyID so that the whole chain can be revoked when a rotated token
+// is presented again.
+type RefreshToken struct {
+	ID                   primitive.ObjectID `bson:"_id,omitempty"`
+	UserID               string             `bson:"userid"`
+	TokenHash            string             `bson:"tokenHash"`
+	FamilyID             string             `bson:"familyId"`
+	AccessTokenID        string             `bson:"accessTokenId"`
+	AccessTokenExpiresAt time.Time          `bson:"accessTokenExpiresAt"`
+	ExpiresAt            time.Time          `bson:"expiresAt"`
+	CreatedAt            time.Time          `bson:"createdAt"`
+	RevokedAt            *time.Time         `bson:"revokedAt"`
+}
+
+// RevokedToken marks an access token, identified by its jti, as no longer
+// valid until it expires.
+type RevokedToken struct {
+	ID        string    `bson:"_id"`
+	UserID    string    `bson:"userid"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	GetRefreshTokenFamily(ctx context.Context, familyID string) ([]*RefreshToken, error)
+	GetActiveRefreshTokens(ctx context.Context, userID string) ([]*RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id string) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID string) error
+}
+
+type RevokedTokenRepository interface {
+	RevokeToken(ctx context.Context, token RevokedToken) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
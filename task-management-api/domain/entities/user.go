@@ -2,21 +2,49 @@ package entities
 
 import (
 	"context"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/model"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var (
+	ErrUsernameRequired = apperrors.Validation("invalid user", apperrors.FieldError{Field: "username", Message: "is required"})
+	ErrPasswordRequired = apperrors.Validation("invalid user", apperrors.FieldError{Field: "password", Message: "is required"})
+	// ErrUserForbidden is returned when a user changes an account other than
+	// their own without being an administrator.
+	ErrUserForbidden = apperrors.Forbidden("you can only change your own account")
+	// ErrUsernameTaken is returned when a user registers or renames to a
+	// username another account has.
+	ErrUsernameTaken = apperrors.Conflict("username already exists")
+)
+
+// User fields an update can change, named as in JSON.
+const (
+	UserFieldUsername = "username"
+	UserFieldPassword = "password"
+	UserFieldEmail    = "email"
+	UserFieldName     = "name"
+	UserFieldBio      = "bio"
+	UserFieldRole     = "role"
+)
+
 type User struct {
 	ID              primitive.ObjectID `bson:"_id,omitempty"`
 	UserName string `json:"username"`
-	Password string `json:"password"`
+	Password string `json:"-"`
+	Email    string `json:"email,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Bio      string `json:"bio,omitempty"`
+	Role     string `json:"role"`
 }
 
+// UserRepository.UpdateUser writes the listed fields of updatedUser.
 type UserRepository interface {
 	GetUser(ctx context.Context, param string) ([]*User, error)
 	GetUserByID(ctx context.Context, id string) (*User, error)
-	UpdateUser(ctx context.Context, id string, updatedUser User) error
+	UpdateUser(ctx context.Context, id string, updatedUser User, fields []string) error
+	UpdatePassword(ctx context.Context, id string, hashedPassword string) error
 	DeleteUser(ctx context.Context, id string) error
 	CreateUser(ctx context.Context, newUser model.UserCreate) (*model.UserInfo, error)
 	GetUserByUsername(ctx context.Context, username string) (*User, error)
@@ -25,7 +53,7 @@ type UserRepository interface {
 type UserUsecase interface {
 	GetUsers(ctx context.Context, param string) ([]*User, error)
 	GetUserByID(ctx context.Context, id string) (*User, error)
-	UpdateUser(ctx context.Context, id string, updatedUser User) error
+	UpdateUser(ctx context.Context, currUser AuthenticatedUser, id string, patch model.UserPatch) (*User, error)
 	DeleteUser(ctx context.Context, id string) error
 	CreateUser(ctx context.Context, newUser model.UserCreate) error
 }
@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Events sent to webhooks. A task moved to the trash is deleted, and one
+// restored from it updated. Marking a task done sends both task.updated and
+// task.completed. webhook.test is only sent by WebhookUsecase.TestWebhook.
+const (
+	EventTaskCreated   = "task.created"
+	EventTaskUpdated   = "task.updated"
+	EventTaskDeleted   = "task.deleted"
+	EventTaskCompleted = "task.completed"
+	EventWebhookTest   = "webhook.test"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to.
+var WebhookEvents = []string{EventTaskCreated, EventTaskUpdated, EventTaskDeleted, EventTaskCompleted}
+
+// IsValidWebhookEvent reports whether a webhook can subscribe to event.
+func IsValidWebhookEvent(event string) bool {
+	for _, valid := range WebhookEvents {
+		if event == valid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaxWebhooks is the most webhooks a user can register.
+const MaxWebhooks = 10
+
+// States of a webhook delivery. A pending delivery is tried again at its
+// NextAttemptAt, until it succeeds or MaxWebhookAttempts have failed.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+const (
+	// MaxWebhookAttempts is how many times a delivery is tried before it is
+	// given up on.
+	MaxWebhookAttempts = 8
+	// webhookRetryDelay is the wait after the first failed attempt. It
+	// doubles after every further one.
+	webhookRetryDelay = 30 * time.Second
+)
+
+// WebhookRetryDelay returns how long to wait before trying a delivery again
+// after its attempts-th attempt failed: 30 seconds, then 1, 2, 4 minutes and
+// so on.
+func WebhookRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return webhookRetryDelay << (attempts - 1)
+}
+
+var (
+	// ErrWebhookNotFound is also returned for webhooks of other users, so the
+	// two cases cannot be told apart.
+	ErrWebhookNotFound     = apperrors.NotFound("webhook not found")
+	ErrTooManyWebhooks     = apperrors.Conflict("you cannot register more than 10 webhooks")
+	ErrInvalidWebhookURL   = apperrors.Validation("invalid url", apperrors.FieldError{Field: "url", Message: "must be an http or https URL"})
+	ErrWebhookURLNotPublic = apperrors.Validation("invalid url", apperrors.FieldError{Field: "url", Message: "must resolve to a public address"})
+	ErrInvalidWebhookEvent = apperrors.Validation("invalid events", apperrors.FieldError{Field: "events", Message: "must be among task.created, task.updated, task.deleted, task.completed"})
+)
+
+// IsValidWebhookURL reports whether deliveries can be posted to rawURL.
+func IsValidWebhookURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// Webhook is a URL a user registered to be told about task events. Events are
+// sent for the tasks the user can read at the time, signed with Secret.
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    string             `bson:"userId"`
+	URL       string             `bson:"url"`
+	Events    []string           `bson:"events"`
+	Secret    string             `bson:"secret"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// Subscribes reports whether the webhook is sent event.
+func (w *Webhook) Subscribes(event string) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Info leaves the secret out, as it is only shown when the webhook is
+// created.
+func (w *Webhook) Info() *model.WebhookInfo {
+	return &model.WebhookInfo{
+		ID:        w.ID.Hex(),
+		URL:       w.URL,
+		Events:    append([]string{}, w.Events...),
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+// WebhookDelivery is one event to post to a webhook, and the outcome of its
+// latest attempt. Payload holds the JSON body, so that every attempt sends the
+// same bytes.
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	WebhookID      string             `bson:"webhookId"`
+	Event          string             `bson:"event"`
+	Payload        string             `bson:"payload"`
+	Status         string             `bson:"status"`
+	Attempts       int                `bson:"attempts"`
+	NextAttemptAt  time.Time          `bson:"nextAttemptAt"`
+	LastAttemptAt  *time.Time         `bson:"lastAttemptAt"`
+	ResponseStatus int                `bson:"responseStatus"`
+	Error          string             `bson:"error"`
+	CreatedAt      time.Time          `bson:"createdAt"`
+}
+
+// RecordAttempt sets the outcome of an attempt made at now, which answered
+// responseStatus, or 0 without an answer. A failed delivery is tried again
+// after WebhookRetryDelay, unless it is out of attempts.
+func (d *WebhookDelivery) RecordAttempt(now time.Time, responseStatus int, err error) {
+	d.LastAttemptAt = &now
+	d.ResponseStatus = responseStatus
+	d.Error = ""
+
+	switch {
+	case err == nil:
+		d.Status = WebhookDeliverySucceeded
+	case d.Attempts >= MaxWebhookAttempts:
+		d.Status = WebhookDeliveryFailed
+		d.Error = err.Error()
+	default:
+		d.Status = WebhookDeliveryPending
+		d.Error = err.Error()
+		d.NextAttemptAt = now.Add(WebhookRetryDelay(d.Attempts))
+	}
+}
+
+func (d *WebhookDelivery) Info() *model.WebhookDeliveryInfo {
+	info := &model.WebhookDeliveryInfo{
+		ID:             d.ID.Hex(),
+		WebhookID:      d.WebhookID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == WebhookDeliveryPending {
+		nextAttemptAt := d.NextAttemptAt
+		info.NextAttemptAt = &nextAttemptAt
+	}
+
+	return info
+}
+
+// WebhookRepository stores webhooks and the log of their deliveries.
+// GetWebhooks returns the webhooks of a user, oldest first, and
+// GetEventWebhooks those of the given users subscribed to event. DeleteWebhook
+// also deletes its deliveries. GetDeliveries returns the newest deliveries
+// first.
+//
+// ClaimDueDeliveries claims up to limit pending deliveries due at now, oldest
+// first, by counting an attempt and postponing the next one by lease. A
+// delivery is claimed by a single caller, even when several claim at once, and
+// is claimed again once the lease ends unless UpdateDelivery has recorded the
+// outcome of the attempt by then.
+type WebhookRepository interface {
+	GetWebhooks(ctx context.Context, userID string) ([]*Webhook, error)
+	GetWebhookByID(ctx context.Context, id string) (*Webhook, error)
+	GetEventWebhooks(ctx context.Context, event string, userIDs []string) ([]*Webhook, error)
+	CreateWebhook(ctx context.Context, webhook Webhook) error
+	DeleteWebhook(ctx context.Context, id string) error
+	AddDeliveries(ctx context.Context, deliveries []WebhookDelivery) error
+	GetDeliveries(ctx context.Context, webhookID string, query model.PageQuery) (*model.WebhookDeliveryPage, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery WebhookDelivery) error
+}
+
+// WebhookSender posts a delivery to the URL of a webhook, signed with its
+// secret. It returns the status the receiver answered with, or 0 when it did
+// not answer; any status but 2xx is an error. CheckURL fails for URLs whose
+// host the sender refuses to post to, such as addresses of the internal
+// network.
+type WebhookSender interface {
+	Send(ctx context.Context, webhook Webhook, delivery WebhookDelivery) (int, error)
+	CheckURL(ctx context.Context, rawURL string) error
+}
+
+// WebhookUsecase manages the webhooks of a user, who can only see and change
+// their own. CreateWebhook returns the new webhook with its secret.
+// TestWebhook sends a webhook.test event to a webhook right away, once, and
+// returns the logged delivery whether or not it succeeded.
+type WebhookUsecase interface {
+	GetWebhooks(ctx context.Context, userID string) ([]*model.WebhookInfo, error)
+	GetWebhookByID(ctx context.Context, id string, userID string) (*model.WebhookInfo, error)
+	CreateWebhook(ctx context.Context, url string, events []string, userID string) (*model.WebhookInfo, error)
+	DeleteWebhook(ctx context.Context, id string, userID string) error
+	GetDeliveries(ctx context.Context, id string, userID string, query model.PageQuery) (*model.WebhookDeliveryPage, error)
+	TestWebhook(ctx context.Context, id string, userID string) (*model.WebhookDeliveryInfo, error)
+}
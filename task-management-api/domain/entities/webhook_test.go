@@ -0,0 +1,63 @@
+package entities_test
+
+import (
+	"errors"
+	"task-management-api/domain/entities"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, entities.WebhookRetryDelay(1))
+	assert.Equal(t, time.Minute, entities.WebhookRetryDelay(2))
+	assert.Equal(t, 4*time.Minute, entities.WebhookRetryDelay(4))
+	assert.Equal(t, 30*time.Second, entities.WebhookRetryDelay(0))
+}
+
+func TestWebhookDeliveryRecordAttempt(t *testing.T) {
+	now := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+
+	t.Run("succeeded", func(t *testing.T) {
+		delivery := entities.WebhookDelivery{Status: entities.WebhookDeliveryPending, Attempts: 2, Error: "webhook answered 500 Internal Server Error"}
+
+		delivery.RecordAttempt(now, 204, nil)
+
+		assert.Equal(t, entities.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 204, delivery.ResponseStatus)
+		assert.Empty(t, delivery.Error)
+		assert.Equal(t, &now, delivery.LastAttemptAt)
+		assert.Nil(t, delivery.Info().NextAttemptAt)
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		delivery := entities.WebhookDelivery{Status: entities.WebhookDeliveryPending, Attempts: 3}
+
+		delivery.RecordAttempt(now, 503, errors.New("webhook answered 503 Service Unavailable"))
+
+		assert.Equal(t, entities.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, 503, delivery.ResponseStatus)
+		assert.Equal(t, "webhook answered 503 Service Unavailable", delivery.Error)
+		assert.Equal(t, now.Add(2*time.Minute), delivery.NextAttemptAt)
+		assert.Equal(t, now.Add(2*time.Minute), *delivery.Info().NextAttemptAt)
+	})
+
+	t.Run("given up after the last attempt", func(t *testing.T) {
+		delivery := entities.WebhookDelivery{Status: entities.WebhookDeliveryPending, Attempts: entities.MaxWebhookAttempts}
+
+		delivery.RecordAttempt(now, 0, errors.New("connection refused"))
+
+		assert.Equal(t, entities.WebhookDeliveryFailed, delivery.Status)
+		assert.Equal(t, 0, delivery.ResponseStatus)
+		assert.Equal(t, "connection refused", delivery.Error)
+	})
+}
+
+func TestIsValidWebhookURL(t *testing.T) {
+	assert.True(t, entities.IsValidWebhookURL("https://hooks.example.com/tasks"))
+	assert.True(t, entities.IsValidWebhookURL("http://localhost:9000"))
+	assert.False(t, entities.IsValidWebhookURL("ftp://hooks.example.com"))
+	assert.False(t, entities.IsValidWebhookURL("hooks.example.com/tasks"))
+	assert.False(t, entities.IsValidWebhookURL("https://"))
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	entities "task-management-api/domain/entities"
 
 	mock "github.com/stretchr/testify/mock"
@@ -15,9 +16,9 @@ type AuthUseCase struct {
 	mock.Mock
 }
 
-// AdminRegister provides a mock function with given fields: currUser, userCreate, param
-func (_m *AuthUseCase) AdminRegister(currUser entities.AuthenticatedUser, userCreate *model.UserCreate, param interface{}) (*model.UserInfo, error) {
-	ret := _m.Called(currUser, userCreate, param)
+// AdminRegister provides a mock function with given fields: ctx, currUser, userCreate, param
+func (_m *AuthUseCase) AdminRegister(ctx context.Context, currUser entities.AuthenticatedUser, userCreate *model.UserCreate, param interface{}) (*model.UserInfo, error) {
+	ret := _m.Called(ctx, currUser, userCreate, param)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AdminRegister")
@@ -25,19 +26,19 @@ func (_m *AuthUseCase) AdminRegister(currUser entities.AuthenticatedUser, userCr
 
 	var r0 *model.UserInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(entities.AuthenticatedUser, *model.UserCreate, interface{}) (*model.UserInfo, error)); ok {
-		return rf(currUser, userCreate, param)
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AuthenticatedUser, *model.UserCreate, interface{}) (*model.UserInfo, error)); ok {
+		return rf(ctx, currUser, userCreate, param)
 	}
-	if rf, ok := ret.Get(0).(func(entities.AuthenticatedUser, *model.UserCreate, interface{}) *model.UserInfo); ok {
-		r0 = rf(currUser, userCreate, param)
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AuthenticatedUser, *model.UserCreate, interface{}) *model.UserInfo); ok {
+		r0 = rf(ctx, currUser, userCreate, param)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.UserInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(entities.AuthenticatedUser, *model.UserCreate, interface{}) error); ok {
-		r1 = rf(currUser, userCreate, param)
+	if rf, ok := ret.Get(1).(func(context.Context, entities.AuthenticatedUser, *model.UserCreate, interface{}) error); ok {
+		r1 = rf(ctx, currUser, userCreate, param)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -45,27 +46,95 @@ func (_m *AuthUseCase) AdminRegister(currUser entities.AuthenticatedUser, userCr
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: userLogin
-func (_m *AuthUseCase) Login(userLogin *model.UserLogin) (string, error) {
-	ret := _m.Called(userLogin)
+// Login provides a mock function with given fields: ctx, userLogin
+func (_m *AuthUseCase) Login(ctx context.Context, userLogin *model.UserLogin) (*entities.TokenPair, error) {
+	ret := _m.Called(ctx, userLogin)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 string
+	var r0 *entities.TokenPair
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.UserLogin) (string, error)); ok {
-		return rf(userLogin)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserLogin) (*entities.TokenPair, error)); ok {
+		return rf(ctx, userLogin)
 	}
-	if rf, ok := ret.Get(0).(func(*model.UserLogin) string); ok {
-		r0 = rf(userLogin)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserLogin) *entities.TokenPair); ok {
+		r0 = rf(ctx, userLogin)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.TokenPair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.UserLogin) error); ok {
+		r1 = rf(ctx, userLogin)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, currUser, refreshToken
+func (_m *AuthUseCase) Logout(ctx context.Context, currUser entities.AuthenticatedUser, refreshToken string) error {
+	ret := _m.Called(ctx, currUser, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AuthenticatedUser, string) error); ok {
+		r0 = rf(ctx, currUser, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LogoutAll provides a mock function with given fields: ctx, currUser
+func (_m *AuthUseCase) LogoutAll(ctx context.Context, currUser entities.AuthenticatedUser) error {
+	ret := _m.Called(ctx, currUser)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogoutAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AuthenticatedUser) error); ok {
+		r0 = rf(ctx, currUser)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Refresh provides a mock function with given fields: ctx, refreshToken
+func (_m *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (*entities.TokenPair, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 *entities.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entities.TokenPair, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entities.TokenPair); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.TokenPair)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.UserLogin) error); ok {
-		r1 = rf(userLogin)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -73,9 +142,9 @@ func (_m *AuthUseCase) Login(userLogin *model.UserLogin) (string, error) {
 	return r0, r1
 }
 
-// Register provides a mock function with given fields: userCreate
-func (_m *AuthUseCase) Register(userCreate *model.UserCreate) (*model.UserInfo, error) {
-	ret := _m.Called(userCreate)
+// Register provides a mock function with given fields: ctx, userCreate
+func (_m *AuthUseCase) Register(ctx context.Context, userCreate *model.UserCreate) (*model.UserInfo, error) {
+	ret := _m.Called(ctx, userCreate)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Register")
@@ -83,19 +152,19 @@ func (_m *AuthUseCase) Register(userCreate *model.UserCreate) (*model.UserInfo,
 
 	var r0 *model.UserInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.UserCreate) (*model.UserInfo, error)); ok {
-		return rf(userCreate)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserCreate) (*model.UserInfo, error)); ok {
+		return rf(ctx, userCreate)
 	}
-	if rf, ok := ret.Get(0).(func(*model.UserCreate) *model.UserInfo); ok {
-		r0 = rf(userCreate)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserCreate) *model.UserInfo); ok {
+		r0 = rf(ctx, userCreate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.UserInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.UserCreate) error); ok {
-		r1 = rf(userCreate)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.UserCreate) error); ok {
+		r1 = rf(ctx, userCreate)
 	} else {
 		r1 = ret.Error(1)
 	}
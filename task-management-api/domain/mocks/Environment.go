@@ -2,13 +2,93 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	entities "task-management-api/domain/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // Environment is an autogenerated mock type for the Environment type
 type Environment struct {
 	mock.Mock
 }
 
+// GetAuthRateLimit provides a mock function with given fields:
+func (_m *Environment) GetAuthRateLimit() entities.RateLimit {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuthRateLimit")
+	}
+
+	var r0 entities.RateLimit
+	if rf, ok := ret.Get(0).(func() entities.RateLimit); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(entities.RateLimit)
+	}
+
+	return r0
+}
+
+// GetBcryptCost provides a mock function with given fields:
+func (_m *Environment) GetBcryptCost() int {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBcryptCost")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// GetCorsAllowedOrigins provides a mock function with given fields:
+func (_m *Environment) GetCorsAllowedOrigins() []string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCorsAllowedOrigins")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GetDbDriver provides a mock function with given fields:
+func (_m *Environment) GetDbDriver() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDbDriver")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // GetDbName provides a mock function with given fields:
 func (_m *Environment) GetDbName() string {
 	ret := _m.Called()
@@ -45,6 +125,24 @@ func (_m *Environment) GetDbURL() string {
 	return r0
 }
 
+// GetIdleTimeout provides a mock function with given fields:
+func (_m *Environment) GetIdleTimeout() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdleTimeout")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
 // GetJwtKey provides a mock function with given fields:
 func (_m *Environment) GetJwtKey() string {
 	ret := _m.Called()
@@ -63,6 +161,42 @@ func (_m *Environment) GetJwtKey() string {
 	return r0
 }
 
+// GetLogLevel provides a mock function with given fields:
+func (_m *Environment) GetLogLevel() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLogLevel")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetPasswordHasher provides a mock function with given fields:
+func (_m *Environment) GetPasswordHasher() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPasswordHasher")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // GetPort provides a mock function with given fields:
 func (_m *Environment) GetPort() string {
 	ret := _m.Called()
@@ -81,6 +215,242 @@ func (_m *Environment) GetPort() string {
 	return r0
 }
 
+// GetReadTimeout provides a mock function with given fields:
+func (_m *Environment) GetReadTimeout() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadTimeout")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetReminderInterval provides a mock function with given fields:
+func (_m *Environment) GetReminderInterval() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReminderInterval")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetReminderWebhookURL provides a mock function with given fields:
+func (_m *Environment) GetReminderWebhookURL() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReminderWebhookURL")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSMTPAddr provides a mock function with given fields:
+func (_m *Environment) GetSMTPAddr() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSMTPAddr")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSMTPFrom provides a mock function with given fields:
+func (_m *Environment) GetSMTPFrom() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSMTPFrom")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSMTPPassword provides a mock function with given fields:
+func (_m *Environment) GetSMTPPassword() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSMTPPassword")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSMTPUsername provides a mock function with given fields:
+func (_m *Environment) GetSMTPUsername() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSMTPUsername")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetShutdownTimeout provides a mock function with given fields:
+func (_m *Environment) GetShutdownTimeout() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShutdownTimeout")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetTaskRateLimit provides a mock function with given fields:
+func (_m *Environment) GetTaskRateLimit() entities.RateLimit {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskRateLimit")
+	}
+
+	var r0 entities.RateLimit
+	if rf, ok := ret.Get(0).(func() entities.RateLimit); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(entities.RateLimit)
+	}
+
+	return r0
+}
+
+// GetTrashRetention provides a mock function with given fields:
+func (_m *Environment) GetTrashRetention() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrashRetention")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetTrustedProxies provides a mock function with given fields:
+func (_m *Environment) GetTrustedProxies() []string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrustedProxies")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GetWebhookInterval provides a mock function with given fields:
+func (_m *Environment) GetWebhookInterval() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookInterval")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetWriteTimeout provides a mock function with given fields:
+func (_m *Environment) GetWriteTimeout() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWriteTimeout")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
 // NewEnvironment creates a new instance of Environment. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewEnvironment(t interface {
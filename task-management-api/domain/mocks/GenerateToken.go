@@ -2,16 +2,20 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	entities "task-management-api/domain/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Utils is an autogenerated mock type for the Utils type
 type Utils struct {
 	mock.Mock
 }
 
-// GenerateToken provides a mock function with given fields: userID
-func (_m *Utils) GenerateToken(userID string) (string, error) {
-	ret := _m.Called(userID)
+// GenerateToken provides a mock function with given fields: user
+func (_m *Utils) GenerateToken(user entities.AuthenticatedUser) (string, error) {
+	ret := _m.Called(user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateToken")
@@ -19,17 +23,17 @@ func (_m *Utils) GenerateToken(userID string) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func(entities.AuthenticatedUser) (string, error)); ok {
+		return rf(user)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(entities.AuthenticatedUser) string); ok {
+		r0 = rf(user)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(entities.AuthenticatedUser) error); ok {
+		r1 = rf(user)
 	} else {
 		r1 = ret.Error(1)
 	}
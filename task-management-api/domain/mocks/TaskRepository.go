@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	model "task-management-api/domain/model"
+
+	time "time"
 )
 
 // TaskRepository is an autogenerated mock type for the TaskRepository type
@@ -34,17 +36,17 @@ func (_m *TaskRepository) CreateTask(ctx context.Context, newTask entities.Task)
 	return r0
 }
 
-// DeleteTask provides a mock function with given fields: ctx, id, userID
-func (_m *TaskRepository) DeleteTask(ctx context.Context, id string, userID string) error {
-	ret := _m.Called(ctx, id, userID)
+// DeleteTask provides a mock function with given fields: ctx, id, version
+func (_m *TaskRepository) DeleteTask(ctx context.Context, id string, version int64) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, id, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -52,9 +54,99 @@ func (_m *TaskRepository) DeleteTask(ctx context.Context, id string, userID stri
 	return r0
 }
 
-// GetTaskByID provides a mock function with given fields: ctx, id, userID
-func (_m *TaskRepository) GetTaskByID(ctx context.Context, id string, userID string) (*entities.Task, error) {
-	ret := _m.Called(ctx, id, userID)
+// GetDeletedTasks provides a mock function with given fields: ctx, userID, query
+func (_m *TaskRepository) GetDeletedTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ret := _m.Called(ctx, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedTasks")
+	}
+
+	var r0 *model.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) (*model.TaskPage, error)); ok {
+		return rf(ctx, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) *model.TaskPage); ok {
+		r0 = rf(ctx, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.TaskQuery) error); ok {
+		r1 = rf(ctx, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeriesTasks provides a mock function with given fields: ctx, seriesID
+func (_m *TaskRepository) GetSeriesTasks(ctx context.Context, seriesID string) ([]*entities.Task, error) {
+	ret := _m.Called(ctx, seriesID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeriesTasks")
+	}
+
+	var r0 []*entities.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entities.Task, error)); ok {
+		return rf(ctx, seriesID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entities.Task); ok {
+		r0 = rf(ctx, seriesID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, seriesID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubtasks provides a mock function with given fields: ctx, parentID
+func (_m *TaskRepository) GetSubtasks(ctx context.Context, parentID string) ([]*entities.Task, error) {
+	ret := _m.Called(ctx, parentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubtasks")
+	}
+
+	var r0 []*entities.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entities.Task, error)); ok {
+		return rf(ctx, parentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entities.Task); ok {
+		r0 = rf(ctx, parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskByID provides a mock function with given fields: ctx, id
+func (_m *TaskRepository) GetTaskByID(ctx context.Context, id string) (*entities.Task, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTaskByID")
@@ -62,19 +154,19 @@ func (_m *TaskRepository) GetTaskByID(ctx context.Context, id string, userID str
 
 	var r0 *entities.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entities.Task, error)); ok {
-		return rf(ctx, id, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entities.Task, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entities.Task); ok {
-		r0 = rf(ctx, id, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entities.Task); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entities.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, id, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -82,29 +174,87 @@ func (_m *TaskRepository) GetTaskByID(ctx context.Context, id string, userID str
 	return r0, r1
 }
 
-// GetTasks provides a mock function with given fields: ctx, userID
-func (_m *TaskRepository) GetTasks(ctx context.Context, userID string) ([]*model.TaskInfo, error) {
-	ret := _m.Called(ctx, userID)
+// GetTaskIncludingDeleted provides a mock function with given fields: ctx, id
+func (_m *TaskRepository) GetTaskIncludingDeleted(ctx context.Context, id string) (*entities.Task, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTasks")
+		panic("no return value specified for GetTaskIncludingDeleted")
 	}
 
-	var r0 []*model.TaskInfo
+	var r0 *entities.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.TaskInfo, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entities.Task, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.TaskInfo); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entities.Task); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.TaskInfo)
+			r0 = ret.Get(0).(*entities.Task)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userID)
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTasks provides a mock function with given fields: ctx, userID, query
+func (_m *TaskRepository) GetTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ret := _m.Called(ctx, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTasks")
+	}
+
+	var r0 *model.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) (*model.TaskPage, error)); ok {
+		return rf(ctx, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) *model.TaskPage); ok {
+		r0 = rf(ctx, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.TaskQuery) error); ok {
+		r1 = rf(ctx, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeletedTasks provides a mock function with given fields: ctx, deletedBefore
+func (_m *TaskRepository) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedTasks")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -112,17 +262,35 @@ func (_m *TaskRepository) GetTasks(ctx context.Context, userID string) ([]*model
 	return r0, r1
 }
 
-// UpdateTask provides a mock function with given fields: ctx, id, updatedTask, userID
-func (_m *TaskRepository) UpdateTask(ctx context.Context, id string, updatedTask entities.Task, userID string) error {
-	ret := _m.Called(ctx, id, updatedTask, userID)
+// RestoreTask provides a mock function with given fields: ctx, id, userID
+func (_m *TaskRepository) RestoreTask(ctx context.Context, id string, userID string) error {
+	ret := _m.Called(ctx, id, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTask provides a mock function with given fields: ctx, id, updatedTask, fields
+func (_m *TaskRepository) UpdateTask(ctx context.Context, id string, updatedTask entities.Task, fields []string) error {
+	ret := _m.Called(ctx, id, updatedTask, fields)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, entities.Task, string) error); ok {
-		r0 = rf(ctx, id, updatedTask, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, entities.Task, []string) error); ok {
+		r0 = rf(ctx, id, updatedTask, fields)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	entities "task-management-api/domain/entities"
 
 	mock "github.com/stretchr/testify/mock"
@@ -15,17 +16,47 @@ type TaskUsecase struct {
 	mock.Mock
 }
 
-// CreateTask provides a mock function with given fields: newTask
-func (_m *TaskUsecase) CreateTask(newTask entities.Task) error {
-	ret := _m.Called(newTask)
+// AddTaskBlocker provides a mock function with given fields: ctx, id, blockerID, userID
+func (_m *TaskUsecase) AddTaskBlocker(ctx context.Context, id string, blockerID string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, blockerID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTaskBlocker")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, blockerID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, blockerID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, blockerID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTask provides a mock function with given fields: ctx, newTask
+func (_m *TaskUsecase) CreateTask(ctx context.Context, newTask entities.Task) error {
+	ret := _m.Called(ctx, newTask)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(entities.Task) error); ok {
-		r0 = rf(newTask)
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Task) error); ok {
+		r0 = rf(ctx, newTask)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,17 +64,17 @@ func (_m *TaskUsecase) CreateTask(newTask entities.Task) error {
 	return r0
 }
 
-// DeleteTask provides a mock function with given fields: id, userID
-func (_m *TaskUsecase) DeleteTask(id string, userID string) error {
-	ret := _m.Called(id, userID)
+// DeleteTask provides a mock function with given fields: ctx, id, userID, version
+func (_m *TaskUsecase) DeleteTask(ctx context.Context, id string, userID string, version int64) error {
+	ret := _m.Called(ctx, id, userID, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(id, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) error); ok {
+		r0 = rf(ctx, id, userID, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -51,9 +82,99 @@ func (_m *TaskUsecase) DeleteTask(id string, userID string) error {
 	return r0
 }
 
-// GetTaskByID provides a mock function with given fields: id, userID
-func (_m *TaskUsecase) GetTaskByID(id string, userID string) (*model.TaskInfo, error) {
-	ret := _m.Called(id, userID)
+// GetAssignedTasks provides a mock function with given fields: ctx, userID, query
+func (_m *TaskUsecase) GetAssignedTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ret := _m.Called(ctx, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssignedTasks")
+	}
+
+	var r0 *model.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) (*model.TaskPage, error)); ok {
+		return rf(ctx, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) *model.TaskPage); ok {
+		r0 = rf(ctx, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.TaskQuery) error); ok {
+		r1 = rf(ctx, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProjectTasks provides a mock function with given fields: ctx, projectID, query
+func (_m *TaskUsecase) GetProjectTasks(ctx context.Context, projectID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ret := _m.Called(ctx, projectID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProjectTasks")
+	}
+
+	var r0 *model.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) (*model.TaskPage, error)); ok {
+		return rf(ctx, projectID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) *model.TaskPage); ok {
+		r0 = rf(ctx, projectID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.TaskQuery) error); ok {
+		r1 = rf(ctx, projectID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskActivity provides a mock function with given fields: ctx, id, userID, query
+func (_m *TaskUsecase) GetTaskActivity(ctx context.Context, id string, userID string, query model.PageQuery) (*model.ActivityPage, error) {
+	ret := _m.Called(ctx, id, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskActivity")
+	}
+
+	var r0 *model.ActivityPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.PageQuery) (*model.ActivityPage, error)); ok {
+		return rf(ctx, id, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.PageQuery) *model.ActivityPage); ok {
+		r0 = rf(ctx, id, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ActivityPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, model.PageQuery) error); ok {
+		r1 = rf(ctx, id, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskByID provides a mock function with given fields: ctx, id, userID
+func (_m *TaskUsecase) GetTaskByID(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, userID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTaskByID")
@@ -61,19 +182,19 @@ func (_m *TaskUsecase) GetTaskByID(id string, userID string) (*model.TaskInfo, e
 
 	var r0 *model.TaskInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (*model.TaskInfo, error)); ok {
-		return rf(id, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, userID)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) *model.TaskInfo); ok {
-		r0 = rf(id, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, userID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.TaskInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(id, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -81,29 +202,239 @@ func (_m *TaskUsecase) GetTaskByID(id string, userID string) (*model.TaskInfo, e
 	return r0, r1
 }
 
-// GetTasks provides a mock function with given fields: userID
-func (_m *TaskUsecase) GetTasks(userID string) ([]*model.TaskInfo, error) {
-	ret := _m.Called(userID)
+// GetTaskSeries provides a mock function with given fields: ctx, seriesID, userID
+func (_m *TaskUsecase) GetTaskSeries(ctx context.Context, seriesID string, userID string) ([]*model.TaskInfo, error) {
+	ret := _m.Called(ctx, seriesID, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTasks")
+		panic("no return value specified for GetTaskSeries")
 	}
 
 	var r0 []*model.TaskInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) ([]*model.TaskInfo, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*model.TaskInfo, error)); ok {
+		return rf(ctx, seriesID, userID)
 	}
-	if rf, ok := ret.Get(0).(func(string) []*model.TaskInfo); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*model.TaskInfo); ok {
+		r0 = rf(ctx, seriesID, userID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.TaskInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, seriesID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskTree provides a mock function with given fields: ctx, id, userID
+func (_m *TaskUsecase) GetTaskTree(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskTree")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTasks provides a mock function with given fields: ctx, userID, query
+func (_m *TaskUsecase) GetTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ret := _m.Called(ctx, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTasks")
+	}
+
+	var r0 *model.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) (*model.TaskPage, error)); ok {
+		return rf(ctx, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) *model.TaskPage); ok {
+		r0 = rf(ctx, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.TaskQuery) error); ok {
+		r1 = rf(ctx, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrash provides a mock function with given fields: ctx, userID, query
+func (_m *TaskUsecase) GetTrash(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ret := _m.Called(ctx, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrash")
+	}
+
+	var r0 *model.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) (*model.TaskPage, error)); ok {
+		return rf(ctx, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskQuery) *model.TaskPage); ok {
+		r0 = rf(ctx, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.TaskQuery) error); ok {
+		r1 = rf(ctx, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveTask provides a mock function with given fields: ctx, id, projectID, userID
+func (_m *TaskUsecase) MoveTask(ctx context.Context, id string, projectID string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, projectID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveTask")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, projectID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, projectID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, projectID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTaskBlocker provides a mock function with given fields: ctx, id, blockerID, userID
+func (_m *TaskUsecase) RemoveTaskBlocker(ctx context.Context, id string, blockerID string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, blockerID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTaskBlocker")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, blockerID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, blockerID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, blockerID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTaskFromProject provides a mock function with given fields: ctx, id, projectID, userID
+func (_m *TaskUsecase) RemoveTaskFromProject(ctx context.Context, id string, projectID string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, projectID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTaskFromProject")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, projectID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, projectID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, projectID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreTask provides a mock function with given fields: ctx, id, userID
+func (_m *TaskUsecase) RestoreTask(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreTask")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -111,22 +442,184 @@ func (_m *TaskUsecase) GetTasks(userID string) ([]*model.TaskInfo, error) {
 	return r0, r1
 }
 
-// UpdateTask provides a mock function with given fields: id, updatedTask, userID
-func (_m *TaskUsecase) UpdateTask(id string, updatedTask entities.Task, userID string) error {
-	ret := _m.Called(id, updatedTask, userID)
+// SetTaskAssignee provides a mock function with given fields: ctx, id, username, userID
+func (_m *TaskUsecase) SetTaskAssignee(ctx context.Context, id string, username string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, username, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTaskAssignee")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, username, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, username, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, username, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetTaskParent provides a mock function with given fields: ctx, id, parentID, userID
+func (_m *TaskUsecase) SetTaskParent(ctx context.Context, id string, parentID string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, parentID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTaskParent")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, parentID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, parentID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, parentID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ShareTask provides a mock function with given fields: ctx, id, username, role, userID
+func (_m *TaskUsecase) ShareTask(ctx context.Context, id string, username string, role string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, username, role, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShareTask")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, username, role, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, username, role, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, id, username, role, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnshareTask provides a mock function with given fields: ctx, id, username, userID
+func (_m *TaskUsecase) UnshareTask(ctx context.Context, id string, username string, userID string) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, username, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnshareTask")
+	}
+
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, username, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, username, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, username, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTask provides a mock function with given fields: ctx, id, patch, userID, version
+func (_m *TaskUsecase) UpdateTask(ctx context.Context, id string, patch model.TaskPatch, userID string, version int64) (*model.TaskInfo, error) {
+	ret := _m.Called(ctx, id, patch, userID, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateTask")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, entities.Task, string) error); ok {
-		r0 = rf(id, updatedTask, userID)
+	var r0 *model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskPatch, string, int64) (*model.TaskInfo, error)); ok {
+		return rf(ctx, id, patch, userID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskPatch, string, int64) *model.TaskInfo); ok {
+		r0 = rf(ctx, id, patch, userID, version)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskInfo)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.TaskPatch, string, int64) error); ok {
+		r1 = rf(ctx, id, patch, userID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTaskSeries provides a mock function with given fields: ctx, seriesID, patch, userID
+func (_m *TaskUsecase) UpdateTaskSeries(ctx context.Context, seriesID string, patch model.SeriesPatch, userID string) ([]*model.TaskInfo, error) {
+	ret := _m.Called(ctx, seriesID, patch, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTaskSeries")
+	}
+
+	var r0 []*model.TaskInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.SeriesPatch, string) ([]*model.TaskInfo, error)); ok {
+		return rf(ctx, seriesID, patch, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.SeriesPatch, string) []*model.TaskInfo); ok {
+		r0 = rf(ctx, seriesID, patch, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TaskInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.SeriesPatch, string) error); ok {
+		r1 = rf(ctx, seriesID, patch, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewTaskUsecase creates a new instance of TaskUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -154,17 +154,35 @@ func (_m *UserRepository) GetUserByUsername(ctx context.Context, username string
 	return r0, r1
 }
 
-// UpdateUser provides a mock function with given fields: ctx, id, updatedUser
-func (_m *UserRepository) UpdateUser(ctx context.Context, id string, updatedUser entities.User) error {
-	ret := _m.Called(ctx, id, updatedUser)
+// UpdatePassword provides a mock function with given fields: ctx, id, hashedPassword
+func (_m *UserRepository) UpdatePassword(ctx context.Context, id string, hashedPassword string) error {
+	ret := _m.Called(ctx, id, hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, hashedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUser provides a mock function with given fields: ctx, id, updatedUser, fields
+func (_m *UserRepository) UpdateUser(ctx context.Context, id string, updatedUser entities.User, fields []string) error {
+	ret := _m.Called(ctx, id, updatedUser, fields)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, entities.User) error); ok {
-		r0 = rf(ctx, id, updatedUser)
+	if rf, ok := ret.Get(0).(func(context.Context, string, entities.User, []string) error); ok {
+		r0 = rf(ctx, id, updatedUser, fields)
 	} else {
 		r0 = ret.Error(0)
 	}
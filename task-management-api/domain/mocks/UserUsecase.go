@@ -112,22 +112,34 @@ func (_m *UserUsecase) GetUsers(ctx context.Context, param string) ([]*entities.
 	return r0, r1
 }
 
-// UpdateUser provides a mock function with given fields: ctx, id, updatedUser
-func (_m *UserUsecase) UpdateUser(ctx context.Context, id string, updatedUser entities.User) error {
-	ret := _m.Called(ctx, id, updatedUser)
+// UpdateUser provides a mock function with given fields: ctx, currUser, id, patch
+func (_m *UserUsecase) UpdateUser(ctx context.Context, currUser entities.AuthenticatedUser, id string, patch model.UserPatch) (*entities.User, error) {
+	ret := _m.Called(ctx, currUser, id, patch)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, entities.User) error); ok {
-		r0 = rf(ctx, id, updatedUser)
+	var r0 *entities.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AuthenticatedUser, string, model.UserPatch) (*entities.User, error)); ok {
+		return rf(ctx, currUser, id, patch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AuthenticatedUser, string, model.UserPatch) *entities.User); ok {
+		r0 = rf(ctx, currUser, id, patch)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.User)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, entities.AuthenticatedUser, string, model.UserPatch) error); ok {
+		r1 = rf(ctx, currUser, id, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewUserUsecase creates a new instance of UserUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
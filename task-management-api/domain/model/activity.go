@@ -0,0 +1,28 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ActivityInfo struct {
+	ID        string        `json:"id"`
+	TaskID    string        `json:"task_id"`
+	ActorID   string        `json:"actor_id"`
+	Action    string        `json:"action"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// FieldChange is the value of a task field before and after an update.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// ActivityPage is one page of a task's activity, newest first.
+type ActivityPage struct {
+	Activity []*ActivityInfo `json:"activity"`
+	Next     string          `json:"next,omitempty"`
+}
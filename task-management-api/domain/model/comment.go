@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+type CommentInfo struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	AuthorID  string    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommentPage is one page of a task's comments, oldest first.
+type CommentPage struct {
+	Comments []*CommentInfo `json:"comments"`
+	Next     string         `json:"next,omitempty"`
+}
+
+// CommentRequest is the request body of POST /task/:id/comments and
+// PATCH /task/:id/comments/:commentId.
+type CommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
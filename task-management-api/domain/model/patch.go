@@ -0,0 +1,50 @@
+package model
+
+import "encoding/json"
+
+// MergePatchContentType is the media type of JSON merge patches (RFC 7386).
+const MergePatchContentType = "application/merge-patch+json"
+
+// Patch is one member of a JSON merge patch. Set reports whether the member
+// was present in the patch and Null whether it was present as null, which
+// removes the current value.
+type Patch[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (p *Patch[T]) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if string(data) == "null" {
+		p.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &p.Value)
+}
+
+// Merge returns current patched by p: unchanged when the member is absent,
+// the zero value when it is null and the new value otherwise.
+func (p Patch[T]) Merge(current T) T {
+	switch {
+	case !p.Set:
+		return current
+	case p.Null:
+		var zero T
+		return zero
+	default:
+		return p.Value
+	}
+}
+
+// ValueOrNil returns the new value of a member set to a value, and nil for
+// absent and null members. Request validation checks only the values a patch
+// provides.
+func (p Patch[T]) ValueOrNil() interface{} {
+	if !p.Set || p.Null {
+		return nil
+	}
+
+	return p.Value
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type ProjectInfo struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	OwnerID    string          `json:"owner_id"`
+	Members    []ProjectMember `json:"members"`
+	ArchivedAt *time.Time      `json:"archived_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// ProjectMember is a user added to a project and their role in it.
+type ProjectMember struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// ProjectRequest is the request body of POST /projects/ and
+// PATCH /projects/:id.
+type ProjectRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// MemberRequest is the request body of PUT /projects/:id/members/:username.
+type MemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor"`
+}
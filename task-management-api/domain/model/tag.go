@@ -0,0 +1,12 @@
+package model
+
+// TagInfo is a tag together with the number of the user's tasks carrying it.
+type TagInfo struct {
+	Name  string `json:"name" bson:"_id"`
+	Count int64  `json:"count" bson:"count"`
+}
+
+// TagRename is the request body of PATCH /tags/:name.
+type TagRename struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
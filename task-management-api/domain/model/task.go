@@ -1,13 +1,119 @@
 package model
 
+import "time"
+
 type TaskInfo struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	DueDate     string `json:"due_date"`
+	ID            string         `json:"id"`
+	OwnerID       string         `json:"owner_id,omitempty"`
+	Title         string         `json:"title"`
+	Description   string         `json:"description"`
+	Status        string         `json:"status"`
+	Priority      string         `json:"priority"`
+	Tags          []string       `json:"tags"`
+	DueDate       *time.Time     `json:"due_date"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	CompletedAt   *time.Time     `json:"completed_at"`
+	Version       int64          `json:"version"`
+	DeletedAt     *time.Time     `json:"deleted_at,omitempty"`
+	ParentID      string         `json:"parent_id,omitempty"`
+	BlockedBy     []string       `json:"blocked_by,omitempty"`
+	Collaborators []Collaborator `json:"collaborators,omitempty"`
+	AssigneeID    string         `json:"assignee_id,omitempty"`
+	ProjectID     string         `json:"project_id,omitempty"`
+	Recurrence    string         `json:"recurrence,omitempty"`
+	SeriesID      string         `json:"series_id,omitempty"`
+	Occurrence    int            `json:"occurrence,omitempty"`
+	Reminders     []string       `json:"reminders,omitempty"`
+	// Subtasks and Progress are only filled in a task tree. Progress is the
+	// percentage of work done: 0 or 100 for a task without subtasks, the
+	// average progress of its subtasks otherwise.
+	Subtasks []*TaskInfo `json:"subtasks,omitempty"`
+	Progress *int        `json:"progress,omitempty"`
 }
 
-type TaskUpdate  struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-}
\ No newline at end of file
+const (
+	DefaultTaskPageSize = 20
+	MaxTaskPageSize     = 100
+)
+
+// TaskQuery holds the filters, sort order and page position used to list a
+// user's tasks, or a project's tasks when ProjectID is set. Zero values leave
+// the corresponding filter unset. TagMatch says whether tasks need any (the
+// default) or all of Tags.
+type TaskQuery struct {
+	ProjectID  string
+	AssigneeID string
+	Status     string
+	Priority   string
+	Tags       []string
+	TagMatch   string
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	Text       string
+	Sort       string
+	Cursor     string
+	Limit      int
+}
+
+// Collaborator is a user a task is shared with and their role on it.
+type Collaborator struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// ShareRequest is the request body of PUT /task/:id/collaborators/:username.
+type ShareRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor"`
+}
+
+// TaskPage is one page of tasks. Next is an opaque token for the following
+// page and is empty on the last one.
+type TaskPage struct {
+	Tasks []*TaskInfo `json:"tasks"`
+	Next  string      `json:"next,omitempty"`
+}
+
+// PageQuery selects one page of the comments or activity of a task. Cursor is
+// the next token of the previous page.
+type PageQuery struct {
+	Cursor string
+	Limit  int
+}
+
+// TaskCreate is the request body of POST /task/.
+type TaskCreate struct {
+	Title       string     `json:"title" binding:"required,max=200"`
+	Description string     `json:"description" binding:"max=2000"`
+	Status      string     `json:"status" binding:"omitempty,oneof=todo in_progress done"`
+	Priority    string     `json:"priority" binding:"omitempty,oneof=low medium high"`
+	Tags        []string   `json:"tags" binding:"max=20,dive,required,max=50"`
+	DueDate     *time.Time `json:"due_date" binding:"omitempty,notpast"`
+	Recurrence  string     `json:"recurrence" binding:"max=200"`
+	Reminders   []string   `json:"reminders" binding:"max=5,dive,required,max=20"`
+}
+
+// TaskPatch is the request body of PATCH /task/:id, a JSON merge patch. Its
+// members are the only task fields a patch may change; absent members keep
+// their value and null ones are cleared.
+type TaskPatch struct {
+	Title       Patch[string]    `json:"title" binding:"omitempty,max=200"`
+	Description Patch[string]    `json:"description" binding:"omitempty,max=2000"`
+	Status      Patch[string]    `json:"status" binding:"omitempty,oneof=todo in_progress done"`
+	Priority    Patch[string]    `json:"priority" binding:"omitempty,oneof=low medium high"`
+	Tags        Patch[[]string]  `json:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+	DueDate     Patch[time.Time] `json:"due_date" binding:"omitempty,notpast"`
+	Recurrence  Patch[string]    `json:"recurrence" binding:"omitempty,max=200"`
+	Reminders   Patch[[]string]  `json:"reminders" binding:"omitempty,max=5,dive,required,max=20"`
+}
+
+// SeriesPatch is the request body of PATCH /task/series/:id, a JSON merge
+// patch applied to every open occurrence of a recurring task.
+type SeriesPatch struct {
+	Title       Patch[string]   `json:"title" binding:"omitempty,max=200"`
+	Description Patch[string]   `json:"description" binding:"omitempty,max=2000"`
+	Priority    Patch[string]   `json:"priority" binding:"omitempty,oneof=low medium high"`
+	Tags        Patch[[]string] `json:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+	Recurrence  Patch[string]   `json:"recurrence" binding:"omitempty,max=200"`
+	Reminders   Patch[[]string] `json:"reminders" binding:"omitempty,max=5,dive,required,max=20"`
+}
@@ -8,12 +8,48 @@ type UserCreate struct {
 	Email    string `json:"email"`
 	Name     string `json:"name"`
 	Bio      string `json:"bio"`
+	Role     string `json:"role"`
 	ID       primitive.ObjectID `bson:"_id,omitempty"`
 }
 
+// UserRegister is the request body of POST /auth/register.
+type UserRegister struct {
+	Username string `json:"username" binding:"required,min=3,max=32"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+	Email    string `json:"email" binding:"omitempty,email,max=254"`
+	Name     string `json:"name" binding:"max=100"`
+	Bio      string `json:"bio" binding:"max=500"`
+}
+
+// AdminUserCreate is the request body of POST /admin/users, which can also
+// pick the role of the new user.
+type AdminUserCreate struct {
+	Username string `json:"username" binding:"required,min=3,max=32"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+	Email    string `json:"email" binding:"omitempty,email,max=254"`
+	Name     string `json:"name" binding:"max=100"`
+	Bio      string `json:"bio" binding:"max=500"`
+	Role     string `json:"role" binding:"omitempty,oneof=USER ADMIN"`
+}
+
+// UserPatch is the request body of PATCH /:id, a JSON merge patch. Its
+// members are the only user fields a patch may change; absent members keep
+// their value and null ones are cleared.
+type UserPatch struct {
+	Username Patch[string] `json:"username" binding:"omitempty,min=3,max=32"`
+	Password Patch[string] `json:"password" binding:"omitempty,min=8,max=72"`
+	Email    Patch[string] `json:"email" binding:"omitempty,email,max=254"`
+	Name     Patch[string] `json:"name" binding:"omitempty,max=100"`
+	Bio      Patch[string] `json:"bio" binding:"omitempty,max=500"`
+}
+
 type UserLogin struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type TokenRefresh struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 type UserInfo struct {
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookInfo carries the secret of a webhook only in the response creating
+// it.
+type WebhookInfo struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookRequest is the request body of POST /webhooks/.
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required,max=2000"`
+	Events []string `json:"events" binding:"required,min=1,max=4,dive,oneof=task.created task.updated task.deleted task.completed"`
+}
+
+type WebhookDeliveryInfo struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// WebhookDeliveryPage is one page of the delivery log of a webhook, newest
+// first.
+type WebhookDeliveryPage struct {
+	Deliveries []*WebhookDeliveryInfo `json:"deliveries"`
+	Next       string                 `json:"next,omitempty"`
+}
+
+// WebhookPayload is the body posted to webhooks. ID identifies the event, and
+// is the same in the deliveries of one event to several webhooks. The task is
+// as it was after the event, and Changes lists what a task.updated event
+// changed, as in the activity log.
+type WebhookPayload struct {
+	ID        string        `json:"id"`
+	Event     string        `json:"event"`
+	CreatedAt time.Time     `json:"created_at"`
+	ActorID   string        `json:"actor_id,omitempty"`
+	Task      *TaskInfo     `json:"task,omitempty"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
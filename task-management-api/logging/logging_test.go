@@ -0,0 +1,35 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"task-management-api/logging"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, "warn")
+
+	logger.Info("dropped")
+	logger.Warn("kept", "task_id", "1")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "kept", record["msg"])
+	assert.Equal(t, "1", record["task_id"])
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), logging.FromContext(context.Background()))
+
+	logger := logging.New(&bytes.Buffer{}, "info")
+	ctx := logging.WithLogger(context.Background(), logger)
+	assert.Same(t, logger, logging.FromContext(ctx))
+}
@@ -0,0 +1,107 @@
+// Package metrics defines the Prometheus metrics the service exposes on
+// /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Default holds the metrics of the running service.
+var Default = New()
+
+// unmatchedRoute labels requests that did not match any route, so that
+// arbitrary paths cannot blow up the number of series.
+const unmatchedRoute = "unmatched"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	loginAttempts       *prometheus.CounterVec
+	dbOperationDuration *prometheus.HistogramVec
+	dbOperationErrors   *prometheus.CounterVec
+}
+
+// New creates the metrics on a registry of their own, together with the Go
+// runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests handled, by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time taken to handle HTTP requests, by method, route and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		loginAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_login_attempts_total",
+			Help: "Login attempts, by result (success or failure).",
+		}, []string{"result"}),
+		dbOperationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_operation_duration_seconds",
+			Help:    "Time taken by database operations, by collection and operation.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"collection", "operation"}),
+		dbOperationErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_operation_errors_total",
+			Help: "Database operations that failed, by collection and operation.",
+		}, []string{"collection", "operation"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.loginAttempts,
+		m.dbOperationDuration,
+		m.dbOperationErrors,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a handled HTTP request. route is the matched route
+// pattern, such as /task/:id, and is empty when no route matched.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+	m.httpRequests.With(labels).Inc()
+	m.httpRequestDuration.With(labels).Observe(duration.Seconds())
+}
+
+// RecordLogin counts a login attempt.
+func (m *Metrics) RecordLogin(success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+
+	m.loginAttempts.WithLabelValues(result).Inc()
+}
+
+// ObserveOperation records a database operation and whether it failed.
+func (m *Metrics) ObserveOperation(collection, operation string, duration time.Duration, err error) {
+	m.dbOperationDuration.WithLabelValues(collection, operation).Observe(duration.Seconds())
+	if err != nil {
+		m.dbOperationErrors.WithLabelValues(collection, operation).Inc()
+	}
+}
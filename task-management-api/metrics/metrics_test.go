@@ -0,0 +1,50 @@
+package metrics_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"task-management-api/metrics"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	m.Handler().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+
+	m.ObserveRequest(http.MethodGet, "/task/:id", http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/task/:id", http.StatusOK, 30*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "", http.StatusNotFound, time.Millisecond)
+	m.RecordLogin(true)
+	m.RecordLogin(false)
+	m.RecordLogin(false)
+	m.ObserveOperation("task", "find", 2*time.Millisecond, nil)
+	m.ObserveOperation("task", "update_one", 2*time.Millisecond, errors.New("connection reset"))
+
+	body := scrape(t, m)
+
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/task/:id",status="200"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/task/:id",status="200"} 2`)
+	assert.Contains(t, body, `auth_login_attempts_total{result="success"} 1`)
+	assert.Contains(t, body, `auth_login_attempts_total{result="failure"} 2`)
+	assert.Contains(t, body, `db_operation_duration_seconds_count{collection="task",operation="find"} 1`)
+	assert.Contains(t, body, `db_operation_errors_total{collection="task",operation="update_one"} 1`)
+	assert.NotContains(t, body, `db_operation_errors_total{collection="task",operation="find"}`)
+	assert.Contains(t, body, "go_goroutines")
+}
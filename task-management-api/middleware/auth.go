@@ -1,47 +1,99 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
+	"time"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
-	"task-management-api/utils"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
 
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware validates the bearer access token against jwtKey and rejects
+// tokens whose jti has been revoked through logout.
+func AuthMiddleware(jwtKey []byte, revokedTokens entities.RevokedTokenRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			c.Abort()
+			abortWithError(c, apperrors.Unauthorized("Authorization header is required"))
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
-			c.Abort()
+			abortWithError(c, apperrors.Unauthorized("Invalid token format"))
 			return
 		}
 
 		claims := &entities.Claims{}
 		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return utils.GetJwtKey(), nil
+			return jwtKey, nil
 		})
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+		if err != nil || !token.Valid || claims.Id == "" {
+			abortWithError(c, apperrors.Unauthorized("Invalid token"))
 			return
 		}
 
+		revoked, err := revokedTokens.IsTokenRevoked(c.Request.Context(), claims.Id)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if revoked {
+			abortWithError(c, apperrors.Unauthorized("Token has been revoked"))
+			return
+		}
+
+		role := claims.Role
+		if role == "" {
+			role = entities.RoleUser
+		}
+
 		c.Set("user_id", claims.UserID)
+		c.Set("user", entities.AuthenticatedUser{
+			UserID:         claims.UserID,
+			Username:       claims.Username,
+			Role:           role,
+			TokenID:        claims.Id,
+			TokenExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		})
 		c.Next()
 	}
 }
 
+// RequireRole only lets requests through when the authenticated user set by
+// AuthMiddleware has one of the given roles.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := CurrentUser(c)
+		if !exists {
+			abortWithError(c, apperrors.Unauthorized("Authorization header is required"))
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		abortWithError(c, apperrors.Forbidden("Insufficient permissions"))
+	}
+}
+
+func CurrentUser(c *gin.Context) (entities.AuthenticatedUser, bool) {
+	value, exists := c.Get("user")
+	if !exists {
+		return entities.AuthenticatedUser{}, false
+	}
+
+	user, ok := value.(entities.AuthenticatedUser)
+	return user, ok
+}
+
 func SetUserID(userID string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Set("user_id", userID)
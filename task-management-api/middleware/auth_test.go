@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task-management-api/domain/entities"
+	"task-management-api/middleware"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(user *entities.AuthenticatedUser) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			if user != nil {
+				c.Set("user", *user)
+			}
+			c.Next()
+		})
+		router.GET("/admin", middleware.RequireRole(entities.RoleAdmin), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	t.Run("allowed role", func(t *testing.T) {
+		router := newRouter(&entities.AuthenticatedUser{UserID: "1", Role: entities.RoleAdmin})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("forbidden role", func(t *testing.T) {
+		router := newRouter(&entities.AuthenticatedUser{UserID: "1", Role: entities.RoleUser})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Forbidden", "status": 403, "detail": "Insufficient permissions", "instance": "/admin"}`, w.Body.String())
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		router := newRouter(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"task-management-api/domain/apperrors"
+	"task-management-api/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Validation problems list the
+// rejected fields in Errors.
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Errors   []apperrors.FieldError `json:"errors,omitempty"`
+}
+
+// ErrorHandler renders the last error a handler added with c.Error as a
+// problem+json response. Errors that are not domain errors become a 500
+// without details, so internal messages never reach the client.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		problem := NewProblem(err, c.Request.URL.Path)
+		if problem.Status == http.StatusInternalServerError {
+			ctx := c.Request.Context()
+			logging.FromContext(ctx).ErrorContext(ctx, "request failed", "error", err)
+		}
+
+		if appErr, ok := apperrors.As(err); ok && appErr.RetryAfter > 0 {
+			c.Header("Retry-After", retryAfterSeconds(appErr.RetryAfter))
+		}
+
+		body, err := json.Marshal(problem)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+
+		c.Data(problem.Status, ProblemContentType, body)
+	}
+}
+
+// NewProblem maps err to the problem details returned for it.
+func NewProblem(err error, instance string) Problem {
+	status := statusOf(err)
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: instance,
+	}
+
+	if appErr, ok := apperrors.As(err); ok && status != http.StatusInternalServerError {
+		problem.Detail = appErr.Detail
+		problem.Errors = appErr.Fields
+	}
+
+	return problem
+}
+
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, apperrors.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperrors.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperrors.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperrors.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, apperrors.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, apperrors.ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, apperrors.ErrRateLimited):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// retryAfterSeconds formats d as a Retry-After value, rounding up so that
+// clients never retry too early.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// abortWithError stops the handler chain and leaves err for ErrorHandler to
+// render.
+func abortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,123 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"task-management-api/domain/apperrors"
+	"task-management-api/middleware"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(handler gin.HandlerFunc) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.GET("/resource", handler)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/resource", nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		status   int
+		expected string
+	}{
+		{
+			name:     "not found",
+			err:      apperrors.NotFound("task not found"),
+			status:   http.StatusNotFound,
+			expected: `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "task not found", "instance": "/resource"}`,
+		},
+		{
+			name:     "wrapped conflict",
+			err:      fmt.Errorf("register: %w", apperrors.Conflict("username already exists")),
+			status:   http.StatusConflict,
+			expected: `{"type": "about:blank", "title": "Conflict", "status": 409, "detail": "username already exists", "instance": "/resource"}`,
+		},
+		{
+			name:     "unavailable",
+			err:      apperrors.Unavailable("database unavailable").Wrap(errors.New("connection refused")),
+			status:   http.StatusServiceUnavailable,
+			expected: `{"type": "about:blank", "title": "Service Unavailable", "status": 503, "detail": "database unavailable", "instance": "/resource"}`,
+		},
+		{
+			name:   "validation",
+			err:    apperrors.Validation("invalid task", apperrors.FieldError{Field: "title", Message: "is required"}),
+			status: http.StatusBadRequest,
+			expected: `{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "invalid task", "instance": "/resource",
+				"errors": [{"field": "title", "message": "is required"}]}`,
+		},
+		{
+			name:     "unauthorized",
+			err:      apperrors.Unauthorized("invalid username or password"),
+			status:   http.StatusUnauthorized,
+			expected: `{"type": "about:blank", "title": "Unauthorized", "status": 401, "detail": "invalid username or password", "instance": "/resource"}`,
+		},
+		{
+			name:     "forbidden",
+			err:      apperrors.Forbidden("admin role required"),
+			status:   http.StatusForbidden,
+			expected: `{"type": "about:blank", "title": "Forbidden", "status": 403, "detail": "admin role required", "instance": "/resource"}`,
+		},
+		{
+			name:     "precondition failed",
+			err:      apperrors.PreconditionFailed("task was modified"),
+			status:   http.StatusPreconditionFailed,
+			expected: `{"type": "about:blank", "title": "Precondition Failed", "status": 412, "detail": "task was modified", "instance": "/resource"}`,
+		},
+		{
+			name:     "rate limited",
+			err:      apperrors.RateLimited("rate limit exceeded", 1500*time.Millisecond),
+			status:   http.StatusTooManyRequests,
+			expected: `{"type": "about:blank", "title": "Too Many Requests", "status": 429, "detail": "rate limit exceeded", "instance": "/resource"}`,
+		},
+		{
+			name:     "internal error hides the message",
+			err:      errors.New("connection refused"),
+			status:   http.StatusInternalServerError,
+			expected: `{"type": "about:blank", "title": "Internal Server Error", "status": 500, "instance": "/resource"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expected, w.Body.String())
+		})
+	}
+
+	t.Run("retry after is rounded up to whole seconds", func(t *testing.T) {
+		w := serve(func(c *gin.Context) {
+			c.Error(apperrors.RateLimited("rate limit exceeded", 1500*time.Millisecond))
+		})
+
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("written response is kept", func(t *testing.T) {
+		w := serve(func(c *gin.Context) {
+			c.Error(errors.New("logged only"))
+			c.JSON(http.StatusOK, gin.H{"message": "ok"})
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "ok"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-management-api/logging"
+	"task-management-api/middleware"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.RequestLogger())
+	router.GET("/task/:id", middleware.SetUserID("user-1"), func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("handler")
+		c.Status(http.StatusNotFound)
+	})
+
+	serve := func(requestID string) (*httptest.ResponseRecorder, []map[string]interface{}) {
+		var buf bytes.Buffer
+		req, _ := http.NewRequest(http.MethodGet, "/task/42", nil)
+		req = req.WithContext(logging.WithLogger(req.Context(), logging.New(&buf, "debug")))
+		if requestID != "" {
+			req.Header.Set(middleware.RequestIDHeader, requestID)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var records []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var record map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			records = append(records, record)
+		}
+		return w, records
+	}
+
+	t.Run("propagates the client's request ID", func(t *testing.T) {
+		w, records := serve("req-123")
+
+		assert.Equal(t, "req-123", w.Header().Get(middleware.RequestIDHeader))
+		require.Len(t, records, 2)
+		assert.Equal(t, "handler", records[0]["msg"])
+		assert.Equal(t, "req-123", records[0]["request_id"])
+
+		access := records[1]
+		assert.Equal(t, "request", access["msg"])
+		assert.Equal(t, "WARN", access["level"])
+		assert.Equal(t, "req-123", access["request_id"])
+		assert.Equal(t, "/task/:id", access["route"])
+		assert.Equal(t, "/task/42", access["path"])
+		assert.Equal(t, float64(http.StatusNotFound), access["status"])
+		assert.Equal(t, "user-1", access["user_id"])
+		assert.Contains(t, access, "latency_ms")
+	})
+
+	t.Run("generates an ID when none or a malformed one is sent", func(t *testing.T) {
+		for _, requestID := range []string{"", "bad id\n", strings.Repeat("a", 200)} {
+			w, records := serve(requestID)
+
+			generated := w.Header().Get(middleware.RequestIDHeader)
+			assert.Len(t, generated, 32)
+			assert.Equal(t, generated, records[1]["request_id"])
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task-management-api/middleware"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type observedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+type recordingRequestObserver struct {
+	requests []observedRequest
+}
+
+func (ro *recordingRequestObserver) ObserveRequest(method, route string, status int, duration time.Duration) {
+	ro.requests = append(ro.requests, observedRequest{method, route, status})
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	observer := &recordingRequestObserver{}
+	router := gin.New()
+	router.Use(middleware.Metrics(observer))
+	router.DELETE("/task/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, path := range []string{"/task/1", "/task/2", "/unknown"} {
+		req, _ := http.NewRequest(http.MethodDelete, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, []observedRequest{
+		{http.MethodDelete, "/task/:id", http.StatusNoContent},
+		{http.MethodDelete, "/task/:id", http.StatusNoContent},
+		{http.MethodDelete, "", http.StatusNotFound},
+	}, observer.requests)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireProjectRole only lets requests through when the user set by
+// AuthMiddleware has at least the given role in the project whose ID is the
+// :id path parameter. Projects the user is not a member of are reported as
+// missing, so that users cannot find out which projects exist.
+func RequireProjectRole(projects entities.ProjectRepository, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		if userID == "" {
+			abortWithError(c, apperrors.Unauthorized("Authorization header is required"))
+			return
+		}
+
+		project, err := projects.GetProjectByID(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		userRole := project.RoleOf(userID)
+		if userRole == "" {
+			abortWithError(c, entities.ErrProjectNotFound)
+			return
+		}
+		if !entities.RoleAllows(userRole, role) {
+			abortWithError(c, entities.ErrProjectForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/middleware"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequireProjectRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	project := &entities.Project{OwnerID: "ownerID", Members: []entities.ProjectMember{{UserID: "viewerID", Role: entities.ProjectRoleViewer}}}
+
+	newRouter := func(projects entities.ProjectRepository, userID string) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			if userID != "" {
+				c.Set("user_id", userID)
+			}
+			c.Next()
+		})
+		router.GET("/projects/:id", middleware.RequireProjectRole(projects, entities.ProjectRoleEditor), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	request := func(router *gin.Engine) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/projects/projectID", nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("allowed role", func(t *testing.T) {
+		projects := mocks.NewProjectRepository(t)
+		projects.On("GetProjectByID", mock.Anything, "projectID").Return(project, nil).Once()
+
+		w := request(newRouter(projects, "ownerID"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("insufficient role", func(t *testing.T) {
+		projects := mocks.NewProjectRepository(t)
+		projects.On("GetProjectByID", mock.Anything, "projectID").Return(project, nil).Once()
+
+		w := request(newRouter(projects, "viewerID"))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		projects := mocks.NewProjectRepository(t)
+		projects.On("GetProjectByID", mock.Anything, "projectID").Return(project, nil).Once()
+
+		w := request(newRouter(projects, "strangerID"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("missing project", func(t *testing.T) {
+		projects := mocks.NewProjectRepository(t)
+		projects.On("GetProjectByID", mock.Anything, "projectID").Return(nil, entities.ErrProjectNotFound).Once()
+
+		w := request(newRouter(projects, "ownerID"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		w := request(newRouter(mocks.NewProjectRepository(t), ""))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
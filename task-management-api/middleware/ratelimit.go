@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"strconv"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKey picks the client a request is counted against.
+type RateLimitKey func(c *gin.Context) string
+
+// ByClientIP counts requests per client IP address.
+func ByClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUserID counts requests per authenticated user, falling back to the client
+// IP address when AuthMiddleware has not set a user.
+func ByUserID(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+
+	return ByClientIP(c)
+}
+
+// RateLimit applies limit to the requests of each client, as identified by
+// key, within the route group name. Responses carry the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers; rejected requests get a
+// 429 with Retry-After. When the store fails, requests are let through.
+func RateLimit(store entities.RateLimitStore, name string, limit entities.RateLimit, key RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		decision, err := store.Allow(ctx, name+":"+key(c), limit)
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "rate limit store failed", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Header("RateLimit-Reset", retryAfterSeconds(decision.ResetAfter))
+
+		if !decision.Allowed {
+			abortWithError(c, apperrors.RateLimited("rate limit exceeded", decision.RetryAfter))
+			return
+		}
+
+		c.Next()
+	}
+}
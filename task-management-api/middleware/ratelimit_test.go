@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task-management-api/domain/entities"
+	"task-management-api/middleware"
+	"task-management-api/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Allow(ctx context.Context, key string, limit entities.RateLimit) (entities.RateLimitDecision, error) {
+	return entities.RateLimitDecision{}, errors.New("store unavailable")
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	limit := entities.RateLimit{Rate: 0.5, Burst: 2}
+
+	newRouter := func(store entities.RateLimitStore, limit entities.RateLimit, key middleware.RateLimitKey) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.GET("/task/", func(c *gin.Context) {
+			if userID := c.GetHeader("X-User"); userID != "" {
+				c.Set("user_id", userID)
+			}
+			c.Next()
+		}, middleware.RateLimit(store, "task", limit, key), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	serve := func(router *gin.Engine, userID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/task/", nil)
+		req.Header.Set("X-User", userID)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("limits each user", func(t *testing.T) {
+		router := newRouter(ratelimit.NewMemoryStore(func() time.Time { return now }), limit, middleware.ByUserID)
+
+		w := serve(router, "alice")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Reset"))
+
+		assert.Equal(t, http.StatusOK, serve(router, "alice").Code)
+
+		w = serve(router, "alice")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Too Many Requests", "status": 429, "detail": "rate limit exceeded", "instance": "/task/"}`, w.Body.String())
+
+		assert.Equal(t, http.StatusOK, serve(router, "bob").Code)
+	})
+
+	t.Run("disabled limit", func(t *testing.T) {
+		router := newRouter(failingRateLimitStore{}, entities.RateLimit{}, middleware.ByClientIP)
+
+		w := serve(router, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("store failures let requests through", func(t *testing.T) {
+		router := newRouter(failingRateLimitStore{}, limit, middleware.ByClientIP)
+
+		assert.Equal(t, http.StatusOK, serve(router, "").Code)
+	})
+}
@@ -0,0 +1,151 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OperationObserver is told about every collection operation: which one ran,
+// how long it took and the error it failed with, if any.
+type OperationObserver interface {
+	ObserveOperation(collection, operation string, duration time.Duration, err error)
+}
+
+// NewInstrumentedDatabase decorates database so that the operations of its
+// collections are reported to observer. A lookup that finds no document is
+// not reported as an error.
+func NewInstrumentedDatabase(database Database, observer OperationObserver) Database {
+	return &instrumentedDatabase{
+		database: database,
+		observer: observer,
+	}
+}
+
+type instrumentedDatabase struct {
+	database Database
+	observer OperationObserver
+}
+
+type instrumentedCollection struct {
+	collection Collection
+	name       string
+	observer   OperationObserver
+}
+
+// instrumentedSingleResult defers the observation of FindOne until the result
+// is decoded, which is when the error of the lookup becomes known.
+type instrumentedSingleResult struct {
+	result  SingleResult
+	observe func(error)
+}
+
+func (id *instrumentedDatabase) Collection(name string) Collection {
+	return &instrumentedCollection{
+		collection: id.database.Collection(name),
+		name:       name,
+		observer:   id.observer,
+	}
+}
+
+func (id *instrumentedDatabase) Client() Client {
+	return id.database.Client()
+}
+
+// track starts timing operation and returns the function reporting it.
+func (ic *instrumentedCollection) track(operation string) func(error) {
+	start := time.Now()
+	return func(err error) {
+		if errors.Is(err, ErrNoDocuments) {
+			err = nil
+		}
+		ic.observer.ObserveOperation(ic.name, operation, time.Since(start), err)
+	}
+}
+
+func (ic *instrumentedCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) SingleResult {
+	observe := ic.track("find_one")
+	return &instrumentedSingleResult{
+		result:  ic.collection.FindOne(ctx, filter, opts...),
+		observe: observe,
+	}
+}
+
+func (ic *instrumentedCollection) InsertOne(ctx context.Context, document interface{}) (interface{}, error) {
+	observe := ic.track("insert_one")
+	id, err := ic.collection.InsertOne(ctx, document)
+	observe(err)
+	return id, err
+}
+
+func (ic *instrumentedCollection) InsertMany(ctx context.Context, documents []interface{}) ([]interface{}, error) {
+	observe := ic.track("insert_many")
+	ids, err := ic.collection.InsertMany(ctx, documents)
+	observe(err)
+	return ids, err
+}
+
+func (ic *instrumentedCollection) DeleteOne(ctx context.Context, filter interface{}) (int64, error) {
+	observe := ic.track("delete_one")
+	count, err := ic.collection.DeleteOne(ctx, filter)
+	observe(err)
+	return count, err
+}
+
+func (ic *instrumentedCollection) DeleteMany(ctx context.Context, filter interface{}) (int64, error) {
+	observe := ic.track("delete_many")
+	count, err := ic.collection.DeleteMany(ctx, filter)
+	observe(err)
+	return count, err
+}
+
+func (ic *instrumentedCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (Cursor, error) {
+	observe := ic.track("find")
+	cursor, err := ic.collection.Find(ctx, filter, opts...)
+	observe(err)
+	return cursor, err
+}
+
+func (ic *instrumentedCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	observe := ic.track("count_documents")
+	count, err := ic.collection.CountDocuments(ctx, filter, opts...)
+	observe(err)
+	return count, err
+}
+
+func (ic *instrumentedCollection) Aggregate(ctx context.Context, pipeline interface{}) (Cursor, error) {
+	observe := ic.track("aggregate")
+	cursor, err := ic.collection.Aggregate(ctx, pipeline)
+	observe(err)
+	return cursor, err
+}
+
+func (ic *instrumentedCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	observe := ic.track("update_one")
+	result, err := ic.collection.UpdateOne(ctx, filter, update, opts...)
+	observe(err)
+	return result, err
+}
+
+func (ic *instrumentedCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	observe := ic.track("update_many")
+	result, err := ic.collection.UpdateMany(ctx, filter, update, opts...)
+	observe(err)
+	return result, err
+}
+
+func (ic *instrumentedCollection) CreateIndexes(ctx context.Context, models []IndexModel) ([]string, error) {
+	observe := ic.track("create_indexes")
+	names, err := ic.collection.CreateIndexes(ctx, models)
+	observe(err)
+	return names, err
+}
+
+func (isr *instrumentedSingleResult) Decode(v interface{}) error {
+	err := isr.result.Decode(v)
+	isr.observe(err)
+	return err
+}
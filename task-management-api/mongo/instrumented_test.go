@@ -0,0 +1,58 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-management-api/mongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type observedOperation struct {
+	collection string
+	operation  string
+	failed     bool
+}
+
+type recordingObserver struct {
+	operations []observedOperation
+}
+
+func (ro *recordingObserver) ObserveOperation(collection, operation string, duration time.Duration, err error) {
+	ro.operations = append(ro.operations, observedOperation{collection, operation, err != nil})
+}
+
+func TestInstrumentedDatabase(t *testing.T) {
+	observer := &recordingObserver{}
+	database := mongo.NewInstrumentedDatabase(mongo.NewMemoryClient().Database("test"), observer)
+	collection := database.Collection("task")
+	ctx := context.TODO()
+
+	_, err := collection.InsertOne(ctx, &memoryTask{UserID: "u1", Title: "Write report"})
+	require.NoError(t, err)
+
+	var task memoryTask
+	require.NoError(t, collection.FindOne(ctx, bson.M{"title": "Write report"}).Decode(&task))
+	assert.ErrorIs(t, collection.FindOne(ctx, bson.M{"title": "missing"}).Decode(&task), mongo.ErrNoDocuments)
+
+	_, err = collection.UpdateOne(ctx, bson.M{}, "not an update document")
+	assert.Error(t, err)
+
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	assert.Equal(t, []observedOperation{
+		{"task", "insert_one", false},
+		{"task", "find_one", false},
+		{"task", "find_one", false},
+		{"task", "update_one", true},
+		{"task", "count_documents", false},
+	}, observer.operations)
+
+	assert.NotNil(t, database.Client())
+}
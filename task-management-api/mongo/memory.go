@@ -0,0 +1,1292 @@
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrDuplicateKey           = errors.New("duplicate key error")
+	ErrUnsupportedOperator    = errors.New("operator not supported by the in-memory database")
+	ErrSessionsNotSupported   = errors.New("sessions are not supported by the in-memory database")
+	errUpdateRequiresOperator = errors.New("update document must contain only update operators")
+)
+
+// NewMemoryClient returns a Client whose databases live in process memory. It
+// understands the filters, update operators, find options and aggregation
+// stages used by the repositories, so the API can run without a MongoDB
+// server.
+func NewMemoryClient() Client {
+	return &memoryClient{
+		databases: map[string]*memoryDatabase{},
+	}
+}
+
+type memoryClient struct {
+	mu        sync.Mutex
+	databases map[string]*memoryDatabase
+}
+
+type memoryDatabase struct {
+	mu          sync.Mutex
+	client      *memoryClient
+	collections map[string]*memoryCollection
+}
+
+type memoryCollection struct {
+	mu        sync.RWMutex
+	documents []bson.M
+	// unique holds the fields of the unique indexes, which inserts and
+	// updates check like MongoDB does.
+	unique [][]string
+}
+
+type memoryCursor struct {
+	documents []bson.M
+	current   bson.M
+}
+
+type memorySingleResult struct {
+	document bson.M
+	err      error
+}
+
+func (mc *memoryClient) Database(dbName string) Database {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	db, ok := mc.databases[dbName]
+	if !ok {
+		db = &memoryDatabase{client: mc, collections: map[string]*memoryCollection{}}
+		mc.databases[dbName] = db
+	}
+
+	return db
+}
+
+func (mc *memoryClient) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (mc *memoryClient) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+func (mc *memoryClient) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (mc *memoryClient) StartSession() (mongo.Session, error) {
+	return nil, ErrSessionsNotSupported
+}
+
+func (mc *memoryClient) UseSession(ctx context.Context, fn func(mongo.SessionContext) error) error {
+	return ErrSessionsNotSupported
+}
+
+func (md *memoryDatabase) Collection(colName string) Collection {
+	md.mu.Lock()
+	defer md.mu.Unlock()
+
+	collection, ok := md.collections[colName]
+	if !ok {
+		collection = &memoryCollection{}
+		md.collections[colName] = collection
+	}
+
+	return collection
+}
+
+func (md *memoryDatabase) Client() Client {
+	return md.client
+}
+
+func (mc *memoryCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) SingleResult {
+	findOneOptions := options.MergeFindOneOptions(opts...)
+
+	findOptions := options.Find().SetLimit(1)
+	if findOneOptions.Sort != nil {
+		findOptions.SetSort(findOneOptions.Sort)
+	}
+	if findOneOptions.Skip != nil {
+		findOptions.SetSkip(*findOneOptions.Skip)
+	}
+
+	documents, err := mc.find(filter, findOptions)
+	if err != nil {
+		return &memorySingleResult{err: err}
+	}
+	if len(documents) == 0 {
+		return &memorySingleResult{err: ErrNoDocuments}
+	}
+
+	return &memorySingleResult{document: documents[0]}
+}
+
+func (mc *memoryCollection) InsertOne(ctx context.Context, document interface{}) (interface{}, error) {
+	if err := stampTimestamps(document); err != nil {
+		return nil, err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	return mc.insert(document)
+}
+
+func (mc *memoryCollection) InsertMany(ctx context.Context, documents []interface{}) ([]interface{}, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	var ids []interface{}
+	for _, document := range documents {
+		id, err := mc.insert(document)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (mc *memoryCollection) DeleteOne(ctx context.Context, filter interface{}) (int64, error) {
+	return mc.delete(filter, 1)
+}
+
+func (mc *memoryCollection) DeleteMany(ctx context.Context, filter interface{}) (int64, error) {
+	return mc.delete(filter, 0)
+}
+
+func (mc *memoryCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (Cursor, error) {
+	documents, err := mc.find(filter, options.MergeFindOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoryCursor{documents: documents}, nil
+}
+
+func (mc *memoryCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	countOptions := options.MergeCountOptions(opts...)
+
+	findOptions := options.Find()
+	if countOptions.Limit != nil {
+		findOptions.SetLimit(*countOptions.Limit)
+	}
+	if countOptions.Skip != nil {
+		findOptions.SetSkip(*countOptions.Skip)
+	}
+
+	documents, err := mc.find(filter, findOptions)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(documents)), nil
+}
+
+func (mc *memoryCollection) Aggregate(ctx context.Context, pipeline interface{}) (Cursor, error) {
+	stages, err := normalizePipeline(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	mc.mu.RLock()
+	documents := make([]bson.M, len(mc.documents))
+	for i, doc := range mc.documents {
+		documents[i] = copyDocument(doc)
+	}
+	mc.mu.RUnlock()
+
+	for _, stage := range stages {
+		documents, err = stage(documents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &memoryCursor{documents: documents}, nil
+}
+
+func (mc *memoryCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	touchUpdatedAt(update)
+
+	return mc.update(filter, update, options.MergeUpdateOptions(opts...), false)
+}
+
+func (mc *memoryCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return mc.update(filter, update, options.MergeUpdateOptions(opts...), true)
+}
+
+// CreateIndexes only reports the index names; the in-memory database always
+// scans the whole collection.
+func (mc *memoryCollection) CreateIndexes(ctx context.Context, models []IndexModel) ([]string, error) {
+	var names []string
+	for _, model := range models {
+		keys, err := normalizeSort(model.Keys)
+		if err != nil {
+			return nil, err
+		}
+
+		if model.Options != nil && model.Options.Unique != nil && *model.Options.Unique {
+			var fields []string
+			for _, key := range keys {
+				fields = append(fields, key.field)
+			}
+			mc.mu.Lock()
+			mc.unique = append(mc.unique, fields)
+			mc.mu.Unlock()
+		}
+
+		if model.Options != nil && model.Options.Name != nil {
+			names = append(names, *model.Options.Name)
+			continue
+		}
+
+		var parts []string
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s_%d", key.field, key.direction))
+		}
+		names = append(names, strings.Join(parts, "_"))
+	}
+
+	return names, nil
+}
+
+func (mc *memoryCollection) insert(document interface{}) (interface{}, error) {
+	doc, err := normalizeDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, ok := doc["_id"]; !ok || id == nil {
+		doc["_id"] = primitive.NewObjectID()
+	}
+
+	for _, existing := range mc.documents {
+		if valuesEqual(existing["_id"], doc["_id"]) {
+			return nil, ErrDuplicateKey
+		}
+	}
+	if err := mc.checkUnique(doc, -1); err != nil {
+		return nil, err
+	}
+
+	mc.documents = append(mc.documents, doc)
+	return doc["_id"], nil
+}
+
+// checkUnique fails with ErrDuplicateKey when a document other than the one
+// at index skip has the same values as doc for the fields of a unique index.
+func (mc *memoryCollection) checkUnique(doc bson.M, skip int) error {
+	for _, fields := range mc.unique {
+		for i, existing := range mc.documents {
+			if i == skip {
+				continue
+			}
+
+			same := true
+			for _, field := range fields {
+				a, _ := lookupValue(doc, field)
+				b, _ := lookupValue(existing, field)
+				if !valuesEqual(a, b) {
+					same = false
+					break
+				}
+			}
+			if same {
+				return ErrDuplicateKey
+			}
+		}
+	}
+
+	return nil
+}
+
+func (mc *memoryCollection) find(filter interface{}, findOptions *options.FindOptions) ([]bson.M, error) {
+	query, err := normalizeDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	mc.mu.RLock()
+	var documents []bson.M
+	for _, doc := range mc.documents {
+		matched, err := matchDocument(doc, query)
+		if err != nil {
+			mc.mu.RUnlock()
+			return nil, err
+		}
+		if matched {
+			documents = append(documents, copyDocument(doc))
+		}
+	}
+	mc.mu.RUnlock()
+
+	if findOptions.Sort != nil {
+		keys, err := normalizeSort(findOptions.Sort)
+		if err != nil {
+			return nil, err
+		}
+
+		sort.SliceStable(documents, func(i, j int) bool {
+			for _, key := range keys {
+				a, _ := lookupValue(documents[i], key.field)
+				b, _ := lookupValue(documents[j], key.field)
+				if c := sortCompare(a, b); c != 0 {
+					return c*key.direction < 0
+				}
+			}
+			return false
+		})
+	}
+
+	if findOptions.Skip != nil {
+		skip := int(*findOptions.Skip)
+		if skip > len(documents) {
+			skip = len(documents)
+		}
+		documents = documents[skip:]
+	}
+
+	if findOptions.Limit != nil && *findOptions.Limit > 0 && int(*findOptions.Limit) < len(documents) {
+		documents = documents[:*findOptions.Limit]
+	}
+
+	return documents, nil
+}
+
+func (mc *memoryCollection) update(filter interface{}, update interface{}, updateOptions *options.UpdateOptions, many bool) (*mongo.UpdateResult, error) {
+	query, err := normalizeDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	apply, err := updateFunc(update)
+	if err != nil {
+		return nil, err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	result := &mongo.UpdateResult{}
+	for i, doc := range mc.documents {
+		matched, err := matchDocument(doc, query)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		updated, err := apply(copyDocument(doc), false)
+		if err != nil {
+			return nil, err
+		}
+
+		result.MatchedCount++
+		if !reflect.DeepEqual(doc, updated) {
+			if err := mc.checkUnique(updated, i); err != nil {
+				return nil, err
+			}
+			mc.documents[i] = updated
+			result.ModifiedCount++
+		}
+
+		if !many {
+			break
+		}
+	}
+
+	if result.MatchedCount == 0 && updateOptions.Upsert != nil && *updateOptions.Upsert {
+		doc := bson.M{}
+		seedUpsert(doc, query)
+
+		doc, err := apply(doc, true)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := mc.insert(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		result.UpsertedCount = 1
+		result.UpsertedID = id
+	}
+
+	return result, nil
+}
+
+// updateFunc returns the function applying an update, given either as a
+// document of update operators or as a pipeline of $set, $addFields and
+// $unset stages.
+func updateFunc(update interface{}) (func(doc bson.M, inserting bool) (bson.M, error), error) {
+	if isPipeline(update) {
+		stages, names, err := parsePipeline(update)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if name != "$set" && name != "$addFields" && name != "$unset" {
+				return nil, fmt.Errorf("%w in an update: %s", ErrUnsupportedOperator, name)
+			}
+		}
+
+		return func(doc bson.M, inserting bool) (bson.M, error) {
+			documents := []bson.M{doc}
+			for _, stage := range stages {
+				var err error
+				if documents, err = stage(documents); err != nil {
+					return nil, err
+				}
+			}
+			return documents[0], nil
+		}, nil
+	}
+
+	changes, err := normalizeDocument(update)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(doc bson.M, inserting bool) (bson.M, error) {
+		if err := applyUpdate(doc, changes, inserting); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}, nil
+}
+
+// isPipeline reports whether an update is a list of stages rather than a
+// document; bson.D is a slice too, but an ordered document.
+func isPipeline(update interface{}) bool {
+	switch update.(type) {
+	case bson.D, bson.Raw, []byte:
+		return false
+	}
+
+	kind := reflect.ValueOf(update).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+func (mc *memoryCollection) delete(filter interface{}, limit int) (int64, error) {
+	query, err := normalizeDocument(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	// The documents are only replaced once every filter has matched, so a
+	// failing delete leaves the collection as it was.
+	var deleted int64
+	remaining := make([]bson.M, 0, len(mc.documents))
+	for _, doc := range mc.documents {
+		if limit == 0 || deleted < int64(limit) {
+			matched, err := matchDocument(doc, query)
+			if err != nil {
+				return 0, err
+			}
+			if matched {
+				deleted++
+				continue
+			}
+		}
+		remaining = append(remaining, doc)
+	}
+	mc.documents = remaining
+
+	return deleted, nil
+}
+
+func (mr *memoryCursor) Close(ctx context.Context) error {
+	return nil
+}
+
+func (mr *memoryCursor) Next(ctx context.Context) bool {
+	if len(mr.documents) == 0 {
+		return false
+	}
+
+	mr.current = mr.documents[0]
+	mr.documents = mr.documents[1:]
+	return true
+}
+
+func (mr *memoryCursor) Decode(v interface{}) error {
+	return decodeDocument(mr.current, v)
+}
+
+func (mr *memoryCursor) All(ctx context.Context, results interface{}) error {
+	resultsValue := reflect.ValueOf(results)
+	if resultsValue.Kind() != reflect.Ptr || resultsValue.Elem().Kind() != reflect.Slice {
+		return errors.New("results argument must be a pointer to a slice")
+	}
+
+	sliceValue := resultsValue.Elem()
+	elementType := sliceValue.Type().Elem()
+
+	for mr.Next(ctx) {
+		element := reflect.New(elementType)
+		if err := mr.Decode(element.Interface()); err != nil {
+			return err
+		}
+		sliceValue = reflect.Append(sliceValue, element.Elem())
+	}
+
+	resultsValue.Elem().Set(sliceValue)
+	return nil
+}
+
+func (sr *memorySingleResult) Decode(v interface{}) error {
+	if sr.err != nil {
+		return sr.err
+	}
+
+	return decodeDocument(sr.document, v)
+}
+
+// normalizeDocument round-trips a value through BSON so documents, filters
+// and updates all hold the same Go types (primitive.DateTime, int32, bson.M,
+// primitive.A, ...) whatever they were built from.
+func normalizeDocument(document interface{}) (bson.M, error) {
+	if document == nil {
+		return bson.M{}, nil
+	}
+
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func decodeDocument(document bson.M, v interface{}) error {
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	return bson.Unmarshal(data, v)
+}
+
+func copyDocument(document bson.M) bson.M {
+	copied := make(bson.M, len(document))
+	for key, value := range document {
+		copied[key] = copyValue(value)
+	}
+
+	return copied
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.M:
+		return copyDocument(v)
+	case primitive.A:
+		copied := make(primitive.A, len(v))
+		for i, element := range v {
+			copied[i] = copyValue(element)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// lookupValue resolves a dotted path. Arrays met along the way are searched
+// element by element, as MongoDB does, so the result can hold several values.
+func lookupValue(document bson.M, path string) (interface{}, bool) {
+	values := lookupValues(document, strings.Split(path, "."))
+	switch len(values) {
+	case 0:
+		return nil, false
+	case 1:
+		return values[0], true
+	default:
+		return primitive.A(values), true
+	}
+}
+
+func lookupValues(value interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		return []interface{}{value}
+	}
+
+	switch v := value.(type) {
+	case bson.M:
+		field, ok := v[path[0]]
+		if !ok {
+			return nil
+		}
+		return lookupValues(field, path[1:])
+	case primitive.A:
+		var values []interface{}
+		for _, element := range v {
+			values = append(values, lookupValues(element, path)...)
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func matchDocument(document bson.M, filter bson.M) (bool, error) {
+	for key, condition := range filter {
+		var (
+			matched bool
+			err     error
+		)
+
+		switch key {
+		case "$and", "$or", "$nor":
+			matched, err = matchLogical(document, key, condition)
+		default:
+			if strings.HasPrefix(key, "$") {
+				return false, fmt.Errorf("%w: %s", ErrUnsupportedOperator, key)
+			}
+			values := lookupValues(document, strings.Split(key, "."))
+			matched, err = matchCondition(values, condition)
+		}
+
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchLogical(document bson.M, operator string, condition interface{}) (bool, error) {
+	clauses, ok := condition.(primitive.A)
+	if !ok {
+		return false, fmt.Errorf("%s must be an array", operator)
+	}
+
+	for _, clause := range clauses {
+		subFilter, ok := clause.(bson.M)
+		if !ok {
+			return false, fmt.Errorf("%s entries must be documents", operator)
+		}
+
+		matched, err := matchDocument(document, subFilter)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case operator == "$and" && !matched:
+			return false, nil
+		case operator == "$or" && matched:
+			return true, nil
+		case operator == "$nor" && matched:
+			return false, nil
+		}
+	}
+
+	return operator != "$or", nil
+}
+
+// matchCondition tests the values found at a path against either a literal
+// (equality), a regular expression or a document of query operators.
+func matchCondition(values []interface{}, condition interface{}) (bool, error) {
+	if operators, ok := condition.(bson.M); ok && isOperatorDocument(operators) {
+		for operator, operand := range operators {
+			matched, err := matchOperator(values, operator, operand, operators)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	if pattern, ok := condition.(primitive.Regex); ok {
+		return matchRegex(values, pattern)
+	}
+
+	return matchEquality(values, condition), nil
+}
+
+func matchOperator(values []interface{}, operator string, operand interface{}, operators bson.M) (bool, error) {
+	switch operator {
+	case "$eq":
+		return matchEquality(values, operand), nil
+	case "$ne":
+		return !matchEquality(values, operand), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		for _, value := range expandArrays(values) {
+			c, ok := compareValues(value, operand)
+			if !ok {
+				continue
+			}
+			if (operator == "$gt" && c > 0) || (operator == "$gte" && c >= 0) ||
+				(operator == "$lt" && c < 0) || (operator == "$lte" && c <= 0) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "$in", "$nin":
+		candidates, ok := operand.(primitive.A)
+		if !ok {
+			return false, fmt.Errorf("%s needs an array", operator)
+		}
+		found := false
+		for _, candidate := range candidates {
+			matched, err := matchCondition(values, candidate)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				found = true
+				break
+			}
+		}
+		return found == (operator == "$in"), nil
+	case "$all":
+		candidates, ok := operand.(primitive.A)
+		if !ok {
+			return false, errors.New("$all needs an array")
+		}
+		for _, candidate := range candidates {
+			if !matchEquality(values, candidate) {
+				return false, nil
+			}
+		}
+		return len(candidates) > 0, nil
+	case "$exists":
+		exists, _ := operand.(bool)
+		return (len(values) > 0) == exists, nil
+	case "$size":
+		size, ok := toFloat(operand)
+		if !ok {
+			return false, errors.New("$size needs a number")
+		}
+		for _, value := range values {
+			if array, ok := value.(primitive.A); ok && float64(len(array)) == size {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "$regex":
+		pattern := primitive.Regex{}
+		switch p := operand.(type) {
+		case string:
+			pattern.Pattern = p
+		case primitive.Regex:
+			pattern = p
+		default:
+			return false, errors.New("$regex needs a string")
+		}
+		if opts, ok := operators["$options"].(string); ok {
+			pattern.Options = opts
+		}
+		return matchRegex(values, pattern)
+	case "$options":
+		return true, nil
+	case "$not":
+		matched, err := matchCondition(values, operand)
+		return !matched, err
+	case "$elemMatch":
+		subFilter, ok := operand.(bson.M)
+		if !ok {
+			return false, errors.New("$elemMatch needs a document")
+		}
+		for _, value := range values {
+			array, ok := value.(primitive.A)
+			if !ok {
+				continue
+			}
+			for _, element := range array {
+				var (
+					matched bool
+					err     error
+				)
+				if doc, ok := element.(bson.M); ok && !isOperatorDocument(subFilter) {
+					matched, err = matchDocument(doc, subFilter)
+				} else {
+					matched, err = matchCondition([]interface{}{element}, subFilter)
+				}
+				if err != nil {
+					return false, err
+				}
+				if matched {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedOperator, operator)
+	}
+}
+
+func matchEquality(values []interface{}, expected interface{}) bool {
+	// A null query value also matches documents without the field.
+	if expected == nil && len(values) == 0 {
+		return true
+	}
+
+	for _, value := range values {
+		if valuesEqual(value, expected) {
+			return true
+		}
+		if array, ok := value.(primitive.A); ok {
+			for _, element := range array {
+				if valuesEqual(element, expected) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func matchRegex(values []interface{}, pattern primitive.Regex) (bool, error) {
+	expression := pattern.Pattern
+	flags := ""
+	for _, option := range pattern.Options {
+		if strings.ContainsRune("ims", option) {
+			flags += string(option)
+		}
+	}
+	if flags != "" {
+		expression = "(?" + flags + ")" + expression
+	}
+
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return false, err
+	}
+
+	for _, value := range expandArrays(values) {
+		if s, ok := value.(string); ok && re.MatchString(s) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func expandArrays(values []interface{}) []interface{} {
+	var expanded []interface{}
+	for _, value := range values {
+		if array, ok := value.(primitive.A); ok {
+			expanded = append(expanded, array...)
+			continue
+		}
+		expanded = append(expanded, value)
+	}
+
+	return expanded
+}
+
+func isOperatorDocument(document bson.M) bool {
+	if len(document) == 0 {
+		return false
+	}
+
+	for key := range document {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func valuesEqual(a interface{}, b interface{}) bool {
+	if c, ok := compareValues(a, b); ok {
+		return c == 0
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// compareValues orders two values of the same BSON type. The second result is
+// false when the values cannot be compared, in which case range operators do
+// not match.
+func compareValues(a interface{}, b interface{}) (int, bool) {
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return 0, true
+		}
+		return 0, false
+	}
+
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	case primitive.DateTime:
+		y, ok := b.(primitive.DateTime)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case primitive.ObjectID:
+		y, ok := b.(primitive.ObjectID)
+		if !ok {
+			return 0, false
+		}
+		return bytes.Compare(x[:], y[:]), true
+	case bool:
+		y, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x == y:
+			return 0, true
+		case !x:
+			return -1, true
+		default:
+			return 1, true
+		}
+	}
+
+	return 0, false
+}
+
+// sortCompare orders any two values, falling back to MongoDB's ordering of
+// BSON types when they differ.
+func sortCompare(a interface{}, b interface{}) int {
+	if rankA, rankB := typeRank(a), typeRank(b); rankA != rankB {
+		return rankA - rankB
+	}
+
+	c, _ := compareValues(a, b)
+	return c
+}
+
+func typeRank(value interface{}) int {
+	if _, ok := toFloat(value); ok {
+		return 2
+	}
+
+	switch value.(type) {
+	case nil:
+		return 1
+	case string:
+		return 3
+	case bson.M:
+		return 4
+	case primitive.A:
+		return 5
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case primitive.DateTime:
+		return 9
+	default:
+		return 10
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+type sortKey struct {
+	field     string
+	direction int
+}
+
+func normalizeSort(spec interface{}) ([]sortKey, error) {
+	var keys []sortKey
+
+	switch s := spec.(type) {
+	case bson.D:
+		for _, element := range s {
+			direction, ok := toFloat(element.Value)
+			if !ok {
+				return nil, fmt.Errorf("invalid sort direction for %s", element.Key)
+			}
+			keys = append(keys, sortKey{field: element.Key, direction: sortDirection(direction)})
+		}
+	case bson.M:
+		fields := make([]string, 0, len(s))
+		for field := range s {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			direction, ok := toFloat(s[field])
+			if !ok {
+				return nil, fmt.Errorf("invalid sort direction for %s", field)
+			}
+			keys = append(keys, sortKey{field: field, direction: sortDirection(direction)})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported sort specification %T", spec)
+	}
+
+	return keys, nil
+}
+
+func sortDirection(direction float64) int {
+	if direction < 0 {
+		return -1
+	}
+
+	return 1
+}
+
+func applyUpdate(document bson.M, update bson.M, inserting bool) error {
+	if len(update) == 0 {
+		return errUpdateRequiresOperator
+	}
+
+	for operator, fields := range update {
+		changes, ok := fields.(bson.M)
+		if !ok {
+			return errUpdateRequiresOperator
+		}
+
+		for path, value := range changes {
+			var err error
+
+			switch operator {
+			case "$set":
+				setPath(document, path, value)
+			case "$setOnInsert":
+				if inserting {
+					setPath(document, path, value)
+				}
+			case "$unset":
+				unsetPath(document, path)
+			case "$inc":
+				err = incrementPath(document, path, value)
+			case "$push", "$addToSet":
+				err = pushPath(document, path, value, operator == "$addToSet")
+			case "$pull":
+				err = pullPath(document, path, value)
+			default:
+				if strings.HasPrefix(operator, "$") {
+					return fmt.Errorf("%w: %s", ErrUnsupportedOperator, operator)
+				}
+				return errUpdateRequiresOperator
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parentOf returns the document holding the last element of a dotted path,
+// creating intermediate documents as needed.
+func parentOf(document bson.M, path string) (bson.M, string) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := document[part].(bson.M)
+		if !ok {
+			child = bson.M{}
+			document[part] = child
+		}
+		document = child
+	}
+
+	return document, parts[len(parts)-1]
+}
+
+func setPath(document bson.M, path string, value interface{}) {
+	parent, field := parentOf(document, path)
+	parent[field] = copyValue(value)
+}
+
+func unsetPath(document bson.M, path string) {
+	parent, field := parentOf(document, path)
+	delete(parent, field)
+}
+
+func incrementPath(document bson.M, path string, value interface{}) error {
+	parent, field := parentOf(document, path)
+
+	increment, ok := toFloat(value)
+	if !ok {
+		return errors.New("$inc needs a number")
+	}
+
+	current, exists := parent[field]
+	if !exists {
+		parent[field] = value
+		return nil
+	}
+
+	switch v := current.(type) {
+	case int32:
+		parent[field] = v + int32(increment)
+	case int64:
+		parent[field] = v + int64(increment)
+	case float64:
+		parent[field] = v + increment
+	default:
+		return fmt.Errorf("cannot increment non-numeric field %s", path)
+	}
+
+	return nil
+}
+
+func pushPath(document bson.M, path string, value interface{}, unique bool) error {
+	parent, field := parentOf(document, path)
+
+	var array primitive.A
+	if current, exists := parent[field]; exists && current != nil {
+		existing, ok := current.(primitive.A)
+		if !ok {
+			return fmt.Errorf("cannot push to non-array field %s", path)
+		}
+		array = append(array, existing...)
+	}
+
+	values := primitive.A{value}
+	if modifiers, ok := value.(bson.M); ok {
+		if each, ok := modifiers["$each"].(primitive.A); ok {
+			values = each
+		}
+	}
+
+	for _, element := range values {
+		if unique && matchEquality(array, element) {
+			continue
+		}
+		array = append(array, copyValue(element))
+	}
+
+	parent[field] = array
+	return nil
+}
+
+func pullPath(document bson.M, path string, condition interface{}) error {
+	parent, field := parentOf(document, path)
+
+	current, exists := parent[field]
+	if !exists || current == nil {
+		return nil
+	}
+
+	array, ok := current.(primitive.A)
+	if !ok {
+		return fmt.Errorf("cannot pull from non-array field %s", path)
+	}
+
+	remaining := primitive.A{}
+	for _, element := range array {
+		var (
+			matched bool
+			err     error
+		)
+		if subFilter, ok := condition.(bson.M); ok && !isOperatorDocument(subFilter) {
+			if doc, ok := element.(bson.M); ok {
+				matched, err = matchDocument(doc, subFilter)
+			}
+		} else {
+			matched, err = matchCondition([]interface{}{element}, condition)
+		}
+		if err != nil {
+			return err
+		}
+		if !matched {
+			remaining = append(remaining, element)
+		}
+	}
+
+	parent[field] = remaining
+	return nil
+}
+
+// seedUpsert copies the equality conditions of a filter into a document
+// created by an upsert.
+func seedUpsert(document bson.M, filter bson.M) {
+	for key, condition := range filter {
+		if key == "$and" {
+			if clauses, ok := condition.(primitive.A); ok {
+				for _, clause := range clauses {
+					if subFilter, ok := clause.(bson.M); ok {
+						seedUpsert(document, subFilter)
+					}
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+
+		if operators, ok := condition.(bson.M); ok && isOperatorDocument(operators) {
+			if value, ok := operators["$eq"]; ok {
+				setPath(document, key, value)
+			}
+			continue
+		}
+		if _, ok := condition.(primitive.Regex); ok {
+			continue
+		}
+
+		setPath(document, key, condition)
+	}
+}
@@ -0,0 +1,364 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// aggregateStage transforms the documents flowing through a pipeline.
+type aggregateStage func(documents []bson.M) ([]bson.M, error)
+
+// normalizePipeline parses a pipeline given as a mongo.Pipeline, a slice of
+// bson.D or bson.M, or a bson.A. The stages supported are $match, $unwind,
+// $group (with $sum, $min, $max and $first), $set (or $addFields), $unset,
+// $sort, $skip and $limit.
+func normalizePipeline(pipeline interface{}) ([]aggregateStage, error) {
+	stages, _, err := parsePipeline(pipeline)
+	return stages, err
+}
+
+// parsePipeline parses a pipeline like normalizePipeline and also returns the
+// names of its stages.
+func parsePipeline(pipeline interface{}) ([]aggregateStage, []string, error) {
+	data, err := bson.Marshal(bson.M{"pipeline": pipeline})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wrapper struct {
+		Pipeline []bson.Raw `bson:"pipeline"`
+	}
+	if err := bson.Unmarshal(data, &wrapper); err != nil {
+		return nil, nil, err
+	}
+
+	stages := make([]aggregateStage, 0, len(wrapper.Pipeline))
+	names := make([]string, 0, len(wrapper.Pipeline))
+	for _, raw := range wrapper.Pipeline {
+		elements, err := raw.Elements()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(elements) != 1 {
+			return nil, nil, errors.New("a pipeline stage must have exactly one field")
+		}
+
+		stage, err := parseStage(elements[0].Key(), elements[0].Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		stages = append(stages, stage)
+		names = append(names, elements[0].Key())
+	}
+
+	return stages, names, nil
+}
+
+func parseStage(name string, operand bson.RawValue) (aggregateStage, error) {
+	switch name {
+	case "$match":
+		var filter bson.M
+		if err := operand.Unmarshal(&filter); err != nil {
+			return nil, fmt.Errorf("$match needs a document: %w", err)
+		}
+		return matchStage(filter), nil
+	case "$unwind":
+		path, ok := operand.StringValueOK()
+		if !ok {
+			var spec struct {
+				Path string `bson:"path"`
+			}
+			if err := operand.Unmarshal(&spec); err != nil {
+				return nil, fmt.Errorf("$unwind needs a path: %w", err)
+			}
+			path = spec.Path
+		}
+		if !strings.HasPrefix(path, "$") {
+			return nil, errors.New("$unwind path must start with $")
+		}
+		return unwindStage(strings.TrimPrefix(path, "$")), nil
+	case "$group":
+		var spec bson.M
+		if err := operand.Unmarshal(&spec); err != nil {
+			return nil, fmt.Errorf("$group needs a document: %w", err)
+		}
+		return groupStage(spec)
+	case "$set", "$addFields":
+		var spec bson.M
+		if err := operand.Unmarshal(&spec); err != nil {
+			return nil, fmt.Errorf("%s needs a document: %w", name, err)
+		}
+		return setStage(spec), nil
+	case "$unset":
+		var fields []string
+		if field, ok := operand.StringValueOK(); ok {
+			fields = []string{field}
+		} else if err := operand.Unmarshal(&fields); err != nil {
+			return nil, fmt.Errorf("$unset needs a field or an array of fields: %w", err)
+		}
+		return unsetStage(fields), nil
+	case "$sort":
+		var spec bson.D
+		if err := operand.Unmarshal(&spec); err != nil {
+			return nil, fmt.Errorf("$sort needs a document: %w", err)
+		}
+		keys, err := normalizeSort(spec)
+		if err != nil {
+			return nil, err
+		}
+		return sortStage(keys), nil
+	case "$skip", "$limit":
+		n, ok := toFloat(rawValueInterface(operand))
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("%s needs a non-negative number", name)
+		}
+		if name == "$skip" {
+			return skipStage(int(n)), nil
+		}
+		return limitStage(int(n)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedOperator, name)
+	}
+}
+
+func rawValueInterface(value bson.RawValue) interface{} {
+	var v interface{}
+	if err := value.Unmarshal(&v); err != nil {
+		return nil
+	}
+
+	return v
+}
+
+func matchStage(filter bson.M) aggregateStage {
+	return func(documents []bson.M) ([]bson.M, error) {
+		var matched []bson.M
+		for _, doc := range documents {
+			ok, err := matchDocument(doc, filter)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				matched = append(matched, doc)
+			}
+		}
+		return matched, nil
+	}
+}
+
+// unwindStage outputs one document per element of the array at path.
+// Documents without the field or with an empty array are dropped, as MongoDB
+// does by default.
+func unwindStage(path string) aggregateStage {
+	return func(documents []bson.M) ([]bson.M, error) {
+		var unwound []bson.M
+		for _, doc := range documents {
+			value, ok := lookupValue(doc, path)
+			if !ok || value == nil {
+				continue
+			}
+
+			array, ok := value.(primitive.A)
+			if !ok {
+				unwound = append(unwound, doc)
+				continue
+			}
+
+			for _, element := range array {
+				copied := copyDocument(doc)
+				setPath(copied, path, copyValue(element))
+				unwound = append(unwound, copied)
+			}
+		}
+		return unwound, nil
+	}
+}
+
+// setStage sets each field of spec to its expression, evaluated against the
+// document as it was before the stage.
+func setStage(spec bson.M) aggregateStage {
+	return func(documents []bson.M) ([]bson.M, error) {
+		for i, doc := range documents {
+			updated := copyDocument(doc)
+			for path, expression := range spec {
+				value, err := evaluateExpression(doc, expression)
+				if err != nil {
+					return nil, err
+				}
+				setPath(updated, path, value)
+			}
+			documents[i] = updated
+		}
+		return documents, nil
+	}
+}
+
+func unsetStage(fields []string) aggregateStage {
+	return func(documents []bson.M) ([]bson.M, error) {
+		for _, doc := range documents {
+			for _, field := range fields {
+				unsetPath(doc, field)
+			}
+		}
+		return documents, nil
+	}
+}
+
+type accumulator struct {
+	field    string
+	operator string
+	operand  interface{}
+}
+
+// groupStage groups documents by the _id expression and computes the
+// accumulated fields of each group. Groups are output in the order their
+// first document was seen.
+func groupStage(spec bson.M) (aggregateStage, error) {
+	idExpression, ok := spec["_id"]
+	if !ok {
+		return nil, errors.New("$group needs an _id")
+	}
+
+	var accumulators []accumulator
+	for field, value := range spec {
+		if field == "_id" {
+			continue
+		}
+
+		operators, ok := value.(bson.M)
+		if !ok || len(operators) != 1 {
+			return nil, fmt.Errorf("$group field %s needs a single accumulator", field)
+		}
+		for operator, operand := range operators {
+			switch operator {
+			case "$sum", "$min", "$max", "$first":
+			default:
+				return nil, fmt.Errorf("%w: %s", ErrUnsupportedOperator, operator)
+			}
+			accumulators = append(accumulators, accumulator{field: field, operator: operator, operand: operand})
+		}
+	}
+
+	return func(documents []bson.M) ([]bson.M, error) {
+		var groups []bson.M
+		for _, doc := range documents {
+			key, err := evaluateExpression(doc, idExpression)
+			if err != nil {
+				return nil, err
+			}
+
+			var group bson.M
+			for _, candidate := range groups {
+				if valuesEqual(candidate["_id"], key) {
+					group = candidate
+					break
+				}
+			}
+			first := group == nil
+			if first {
+				group = bson.M{"_id": key}
+				groups = append(groups, group)
+			}
+
+			for _, acc := range accumulators {
+				value, err := evaluateExpression(doc, acc.operand)
+				if err != nil {
+					return nil, err
+				}
+				switch acc.operator {
+				case "$sum":
+					group[acc.field] = addNumbers(group[acc.field], value)
+				case "$min", "$max":
+					current, seen := group[acc.field]
+					if value == nil {
+						continue
+					}
+					c := sortCompare(value, current)
+					if !seen || (acc.operator == "$min" && c < 0) || (acc.operator == "$max" && c > 0) {
+						group[acc.field] = value
+					}
+				case "$first":
+					if first {
+						group[acc.field] = value
+					}
+				}
+			}
+		}
+		return groups, nil
+	}, nil
+}
+
+// addNumbers adds value to total for $sum, ignoring non-numeric values. The
+// total stays an integer until a floating point value is added.
+func addNumbers(total interface{}, value interface{}) interface{} {
+	if total == nil {
+		total = int32(0)
+	}
+
+	var integer int64
+	switch v := value.(type) {
+	case int32:
+		integer = int64(v)
+	case int64:
+		integer = v
+	case float64:
+		t, _ := toFloat(total)
+		return t + v
+	default:
+		return total
+	}
+
+	switch t := total.(type) {
+	case int32:
+		sum := int64(t) + integer
+		if sum == int64(int32(sum)) {
+			return int32(sum)
+		}
+		return sum
+	case int64:
+		return t + integer
+	case float64:
+		return t + float64(integer)
+	default:
+		return total
+	}
+}
+
+func sortStage(keys []sortKey) aggregateStage {
+	return func(documents []bson.M) ([]bson.M, error) {
+		sort.SliceStable(documents, func(i, j int) bool {
+			for _, key := range keys {
+				a, _ := lookupValue(documents[i], key.field)
+				b, _ := lookupValue(documents[j], key.field)
+				if c := sortCompare(a, b); c != 0 {
+					return c*key.direction < 0
+				}
+			}
+			return false
+		})
+		return documents, nil
+	}
+}
+
+func skipStage(n int) aggregateStage {
+	return func(documents []bson.M) ([]bson.M, error) {
+		if n >= len(documents) {
+			return nil, nil
+		}
+		return documents[n:], nil
+	}
+}
+
+func limitStage(n int) aggregateStage {
+	return func(documents []bson.M) ([]bson.M, error) {
+		if n > 0 && n < len(documents) {
+			documents = documents[:n]
+		}
+		return documents, nil
+	}
+}
@@ -0,0 +1,272 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// expressionScope holds the document an expression is evaluated against and
+// the variables bound by $map and $filter.
+type expressionScope struct {
+	doc  bson.M
+	vars map[string]interface{}
+}
+
+// bind returns a scope with name bound to value, leaving s unchanged.
+func (s expressionScope) bind(name string, value interface{}) expressionScope {
+	vars := make(map[string]interface{}, len(s.vars)+1)
+	for key, v := range s.vars {
+		vars[key] = v
+	}
+	vars[name] = value
+
+	return expressionScope{doc: s.doc, vars: vars}
+}
+
+// evaluateExpression evaluates an aggregation expression against doc. It
+// understands "$field" paths, "$$variable" references, documents and arrays
+// of expressions, and the operators $literal, $eq, $ne, $in, $cond, $ifNull,
+// $add, $map and $filter.
+func evaluateExpression(doc bson.M, expression interface{}) (interface{}, error) {
+	return expressionScope{doc: doc}.evaluate(expression)
+}
+
+func (s expressionScope) evaluate(expression interface{}) (interface{}, error) {
+	switch e := expression.(type) {
+	case string:
+		return s.resolve(e)
+	case primitive.A:
+		values := make(primitive.A, len(e))
+		for i, element := range e {
+			value, err := s.evaluate(element)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	case bson.M:
+		if len(e) == 1 {
+			for operator, operand := range e {
+				if strings.HasPrefix(operator, "$") {
+					return s.evaluateOperator(operator, operand)
+				}
+			}
+		}
+
+		values := make(bson.M, len(e))
+		for field, element := range e {
+			value, err := s.evaluate(element)
+			if err != nil {
+				return nil, err
+			}
+			values[field] = value
+		}
+		return values, nil
+	default:
+		return expression, nil
+	}
+}
+
+// resolve looks up "$field" paths and "$$variable" references; any other
+// string is a literal.
+func (s expressionScope) resolve(expression string) (interface{}, error) {
+	if strings.HasPrefix(expression, "$$") {
+		name, path, _ := strings.Cut(strings.TrimPrefix(expression, "$$"), ".")
+		value, ok := s.vars[name]
+		if !ok {
+			return nil, fmt.Errorf("undefined variable $$%s", name)
+		}
+		if path == "" {
+			return value, nil
+		}
+		document, ok := value.(bson.M)
+		if !ok {
+			return nil, nil
+		}
+		value, _ = lookupValue(document, path)
+		return value, nil
+	}
+
+	if strings.HasPrefix(expression, "$") {
+		value, _ := lookupValue(s.doc, strings.TrimPrefix(expression, "$"))
+		return value, nil
+	}
+
+	return expression, nil
+}
+
+func (s expressionScope) evaluateOperator(operator string, operand interface{}) (interface{}, error) {
+	if operator == "$literal" {
+		return operand, nil
+	}
+
+	switch operator {
+	case "$eq", "$ne", "$in", "$ifNull", "$add", "$cond":
+		args, err := s.evaluateArgs(operator, operand)
+		if err != nil {
+			return nil, err
+		}
+		return applyOperator(operator, args)
+	case "$map", "$filter":
+		return s.evaluateArrayOperator(operator, operand)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedOperator, operator)
+	}
+}
+
+// evaluateArgs evaluates the arguments of an operator, given as an array or,
+// for $cond, as an {if, then, else} document. $cond only evaluates the branch
+// it takes.
+func (s expressionScope) evaluateArgs(operator string, operand interface{}) ([]interface{}, error) {
+	var args primitive.A
+	switch o := operand.(type) {
+	case primitive.A:
+		args = o
+	case bson.M:
+		if operator != "$cond" {
+			return nil, fmt.Errorf("%s needs an array of arguments", operator)
+		}
+		args = primitive.A{o["if"], o["then"], o["else"]}
+	default:
+		args = primitive.A{o}
+	}
+
+	if operator == "$cond" {
+		if len(args) != 3 {
+			return nil, errors.New("$cond needs if, then and else")
+		}
+		condition, err := s.evaluate(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if isTruthy(condition) {
+			return s.evaluateAll(args[1:2])
+		}
+		return s.evaluateAll(args[2:3])
+	}
+
+	return s.evaluateAll(args)
+}
+
+func (s expressionScope) evaluateAll(expressions primitive.A) ([]interface{}, error) {
+	values := make([]interface{}, len(expressions))
+	for i, expression := range expressions {
+		value, err := s.evaluate(expression)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+func applyOperator(operator string, args []interface{}) (interface{}, error) {
+	switch operator {
+	case "$eq", "$ne":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%s needs two arguments", operator)
+		}
+		return valuesEqual(args[0], args[1]) == (operator == "$eq"), nil
+	case "$in":
+		if len(args) != 2 {
+			return nil, errors.New("$in needs two arguments")
+		}
+		array, ok := args[1].(primitive.A)
+		if !ok {
+			return nil, errors.New("$in needs an array as its second argument")
+		}
+		for _, element := range array {
+			if valuesEqual(element, args[0]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "$ifNull":
+		for _, arg := range args {
+			if arg != nil {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	case "$add":
+		var total interface{} = int32(0)
+		for _, arg := range args {
+			if _, ok := toFloat(arg); !ok {
+				return nil, fmt.Errorf("$add only supports numbers, got %T", arg)
+			}
+			total = addNumbers(total, arg)
+		}
+		return total, nil
+	default:
+		// $cond has already evaluated the branch it takes.
+		return args[0], nil
+	}
+}
+
+// evaluateArrayOperator evaluates $map and $filter, which bind each element
+// of their input to a variable, $$this unless named with as.
+func (s expressionScope) evaluateArrayOperator(operator string, operand interface{}) (interface{}, error) {
+	spec, ok := operand.(bson.M)
+	if !ok {
+		return nil, fmt.Errorf("%s needs a document", operator)
+	}
+
+	input, err := s.evaluate(spec["input"])
+	if err != nil || input == nil {
+		return nil, err
+	}
+	array, ok := input.(primitive.A)
+	if !ok {
+		return nil, fmt.Errorf("%s needs an array as input", operator)
+	}
+
+	name := "this"
+	if as, ok := spec["as"].(string); ok {
+		name = as
+	}
+
+	result := primitive.A{}
+	for _, element := range array {
+		scope := s.bind(name, element)
+		if operator == "$map" {
+			value, err := scope.evaluate(spec["in"])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		keep, err := scope.evaluate(spec["cond"])
+		if err != nil {
+			return nil, err
+		}
+		if isTruthy(keep) {
+			result = append(result, element)
+		}
+	}
+
+	return result, nil
+}
+
+// isTruthy reports whether a value counts as true in an expression: anything
+// but false, null, missing and zero.
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		if n, ok := toFloat(v); ok {
+			return n != 0
+		}
+		return true
+	}
+}
@@ -0,0 +1,438 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-management-api/mongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type memoryTask struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    string             `bson:"userid"`
+	Title     string             `bson:"title"`
+	Priority  int                `bson:"priority"`
+	Tags      []string           `bson:"tags"`
+	DueDate   time.Time          `bson:"dueDate"`
+	DeletedAt *time.Time         `bson:"deletedAt"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+func seedTasks(t *testing.T, collection mongo.Collection) []primitive.ObjectID {
+	base := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tasks := []memoryTask{
+		{UserID: "u1", Title: "Write report", Priority: 3, Tags: []string{"work"}, DueDate: base},
+		{UserID: "u1", Title: "Buy milk", Priority: 1, Tags: []string{"home", "errand"}, DueDate: base.Add(48 * time.Hour)},
+		{UserID: "u1", Title: "Review REPORT draft", Priority: 2, Tags: []string{"work", "review"}, DueDate: base.Add(24 * time.Hour)},
+		{UserID: "u2", Title: "Other user's task", Priority: 3},
+	}
+
+	var ids []primitive.ObjectID
+	for i := range tasks {
+		id, err := collection.InsertOne(context.TODO(), &tasks[i])
+		require.NoError(t, err)
+		ids = append(ids, id.(primitive.ObjectID))
+	}
+
+	return ids
+}
+
+func findTitles(t *testing.T, collection mongo.Collection, filter interface{}, opts ...*options.FindOptions) []string {
+	cursor, err := collection.Find(context.TODO(), filter, opts...)
+	require.NoError(t, err)
+	defer cursor.Close(context.TODO())
+
+	var titles []string
+	for cursor.Next(context.TODO()) {
+		var task memoryTask
+		require.NoError(t, cursor.Decode(&task))
+		titles = append(titles, task.Title)
+	}
+
+	return titles
+}
+
+func TestMemoryCollectionInsertAndFindOne(t *testing.T) {
+	collection := mongo.NewMemoryClient().Database("test").Collection("task")
+
+	task := memoryTask{UserID: "u1", Title: "Write report"}
+	id, err := collection.InsertOne(context.TODO(), &task)
+
+	assert.NoError(t, err)
+	assert.False(t, task.CreatedAt.IsZero())
+	assert.False(t, task.UpdatedAt.IsZero())
+
+	var found memoryTask
+	err = collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&found)
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, found.ID)
+	assert.Equal(t, "Write report", found.Title)
+	assert.Nil(t, found.DeletedAt)
+
+	err = collection.FindOne(context.TODO(), bson.M{"_id": primitive.NewObjectID()}).Decode(&found)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	_, err = collection.InsertOne(context.TODO(), task)
+	assert.Error(t, err, "documents must be passed by pointer like the driver wrapper")
+
+	duplicate := memoryTask{ID: id.(primitive.ObjectID)}
+	_, err = collection.InsertOne(context.TODO(), &duplicate)
+	assert.ErrorIs(t, err, mongo.ErrDuplicateKey)
+}
+
+func TestMemoryCollectionUniqueIndex(t *testing.T) {
+	collection := mongo.NewMemoryClient().Database("test").Collection("user")
+	_, err := collection.CreateIndexes(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	require.NoError(t, err)
+
+	type memoryUser struct {
+		ID       primitive.ObjectID `bson:"_id,omitempty"`
+		Username string             `bson:"username"`
+	}
+	_, err = collection.InsertOne(context.TODO(), &memoryUser{Username: "alice"})
+	require.NoError(t, err)
+	_, err = collection.InsertOne(context.TODO(), &memoryUser{Username: "bob"})
+	require.NoError(t, err)
+
+	_, err = collection.InsertOne(context.TODO(), &memoryUser{Username: "alice"})
+	assert.True(t, mongo.IsDuplicateKeyError(err))
+
+	_, err = collection.UpdateOne(context.TODO(), bson.M{"username": "bob"}, bson.M{"$set": bson.M{"username": "alice"}})
+	assert.True(t, mongo.IsDuplicateKeyError(err))
+
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"username": "bob"}, bson.M{"$set": bson.M{"username": "bob", "name": "Bob"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.ModifiedCount)
+}
+
+func TestMemoryCollectionDatabasesAreShared(t *testing.T) {
+	client := mongo.NewMemoryClient()
+
+	_, err := client.Database("test").Collection("task").InsertOne(context.TODO(), &memoryTask{Title: "Shared"})
+	require.NoError(t, err)
+
+	count, err := client.Database("test").Collection("task").CountDocuments(context.TODO(), bson.M{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = client.Database("other").Collection("task").CountDocuments(context.TODO(), bson.M{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestMemoryCollectionFilters(t *testing.T) {
+	collection := mongo.NewMemoryClient().Database("test").Collection("task")
+	ids := seedTasks(t, collection)
+	base := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	byTitle := options.Find().SetSort(bson.D{{Key: "title", Value: 1}})
+
+	tests := []struct {
+		name     string
+		filter   interface{}
+		expected []string
+	}{
+		{
+			name:     "equality",
+			filter:   bson.M{"userid": "u1", "priority": 3},
+			expected: []string{"Write report"},
+		},
+		{
+			name:     "equality on array element",
+			filter:   bson.M{"tags": "work"},
+			expected: []string{"Review REPORT draft", "Write report"},
+		},
+		{
+			name:     "and",
+			filter:   bson.M{"$and": []bson.M{{"_id": ids[0]}, {"userid": "u1"}}},
+			expected: []string{"Write report"},
+		},
+		{
+			name:     "or",
+			filter:   bson.M{"$or": []bson.M{{"priority": 1}, {"userid": "u2"}}},
+			expected: []string{"Buy milk", "Other user's task"},
+		},
+		{
+			name:     "case insensitive regex",
+			filter:   bson.M{"title": primitive.Regex{Pattern: "report", Options: "i"}},
+			expected: []string{"Review REPORT draft", "Write report"},
+		},
+		{
+			name:     "regex operator",
+			filter:   bson.M{"title": bson.M{"$regex": "^Buy"}},
+			expected: []string{"Buy milk"},
+		},
+		{
+			name:     "comparison on dates",
+			filter:   bson.M{"dueDate": bson.M{"$gt": base, "$lt": base.Add(72 * time.Hour)}},
+			expected: []string{"Buy milk", "Review REPORT draft"},
+		},
+		{
+			name:     "in and nin",
+			filter:   bson.M{"tags": bson.M{"$in": []string{"home", "review"}, "$nin": []string{"errand"}}},
+			expected: []string{"Review REPORT draft"},
+		},
+		{
+			name:     "all",
+			filter:   bson.M{"tags": bson.M{"$all": []string{"work", "review"}}},
+			expected: []string{"Review REPORT draft"},
+		},
+		{
+			name:     "null matches missing fields",
+			filter:   bson.M{"userid": "u2", "tags": nil},
+			expected: []string{"Other user's task"},
+		},
+		{
+			name:     "exists",
+			filter:   bson.M{"missing": bson.M{"$exists": false}, "priority": bson.M{"$gte": 3}},
+			expected: []string{"Other user's task", "Write report"},
+		},
+		{
+			name:     "ne",
+			filter:   bson.M{"userid": bson.M{"$ne": "u1"}},
+			expected: []string{"Other user's task"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, findTitles(t, collection, tt.filter, byTitle))
+		})
+	}
+
+	t.Run("unsupported operator", func(t *testing.T) {
+		_, err := collection.Find(context.TODO(), bson.M{"title": bson.M{"$where": "true"}})
+		assert.ErrorIs(t, err, mongo.ErrUnsupportedOperator)
+	})
+}
+
+func TestMemoryCollectionFindOptions(t *testing.T) {
+	collection := mongo.NewMemoryClient().Database("test").Collection("task")
+	seedTasks(t, collection)
+
+	filter := bson.M{"userid": "u1"}
+
+	titles := findTitles(t, collection, filter, options.Find().SetSort(bson.D{{Key: "priority", Value: -1}}))
+	assert.Equal(t, []string{"Write report", "Review REPORT draft", "Buy milk"}, titles)
+
+	titles = findTitles(t, collection, filter, options.Find().SetSort(bson.D{{Key: "dueDate", Value: 1}}).SetSkip(1).SetLimit(1))
+	assert.Equal(t, []string{"Review REPORT draft"}, titles)
+
+	cursor, err := collection.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "priority", Value: 1}}))
+	require.NoError(t, err)
+
+	var tasks []memoryTask
+	assert.NoError(t, cursor.All(context.TODO(), &tasks))
+	assert.Len(t, tasks, 3)
+	assert.Equal(t, "Buy milk", tasks[0].Title)
+}
+
+func TestMemoryCollectionUpdates(t *testing.T) {
+	collection := mongo.NewMemoryClient().Database("test").Collection("task")
+	ids := seedTasks(t, collection)
+
+	t.Run("set", func(t *testing.T) {
+		result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": ids[0], "userid": "u1"}, bson.M{
+			"$set": bson.M{"title": "Write final report"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.MatchedCount)
+		assert.Equal(t, int64(1), result.ModifiedCount)
+
+		var task memoryTask
+		require.NoError(t, collection.FindOne(context.TODO(), bson.M{"_id": ids[0]}).Decode(&task))
+		assert.Equal(t, "Write final report", task.Title)
+		assert.True(t, task.UpdatedAt.After(task.CreatedAt) || task.UpdatedAt.Equal(task.CreatedAt))
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": ids[0], "userid": "u2"}, bson.M{
+			"$set": bson.M{"title": "Stolen"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), result.MatchedCount)
+		assert.Equal(t, int64(0), result.ModifiedCount)
+	})
+
+	t.Run("conditional update on null field", func(t *testing.T) {
+		filter := bson.M{"_id": ids[1], "deletedAt": nil}
+		update := bson.M{"$set": bson.M{"deletedAt": time.Now()}}
+
+		result, err := collection.UpdateOne(context.TODO(), filter, update)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.ModifiedCount)
+
+		result, err = collection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"deletedAt": time.Now()}})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), result.MatchedCount)
+	})
+
+	t.Run("many with array operators", func(t *testing.T) {
+		result, err := collection.UpdateMany(context.TODO(), bson.M{"tags": "work"}, bson.M{
+			"$pull":     bson.M{"tags": "work"},
+			"$addToSet": bson.M{"tags": bson.M{"$each": []string{"job", "review"}}},
+			"$inc":      bson.M{"priority": 1},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.ModifiedCount)
+
+		var task memoryTask
+		require.NoError(t, collection.FindOne(context.TODO(), bson.M{"_id": ids[2]}).Decode(&task))
+		assert.Equal(t, []string{"review", "job"}, task.Tags)
+		assert.Equal(t, 3, task.Priority)
+	})
+
+	t.Run("pipeline", func(t *testing.T) {
+		result, err := collection.UpdateMany(context.TODO(), bson.M{"tags": "home"}, []bson.D{
+			{{Key: "$set", Value: bson.M{
+				"tags": bson.M{"$map": bson.M{
+					"input": "$tags",
+					"in":    bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$$this", "home"}}, bson.M{"$literal": "$house"}, "$$this"}},
+				}},
+				"priority": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$priority", 0}}, 10}},
+			}}},
+			{{Key: "$set", Value: bson.M{
+				"tags": bson.M{"$filter": bson.M{"input": "$tags", "as": "tag", "cond": bson.M{"$ne": bson.A{"$$tag", "errand"}}}},
+			}}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.ModifiedCount)
+
+		var task memoryTask
+		require.NoError(t, collection.FindOne(context.TODO(), bson.M{"_id": ids[1]}).Decode(&task))
+		assert.Equal(t, []string{"$house"}, task.Tags)
+		assert.Equal(t, 11, task.Priority)
+	})
+
+	t.Run("pipeline stages not allowed in updates", func(t *testing.T) {
+		_, err := collection.UpdateMany(context.TODO(), bson.M{}, bson.A{bson.M{"$match": bson.M{"userid": "u1"}}})
+		assert.ErrorIs(t, err, mongo.ErrUnsupportedOperator)
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": "jti"}, bson.M{
+			"$set": bson.M{"userid": "u3"},
+		}, options.Update().SetUpsert(true))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.UpsertedCount)
+		assert.Equal(t, "jti", result.UpsertedID)
+
+		var doc bson.M
+		require.NoError(t, collection.FindOne(context.TODO(), bson.M{"_id": "jti"}).Decode(&doc))
+		assert.Equal(t, "u3", doc["userid"])
+	})
+
+	t.Run("replacement documents are rejected", func(t *testing.T) {
+		_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": ids[0]}, bson.M{"title": "Replaced"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryCollectionDelete(t *testing.T) {
+	collection := mongo.NewMemoryClient().Database("test").Collection("task")
+	seedTasks(t, collection)
+
+	deleted, err := collection.DeleteOne(context.TODO(), bson.M{"userid": "u1"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = collection.DeleteMany(context.TODO(), bson.M{"userid": "u1"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	count, err := collection.CountDocuments(context.TODO(), bson.M{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMemoryCollectionFailedDelete(t *testing.T) {
+	collection := mongo.NewMemoryClient().Database("test").Collection("task")
+	seedTasks(t, collection)
+
+	// The first task matches, the second does not, and the filter fails on
+	// the third.
+	_, err := collection.DeleteMany(context.TODO(), bson.M{"$and": bson.A{
+		bson.M{"priority": bson.M{"$ne": 1}},
+		bson.M{"$or": bson.A{
+			bson.M{"title": "Write report"},
+			bson.M{"tags": bson.M{"$elemMatch": bson.M{"$unknown": 1}}},
+		}},
+	}})
+	assert.ErrorIs(t, err, mongo.ErrUnsupportedOperator)
+
+	assert.Equal(t, []string{"Write report", "Buy milk", "Review REPORT draft", "Other user's task"}, findTitles(t, collection, bson.M{}))
+}
+
+func TestMemoryCollectionAggregate(t *testing.T) {
+	collection := mongo.NewMemoryClient().Database("test").Collection("task")
+	seedTasks(t, collection)
+
+	type tagCount struct {
+		Tag         string `bson:"_id"`
+		Count       int    `bson:"count"`
+		MaxPriority int    `bson:"maxPriority"`
+	}
+
+	aggregate := func(pipeline interface{}) []tagCount {
+		cursor, err := collection.Aggregate(context.TODO(), pipeline)
+		require.NoError(t, err)
+
+		var counts []tagCount
+		require.NoError(t, cursor.All(context.TODO(), &counts))
+		return counts
+	}
+
+	t.Run("counts tags", func(t *testing.T) {
+		counts := aggregate(bson.A{
+			bson.M{"$match": bson.M{"userid": "u1"}},
+			bson.M{"$unwind": "$tags"},
+			bson.M{"$group": bson.M{
+				"_id":         "$tags",
+				"count":       bson.M{"$sum": 1},
+				"maxPriority": bson.M{"$max": "$priority"},
+			}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		})
+
+		assert.Equal(t, []tagCount{
+			{Tag: "work", Count: 2, MaxPriority: 3},
+			{Tag: "errand", Count: 1, MaxPriority: 1},
+			{Tag: "home", Count: 1, MaxPriority: 1},
+			{Tag: "review", Count: 1, MaxPriority: 2},
+		}, counts)
+	})
+
+	t.Run("skip and limit", func(t *testing.T) {
+		counts := aggregate([]bson.D{
+			{{Key: "$unwind", Value: bson.M{"path": "$tags"}}},
+			{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+			{{Key: "$sort", Value: bson.M{"_id": 1}}},
+			{{Key: "$skip", Value: 1}},
+			{{Key: "$limit", Value: 2}},
+		})
+
+		assert.Equal(t, []tagCount{{Tag: "home", Count: 1}, {Tag: "review", Count: 1}}, counts)
+	})
+
+	t.Run("unsupported stage", func(t *testing.T) {
+		_, err := collection.Aggregate(context.TODO(), bson.A{bson.M{"$lookup": bson.M{"from": "user"}}})
+		assert.ErrorIs(t, err, mongo.ErrUnsupportedOperator)
+	})
+}
@@ -16,6 +16,14 @@ import (
 
 var ErrNoDocuments = mongo.ErrNoDocuments
 
+// IsDuplicateKeyError reports whether a write failed on a unique index, of
+// MongoDB or of the in-memory database.
+func IsDuplicateKeyError(err error) bool {
+	return errors.Is(err, ErrDuplicateKey) || mongo.IsDuplicateKeyError(err)
+}
+
+type IndexModel = mongo.IndexModel
+
 type Database interface {
 	Collection(string) Collection
 	Client() Client
@@ -32,6 +40,7 @@ type Collection interface {
 	Aggregate(context.Context, interface{}) (Cursor, error)
 	UpdateOne(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	UpdateMany(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	CreateIndexes(context.Context, []IndexModel) ([]string, error)
 }
 
 type SingleResult interface {
@@ -148,6 +157,26 @@ func (mc *mongoCollection) FindOne(ctx context.Context, filter interface{}, opts
 }
 
 func (mc *mongoCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	touchUpdatedAt(update)
+
+	return mc.coll.UpdateOne(ctx, filter, update, opts[:]...)
+}
+
+func (mc *mongoCollection) InsertOne(ctx context.Context, document interface{}) (interface{}, error) {
+	if err := stampTimestamps(document); err != nil {
+		return nil, err
+	}
+
+	result, err := mc.coll.InsertOne(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.InsertedID, nil
+}
+
+// touchUpdatedAt sets "updatedAt" in the "$set" map of an update document.
+func touchUpdatedAt(update interface{}) {
 	doc := reflect.ValueOf(update)
 
 	// Check if the provided interface is a pointer to a map
@@ -165,21 +194,21 @@ func (mc *mongoCollection) UpdateOne(ctx context.Context, filter interface{}, up
 			}
 		}
 	}
-
-	return mc.coll.UpdateOne(ctx, filter, update, opts[:]...)
 }
 
-func (mc *mongoCollection) InsertOne(ctx context.Context, document interface{}) (interface{}, error) {
+// stampTimestamps sets the CreatedAt and UpdatedAt fields of a document about
+// to be inserted. The document must be a pointer to a struct.
+func stampTimestamps(document interface{}) error {
 	doc := reflect.ValueOf(document)
 
 	if doc.Kind() != reflect.Ptr {
-		return nil, errors.New("document must be a pointer to a struct")
+		return errors.New("document must be a pointer to a struct")
 	}
 
 	doc = doc.Elem()
 
 	if doc.Kind() != reflect.Struct {
-		return nil, errors.New("document must be a struct")
+		return errors.New("document must be a struct")
 	}
 
 	now := time.Now()
@@ -194,12 +223,7 @@ func (mc *mongoCollection) InsertOne(ctx context.Context, document interface{})
 		updatedAtField.Set(reflect.ValueOf(now))
 	}
 
-	result, err := mc.coll.InsertOne(ctx, document)
-	if err != nil {
-		return nil, err
-	}
-
-	return result.InsertedID, nil
+	return nil
 }
 
 
@@ -234,6 +258,10 @@ func (mc *mongoCollection) CountDocuments(ctx context.Context, filter interface{
 	return mc.coll.CountDocuments(ctx, filter, opts...)
 }
 
+func (mc *mongoCollection) CreateIndexes(ctx context.Context, models []IndexModel) ([]string, error) {
+	return mc.coll.Indexes().CreateMany(ctx, models)
+}
+
 func (sr *mongoSingleResult) Decode(v interface{}) error {
 	return sr.sr.Decode(v)
 }
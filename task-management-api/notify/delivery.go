@@ -0,0 +1,157 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"task-management-api/domain/entities"
+	"time"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+// SignWebhook returns the signature of a delivery body sent at timestamp, in
+// Unix seconds: "sha256=" followed by the hex encoded HMAC-SHA256 of
+// "<timestamp>.<body>", keyed with the secret of the webhook. Receivers
+// compute it again to check that a delivery comes from this service and was
+// not altered or replayed later.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckWebhookAddress refuses the addresses users must not reach through
+// their webhooks: loopback, private, link-local, multicast and unspecified
+// ones, which belong to this host or the network it runs in.
+func CheckWebhookAddress(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("webhook address %s is not public", ip)
+	}
+
+	return nil
+}
+
+// NewWebhookClient returns a client for webhook requests, timing out after 10
+// seconds. It does not follow redirects, and it passes every address it
+// connects to, after DNS resolution, to checkAddress, so that a host resolving
+// to another address later is checked as well.
+func NewWebhookClient(checkAddress func(net.IP) error) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return fmt.Errorf("webhook address %s is not an IP address", host)
+			}
+			return checkAddress(ip)
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   webhookTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// WebhookSender posts deliveries to the URLs of webhooks, signed with
+// SignWebhook.
+type WebhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender returns a sender posting with client, or when client is nil
+// with a client from NewWebhookClient that only reaches public addresses.
+// Redirects answer as they are, and count as failures.
+func NewWebhookSender(client *http.Client) *WebhookSender {
+	if client == nil {
+		client = NewWebhookClient(CheckWebhookAddress)
+	}
+
+	return &WebhookSender{
+		client: client,
+	}
+}
+
+// CheckURL resolves the host of a webhook URL and fails unless every address
+// it resolves to passes CheckWebhookAddress.
+func (ws *WebhookSender) CheckURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil {
+		return CheckWebhookAddress(ip)
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, parsed.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if err := CheckWebhookAddress(address.IP); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ws *WebhookSender) Send(ctx context.Context, webhook entities.Webhook, delivery entities.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderWebhookEvent, delivery.Event)
+	request.Header.Set(HeaderWebhookDelivery, delivery.ID.Hex())
+	request.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderWebhookSignature, SignWebhook(webhook.Secret, timestamp, body))
+
+	response, err := ws.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("webhook answered %s", response.Status)
+	}
+
+	return response.StatusCode, nil
+}
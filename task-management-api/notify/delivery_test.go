@@ -0,0 +1,173 @@
+package notify_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"task-management-api/domain/entities"
+	"task-management-api/notify"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSignWebhook(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte(`1727697600.{"event":"task.created"}`))
+
+	signature := notify.SignWebhook("whsec_test", 1727697600, []byte(`{"event":"task.created"}`))
+
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+	assert.NotEqual(t, signature, notify.SignWebhook("whsec_other", 1727697600, []byte(`{"event":"task.created"}`)))
+	assert.NotEqual(t, signature, notify.SignWebhook("whsec_test", 1727697601, []byte(`{"event":"task.created"}`)))
+}
+
+func TestWebhookSender(t *testing.T) {
+	webhook := entities.Webhook{ID: primitive.NewObjectID(), UserID: "u1", Secret: "whsec_test", Events: []string{entities.EventTaskCreated}}
+	delivery := entities.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID.Hex(), Event: entities.EventTaskCreated,
+		Payload: `{"id":"e1","event":"task.created"}`}
+
+	t.Run("posts the signed payload", func(t *testing.T) {
+		type request struct {
+			header http.Header
+			body   string
+		}
+		received := make(chan request, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			received <- request{header: r.Header, body: string(body)}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		webhook := webhook
+		webhook.URL = server.URL
+		before := time.Now().Unix()
+
+		status, err := notify.NewWebhookSender(server.Client()).Send(context.TODO(), webhook, delivery)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, status)
+
+		got := <-received
+		assert.Equal(t, delivery.Payload, got.body)
+		assert.Equal(t, "application/json", got.header.Get("Content-Type"))
+		assert.Equal(t, "task.created", got.header.Get(notify.HeaderWebhookEvent))
+		assert.Equal(t, delivery.ID.Hex(), got.header.Get(notify.HeaderWebhookDelivery))
+
+		timestamp, err := strconv.ParseInt(got.header.Get(notify.HeaderWebhookTimestamp), 10, 64)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, timestamp, before)
+		assert.Equal(t, notify.SignWebhook("whsec_test", timestamp, []byte(delivery.Payload)), got.header.Get(notify.HeaderWebhookSignature))
+	})
+
+	t.Run("fails on an error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		webhook := webhook
+		webhook.URL = server.URL
+
+		status, err := notify.NewWebhookSender(server.Client()).Send(context.TODO(), webhook, delivery)
+
+		assert.ErrorContains(t, err, "webhook answered 500 Internal Server Error")
+		assert.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("fails without an answer", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		webhook := webhook
+		webhook.URL = server.URL
+		server.Close()
+
+		status, err := notify.NewWebhookSender(server.Client()).Send(context.TODO(), webhook, delivery)
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, status)
+	})
+}
+
+func TestCheckWebhookAddress(t *testing.T) {
+	for _, address := range []string{"127.0.0.1", "::1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "fd00::1", "169.254.169.254",
+		"fe80::1", "0.0.0.0", "::", "224.0.0.1", "::ffff:127.0.0.1"} {
+		t.Run(address, func(t *testing.T) {
+			assert.ErrorContains(t, notify.CheckWebhookAddress(net.ParseIP(address)), "is not public")
+		})
+	}
+
+	t.Run("public", func(t *testing.T) {
+		assert.NoError(t, notify.CheckWebhookAddress(net.ParseIP("93.184.216.34")))
+		assert.NoError(t, notify.CheckWebhookAddress(net.ParseIP("2606:2800:220:1::1")))
+	})
+}
+
+func TestWebhookSenderCheckURL(t *testing.T) {
+	sender := notify.NewWebhookSender(nil)
+
+	for _, url := range []string{"http://127.0.0.1:8080/hook", "http://[::1]/hook", "http://169.254.169.254/latest/meta-data",
+		"https://10.1.2.3/hook", "http://localhost/hook"} {
+		t.Run(url, func(t *testing.T) {
+			assert.ErrorContains(t, sender.CheckURL(context.TODO(), url), "is not public")
+		})
+	}
+
+	t.Run("public", func(t *testing.T) {
+		assert.NoError(t, sender.CheckURL(context.TODO(), "https://93.184.216.34/hook"))
+	})
+}
+
+func TestWebhookSenderRefusesInternalNetwork(t *testing.T) {
+	webhook := entities.Webhook{ID: primitive.NewObjectID(), UserID: "u1", Secret: "whsec_test", Events: []string{entities.EventTaskCreated}}
+	delivery := entities.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID.Hex(), Event: entities.EventTaskCreated,
+		Payload: `{"id":"e1","event":"task.created"}`}
+
+	t.Run("at dial time", func(t *testing.T) {
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		webhook := webhook
+		webhook.URL = server.URL
+
+		status, err := notify.NewWebhookSender(nil).Send(context.TODO(), webhook, delivery)
+
+		assert.ErrorContains(t, err, "is not public")
+		assert.Equal(t, 0, status)
+		assert.False(t, called)
+	})
+
+	t.Run("through redirects", func(t *testing.T) {
+		redirected := false
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			redirected = true
+		}))
+		defer target.Close()
+		server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+		defer server.Close()
+
+		webhook := webhook
+		webhook.URL = server.URL
+
+		allowAll := func(net.IP) error { return nil }
+		status, err := notify.NewWebhookSender(notify.NewWebhookClient(allowAll)).Send(context.TODO(), webhook, delivery)
+
+		assert.ErrorContains(t, err, "webhook answered 302 Found")
+		assert.Equal(t, http.StatusFound, status)
+		assert.False(t, redirected)
+	})
+}
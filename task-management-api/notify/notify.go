@@ -0,0 +1,25 @@
+// Package notify delivers notifications to users, by email or to a webhook,
+// and posts task events to the webhooks users registered.
+package notify
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/entities"
+)
+
+// Multi sends every notification over each of its notifiers in turn. A
+// failure of one does not keep the others from being tried, and all failures
+// are returned together.
+type Multi []entities.Notifier
+
+func (m Multi) Notify(ctx context.Context, notification entities.Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"task-management-api/domain/entities"
+	"time"
+)
+
+// SMTPNotifier emails notifications through an SMTP server. It switches to
+// TLS when the server offers STARTTLS and only authenticates when given a
+// username. Users without an email address are skipped.
+type SMTPNotifier struct {
+	addr     string
+	host     string
+	from     string
+	sender   string
+	username string
+	password string
+}
+
+// NewSMTPNotifier returns a notifier sending mail from the address from, which
+// may include a display name, through the server at addr, a host:port pair.
+func NewSMTPNotifier(addr string, from string, username string, password string) *SMTPNotifier {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	sender := from
+	if address, err := mail.ParseAddress(from); err == nil {
+		sender = address.Address
+	}
+
+	return &SMTPNotifier{
+		addr:     addr,
+		host:     host,
+		from:     from,
+		sender:   sender,
+		username: username,
+		password: password,
+	}
+}
+
+func (sn *SMTPNotifier) Notify(ctx context.Context, notification entities.Notification) error {
+	if notification.Email == "" {
+		return nil
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", sn.addr)
+	if err != nil {
+		return fmt.Errorf("connect to the SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sn.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("connect to the SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: sn.host}); err != nil {
+			return fmt.Errorf("start TLS: %w", err)
+		}
+	}
+	if sn.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", sn.username, sn.password, sn.host)); err != nil {
+			return fmt.Errorf("authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(sn.sender); err != nil {
+		return err
+	}
+	if err := client.Rcpt(notification.Email); err != nil {
+		return err
+	}
+
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := data.Write(sn.message(notification)); err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// message formats a plain text email. Header values are encoded when they
+// hold anything but printable ASCII, which also keeps line breaks in task
+// titles out of the headers.
+func (sn *SMTPNotifier) message(notification entities.Notification) []byte {
+	var message strings.Builder
+	header := func(name, value string) {
+		message.WriteString(name + ": " + value + "\r\n")
+	}
+
+	header("From", sn.from)
+	header("To", notification.Email)
+	header("Subject", mime.QEncoding.Encode("utf-8", notification.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	message.WriteString("\r\n")
+	message.WriteString(notification.Message + "\r\n")
+
+	return []byte(message.String())
+}
@@ -0,0 +1,194 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net"
+	"strings"
+	"task-management-api/domain/entities"
+	"task-management-api/notify"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMail is what the fake SMTP server received in one session.
+type fakeMail struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// startFakeSMTPServer accepts SMTP sessions on a local port and sends what
+// each delivered on the returned channel. Recipients listed in reject are
+// refused.
+func startFakeSMTPServer(t *testing.T, reject ...string) (string, <-chan fakeMail) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan fakeMail, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeSMTP(conn, mails, reject)
+		}
+	}()
+
+	return listener.Addr().String(), mails
+}
+
+func serveFakeSMTP(conn net.Conn, mails chan<- fakeMail, reject []string) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			conn.Write([]byte(line + "\r\n"))
+		}
+	}
+
+	var mail fakeMail
+	reply("220 fake.test ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+
+		switch verb {
+		case "EHLO":
+			reply("250-fake.test", "250-8BITMIME", "250 AUTH PLAIN")
+		case "AUTH":
+			fields := strings.Fields(command)
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			mail.auth = string(decoded)
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			mail.from = command
+			reply("250 OK")
+		case "RCPT":
+			refused := false
+			for _, address := range reject {
+				refused = refused || strings.Contains(command, address)
+			}
+			if refused {
+				reply("550 No such user")
+				continue
+			}
+			mail.to = append(mail.to, command)
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			mail.data = data.String()
+			mails <- mail
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func reminderNotification() entities.Notification {
+	return entities.Notification{
+		Event:    entities.NotificationTaskReminder,
+		UserID:   "u1",
+		Username: "alice",
+		Email:    "alice@example.com",
+		TaskID:   "t1",
+		Title:    "Write report",
+		DueDate:  time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC),
+		Subject:  "Reminder: Write report",
+		Message:  `"Write report" is due on Tue, 01 Oct 2024 09:00 UTC.`,
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	t.Run("sends the notification by email", func(t *testing.T) {
+		addr, mails := startFakeSMTPServer(t)
+		notifier := notify.NewSMTPNotifier(addr, "Tasks <tasks@example.com>", "", "")
+
+		require.NoError(t, notifier.Notify(context.TODO(), reminderNotification()))
+
+		mail := <-mails
+		assert.Empty(t, mail.auth)
+		assert.Equal(t, "MAIL FROM:<tasks@example.com> BODY=8BITMIME", mail.from)
+		assert.Equal(t, []string{"RCPT TO:<alice@example.com>"}, mail.to)
+		assert.Contains(t, mail.data, "From: Tasks <tasks@example.com>\r\n")
+		assert.Contains(t, mail.data, "To: alice@example.com\r\n")
+		assert.Contains(t, mail.data, "Subject: Reminder: Write report\r\n")
+		assert.Contains(t, mail.data, "\r\n\r\n\"Write report\" is due on Tue, 01 Oct 2024 09:00 UTC.\r\n")
+	})
+
+	t.Run("authenticates when given a username", func(t *testing.T) {
+		addr, mails := startFakeSMTPServer(t)
+		notifier := notify.NewSMTPNotifier(addr, "tasks@example.com", "mailer", "secret")
+
+		require.NoError(t, notifier.Notify(context.TODO(), reminderNotification()))
+
+		assert.Equal(t, "\x00mailer\x00secret", (<-mails).auth)
+	})
+
+	t.Run("encodes line breaks in the subject", func(t *testing.T) {
+		addr, mails := startFakeSMTPServer(t)
+		notifier := notify.NewSMTPNotifier(addr, "tasks@example.com", "", "")
+		notification := reminderNotification()
+		notification.Subject = "Reminder: Write\r\nBcc: eve@example.com"
+
+		require.NoError(t, notifier.Notify(context.TODO(), notification))
+
+		mail := <-mails
+		assert.NotContains(t, mail.data, "\r\nBcc:")
+		assert.Equal(t, []string{"RCPT TO:<alice@example.com>"}, mail.to)
+	})
+
+	t.Run("skips users without an email address", func(t *testing.T) {
+		notifier := notify.NewSMTPNotifier("127.0.0.1:1", "tasks@example.com", "", "")
+		notification := reminderNotification()
+		notification.Email = ""
+
+		assert.NoError(t, notifier.Notify(context.TODO(), notification))
+	})
+
+	t.Run("fails when the recipient is refused", func(t *testing.T) {
+		addr, _ := startFakeSMTPServer(t, "alice@example.com")
+		notifier := notify.NewSMTPNotifier(addr, "tasks@example.com", "", "")
+
+		assert.Error(t, notifier.Notify(context.TODO(), reminderNotification()))
+	})
+
+	t.Run("fails when the server is unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+
+		notifier := notify.NewSMTPNotifier(addr, "tasks@example.com", "", "")
+
+		assert.Error(t, notifier.Notify(context.TODO(), reminderNotification()))
+	})
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"task-management-api/domain/entities"
+	"time"
+)
+
+// webhookTimeout bounds a webhook request when no client is given.
+const webhookTimeout = 10 * time.Second
+
+// WebhookNotifier posts notifications as JSON to a URL. Any answer but a 2xx
+// status is a failure.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a notifier posting to url with client, or with a
+// client timing out after 10 seconds when client is nil.
+func NewWebhookNotifier(url string, client *http.Client) *WebhookNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+
+	return &WebhookNotifier{
+		url:    url,
+		client: client,
+	}
+}
+
+func (wn *WebhookNotifier) Notify(ctx context.Context, notification entities.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := wn.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", response.Status)
+	}
+
+	return nil
+}
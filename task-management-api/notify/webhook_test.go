@@ -0,0 +1,67 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task-management-api/domain/mocks"
+	"task-management-api/notify"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Run("posts the notification as JSON", func(t *testing.T) {
+		received := make(chan map[string]interface{}, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			received <- body
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		notifier := notify.NewWebhookNotifier(server.URL, nil)
+
+		require.NoError(t, notifier.Notify(context.TODO(), reminderNotification()))
+
+		body := <-received
+		assert.Equal(t, "task.reminder", body["event"])
+		assert.Equal(t, "alice", body["username"])
+		assert.Equal(t, "t1", body["task_id"])
+		assert.Equal(t, "2024-10-01T09:00:00Z", body["due_date"])
+	})
+
+	t.Run("fails on an error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		notifier := notify.NewWebhookNotifier(server.URL, nil)
+
+		err := notifier.Notify(context.TODO(), reminderNotification())
+
+		assert.ErrorContains(t, err, "503")
+	})
+}
+
+func TestMulti(t *testing.T) {
+	notification := reminderNotification()
+	failing := mocks.NewNotifier(t)
+	failing.On("Notify", mock.Anything, notification).Return(errors.New("smtp down")).Once()
+	working := mocks.NewNotifier(t)
+	working.On("Notify", mock.Anything, notification).Return(nil).Once()
+
+	err := notify.Multi{failing, working}.Notify(context.TODO(), notification)
+
+	assert.ErrorContains(t, err, "smtp down")
+}
@@ -0,0 +1,172 @@
+// Package ratelimit provides in-memory implementations of the rate limiting
+// stores. They only limit the process they run in; several instances of the
+// service need a shared entities.RateLimitStore instead.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"task-management-api/domain/entities"
+)
+
+// sweepInterval is how often idle entries are dropped from the stores.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   entities.RateLimit
+}
+
+// MemoryStore keeps token buckets in process memory.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns an empty store reading the time from now, or from
+// time.Now when now is nil.
+func NewMemoryStore(now func() time.Time) *MemoryStore {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     now,
+	}
+}
+
+func (ms *MemoryStore) Allow(ctx context.Context, key string, limit entities.RateLimit) (entities.RateLimitDecision, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := ms.now()
+	ms.sweep(now)
+
+	b, ok := ms.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		ms.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	decision := entities.RateLimitDecision{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+
+	decision.Remaining = int(b.tokens)
+	decision.ResetAfter = secondsToDuration((float64(limit.Burst) - b.tokens) / limit.Rate)
+
+	return decision, nil
+}
+
+// sweep drops the buckets that have refilled completely, which behave exactly
+// like missing ones.
+func (ms *MemoryStore) sweep(now time.Time) {
+	if now.Sub(ms.lastSweep) < sweepInterval {
+		return
+	}
+	ms.lastSweep = now
+
+	for key, b := range ms.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(ms.buckets, key)
+		}
+	}
+}
+
+// MemoryLoginAttemptStore keeps failed login counts in process memory.
+type MemoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	failures  map[string]entities.LoginFailures
+	retention time.Duration
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryLoginAttemptStore returns a store forgetting failures once no new
+// failure was recorded for retention. Time is read from now, or from time.Now
+// when now is nil.
+func NewMemoryLoginAttemptStore(retention time.Duration, now func() time.Time) *MemoryLoginAttemptStore {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &MemoryLoginAttemptStore{
+		failures:  map[string]entities.LoginFailures{},
+		retention: retention,
+		now:       now,
+	}
+}
+
+func (ms *MemoryLoginAttemptStore) GetFailures(ctx context.Context, username string) (entities.LoginFailures, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return ms.current(username, ms.now()), nil
+}
+
+func (ms *MemoryLoginAttemptStore) RecordAttempt(ctx context.Context, username string, lockedUntil func(entities.LoginFailures) time.Time) (entities.LoginFailures, time.Time, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := ms.now()
+	ms.sweep(now)
+
+	failures := ms.current(username, now)
+	if until := lockedUntil(failures); now.Before(until) {
+		return failures, until, nil
+	}
+
+	failures.Count++
+	failures.LastFailure = now
+	ms.failures[username] = failures
+
+	return failures, time.Time{}, nil
+}
+
+func (ms *MemoryLoginAttemptStore) ResetFailures(ctx context.Context, username string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.failures, username)
+	return nil
+}
+
+func (ms *MemoryLoginAttemptStore) current(username string, now time.Time) entities.LoginFailures {
+	failures, ok := ms.failures[username]
+	if !ok || now.Sub(failures.LastFailure) >= ms.retention {
+		return entities.LoginFailures{}
+	}
+
+	return failures
+}
+
+func (ms *MemoryLoginAttemptStore) sweep(now time.Time) {
+	if now.Sub(ms.lastSweep) < sweepInterval {
+		return
+	}
+	ms.lastSweep = now
+
+	for username, failures := range ms.failures {
+		if now.Sub(failures.LastFailure) >= ms.retention {
+			delete(ms.failures, username)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,160 @@
+package ratelimit_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-management-api/domain/entities"
+	"task-management-api/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.now = fc.now.Add(d)
+}
+
+func TestMemoryStore(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)}
+	store := ratelimit.NewMemoryStore(clock.Now)
+	limit := entities.RateLimit{Rate: 1, Burst: 3}
+	ctx := context.TODO()
+
+	for i := 2; i >= 0; i-- {
+		decision, err := store.Allow(ctx, "ip:1", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 3, decision.Limit)
+		assert.Equal(t, i, decision.Remaining)
+	}
+
+	decision, err := store.Allow(ctx, "ip:1", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	assert.Equal(t, time.Second, decision.RetryAfter)
+	assert.Equal(t, 3*time.Second, decision.ResetAfter)
+
+	t.Run("buckets are per key", func(t *testing.T) {
+		decision, err := store.Allow(ctx, "ip:2", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	})
+
+	t.Run("tokens refill over time", func(t *testing.T) {
+		clock.Advance(1500 * time.Millisecond)
+
+		decision, err := store.Allow(ctx, "ip:1", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 0, decision.Remaining)
+
+		decision, err = store.Allow(ctx, "ip:1", limit)
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+	})
+
+	t.Run("refilled buckets are swept", func(t *testing.T) {
+		clock.Advance(2 * time.Minute)
+
+		decision, err := store.Allow(ctx, "ip:1", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 2, decision.Remaining)
+	})
+}
+
+func neverLocked(entities.LoginFailures) time.Time {
+	return time.Time{}
+}
+
+func TestMemoryLoginAttemptStore(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)}
+	store := ratelimit.NewMemoryLoginAttemptStore(time.Hour, clock.Now)
+	ctx := context.TODO()
+
+	failures, err := store.GetFailures(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, entities.LoginFailures{}, failures)
+
+	store.RecordAttempt(ctx, "alice", neverLocked)
+	clock.Advance(time.Minute)
+	failures, lockedUntil, err := store.RecordAttempt(ctx, "alice", neverLocked)
+	require.NoError(t, err)
+	assert.Equal(t, entities.LoginFailures{Count: 2, LastFailure: clock.now}, failures)
+	assert.True(t, lockedUntil.IsZero())
+
+	failures, err = store.GetFailures(ctx, "bob")
+	require.NoError(t, err)
+	assert.Zero(t, failures.Count)
+
+	t.Run("reset", func(t *testing.T) {
+		require.NoError(t, store.ResetFailures(ctx, "alice"))
+
+		failures, err := store.GetFailures(ctx, "alice")
+		require.NoError(t, err)
+		assert.Zero(t, failures.Count)
+	})
+
+	t.Run("failures expire", func(t *testing.T) {
+		store.RecordAttempt(ctx, "carol", neverLocked)
+		clock.Advance(time.Hour)
+
+		failures, err := store.GetFailures(ctx, "carol")
+		require.NoError(t, err)
+		assert.Zero(t, failures.Count)
+	})
+
+	t.Run("locked out attempts are not counted", func(t *testing.T) {
+		lockedAfterTwo := func(failures entities.LoginFailures) time.Time {
+			if failures.Count < 2 {
+				return time.Time{}
+			}
+			return failures.LastFailure.Add(time.Minute)
+		}
+
+		store.RecordAttempt(ctx, "dave", lockedAfterTwo)
+		store.RecordAttempt(ctx, "dave", lockedAfterTwo)
+		failures, lockedUntil, err := store.RecordAttempt(ctx, "dave", lockedAfterTwo)
+		require.NoError(t, err)
+		assert.Equal(t, 2, failures.Count)
+		assert.Equal(t, clock.now.Add(time.Minute), lockedUntil)
+	})
+
+	t.Run("parallel attempts are each counted", func(t *testing.T) {
+		lockedAfterThree := func(failures entities.LoginFailures) time.Time {
+			if failures.Count < 3 {
+				return time.Time{}
+			}
+			return failures.LastFailure.Add(time.Minute)
+		}
+
+		var wg sync.WaitGroup
+		var allowed atomic.Int32
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, lockedUntil, _ := store.RecordAttempt(ctx, "erin", lockedAfterThree); lockedUntil.IsZero() {
+					allowed.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(3), allowed.Load())
+	})
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"task-management-api/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type activityRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewActivityRepository(database mongo.Database, collection string) entities.ActivityRepository {
+	return &activityRepository{
+		database:   database,
+		collection: collection,
+	}
+}
+
+func (ar *activityRepository) AddActivity(ctx context.Context, activity entities.Activity) error {
+	_, err := ar.database.Collection(ar.collection).InsertOne(ctx, &activity)
+	return err
+}
+
+func (ar *activityRepository) GetActivity(ctx context.Context, taskID string, query model.PageQuery) (*model.ActivityPage, error) {
+	filter := bson.M{"taskId": taskID}
+	if query.Cursor != "" {
+		before, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$lt": before}
+	}
+
+	// One extra entry is fetched to find out whether there is a next page.
+	limit := pageLimit(query)
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limit + 1))
+
+	cursor, err := ar.database.Collection(ar.collection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	page := &model.ActivityPage{
+		Activity: []*model.ActivityInfo{},
+	}
+
+	for cursor.Next(ctx) {
+		var activity entities.Activity
+		if err := cursor.Decode(&activity); err != nil {
+			return nil, err
+		}
+		page.Activity = append(page.Activity, activity.Info())
+	}
+
+	if len(page.Activity) > limit {
+		page.Activity = page.Activity[:limit]
+		page.Next = page.Activity[limit-1].ID
+	}
+
+	return page, nil
+}
+
+// CreateActivityIndexes creates the index used to list the activity of a
+// task.
+func CreateActivityIndexes(ctx context.Context, database mongo.Database, collection string) error {
+	_, err := database.Collection(collection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "taskId", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
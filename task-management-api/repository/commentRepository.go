@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"task-management-api/mongo"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type commentRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewCommentRepository(database mongo.Database, collection string) entities.CommentRepository {
+	return &commentRepository{
+		database:   database,
+		collection: collection,
+	}
+}
+
+func (cr *commentRepository) GetComments(ctx context.Context, taskID string, query model.PageQuery) (*model.CommentPage, error) {
+	filter := bson.M{"taskId": taskID}
+	if query.Cursor != "" {
+		after, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": after}
+	}
+
+	// One extra comment is fetched to find out whether there is a next page.
+	limit := pageLimit(query)
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit + 1))
+
+	cursor, err := cr.database.Collection(cr.collection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	page := &model.CommentPage{
+		Comments: []*model.CommentInfo{},
+	}
+
+	for cursor.Next(ctx) {
+		var comment entities.Comment
+		if err := cursor.Decode(&comment); err != nil {
+			return nil, err
+		}
+		page.Comments = append(page.Comments, comment.Info())
+	}
+
+	if len(page.Comments) > limit {
+		page.Comments = page.Comments[:limit]
+		page.Next = page.Comments[limit-1].ID
+	}
+
+	return page, nil
+}
+
+func (cr *commentRepository) GetCommentByID(ctx context.Context, taskID string, id string) (*entities.Comment, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, entities.ErrCommentNotFound.Wrap(err)
+	}
+
+	var comment entities.Comment
+	err = cr.database.Collection(cr.collection).FindOne(ctx, bson.M{"_id": objectID, "taskId": taskID}).Decode(&comment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, entities.ErrCommentNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (cr *commentRepository) CreateComment(ctx context.Context, comment entities.Comment) error {
+	_, err := cr.database.Collection(cr.collection).InsertOne(ctx, &comment)
+	return err
+}
+
+func (cr *commentRepository) UpdateComment(ctx context.Context, comment entities.Comment) error {
+	filter := bson.M{"_id": comment.ID, "taskId": comment.TaskID}
+	update := bson.M{"$set": bson.M{"body": comment.Body, "updatedAt": time.Now()}}
+
+	result, err := cr.database.Collection(cr.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return entities.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (cr *commentRepository) DeleteComment(ctx context.Context, taskID string, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.ErrCommentNotFound.Wrap(err)
+	}
+
+	deleted, err := cr.database.Collection(cr.collection).DeleteOne(ctx, bson.M{"_id": objectID, "taskId": taskID})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return entities.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// CreateCommentIndexes creates the index used to list the comments of a task.
+func CreateCommentIndexes(ctx context.Context, database mongo.Database, collection string) error {
+	_, err := database.Collection(collection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "taskId", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Comments and activity are listed in the order of their IDs, which grow with
+// creation time. The cursor of such a list is the ID of the last item of the
+// previous page.
+
+func decodeIDCursor(cursor string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(cursor)
+	if err != nil {
+		return id, entities.ErrInvalidTaskCursor
+	}
+
+	return id, nil
+}
+
+func pageLimit(query model.PageQuery) int {
+	if query.Limit <= 0 {
+		return model.DefaultTaskPageSize
+	}
+
+	return query.Limit
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/entities"
+	"task-management-api/mongo"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type projectRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewProjectRepository(database mongo.Database, collection string) entities.ProjectRepository {
+	return &projectRepository{
+		database:   database,
+		collection: collection,
+	}
+}
+
+func (pr *projectRepository) GetProjects(ctx context.Context, userID string, archived bool) ([]*entities.Project, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"ownerId": userID},
+		{"members.userId": userID},
+	}}
+	if !archived {
+		filter["archivedAt"] = nil
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := pr.database.Collection(pr.collection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	projects := []*entities.Project{}
+	for cursor.Next(ctx) {
+		var project entities.Project
+		if err := cursor.Decode(&project); err != nil {
+			return nil, err
+		}
+		projects = append(projects, &project)
+	}
+
+	return projects, nil
+}
+
+func (pr *projectRepository) GetProjectByID(ctx context.Context, id string) (*entities.Project, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, entities.ErrProjectNotFound.Wrap(err)
+	}
+
+	var project entities.Project
+	err = pr.database.Collection(pr.collection).FindOne(ctx, bson.M{"_id": objectID}).Decode(&project)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, entities.ErrProjectNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (pr *projectRepository) CreateProject(ctx context.Context, project entities.Project) error {
+	project.Version = 1
+
+	_, err := pr.database.Collection(pr.collection).InsertOne(ctx, &project)
+	return err
+}
+
+func (pr *projectRepository) UpdateProject(ctx context.Context, project entities.Project) error {
+	update := bson.M{"$set": bson.M{
+		"name":       project.Name,
+		"members":    project.Members,
+		"archivedAt": project.ArchivedAt,
+		"updatedAt":  time.Now(),
+		"version":    project.Version + 1,
+	}}
+
+	filter := bson.M{"_id": project.ID, "version": versionFilter(project.Version)}
+	result, err := pr.database.Collection(pr.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		count, err := pr.database.Collection(pr.collection).CountDocuments(ctx, bson.M{"_id": project.ID})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return entities.ErrProjectNotFound
+		}
+		return entities.ErrProjectModified
+	}
+
+	return nil
+}
+
+// CreateProjectIndexes creates the indexes used to list the projects a user
+// owns or is a member of.
+func CreateProjectIndexes(ctx context.Context, database mongo.Database, collection string) error {
+	_, err := database.Collection(collection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "ownerId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "members.userId", Value: 1}}},
+	})
+	return err
+}
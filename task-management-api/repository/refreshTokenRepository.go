@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/mongo"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var errRefreshTokenNotFound = apperrors.NotFound("refresh token not found")
+
+type refreshTokenRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewRefreshTokenRepository(database mongo.Database, collection string) entities.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		database:   database,
+		collection: collection,
+	}
+}
+
+func (rr *refreshTokenRepository) CreateRefreshToken(ctx context.Context, token entities.RefreshToken) error {
+	_, err := rr.database.Collection(rr.collection).InsertOne(ctx, &token)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rr *refreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	filter := bson.M{
+		"tokenHash": tokenHash,
+	}
+
+	var token entities.RefreshToken
+	if err := rr.database.Collection(rr.collection).FindOne(ctx, filter).Decode(&token); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errRefreshTokenNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (rr *refreshTokenRepository) GetRefreshTokenFamily(ctx context.Context, familyID string) ([]*entities.RefreshToken, error) {
+	filter := bson.M{
+		"familyId": familyID,
+	}
+
+	return rr.find(ctx, filter)
+}
+
+func (rr *refreshTokenRepository) GetActiveRefreshTokens(ctx context.Context, userID string) ([]*entities.RefreshToken, error) {
+	filter := bson.M{
+		"userid":    userID,
+		"revokedAt": nil,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+
+	return rr.find(ctx, filter)
+}
+
+// RevokeRefreshToken marks a single token as revoked and reports whether this
+// call revoked it, so concurrent rotations of the same token can be detected.
+func (rr *refreshTokenRepository) RevokeRefreshToken(ctx context.Context, id string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"_id":       objectID,
+		"revokedAt": nil,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"revokedAt": time.Now(),
+		},
+	}
+
+	result, err := rr.database.Collection(rr.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (rr *refreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	filter := bson.M{
+		"familyId":  familyID,
+		"revokedAt": nil,
+	}
+
+	return rr.revokeMany(ctx, filter)
+}
+
+func (rr *refreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	filter := bson.M{
+		"userid":    userID,
+		"revokedAt": nil,
+	}
+
+	return rr.revokeMany(ctx, filter)
+}
+
+func (rr *refreshTokenRepository) revokeMany(ctx context.Context, filter bson.M) error {
+	update := bson.M{
+		"$set": bson.M{
+			"revokedAt": time.Now(),
+		},
+	}
+
+	_, err := rr.database.Collection(rr.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rr *refreshTokenRepository) find(ctx context.Context, filter bson.M) ([]*entities.RefreshToken, error) {
+	var tokens []*entities.RefreshToken
+
+	cursor, err := rr.database.Collection(rr.collection).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var token entities.RefreshToken
+		if err := cursor.Decode(&token); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+
+	return tokens, nil
+}
+
+// CreateTokenIndexes creates the indexes of the refresh and revoked token
+// collections. Token hashes are unique, as in the SQL schema, and both
+// collections drop their documents once expiresAt has passed; a revoked access
+// token is looked up by its jti, which is its _id and indexed already.
+func CreateTokenIndexes(ctx context.Context, database mongo.Database, refreshCollection string, revokedCollection string) error {
+	expire := options.Index().SetExpireAfterSeconds(0)
+
+	_, err := database.Collection(refreshCollection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tokenHash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "familyId", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}}},
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: expire},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Collection(revokedCollection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: expire},
+	})
+	return err
+}
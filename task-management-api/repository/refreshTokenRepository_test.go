@@ -0,0 +1,107 @@
+package repository_test
+
+import (
+	"context"
+	"task-management-api/domain/entities"
+	"task-management-api/mongo/mocks"
+	"task-management-api/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRevokeRefreshToken(t *testing.T) {
+	ctx := context.TODO()
+	tokenID := primitive.NewObjectID()
+
+	expectedFilter := bson.M{"_id": tokenID, "revokedAt": nil}
+
+	t.Run("revokes active token", func(t *testing.T) {
+		mockCollection := new(mocks.Collection)
+		mockDatabase := new(mocks.Database)
+		rr := repository.NewRefreshTokenRepository(mockDatabase, "refresh_token")
+
+		mockDatabase.On("Collection", "refresh_token").Return(mockCollection)
+		mockCollection.On("UpdateOne", ctx, expectedFilter, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil).Once()
+
+		revoked, err := rr.RevokeRefreshToken(ctx, tokenID.Hex())
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		mockCollection.AssertExpectations(t)
+	})
+
+	t.Run("already revoked token", func(t *testing.T) {
+		mockCollection := new(mocks.Collection)
+		mockDatabase := new(mocks.Database)
+		rr := repository.NewRefreshTokenRepository(mockDatabase, "refresh_token")
+
+		mockDatabase.On("Collection", "refresh_token").Return(mockCollection)
+		mockCollection.On("UpdateOne", ctx, expectedFilter, mock.Anything).Return(&mongo.UpdateResult{}, nil).Once()
+
+		revoked, err := rr.RevokeRefreshToken(ctx, tokenID.Hex())
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+		mockCollection.AssertExpectations(t)
+	})
+}
+
+func TestGetRefreshTokenByHash(t *testing.T) {
+	mockCollection := new(mocks.Collection)
+	mockDatabase := new(mocks.Database)
+	rr := repository.NewRefreshTokenRepository(mockDatabase, "refresh_token")
+
+	ctx := context.TODO()
+	stored := entities.RefreshToken{ID: primitive.NewObjectID(), UserID: "user", TokenHash: "hash", FamilyID: "family"}
+
+	mockDatabase.On("Collection", "refresh_token").Return(mockCollection)
+	mockSingleResult := new(mocks.SingleResult)
+	mockCollection.On("FindOne", ctx, bson.M{"tokenHash": "hash"}).Return(mockSingleResult)
+	mockSingleResult.On("Decode", mock.AnythingOfType("*entities.RefreshToken")).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*entities.RefreshToken)
+		*arg = stored
+	}).Return(nil)
+
+	token, err := rr.GetRefreshTokenByHash(ctx, "hash")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "family", token.FamilyID)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestCreateTokenIndexes(t *testing.T) {
+	ctx := context.TODO()
+	refreshCollection := new(mocks.Collection)
+	revokedCollection := new(mocks.Collection)
+	mockDatabase := new(mocks.Database)
+
+	mockDatabase.On("Collection", "refresh_token").Return(refreshCollection)
+	mockDatabase.On("Collection", "revoked_token").Return(revokedCollection)
+	refreshCollection.On("CreateIndexes", ctx, mock.MatchedBy(func(models []mongo.IndexModel) bool {
+		unique, ttl := false, false
+		for _, model := range models {
+			keys := model.Keys.(bson.D)
+			if keys[0].Key == "tokenHash" {
+				unique = model.Options != nil && *model.Options.Unique
+			}
+			if keys[0].Key == "expiresAt" {
+				ttl = model.Options != nil && *model.Options.ExpireAfterSeconds == 0
+			}
+		}
+		return len(models) == 4 && unique && ttl
+	})).Return([]string{}, nil).Once()
+	revokedCollection.On("CreateIndexes", ctx, mock.MatchedBy(func(models []mongo.IndexModel) bool {
+		return len(models) == 1 && *models[0].Options.ExpireAfterSeconds == 0
+	})).Return([]string{}, nil).Once()
+
+	err := repository.CreateTokenIndexes(ctx, mockDatabase, "refresh_token", "revoked_token")
+
+	assert.NoError(t, err)
+	refreshCollection.AssertExpectations(t)
+	revokedCollection.AssertExpectations(t)
+}
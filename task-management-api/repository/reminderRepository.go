@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"task-management-api/domain/entities"
+	"task-management-api/mongo"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type reminderRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewReminderRepository(database mongo.Database, collection string) entities.ReminderRepository {
+	return &reminderRepository{
+		database:   database,
+		collection: collection,
+	}
+}
+
+// ReplaceReminders leaves alone the reminders a scheduler is sending, which
+// it would otherwise send while their replacements are claimed again.
+func (rr *reminderRepository) ReplaceReminders(ctx context.Context, taskID string, reminders []entities.Reminder, now time.Time) error {
+	collection := rr.database.Collection(rr.collection)
+
+	replaceable := bson.M{
+		"taskId": taskID,
+		"sentAt": nil,
+		"$or": []bson.M{
+			{"claimedUntil": nil},
+			{"claimedUntil": bson.M{"$lte": now}},
+		},
+	}
+	if _, err := collection.DeleteMany(ctx, replaceable); err != nil {
+		return err
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"taskId": taskID})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var kept []entities.Reminder
+	for cursor.Next(ctx) {
+		var reminder entities.Reminder
+		if err := cursor.Decode(&reminder); err != nil {
+			return err
+		}
+		kept = append(kept, reminder)
+	}
+
+	for _, reminder := range newReminders(reminders, kept) {
+		if _, err := collection.InsertOne(ctx, &reminder); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newReminders leaves out of reminders those with the same offset and time
+// as one of kept.
+func newReminders(reminders []entities.Reminder, kept []entities.Reminder) []entities.Reminder {
+	var added []entities.Reminder
+	for _, reminder := range reminders {
+		found := false
+		for _, keptReminder := range kept {
+			if keptReminder.Offset == reminder.Offset && keptReminder.RemindAt.Equal(reminder.RemindAt) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			added = append(added, reminder)
+		}
+	}
+
+	return added
+}
+
+func (rr *reminderRepository) ClaimDueReminders(ctx context.Context, now time.Time, claimant string, lease time.Duration, limit int) ([]*entities.Reminder, error) {
+	collection := rr.database.Collection(rr.collection)
+	claimable := bson.M{
+		"sentAt":   nil,
+		"attempts": bson.M{"$lt": entities.MaxReminderAttempts},
+		"$or": []bson.M{
+			{"claimedUntil": nil},
+			{"claimedUntil": bson.M{"$lte": now}},
+		},
+	}
+
+	due := bson.M{"remindAt": bson.M{"$lte": now}}
+	for key, value := range claimable {
+		due[key] = value
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "remindAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, due, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var candidates []*entities.Reminder
+	for cursor.Next(ctx) {
+		var reminder entities.Reminder
+		if err := cursor.Decode(&reminder); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, &reminder)
+	}
+
+	// Another scheduler may claim a candidate between the query above and
+	// the update below. The update only matches while the reminder is still
+	// claimable, so each one is claimed by a single scheduler.
+	claimedUntil := now.Add(lease)
+	claimed := []*entities.Reminder{}
+	for _, reminder := range candidates {
+		filter := bson.M{"_id": reminder.ID}
+		for key, value := range claimable {
+			filter[key] = value
+		}
+		update := bson.M{
+			"$set": bson.M{"claimedBy": claimant, "claimedUntil": claimedUntil},
+			"$inc": bson.M{"attempts": 1},
+		}
+
+		result, err := collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 1 {
+			reminder.ClaimedBy = claimant
+			reminder.ClaimedUntil = &claimedUntil
+			reminder.Attempts++
+			claimed = append(claimed, reminder)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (rr *reminderRepository) MarkReminderSent(ctx context.Context, id string, sentAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = rr.database.Collection(rr.collection).UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"sentAt": sentAt}})
+	return err
+}
+
+// CreateReminderIndexes creates the indexes used to find the reminders that
+// are due and those of a task.
+func CreateReminderIndexes(ctx context.Context, database mongo.Database, collection string) error {
+	_, err := database.Collection(collection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sentAt", Value: 1}, {Key: "remindAt", Value: 1}}},
+		{Keys: bson.D{{Key: "taskId", Value: 1}}},
+	})
+	return err
+}
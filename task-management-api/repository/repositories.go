@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"task-management-api/domain/entities"
+	"task-management-api/mongo"
+)
+
+// Repositories holds one implementation of every repository, all backed by the
+// same database.
+type Repositories struct {
+	Users         entities.UserRepository
+	Tasks         entities.TaskRepository
+	Tags          entities.TagRepository
+	Comments      entities.CommentRepository
+	Activity      entities.ActivityRepository
+	Projects      entities.ProjectRepository
+	Reminders     entities.ReminderRepository
+	Webhooks      entities.WebhookRepository
+	RefreshTokens entities.RefreshTokenRepository
+	RevokedTokens entities.RevokedTokenRepository
+
+	ping  func(context.Context) error
+	close func(context.Context) error
+}
+
+// Ping checks that the database behind the repositories is reachable.
+func (r *Repositories) Ping(ctx context.Context) error {
+	return r.ping(ctx)
+}
+
+// Close releases the connection to the database. The repositories must not be
+// used afterwards.
+func (r *Repositories) Close(ctx context.Context) error {
+	return r.close(ctx)
+}
+
+// NewMongoRepositories builds the repositories on a MongoDB (or in-memory)
+// database and creates the indexes they rely on.
+func NewMongoRepositories(ctx context.Context, database mongo.Database) (*Repositories, error) {
+	if err := CreateUserIndexes(ctx, database, "user"); err != nil {
+		return nil, err
+	}
+	if err := CreateTaskIndexes(ctx, database, "task"); err != nil {
+		return nil, err
+	}
+	if err := CreateCommentIndexes(ctx, database, "task_comment"); err != nil {
+		return nil, err
+	}
+	if err := CreateActivityIndexes(ctx, database, "task_activity"); err != nil {
+		return nil, err
+	}
+	if err := CreateProjectIndexes(ctx, database, "project"); err != nil {
+		return nil, err
+	}
+	if err := CreateReminderIndexes(ctx, database, "reminder"); err != nil {
+		return nil, err
+	}
+	if err := CreateWebhookIndexes(ctx, database, "webhook", "webhook_delivery"); err != nil {
+		return nil, err
+	}
+	if err := CreateTokenIndexes(ctx, database, "refresh_token", "revoked_token"); err != nil {
+		return nil, err
+	}
+
+	return &Repositories{
+		Users:         NewUserRepository(database, "user"),
+		Tasks:         NewTaskRepository(database, "task"),
+		Tags:          NewTagRepository(database, "task"),
+		Comments:      NewCommentRepository(database, "task_comment"),
+		Activity:      NewActivityRepository(database, "task_activity"),
+		Projects:      NewProjectRepository(database, "project"),
+		Reminders:     NewReminderRepository(database, "reminder"),
+		Webhooks:      NewWebhookRepository(database, "webhook", "webhook_delivery"),
+		RefreshTokens: NewRefreshTokenRepository(database, "refresh_token"),
+		RevokedTokens: NewRevokedTokenRepository(database, "revoked_token"),
+		ping:          database.Client().Ping,
+		close:         database.Client().Disconnect,
+	}, nil
+}
+
+// NewSQLRepositories builds the repositories on a SQL database after bringing
+// its schema up to date.
+func NewSQLRepositories(ctx context.Context, database *SQLDatabase) (*Repositories, error) {
+	if err := database.Migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	return &Repositories{
+		Users:         NewSQLUserRepository(database),
+		Tasks:         NewSQLTaskRepository(database),
+		Tags:          NewSQLTagRepository(database),
+		Comments:      NewSQLCommentRepository(database),
+		Activity:      NewSQLActivityRepository(database),
+		Projects:      NewSQLProjectRepository(database),
+		Reminders:     NewSQLReminderRepository(database),
+		Webhooks:      NewSQLWebhookRepository(database),
+		RefreshTokens: NewSQLRefreshTokenRepository(database),
+		RevokedTokens: NewSQLRevokedTokenRepository(database),
+		ping:          database.DB.PingContext,
+		close: func(context.Context) error {
+			return database.DB.Close()
+		},
+	}, nil
+}
@@ -0,0 +1,1258 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"task-management-api/mongo"
+	"task-management-api/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	_ "modernc.org/sqlite"
+)
+
+// These tests run every repository implementation end to end, against the
+// in-memory database and an embedded SQLite database, so the backends stay
+// interchangeable.
+
+func forEachBackend(t *testing.T, test func(t *testing.T, repositories *repository.Repositories)) {
+	t.Run("memory", func(t *testing.T) {
+		repositories, err := repository.NewMongoRepositories(context.TODO(), mongo.NewMemoryClient().Database("test"))
+		require.NoError(t, err)
+
+		test(t, repositories)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+
+		repositories, err := repository.NewSQLRepositories(context.TODO(), repository.NewSQLDatabase(db, repository.SQLite))
+		require.NoError(t, err)
+
+		test(t, repositories)
+	})
+}
+
+func TestSQLMigrationsAreIdempotent(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	database := repository.NewSQLDatabase(db, repository.SQLite)
+	require.NoError(t, database.Migrate(context.TODO()))
+	require.NoError(t, database.Migrate(context.TODO()))
+
+	var version int
+	require.NoError(t, db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version))
+	assert.Equal(t, 11, version)
+}
+
+func TestTaskRepositories(t *testing.T) {
+	forEachBackend(t, testTaskRepository)
+}
+
+func TestTaskWithoutVersion(t *testing.T) {
+	ctx := context.TODO()
+	database := mongo.NewMemoryClient().Database("test")
+	repositories, err := repository.NewMongoRepositories(ctx, database)
+	require.NoError(t, err)
+
+	// Documents written before tasks were versioned have no version field.
+	type legacyTask struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		UserID string             `bson:"userid"`
+		Title  string             `bson:"title"`
+	}
+	id := primitive.NewObjectID()
+	_, err = database.Collection("task").InsertOne(ctx, &legacyTask{ID: id, UserID: "u1", Title: "Legacy"})
+	require.NoError(t, err)
+
+	task, err := repositories.Tasks.GetTaskByID(ctx, id.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), task.Version)
+
+	task.Title = "Versioned"
+	require.NoError(t, repositories.Tasks.UpdateTask(ctx, id.Hex(), *task, []string{entities.TaskFieldTitle}))
+
+	task, err = repositories.Tasks.GetTaskByID(ctx, id.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "Versioned", task.Title)
+	assert.Equal(t, int64(1), task.Version)
+}
+
+func TestTaskLinks(t *testing.T) {
+	forEachBackend(t, testTaskLinks)
+}
+
+func TestTaskSharing(t *testing.T) {
+	forEachBackend(t, testTaskSharing)
+}
+
+func TestTaskSeries(t *testing.T) {
+	forEachBackend(t, testTaskSeries)
+}
+
+func TestReminderRepositories(t *testing.T) {
+	forEachBackend(t, testReminderRepository)
+}
+
+func TestWebhookRepositories(t *testing.T) {
+	forEachBackend(t, testWebhookRepository)
+}
+
+func TestProjectRepositories(t *testing.T) {
+	forEachBackend(t, testProjectRepository)
+}
+
+func TestTagRepositories(t *testing.T) {
+	forEachBackend(t, testTagRepository)
+}
+
+func TestCommentRepositories(t *testing.T) {
+	forEachBackend(t, testCommentRepository)
+}
+
+func TestActivityRepositories(t *testing.T) {
+	forEachBackend(t, testActivityRepository)
+}
+
+func TestUserRepositories(t *testing.T) {
+	forEachBackend(t, testUserRepository)
+}
+
+func TestTokenRepositories(t *testing.T) {
+	forEachBackend(t, testTokenRepositories)
+}
+
+func TestRepositoriesPingAndClose(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repositories *repository.Repositories) {
+		assert.NoError(t, repositories.Ping(context.TODO()))
+		assert.NoError(t, repositories.Close(context.TODO()))
+	})
+
+	t.Run("ping fails once closed", func(t *testing.T) {
+		db, err := sql.Open("sqlite", "file::memory:")
+		require.NoError(t, err)
+
+		repositories, err := repository.NewSQLRepositories(context.TODO(), repository.NewSQLDatabase(db, repository.SQLite))
+		require.NoError(t, err)
+
+		require.NoError(t, repositories.Close(context.TODO()))
+		assert.Error(t, repositories.Ping(context.TODO()))
+	})
+}
+
+func testTaskRepository(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	tr := repositories.Tasks
+
+	base := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	for i, title := range []string{"Plan sprint", "Write report", "Review report", "Buy milk", "Book flights"} {
+		task := entities.Task{
+			UserID:   "u1",
+			Title:    title,
+			Status:   entities.StatusTodo,
+			Priority: entities.Priority(i%3 + 1),
+			DueDate:  base.Add(time.Duration(i) * 24 * time.Hour),
+		}
+		if i%2 == 0 {
+			task.Tags = []string{"work"}
+		}
+		require.NoError(t, tr.CreateTask(ctx, task))
+	}
+	require.NoError(t, tr.CreateTask(ctx, entities.Task{UserID: "u2", Title: "Someone else's report"}))
+
+	t.Run("pages through every task once", func(t *testing.T) {
+		var titles []string
+		query := model.TaskQuery{Sort: "due_date", Limit: 2}
+
+		for pages := 0; pages < 5; pages++ {
+			page, err := tr.GetTasks(ctx, "u1", query)
+			require.NoError(t, err)
+
+			for _, task := range page.Tasks {
+				titles = append(titles, task.Title)
+			}
+
+			if page.Next == "" {
+				break
+			}
+			query.Cursor = page.Next
+		}
+
+		assert.Equal(t, []string{"Plan sprint", "Write report", "Review report", "Buy milk", "Book flights"}, titles)
+	})
+
+	t.Run("sorts by priority in descending order", func(t *testing.T) {
+		page, err := tr.GetTasks(ctx, "u1", model.TaskQuery{Sort: "-priority", Limit: 2})
+		require.NoError(t, err)
+
+		require.Len(t, page.Tasks, 2)
+		assert.Equal(t, "high", page.Tasks[0].Priority)
+		assert.Equal(t, "medium", page.Tasks[1].Priority)
+
+		page, err = tr.GetTasks(ctx, "u1", model.TaskQuery{Sort: "-priority", Limit: 10, Cursor: page.Next})
+		require.NoError(t, err)
+
+		require.Len(t, page.Tasks, 3)
+		assert.Equal(t, "medium", page.Tasks[0].Priority)
+		assert.Equal(t, "low", page.Tasks[2].Priority)
+		assert.Empty(t, page.Next)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		dueAfter := base
+		page, err := tr.GetTasks(ctx, "u1", model.TaskQuery{
+			Text:     "REPORT",
+			Tags:     []string{"work"},
+			DueAfter: &dueAfter,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, page.Tasks, 1)
+		assert.Equal(t, "Review report", page.Tasks[0].Title)
+	})
+
+	t.Run("updates", func(t *testing.T) {
+		page, err := tr.GetTasks(ctx, "u1", model.TaskQuery{Text: "milk"})
+		require.NoError(t, err)
+		require.Len(t, page.Tasks, 1)
+
+		id := page.Tasks[0].ID
+		task, err := tr.GetTaskByID(ctx, id)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), task.Version)
+
+		status := []string{entities.TaskFieldStatus}
+		task.Status = entities.StatusInProgress
+		task.Title = "Not written"
+		require.NoError(t, tr.UpdateTask(ctx, id, *task, status))
+
+		updated, err := tr.GetTaskByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, entities.StatusInProgress, updated.Status)
+		assert.Equal(t, "Buy milk", updated.Title)
+		assert.Equal(t, int64(2), updated.Version)
+		assert.False(t, updated.UpdatedAt.Before(task.CreatedAt))
+
+		// task still holds version 1, which is no longer current.
+		assert.ErrorIs(t, tr.UpdateTask(ctx, id, *task, status), apperrors.ErrPreconditionFailed)
+		assert.ErrorIs(t, tr.DeleteTask(ctx, id, 1), apperrors.ErrPreconditionFailed)
+
+		updated.Tags = []string{"errand", "home"}
+		updated.DueDate = time.Time{}
+		require.NoError(t, tr.UpdateTask(ctx, id, *updated, []string{entities.TaskFieldTags, entities.TaskFieldDueDate}))
+
+		updated, err = tr.GetTaskByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"errand", "home"}, updated.Tags)
+		assert.True(t, updated.DueDate.IsZero())
+		assert.Equal(t, entities.StatusInProgress, updated.Status)
+		assert.NoError(t, tr.DeleteTask(ctx, id, 3))
+
+		_, err = tr.GetTaskByID(ctx, id)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("trash", func(t *testing.T) {
+		trash, err := tr.GetDeletedTasks(ctx, "u1", model.TaskQuery{})
+		require.NoError(t, err)
+		require.Len(t, trash.Tasks, 1)
+		assert.Equal(t, "Buy milk", trash.Tasks[0].Title)
+		assert.NotNil(t, trash.Tasks[0].DeletedAt)
+
+		id := trash.Tasks[0].ID
+
+		page, err := tr.GetTasks(ctx, "u1", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Len(t, page.Tasks, 4)
+
+		trash, err = tr.GetDeletedTasks(ctx, "u2", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, trash.Tasks)
+
+		assert.ErrorIs(t, tr.UpdateTask(ctx, id, entities.Task{Version: 4}, []string{entities.TaskFieldTitle}), apperrors.ErrNotFound)
+		assert.ErrorIs(t, tr.DeleteTask(ctx, id, entities.AnyTaskVersion), apperrors.ErrNotFound)
+		assert.ErrorIs(t, tr.RestoreTask(ctx, id, "u2"), apperrors.ErrNotFound)
+
+		trashed, err := tr.GetTaskIncludingDeleted(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Buy milk", trashed.Title)
+		assert.NotNil(t, trashed.DeletedAt)
+
+		require.NoError(t, tr.RestoreTask(ctx, id, "u1"))
+		assert.ErrorIs(t, tr.RestoreTask(ctx, id, "u1"), apperrors.ErrNotFound)
+
+		restored, err := tr.GetTaskByID(ctx, id)
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+		assert.Equal(t, []string{"errand", "home"}, restored.Tags)
+		assert.Equal(t, int64(5), restored.Version)
+
+		require.NoError(t, tr.DeleteTask(ctx, id, 5))
+
+		purged, err := tr.PurgeDeletedTasks(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+
+		purged, err = tr.PurgeDeletedTasks(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		trash, err = tr.GetDeletedTasks(ctx, "u1", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, trash.Tasks)
+		assert.ErrorIs(t, tr.RestoreTask(ctx, id, "u1"), apperrors.ErrNotFound)
+		_, err = tr.GetTaskIncludingDeleted(ctx, id)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		page, err = tr.GetTasks(ctx, "u1", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Len(t, page.Tasks, 4)
+	})
+}
+
+func testTaskLinks(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	tr := repositories.Tasks
+
+	ids := map[string]string{}
+	for _, title := range []string{"Release", "Write notes", "Tag build", "Blocker"} {
+		task := entities.Task{UserID: "u1", Title: title, Status: entities.StatusTodo, Priority: entities.PriorityMedium, CreatedAt: time.Now()}
+		require.NoError(t, tr.CreateTask(ctx, task))
+
+		page, err := tr.GetTasks(ctx, "u1", model.TaskQuery{Text: title})
+		require.NoError(t, err)
+		require.Len(t, page.Tasks, 1)
+		ids[title] = page.Tasks[0].ID
+	}
+
+	link := func(t *testing.T, title string, change func(task *entities.Task), field string) {
+		task, err := tr.GetTaskByID(ctx, ids[title])
+		require.NoError(t, err)
+
+		change(task)
+		require.NoError(t, tr.UpdateTask(ctx, ids[title], *task, []string{field}))
+	}
+
+	for _, title := range []string{"Write notes", "Tag build"} {
+		link(t, title, func(task *entities.Task) { task.ParentID = ids["Release"] }, entities.TaskFieldParentID)
+	}
+	link(t, "Tag build", func(task *entities.Task) { task.BlockedBy = []string{ids["Blocker"]} }, entities.TaskFieldBlockedBy)
+
+	t.Run("reads links", func(t *testing.T) {
+		subtasks, err := tr.GetSubtasks(ctx, ids["Release"])
+		require.NoError(t, err)
+		require.Len(t, subtasks, 2)
+		assert.Equal(t, "Write notes", subtasks[0].Title)
+		assert.Equal(t, "Tag build", subtasks[1].Title)
+		assert.Equal(t, ids["Release"], subtasks[1].ParentID)
+		assert.Equal(t, []string{ids["Blocker"]}, subtasks[1].BlockedBy)
+		assert.Equal(t, int64(3), subtasks[1].Version)
+
+		subtasks, err = tr.GetSubtasks(ctx, ids["Blocker"])
+		require.NoError(t, err)
+		assert.Empty(t, subtasks)
+	})
+
+	t.Run("purging unlinks", func(t *testing.T) {
+		for _, title := range []string{"Release", "Blocker"} {
+			require.NoError(t, tr.DeleteTask(ctx, ids[title], entities.AnyTaskVersion))
+		}
+
+		// Subtasks keep their parent while it is in the trash.
+		subtasks, err := tr.GetSubtasks(ctx, ids["Release"])
+		require.NoError(t, err)
+		assert.Len(t, subtasks, 2)
+
+		purged, err := tr.PurgeDeletedTasks(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+
+		task, err := tr.GetTaskByID(ctx, ids["Tag build"])
+		require.NoError(t, err)
+		assert.Empty(t, task.ParentID)
+		assert.Empty(t, task.BlockedBy)
+	})
+}
+
+func testTaskSharing(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	tr := repositories.Tasks
+
+	shared := entities.Task{
+		ID:            primitive.NewObjectID(),
+		UserID:        "u1",
+		Title:         "Shared",
+		Status:        entities.StatusTodo,
+		Priority:      entities.PriorityMedium,
+		Collaborators: []entities.Collaborator{{UserID: "u2", Role: entities.TaskRoleEditor}},
+		AssigneeID:    "u2",
+	}
+	require.NoError(t, tr.CreateTask(ctx, shared))
+	for _, owner := range []string{"u1", "u2"} {
+		require.NoError(t, tr.CreateTask(ctx, entities.Task{UserID: owner, Title: "Private", Status: entities.StatusTodo, Priority: entities.PriorityMedium}))
+	}
+
+	titles := func(page *model.TaskPage) []string {
+		var titles []string
+		for _, task := range page.Tasks {
+			titles = append(titles, task.Title)
+		}
+		sort.Strings(titles)
+		return titles
+	}
+
+	t.Run("lists shared tasks", func(t *testing.T) {
+		page, err := tr.GetTasks(ctx, "u2", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Private", "Shared"}, titles(page))
+
+		page, err = tr.GetTasks(ctx, "u3", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Tasks)
+	})
+
+	t.Run("lists assigned tasks", func(t *testing.T) {
+		page, err := tr.GetTasks(ctx, "u2", model.TaskQuery{AssigneeID: "u2"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Shared"}, titles(page))
+
+		page, err = tr.GetTasks(ctx, "u1", model.TaskQuery{AssigneeID: "u1"})
+		require.NoError(t, err)
+		assert.Empty(t, page.Tasks)
+	})
+
+	t.Run("updates collaborators", func(t *testing.T) {
+		task, err := tr.GetTaskByID(ctx, shared.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, shared.Collaborators, task.Collaborators)
+		assert.Equal(t, "u2", task.AssigneeID)
+
+		task.Collaborators = []entities.Collaborator{{UserID: "u3", Role: entities.TaskRoleViewer}}
+		task.AssigneeID = ""
+		require.NoError(t, tr.UpdateTask(ctx, shared.ID.Hex(), *task, []string{entities.TaskFieldCollaborators, entities.TaskFieldAssigneeID}))
+
+		task, err = tr.GetTaskByID(ctx, shared.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, []entities.Collaborator{{UserID: "u3", Role: entities.TaskRoleViewer}}, task.Collaborators)
+		assert.Empty(t, task.AssigneeID)
+
+		page, err := tr.GetTasks(ctx, "u2", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Private"}, titles(page))
+	})
+
+	t.Run("trash is the owner's", func(t *testing.T) {
+		require.NoError(t, tr.DeleteTask(ctx, shared.ID.Hex(), entities.AnyTaskVersion))
+
+		page, err := tr.GetDeletedTasks(ctx, "u3", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Tasks)
+		assert.ErrorIs(t, tr.RestoreTask(ctx, shared.ID.Hex(), "u3"), apperrors.ErrNotFound)
+
+		page, err = tr.GetDeletedTasks(ctx, "u1", model.TaskQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Shared"}, titles(page))
+	})
+}
+
+func testTaskSeries(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	tr := repositories.Tasks
+
+	dueDate := time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC)
+	first := entities.Task{ID: primitive.NewObjectID(), UserID: "u1", Title: "Water plants", Status: entities.StatusTodo, Priority: entities.PriorityMedium, DueDate: dueDate}
+	require.NoError(t, tr.CreateTask(ctx, first))
+	seriesID := first.ID.Hex()
+
+	read, err := tr.GetTaskByID(ctx, seriesID)
+	require.NoError(t, err)
+	read.Recurrence, read.SeriesID, read.Occurrence = "FREQ=WEEKLY", seriesID, 1
+	require.NoError(t, tr.UpdateTask(ctx, seriesID, *read, []string{entities.TaskFieldRecurrence}))
+
+	second := entities.Task{ID: primitive.NewObjectID(), UserID: "u1", Title: "Water plants", Status: entities.StatusTodo, Priority: entities.PriorityMedium,
+		DueDate: dueDate.AddDate(0, 0, 7), Recurrence: "FREQ=WEEKLY", SeriesID: seriesID, Occurrence: 2}
+	require.NoError(t, tr.CreateTask(ctx, second))
+	require.NoError(t, tr.CreateTask(ctx, entities.Task{UserID: "u1", Title: "Unrelated", Status: entities.StatusTodo, Priority: entities.PriorityMedium}))
+
+	occurrences, err := tr.GetSeriesTasks(ctx, seriesID)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 2)
+	for i, occurrence := range occurrences {
+		assert.Equal(t, "FREQ=WEEKLY", occurrence.Recurrence)
+		assert.Equal(t, seriesID, occurrence.SeriesID)
+		assert.Equal(t, i+1, occurrence.Occurrence)
+	}
+	assert.Equal(t, second.ID, occurrences[1].ID)
+
+	require.NoError(t, tr.DeleteTask(ctx, second.ID.Hex(), entities.AnyTaskVersion))
+	occurrences, err = tr.GetSeriesTasks(ctx, seriesID)
+	require.NoError(t, err)
+	assert.Len(t, occurrences, 1)
+
+	occurrences, err = tr.GetSeriesTasks(ctx, primitive.NewObjectID().Hex())
+	require.NoError(t, err)
+	assert.Empty(t, occurrences)
+}
+
+func testReminderRepository(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	rr := repositories.Reminders
+
+	now := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	task := entities.Task{ID: primitive.NewObjectID(), UserID: "u1", Title: "Write report", Status: entities.StatusTodo, Priority: entities.PriorityMedium,
+		DueDate: now.Add(2 * time.Hour), Reminders: []string{"P1D", "PT2H", "PT1H"}}
+	require.NoError(t, repositories.Tasks.CreateTask(ctx, task))
+
+	read, err := repositories.Tasks.GetTaskByID(ctx, task.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, task.Reminders, read.Reminders)
+
+	read.Reminders = []string{"PT2H"}
+	require.NoError(t, repositories.Tasks.UpdateTask(ctx, task.ID.Hex(), *read, []string{entities.TaskFieldReminders}))
+	read, err = repositories.Tasks.GetTaskByID(ctx, task.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PT2H"}, read.Reminders)
+
+	reminder := func(offset string, remindAt time.Time) entities.Reminder {
+		return entities.Reminder{ID: primitive.NewObjectID(), TaskID: task.ID.Hex(), Offset: offset, RemindAt: remindAt}
+	}
+	due := reminder("PT2H", now)
+	later := reminder("PT1H", now.Add(time.Hour))
+	require.NoError(t, rr.ReplaceReminders(ctx, task.ID.Hex(), []entities.Reminder{due, later}, now))
+
+	claimed, err := rr.ClaimDueReminders(ctx, now, "scheduler-a", time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, due.ID, claimed[0].ID)
+	assert.Equal(t, "PT2H", claimed[0].Offset)
+	assert.Equal(t, now, claimed[0].RemindAt)
+	assert.Equal(t, "scheduler-a", claimed[0].ClaimedBy)
+	assert.Equal(t, 1, claimed[0].Attempts)
+
+	// A claimed reminder cannot be claimed again until its lease expires.
+	claimed, err = rr.ClaimDueReminders(ctx, now.Add(30*time.Second), "scheduler-b", time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	claimed, err = rr.ClaimDueReminders(ctx, now.Add(2*time.Minute), "scheduler-b", time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, due.ID, claimed[0].ID)
+	assert.Equal(t, "scheduler-b", claimed[0].ClaimedBy)
+	assert.Equal(t, 2, claimed[0].Attempts)
+
+	require.NoError(t, rr.MarkReminderSent(ctx, due.ID.Hex(), now.Add(2*time.Minute)))
+	claimed, err = rr.ClaimDueReminders(ctx, now.Add(10*time.Minute), "scheduler-a", time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	// Replacing keeps sent reminders from being scheduled again and drops
+	// the pending ones.
+	rescheduled := reminder("PT1H", now.Add(3*time.Hour))
+	require.NoError(t, rr.ReplaceReminders(ctx, task.ID.Hex(), []entities.Reminder{reminder("PT2H", now), rescheduled}, now.Add(10*time.Minute)))
+
+	claimed, err = rr.ClaimDueReminders(ctx, now.Add(4*time.Hour), "scheduler-a", time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, rescheduled.ID, claimed[0].ID)
+
+	// Reminders are given up once they ran out of attempts.
+	for i := 1; i < entities.MaxReminderAttempts; i++ {
+		claimed, err = rr.ClaimDueReminders(ctx, now.Add(time.Duration(4+i)*time.Hour), "scheduler-a", time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+	}
+	claimed, err = rr.ClaimDueReminders(ctx, now.Add(24*time.Hour), "scheduler-a", time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	require.NoError(t, rr.ReplaceReminders(ctx, task.ID.Hex(), nil, now.Add(24*time.Hour)))
+
+	t.Run("replacing keeps claimed reminders", func(t *testing.T) {
+		other := entities.Task{ID: primitive.NewObjectID(), UserID: "u1", Title: "Plan sprint", Status: entities.StatusTodo, Priority: entities.PriorityMedium,
+			DueDate: now.Add(time.Hour), Reminders: []string{"PT1H"}}
+		require.NoError(t, repositories.Tasks.CreateTask(ctx, other))
+		taskID := other.ID.Hex()
+		pending := entities.Reminder{ID: primitive.NewObjectID(), TaskID: taskID, Offset: "PT1H", RemindAt: now}
+		require.NoError(t, rr.ReplaceReminders(ctx, taskID, []entities.Reminder{pending}, now))
+
+		claimed, err := rr.ClaimDueReminders(ctx, now, "scheduler-a", time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		// The task is saved again while scheduler-a is sending the reminder.
+		replacement := entities.Reminder{ID: primitive.NewObjectID(), TaskID: taskID, Offset: "PT1H", RemindAt: now}
+		require.NoError(t, rr.ReplaceReminders(ctx, taskID, []entities.Reminder{replacement}, now.Add(30*time.Second)))
+
+		claimed, err = rr.ClaimDueReminders(ctx, now.Add(30*time.Second), "scheduler-b", time.Minute, 10)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		// Once the claim has expired unsent, the reminder can be claimed and
+		// replaced again.
+		claimed, err = rr.ClaimDueReminders(ctx, now.Add(2*time.Minute), "scheduler-b", time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, pending.ID, claimed[0].ID)
+
+		require.NoError(t, rr.ReplaceReminders(ctx, taskID, nil, now.Add(4*time.Minute)))
+		claimed, err = rr.ClaimDueReminders(ctx, now.Add(4*time.Minute), "scheduler-b", time.Minute, 10)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+	})
+
+	t.Run("concurrent schedulers claim each reminder once", func(t *testing.T) {
+		var many []entities.Reminder
+		for i := 0; i < 20; i++ {
+			many = append(many, reminder("PT1H", now.Add(time.Duration(i)*time.Minute)))
+		}
+		require.NoError(t, rr.ReplaceReminders(ctx, task.ID.Hex(), many, now))
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		claimedIDs := map[primitive.ObjectID]int{}
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(claimant string) {
+				defer wg.Done()
+				for {
+					claimed, err := rr.ClaimDueReminders(ctx, now.Add(time.Hour), claimant, time.Hour, 3)
+					assert.NoError(t, err)
+					if len(claimed) == 0 {
+						return
+					}
+
+					mu.Lock()
+					for _, reminder := range claimed {
+						claimedIDs[reminder.ID]++
+					}
+					mu.Unlock()
+				}
+			}(primitive.NewObjectID().Hex())
+		}
+		wg.Wait()
+
+		assert.Len(t, claimedIDs, len(many))
+		for _, count := range claimedIDs {
+			assert.Equal(t, 1, count)
+		}
+	})
+}
+
+func testWebhookRepository(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	wr := repositories.Webhooks
+
+	now := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	newWebhook := func(userID string, events ...string) entities.Webhook {
+		webhook := entities.Webhook{ID: primitive.NewObjectID(), UserID: userID, URL: "https://hooks.example.com/" + userID, Events: events,
+			Secret: "whsec_" + userID, CreatedAt: now}
+		require.NoError(t, wr.CreateWebhook(ctx, webhook))
+		now = now.Add(time.Second)
+		return webhook
+	}
+	created := newWebhook("u1", entities.EventTaskCompleted, entities.EventTaskCreated)
+	updated := newWebhook("u1", entities.EventTaskUpdated)
+	other := newWebhook("u2", entities.EventTaskCreated)
+
+	t.Run("reads webhooks", func(t *testing.T) {
+		read, err := wr.GetWebhookByID(ctx, created.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "u1", read.UserID)
+		assert.Equal(t, created.URL, read.URL)
+		assert.Equal(t, []string{entities.EventTaskCompleted, entities.EventTaskCreated}, read.Events)
+		assert.Equal(t, "whsec_u1", read.Secret)
+
+		webhooks, err := wr.GetWebhooks(ctx, "u1")
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+		assert.Equal(t, created.ID, webhooks[0].ID)
+		assert.Equal(t, updated.ID, webhooks[1].ID)
+
+		webhooks, err = wr.GetEventWebhooks(ctx, entities.EventTaskCreated, []string{"u1", "u2"})
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+		assert.Equal(t, created.ID, webhooks[0].ID)
+		assert.Equal(t, other.ID, webhooks[1].ID)
+
+		webhooks, err = wr.GetEventWebhooks(ctx, entities.EventTaskCreated, []string{"u2", "u3"})
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, other.ID, webhooks[0].ID)
+
+		webhooks, err = wr.GetEventWebhooks(ctx, entities.EventTaskDeleted, []string{"u1", "u2"})
+		require.NoError(t, err)
+		assert.Empty(t, webhooks)
+
+		webhooks, err = wr.GetEventWebhooks(ctx, entities.EventTaskCreated, nil)
+		require.NoError(t, err)
+		assert.Empty(t, webhooks)
+
+		_, err = wr.GetWebhookByID(ctx, primitive.NewObjectID().Hex())
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	delivery := func(webhook entities.Webhook, nextAttemptAt time.Time) entities.WebhookDelivery {
+		return entities.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID.Hex(), Event: webhook.Events[0],
+			Payload: `{"event":"` + webhook.Events[0] + `"}`, Status: entities.WebhookDeliveryPending, NextAttemptAt: nextAttemptAt, CreatedAt: now}
+	}
+
+	t.Run("claims due deliveries and records attempts", func(t *testing.T) {
+		due := delivery(created, now)
+		later := delivery(updated, now.Add(time.Hour))
+		require.NoError(t, wr.AddDeliveries(ctx, []entities.WebhookDelivery{due, later}))
+
+		claimed, err := wr.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, due.ID, claimed[0].ID)
+		assert.Equal(t, due.Payload, claimed[0].Payload)
+		assert.Equal(t, 1, claimed[0].Attempts)
+		assert.Equal(t, now.Add(time.Minute), claimed[0].NextAttemptAt)
+
+		// A claimed delivery cannot be claimed again until its lease expires.
+		claimed, err = wr.ClaimDueDeliveries(ctx, now.Add(30*time.Second), time.Minute, 10)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		claimed, err = wr.ClaimDueDeliveries(ctx, now.Add(time.Minute), time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, 2, claimed[0].Attempts)
+
+		attempted := *claimed[0]
+		attempted.RecordAttempt(now.Add(time.Minute), 500, errors.New("webhook answered 500 Internal Server Error"))
+		require.NoError(t, wr.UpdateDelivery(ctx, attempted))
+
+		claimed, err = wr.ClaimDueDeliveries(ctx, attempted.NextAttemptAt.Add(-time.Second), time.Minute, 10)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		claimed, err = wr.ClaimDueDeliveries(ctx, attempted.NextAttemptAt, time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, 3, claimed[0].Attempts)
+		assert.Equal(t, 500, claimed[0].ResponseStatus)
+
+		attempted = *claimed[0]
+		attempted.RecordAttempt(now.Add(2*time.Minute), 200, nil)
+		require.NoError(t, wr.UpdateDelivery(ctx, attempted))
+
+		claimed, err = wr.ClaimDueDeliveries(ctx, now.Add(2*time.Hour), time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, later.ID, claimed[0].ID)
+
+		page, err := wr.GetDeliveries(ctx, created.ID.Hex(), model.PageQuery{})
+		require.NoError(t, err)
+		require.Len(t, page.Deliveries, 1)
+		assert.Equal(t, due.ID.Hex(), page.Deliveries[0].ID)
+		assert.Equal(t, entities.WebhookDeliverySucceeded, page.Deliveries[0].Status)
+		assert.Equal(t, 3, page.Deliveries[0].Attempts)
+		assert.Equal(t, 200, page.Deliveries[0].ResponseStatus)
+		assert.Equal(t, now.Add(2*time.Minute), *page.Deliveries[0].LastAttemptAt)
+		assert.JSONEq(t, due.Payload, string(page.Deliveries[0].Payload))
+	})
+
+	t.Run("pages deliveries newest first", func(t *testing.T) {
+		var deliveries []entities.WebhookDelivery
+		for i := 0; i < 5; i++ {
+			deliveries = append(deliveries, delivery(other, now.Add(48*time.Hour)))
+		}
+		require.NoError(t, wr.AddDeliveries(ctx, deliveries))
+
+		var ids []string
+		query := model.PageQuery{Limit: 2}
+		for {
+			page, err := wr.GetDeliveries(ctx, other.ID.Hex(), query)
+			require.NoError(t, err)
+			for _, delivery := range page.Deliveries {
+				ids = append(ids, delivery.ID)
+			}
+			if page.Next == "" {
+				break
+			}
+			query.Cursor = page.Next
+		}
+
+		require.Len(t, ids, 5)
+		assert.Equal(t, deliveries[4].ID.Hex(), ids[0])
+		assert.Equal(t, deliveries[0].ID.Hex(), ids[4])
+	})
+
+	t.Run("concurrent dispatchers claim each delivery once", func(t *testing.T) {
+		var many []entities.WebhookDelivery
+		for i := 0; i < 20; i++ {
+			many = append(many, delivery(created, now.Add(time.Duration(i)*time.Minute)))
+		}
+		require.NoError(t, wr.AddDeliveries(ctx, many))
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		claimedIDs := map[primitive.ObjectID]int{}
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					claimed, err := wr.ClaimDueDeliveries(ctx, now.Add(time.Hour), time.Hour, 3)
+					assert.NoError(t, err)
+					if len(claimed) == 0 {
+						return
+					}
+
+					mu.Lock()
+					for _, delivery := range claimed {
+						claimedIDs[delivery.ID]++
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, claimedIDs, len(many))
+		for _, count := range claimedIDs {
+			assert.Equal(t, 1, count)
+		}
+	})
+
+	t.Run("deleting a webhook deletes its deliveries", func(t *testing.T) {
+		require.NoError(t, wr.DeleteWebhook(ctx, other.ID.Hex()))
+
+		_, err := wr.GetWebhookByID(ctx, other.ID.Hex())
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		page, err := wr.GetDeliveries(ctx, other.ID.Hex(), model.PageQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Deliveries)
+
+		assert.ErrorIs(t, wr.DeleteWebhook(ctx, other.ID.Hex()), apperrors.ErrNotFound)
+	})
+}
+
+func testProjectRepository(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	pr := repositories.Projects
+	tr := repositories.Tasks
+
+	createdAt := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	project := entities.Project{
+		ID:        primitive.NewObjectID(),
+		Name:      "Launch",
+		OwnerID:   "u1",
+		Members:   []entities.ProjectMember{{UserID: "u2", Role: entities.ProjectRoleEditor}},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	require.NoError(t, pr.CreateProject(ctx, project))
+	require.NoError(t, pr.CreateProject(ctx, entities.Project{ID: primitive.NewObjectID(), Name: "Other", OwnerID: "u3", CreatedAt: createdAt.Add(time.Hour), UpdatedAt: createdAt}))
+
+	names := func(projects []*entities.Project) []string {
+		var names []string
+		for _, project := range projects {
+			names = append(names, project.Name)
+		}
+		return names
+	}
+
+	t.Run("lists the projects of owners and members", func(t *testing.T) {
+		for _, userID := range []string{"u1", "u2"} {
+			projects, err := pr.GetProjects(ctx, userID, false)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Launch"}, names(projects))
+		}
+
+		projects, err := pr.GetProjects(ctx, "u4", false)
+		require.NoError(t, err)
+		assert.Empty(t, projects)
+	})
+
+	t.Run("reads a project", func(t *testing.T) {
+		read, err := pr.GetProjectByID(ctx, project.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, project.Members, read.Members)
+		assert.Nil(t, read.ArchivedAt)
+
+		_, err = pr.GetProjectByID(ctx, primitive.NewObjectID().Hex())
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("updates and archives a project", func(t *testing.T) {
+		archivedAt := time.Now().UTC().Truncate(time.Millisecond)
+		updated, err := pr.GetProjectByID(ctx, project.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), updated.Version)
+		updated.Name = "Launch v2"
+		updated.Members = []entities.ProjectMember{{UserID: "u3", Role: entities.ProjectRoleViewer}}
+		updated.ArchivedAt = &archivedAt
+		require.NoError(t, pr.UpdateProject(ctx, *updated))
+
+		read, err := pr.GetProjectByID(ctx, project.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "Launch v2", read.Name)
+		assert.Equal(t, int64(2), read.Version)
+		assert.Equal(t, updated.Members, read.Members)
+		require.NotNil(t, read.ArchivedAt)
+		assert.True(t, archivedAt.Equal(*read.ArchivedAt))
+
+		projects, err := pr.GetProjects(ctx, "u3", false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Other"}, names(projects))
+
+		projects, err = pr.GetProjects(ctx, "u3", true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Launch v2", "Other"}, names(projects))
+
+		assert.ErrorIs(t, pr.UpdateProject(ctx, entities.Project{ID: primitive.NewObjectID()}), apperrors.ErrNotFound)
+	})
+
+	t.Run("refuses stale updates", func(t *testing.T) {
+		read, err := pr.GetProjectByID(ctx, project.ID.Hex())
+		require.NoError(t, err)
+
+		first, second := *read, *read
+		first.Members = append(first.Members, entities.ProjectMember{UserID: "u4", Role: entities.ProjectRoleEditor})
+		second.Name = "Launch v3"
+		require.NoError(t, pr.UpdateProject(ctx, first))
+		assert.ErrorIs(t, pr.UpdateProject(ctx, second), entities.ErrProjectModified)
+
+		read, err = pr.GetProjectByID(ctx, project.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "Launch v2", read.Name)
+		assert.Equal(t, first.Members, read.Members)
+	})
+
+	t.Run("lists and moves project tasks", func(t *testing.T) {
+		task := entities.Task{ID: primitive.NewObjectID(), UserID: "u5", Title: "In project", Status: entities.StatusTodo, Priority: entities.PriorityMedium, ProjectID: project.ID.Hex()}
+		require.NoError(t, tr.CreateTask(ctx, task))
+		require.NoError(t, tr.CreateTask(ctx, entities.Task{UserID: "u5", Title: "Personal", Status: entities.StatusTodo, Priority: entities.PriorityMedium}))
+
+		page, err := tr.GetTasks(ctx, "", model.TaskQuery{ProjectID: project.ID.Hex()})
+		require.NoError(t, err)
+		require.Len(t, page.Tasks, 1)
+		assert.Equal(t, project.ID.Hex(), page.Tasks[0].ProjectID)
+
+		read, err := tr.GetTaskByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		read.ProjectID = ""
+		require.NoError(t, tr.UpdateTask(ctx, task.ID.Hex(), *read, []string{entities.TaskFieldProjectID}))
+
+		page, err = tr.GetTasks(ctx, "", model.TaskQuery{ProjectID: project.ID.Hex()})
+		require.NoError(t, err)
+		assert.Empty(t, page.Tasks)
+	})
+}
+
+func testTagRepository(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	tr := repositories.Tasks
+	tags := repositories.Tags
+
+	for title, taskTags := range map[string][]string{
+		"Plan sprint":  {"work", "planning"},
+		"Write report": {"work", "writing"},
+		"Journal":      {"writing", "home"},
+		"Old draft":    {"writing"},
+	} {
+		require.NoError(t, tr.CreateTask(ctx, entities.Task{UserID: "u1", Title: title, Tags: taskTags}))
+	}
+	require.NoError(t, tr.CreateTask(ctx, entities.Task{UserID: "u2", Title: "Other", Tags: []string{"work"}}))
+
+	taskByTitle := func(t *testing.T, title string) *entities.Task {
+		page, err := tr.GetTasks(ctx, "u1", model.TaskQuery{Text: title})
+		require.NoError(t, err)
+		require.Len(t, page.Tasks, 1)
+
+		task, err := tr.GetTaskByID(ctx, page.Tasks[0].ID)
+		require.NoError(t, err)
+		return task
+	}
+	titles := func(t *testing.T, query model.TaskQuery) []string {
+		page, err := tr.GetTasks(ctx, "u1", query)
+		require.NoError(t, err)
+
+		var titles []string
+		for _, task := range page.Tasks {
+			titles = append(titles, task.Title)
+		}
+		sort.Strings(titles)
+		return titles
+	}
+
+	draft := taskByTitle(t, "Old draft")
+	require.NoError(t, tr.DeleteTask(ctx, draft.ID.Hex(), draft.Version))
+
+	t.Run("counts tags outside the trash", func(t *testing.T) {
+		infos, err := tags.GetTags(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, []*model.TagInfo{
+			{Name: "home", Count: 1},
+			{Name: "planning", Count: 1},
+			{Name: "work", Count: 2},
+			{Name: "writing", Count: 2},
+		}, infos)
+
+		infos, err = tags.GetTags(ctx, "u3")
+		require.NoError(t, err)
+		assert.Empty(t, infos)
+	})
+
+	t.Run("matches any or all tags", func(t *testing.T) {
+		assert.Equal(t, []string{"Journal", "Plan sprint", "Write report"},
+			titles(t, model.TaskQuery{Tags: []string{"home", "work"}, TagMatch: entities.TagMatchAny}))
+		assert.Equal(t, []string{"Write report"},
+			titles(t, model.TaskQuery{Tags: []string{"writing", "work"}, TagMatch: entities.TagMatchAll}))
+		assert.Empty(t, titles(t, model.TaskQuery{Tags: []string{"home", "work"}, TagMatch: entities.TagMatchAll}))
+	})
+
+	t.Run("renames and merges", func(t *testing.T) {
+		before := taskByTitle(t, "Write report")
+
+		renamed, err := tags.RenameTag(ctx, "u1", "writing", "work")
+		require.NoError(t, err)
+		require.Len(t, renamed, 3)
+		for _, task := range renamed {
+			assert.Contains(t, task.Tags, "writing")
+		}
+
+		after := taskByTitle(t, "Write report")
+		assert.Equal(t, []string{"work"}, after.Tags)
+		assert.Equal(t, before.Version+1, after.Version)
+		assert.ElementsMatch(t, []string{"home", "work"}, taskByTitle(t, "Journal").Tags)
+
+		renamed, err = tags.RenameTag(ctx, "u1", "missing", "other")
+		require.NoError(t, err)
+		assert.Empty(t, renamed)
+
+		other, err := tags.GetTags(ctx, "u2")
+		require.NoError(t, err)
+		assert.Equal(t, []*model.TagInfo{{Name: "work", Count: 1}}, other)
+
+		trash, err := tr.GetDeletedTasks(ctx, "u1", model.TaskQuery{})
+		require.NoError(t, err)
+		require.Len(t, trash.Tasks, 1)
+		assert.Equal(t, []string{"work"}, trash.Tasks[0].Tags)
+	})
+
+	t.Run("deletes", func(t *testing.T) {
+		deleted, err := tags.DeleteTag(ctx, "u1", "work")
+		require.NoError(t, err)
+		assert.Len(t, deleted, 4)
+
+		assert.Empty(t, taskByTitle(t, "Write report").Tags)
+		assert.Equal(t, []string{"planning"}, taskByTitle(t, "Plan sprint").Tags)
+
+		infos, err := tags.GetTags(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, []*model.TagInfo{{Name: "home", Count: 1}, {Name: "planning", Count: 1}}, infos)
+	})
+}
+
+func testCommentRepository(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	cr := repositories.Comments
+
+	var ids []string
+	for _, body := range []string{"First", "Second", "Third"} {
+		comment := entities.Comment{ID: primitive.NewObjectID(), TaskID: "t1", AuthorID: "u1", Body: body, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		require.NoError(t, cr.CreateComment(ctx, comment))
+		ids = append(ids, comment.ID.Hex())
+	}
+	require.NoError(t, cr.CreateComment(ctx, entities.Comment{ID: primitive.NewObjectID(), TaskID: "t2", AuthorID: "u1", Body: "Elsewhere"}))
+
+	t.Run("pages oldest first", func(t *testing.T) {
+		page, err := cr.GetComments(ctx, "t1", model.PageQuery{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Comments, 2)
+		assert.Equal(t, "First", page.Comments[0].Body)
+		assert.Equal(t, "Second", page.Comments[1].Body)
+		assert.Equal(t, ids[1], page.Next)
+
+		page, err = cr.GetComments(ctx, "t1", model.PageQuery{Cursor: page.Next, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Comments, 1)
+		assert.Equal(t, "Third", page.Comments[0].Body)
+		assert.Empty(t, page.Next)
+
+		_, err = cr.GetComments(ctx, "t1", model.PageQuery{Cursor: "bogus", Limit: 2})
+		assert.ErrorIs(t, err, entities.ErrInvalidTaskCursor)
+	})
+
+	t.Run("updates and deletes", func(t *testing.T) {
+		comment, err := cr.GetCommentByID(ctx, "t1", ids[0])
+		require.NoError(t, err)
+		assert.Equal(t, "u1", comment.AuthorID)
+
+		comment.Body = "Edited"
+		require.NoError(t, cr.UpdateComment(ctx, *comment))
+
+		comment, err = cr.GetCommentByID(ctx, "t1", ids[0])
+		require.NoError(t, err)
+		assert.Equal(t, "Edited", comment.Body)
+
+		_, err = cr.GetCommentByID(ctx, "t2", ids[0])
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		require.NoError(t, cr.DeleteComment(ctx, "t1", ids[0]))
+		assert.ErrorIs(t, cr.DeleteComment(ctx, "t1", ids[0]), apperrors.ErrNotFound)
+		assert.ErrorIs(t, cr.DeleteComment(ctx, "t1", "bogus"), apperrors.ErrNotFound)
+
+		comment.Body = "Gone"
+		assert.ErrorIs(t, cr.UpdateComment(ctx, *comment), apperrors.ErrNotFound)
+	})
+}
+
+func testActivityRepository(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	ar := repositories.Activity
+
+	for _, action := range []string{entities.ActivityCreated, entities.ActivityStatusChanged, entities.ActivityDeleted} {
+		activity := entities.Activity{TaskID: "t1", ActorID: "u1", Action: action, CreatedAt: time.Now()}
+		if action == entities.ActivityStatusChanged {
+			activity.Changes = []entities.FieldChange{{Field: "status", From: `"todo"`, To: `"done"`}}
+		}
+		require.NoError(t, ar.AddActivity(ctx, activity))
+	}
+	require.NoError(t, ar.AddActivity(ctx, entities.Activity{TaskID: "t2", ActorID: "u1", Action: entities.ActivityCreated}))
+
+	page, err := ar.GetActivity(ctx, "t1", model.PageQuery{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Activity, 2)
+	assert.Equal(t, entities.ActivityDeleted, page.Activity[0].Action)
+	assert.Equal(t, entities.ActivityStatusChanged, page.Activity[1].Action)
+	require.Len(t, page.Activity[1].Changes, 1)
+	assert.JSONEq(t, `"done"`, string(page.Activity[1].Changes[0].To))
+	assert.NotEmpty(t, page.Next)
+
+	page, err = ar.GetActivity(ctx, "t1", model.PageQuery{Cursor: page.Next, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Activity, 1)
+	assert.Equal(t, entities.ActivityCreated, page.Activity[0].Action)
+	assert.Empty(t, page.Activity[0].Changes)
+	assert.Empty(t, page.Next)
+}
+
+func testUserRepository(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	ur := repositories.Users
+
+	_, err := ur.CreateUser(ctx, model.UserCreate{Username: "alice", Password: "hash-a", Email: "alice@example.com", Role: entities.RoleAdmin})
+	require.NoError(t, err)
+	_, err = ur.CreateUser(ctx, model.UserCreate{Username: "bob", Password: "hash-b", Email: "bob@example.com", Role: entities.RoleUser})
+	require.NoError(t, err)
+
+	alice, err := ur.GetUserByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "hash-a", alice.Password)
+	assert.Equal(t, entities.RoleAdmin, alice.Role)
+
+	_, err = ur.GetUserByUsername(ctx, "carol")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	users, err := ur.GetUser(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	users, err = ur.GetUser(ctx, "BOB")
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "bob", users[0].UserName)
+
+	_, err = ur.CreateUser(ctx, model.UserCreate{Username: "bob", Password: "hash-b2", Role: entities.RoleUser})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	err = ur.UpdateUser(ctx, alice.ID.Hex(), entities.User{UserName: "bob"}, []string{entities.UserFieldUsername})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.ErrorContains(t, err, "username already exists")
+
+	require.NoError(t, ur.UpdatePassword(ctx, alice.ID.Hex(), "hash-a2"))
+	alice, err = ur.GetUserByID(ctx, alice.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "hash-a2", alice.Password)
+
+	require.NoError(t, ur.UpdateUser(ctx, alice.ID.Hex(), entities.User{UserName: "alice2", Password: "hash-a3", Role: entities.RoleAdmin},
+		[]string{entities.UserFieldUsername, entities.UserFieldPassword, entities.UserFieldRole}))
+	alice, err = ur.GetUserByID(ctx, alice.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "alice2", alice.UserName)
+	assert.Equal(t, "hash-a3", alice.Password)
+
+	require.NoError(t, ur.UpdateUser(ctx, alice.ID.Hex(), entities.User{UserName: "alice3", Name: "Alice"},
+		[]string{entities.UserFieldUsername, entities.UserFieldName, entities.UserFieldEmail}))
+	alice, err = ur.GetUserByID(ctx, alice.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "alice3", alice.UserName)
+	assert.Equal(t, "Alice", alice.Name)
+	assert.Empty(t, alice.Email)
+	assert.Equal(t, "hash-a3", alice.Password)
+	assert.Equal(t, entities.RoleAdmin, alice.Role)
+
+	require.NoError(t, ur.DeleteUser(ctx, alice.ID.Hex()))
+	_, err = ur.GetUserByID(ctx, alice.ID.Hex())
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.ErrorIs(t, ur.DeleteUser(ctx, alice.ID.Hex()), apperrors.ErrNotFound)
+	assert.ErrorIs(t, ur.UpdatePassword(ctx, alice.ID.Hex(), "hash"), apperrors.ErrNotFound)
+}
+
+func testTokenRepositories(t *testing.T, repositories *repository.Repositories) {
+	ctx := context.TODO()
+	refreshTokens := repositories.RefreshTokens
+	revokedTokens := repositories.RevokedTokens
+
+	token := entities.RefreshToken{
+		UserID:    "u1",
+		TokenHash: "hash",
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, refreshTokens.CreateRefreshToken(ctx, token))
+
+	stored, err := refreshTokens.GetRefreshTokenByHash(ctx, "hash")
+	require.NoError(t, err)
+	assert.Nil(t, stored.RevokedAt)
+
+	revoked, err := refreshTokens.RevokeRefreshToken(ctx, stored.ID.Hex())
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = refreshTokens.RevokeRefreshToken(ctx, stored.ID.Hex())
+	assert.NoError(t, err)
+	assert.False(t, revoked, "a token can only be revoked once")
+
+	family, err := refreshTokens.GetRefreshTokenFamily(ctx, "family")
+	require.NoError(t, err)
+	require.Len(t, family, 1)
+	assert.NotNil(t, family[0].RevokedAt)
+
+	active, err := refreshTokens.GetActiveRefreshTokens(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	isRevoked, err := revokedTokens.IsTokenRevoked(ctx, "jti")
+	assert.NoError(t, err)
+	assert.False(t, isRevoked)
+
+	require.NoError(t, revokedTokens.RevokeToken(ctx, entities.RevokedToken{ID: "jti", UserID: "u1", ExpiresAt: time.Now().Add(time.Hour)}))
+	require.NoError(t, revokedTokens.RevokeToken(ctx, entities.RevokedToken{ID: "jti", UserID: "u1", ExpiresAt: time.Now().Add(time.Hour)}))
+
+	isRevoked, err = revokedTokens.IsTokenRevoked(ctx, "jti")
+	assert.NoError(t, err)
+	assert.True(t, isRevoked)
+}
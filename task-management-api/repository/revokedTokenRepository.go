@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/entities"
+	"task-management-api/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type revokedTokenRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewRevokedTokenRepository(database mongo.Database, collection string) entities.RevokedTokenRepository {
+	return &revokedTokenRepository{
+		database:   database,
+		collection: collection,
+	}
+}
+
+func (rr *revokedTokenRepository) RevokeToken(ctx context.Context, token entities.RevokedToken) error {
+	filter := bson.M{
+		"_id": token.ID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"userid":    token.UserID,
+			"expiresAt": token.ExpiresAt,
+		},
+	}
+
+	_, err := rr.database.Collection(rr.collection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rr *revokedTokenRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	filter := bson.M{
+		"_id": tokenID,
+	}
+
+	var token entities.RevokedToken
+	err := rr.database.Collection(rr.collection).FindOne(ctx, filter).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sqlActivityRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLActivityRepository(database *SQLDatabase) entities.ActivityRepository {
+	return &sqlActivityRepository{
+		database: database,
+	}
+}
+
+// The field changes of an entry are stored as a JSON array.
+func (ar *sqlActivityRepository) AddActivity(ctx context.Context, activity entities.Activity) error {
+	if activity.ID.IsZero() {
+		activity.ID = primitive.NewObjectID()
+	}
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+
+	changes, err := json.Marshal(activity.Changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = ar.database.exec(ctx, `INSERT INTO task_activity (id, task_id, actor_id, action, changes, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		activity.ID.Hex(), activity.TaskID, activity.ActorID, activity.Action, string(changes), sqlTime(activity.CreatedAt))
+
+	return err
+}
+
+func (ar *sqlActivityRepository) GetActivity(ctx context.Context, taskID string, query model.PageQuery) (*model.ActivityPage, error) {
+	where, args := "task_id = ?", []interface{}{taskID}
+	if query.Cursor != "" {
+		before, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		where += " AND id < ?"
+		args = append(args, before.Hex())
+	}
+
+	// One extra entry is fetched to find out whether there is a next page.
+	limit := pageLimit(query)
+	args = append(args, limit+1)
+
+	rows, err := ar.database.query(ctx,
+		`SELECT id, task_id, actor_id, action, changes, created_at FROM task_activity WHERE `+where+` ORDER BY id DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &model.ActivityPage{
+		Activity: []*model.ActivityInfo{},
+	}
+
+	for rows.Next() {
+		var (
+			activity entities.Activity
+			id       string
+			changes  string
+		)
+
+		if err := rows.Scan(&id, &activity.TaskID, &activity.ActorID, &activity.Action, &changes, &activity.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		activity.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(changes), &activity.Changes); err != nil {
+			return nil, err
+		}
+		activity.CreatedAt = activity.CreatedAt.UTC()
+
+		page.Activity = append(page.Activity, activity.Info())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Activity) > limit {
+		page.Activity = page.Activity[:limit]
+		page.Next = page.Activity[limit-1].ID
+	}
+
+	return page, nil
+}
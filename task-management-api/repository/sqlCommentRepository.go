@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sqlCommentRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLCommentRepository(database *SQLDatabase) entities.CommentRepository {
+	return &sqlCommentRepository{
+		database: database,
+	}
+}
+
+const sqlCommentColumns = `id, task_id, author_id, body, created_at, updated_at`
+
+func (cr *sqlCommentRepository) GetComments(ctx context.Context, taskID string, query model.PageQuery) (*model.CommentPage, error) {
+	where, args := "task_id = ?", []interface{}{taskID}
+	if query.Cursor != "" {
+		after, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		where += " AND id > ?"
+		args = append(args, after.Hex())
+	}
+
+	// One extra comment is fetched to find out whether there is a next page.
+	limit := pageLimit(query)
+	args = append(args, limit+1)
+
+	comments, err := cr.find(ctx, `SELECT `+sqlCommentColumns+` FROM task_comments WHERE `+where+` ORDER BY id LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.CommentPage{
+		Comments: []*model.CommentInfo{},
+	}
+
+	for _, comment := range comments {
+		page.Comments = append(page.Comments, comment.Info())
+	}
+
+	if len(page.Comments) > limit {
+		page.Comments = page.Comments[:limit]
+		page.Next = page.Comments[limit-1].ID
+	}
+
+	return page, nil
+}
+
+func (cr *sqlCommentRepository) GetCommentByID(ctx context.Context, taskID string, id string) (*entities.Comment, error) {
+	comments, err := cr.find(ctx, `SELECT `+sqlCommentColumns+` FROM task_comments WHERE id = ? AND task_id = ?`, id, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(comments) == 0 {
+		return nil, sqlNotFound(sql.ErrNoRows, entities.ErrCommentNotFound)
+	}
+
+	return comments[0], nil
+}
+
+func (cr *sqlCommentRepository) CreateComment(ctx context.Context, comment entities.Comment) error {
+	if comment.ID.IsZero() {
+		comment.ID = primitive.NewObjectID()
+	}
+	now := sqlTime(time.Now())
+
+	_, err := cr.database.exec(ctx, `INSERT INTO task_comments (`+sqlCommentColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		comment.ID.Hex(), comment.TaskID, comment.AuthorID, comment.Body, now, now)
+
+	return err
+}
+
+func (cr *sqlCommentRepository) UpdateComment(ctx context.Context, comment entities.Comment) error {
+	result, err := cr.database.exec(ctx, `UPDATE task_comments SET body = ?, updated_at = ? WHERE id = ? AND task_id = ?`,
+		comment.Body, sqlTime(time.Now()), comment.ID.Hex(), comment.TaskID)
+	if err != nil {
+		return err
+	}
+
+	return sqlAffected(result, entities.ErrCommentNotFound)
+}
+
+func (cr *sqlCommentRepository) DeleteComment(ctx context.Context, taskID string, id string) error {
+	result, err := cr.database.exec(ctx, `DELETE FROM task_comments WHERE id = ? AND task_id = ?`, id, taskID)
+	if err != nil {
+		return err
+	}
+
+	return sqlAffected(result, entities.ErrCommentNotFound)
+}
+
+func (cr *sqlCommentRepository) find(ctx context.Context, query string, args ...interface{}) ([]*entities.Comment, error) {
+	rows, err := cr.database.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*entities.Comment
+	for rows.Next() {
+		var (
+			comment entities.Comment
+			id      string
+		)
+
+		if err := rows.Scan(&id, &comment.TaskID, &comment.AuthorID, &comment.Body, &comment.CreatedAt, &comment.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		comment.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		comment.CreatedAt = comment.CreatedAt.UTC()
+		comment.UpdatedAt = comment.UpdatedAt.UTC()
+
+		comments = append(comments, &comment)
+	}
+
+	return comments, rows.Err()
+}
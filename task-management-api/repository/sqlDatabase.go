@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"task-management-api/domain/apperrors"
+	"time"
+)
+
+type SQLDialect string
+
+const (
+	SQLite   SQLDialect = "sqlite"
+	Postgres SQLDialect = "postgres"
+)
+
+// SQLDatabase is a database/sql connection together with the dialect used to
+// write its queries. Queries are written with "?" placeholders and rewritten
+// for Postgres.
+type SQLDatabase struct {
+	DB      *sql.DB
+	Dialect SQLDialect
+}
+
+func NewSQLDatabase(db *sql.DB, dialect SQLDialect) *SQLDatabase {
+	return &SQLDatabase{
+		DB:      db,
+		Dialect: dialect,
+	}
+}
+
+func (sd *SQLDatabase) rebind(query string) string {
+	if sd.Dialect != Postgres {
+		return query
+	}
+
+	var builder strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			builder.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+func (sd *SQLDatabase) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return sd.DB.ExecContext(ctx, sd.rebind(query), args...)
+}
+
+func (sd *SQLDatabase) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return sd.DB.QueryContext(ctx, sd.rebind(query), args...)
+}
+
+func (sd *SQLDatabase) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return sd.DB.QueryRowContext(ctx, sd.rebind(query), args...)
+}
+
+// withTx runs fn in a transaction, committing it when fn succeeds.
+func (sd *SQLDatabase) withTx(ctx context.Context, fn func(tx *sqlTx) error) error {
+	tx, err := sd.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&sqlTx{tx: tx, database: sd}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type sqlTx struct {
+	tx       *sql.Tx
+	database *SQLDatabase
+}
+
+func (st *sqlTx) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return st.tx.ExecContext(ctx, st.database.rebind(query), args...)
+}
+
+func (st *sqlTx) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return st.tx.QueryRowContext(ctx, st.database.rebind(query), args...)
+}
+
+// sqlMigrations holds the schema, one entry per version. Entries are applied in
+// order and never edited once released; add a new entry to change the schema.
+var sqlMigrations = [][]string{
+	{
+		`CREATE TABLE users (
+			id TEXT PRIMARY KEY,
+			username TEXT NOT NULL UNIQUE,
+			password TEXT NOT NULL,
+			email TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL DEFAULT '',
+			bio TEXT NOT NULL DEFAULT '',
+			role TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE TABLE tasks (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			priority INTEGER NOT NULL,
+			due_date TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			completed_at TIMESTAMP NULL
+		)`,
+		`CREATE INDEX tasks_user_created_at ON tasks (user_id, created_at, id)`,
+		`CREATE INDEX tasks_user_due_date ON tasks (user_id, due_date, id)`,
+		`CREATE INDEX tasks_user_priority ON tasks (user_id, priority, id)`,
+		`CREATE INDEX tasks_user_status ON tasks (user_id, status, created_at)`,
+		`CREATE TABLE task_tags (
+			task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (task_id, tag)
+		)`,
+		`CREATE INDEX task_tags_tag ON task_tags (tag)`,
+		`CREATE TABLE refresh_tokens (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			family_id TEXT NOT NULL,
+			access_token_id TEXT NOT NULL,
+			access_token_expires_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			revoked_at TIMESTAMP NULL
+		)`,
+		`CREATE INDEX refresh_tokens_family ON refresh_tokens (family_id)`,
+		`CREATE INDEX refresh_tokens_user ON refresh_tokens (user_id)`,
+		`CREATE TABLE revoked_tokens (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		)`,
+	},
+	{
+		`ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+	},
+	{
+		`ALTER TABLE tasks ADD COLUMN deleted_at TIMESTAMP NULL`,
+		`CREATE INDEX tasks_deleted_at ON tasks (deleted_at)`,
+	},
+	{
+		`ALTER TABLE tasks ADD COLUMN parent_id TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX tasks_user_parent ON tasks (user_id, parent_id, created_at)`,
+		`CREATE TABLE task_blockers (
+			task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+			blocker_id TEXT NOT NULL,
+			PRIMARY KEY (task_id, blocker_id)
+		)`,
+		`CREATE INDEX task_blockers_blocker ON task_blockers (blocker_id)`,
+	},
+	{
+		`CREATE TABLE task_comments (
+			id TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL,
+			author_id TEXT NOT NULL,
+			body TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX task_comments_task ON task_comments (task_id, id)`,
+		`CREATE TABLE task_activity (
+			id TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL,
+			actor_id TEXT NOT NULL,
+			action TEXT NOT NULL,
+			changes TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX task_activity_task ON task_activity (task_id, id)`,
+	},
+	{
+		`ALTER TABLE tasks ADD COLUMN assignee_id TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX tasks_assignee ON tasks (assignee_id, created_at)`,
+		`CREATE TABLE task_collaborators (
+			task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL,
+			role TEXT NOT NULL,
+			PRIMARY KEY (task_id, user_id)
+		)`,
+		`CREATE INDEX task_collaborators_user ON task_collaborators (user_id)`,
+	},
+	{
+		`CREATE TABLE projects (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			owner_id TEXT NOT NULL,
+			archived_at TIMESTAMP NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX projects_owner ON projects (owner_id, created_at)`,
+		`CREATE TABLE project_members (
+			project_id TEXT NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL,
+			role TEXT NOT NULL,
+			PRIMARY KEY (project_id, user_id)
+		)`,
+		`CREATE INDEX project_members_user ON project_members (user_id)`,
+		`ALTER TABLE tasks ADD COLUMN project_id TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX tasks_project ON tasks (project_id, created_at)`,
+	},
+	{
+		`ALTER TABLE tasks ADD COLUMN recurrence TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE tasks ADD COLUMN series_id TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE tasks ADD COLUMN occurrence INTEGER NOT NULL DEFAULT 0`,
+		`CREATE INDEX tasks_series ON tasks (series_id, occurrence)`,
+	},
+	{
+		`ALTER TABLE tasks ADD COLUMN reminders TEXT NOT NULL DEFAULT ''`,
+		`CREATE TABLE reminders (
+			id TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+			reminder_offset TEXT NOT NULL,
+			remind_at TIMESTAMP NOT NULL,
+			claimed_by TEXT NOT NULL DEFAULT '',
+			claimed_until TIMESTAMP NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			sent_at TIMESTAMP NULL
+		)`,
+		`CREATE INDEX reminders_due ON reminders (sent_at, remind_at)`,
+		`CREATE INDEX reminders_task ON reminders (task_id)`,
+	},
+	{
+		`CREATE TABLE webhooks (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX webhooks_user ON webhooks (user_id, created_at)`,
+		`CREATE TABLE webhook_events (
+			webhook_id TEXT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+			event TEXT NOT NULL,
+			PRIMARY KEY (webhook_id, event)
+		)`,
+		`CREATE INDEX webhook_events_event ON webhook_events (event)`,
+		`CREATE TABLE webhook_deliveries (
+			id TEXT PRIMARY KEY,
+			webhook_id TEXT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+			event TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMP NOT NULL,
+			last_attempt_at TIMESTAMP NULL,
+			response_status INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at)`,
+		`CREATE INDEX webhook_deliveries_webhook ON webhook_deliveries (webhook_id, id)`,
+	},
+	{
+		`ALTER TABLE projects ADD COLUMN version INTEGER NOT NULL DEFAULT 0`,
+	},
+}
+
+// Migrate brings the schema up to date, recording applied versions in the
+// schema_migrations table.
+func (sd *SQLDatabase) Migrate(ctx context.Context) error {
+	_, err := sd.exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := sd.queryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	for i := current; i < len(sqlMigrations); i++ {
+		version := i + 1
+		err := sd.withTx(ctx, func(tx *sqlTx) error {
+			for _, statement := range sqlMigrations[i] {
+				if _, err := tx.exec(ctx, statement); err != nil {
+					return err
+				}
+			}
+
+			_, err := tx.exec(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, sqlTime(time.Now()))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("apply migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// sqlTime stores every timestamp in UTC so values compare and sort the same
+// way in every dialect.
+func sqlTime(t time.Time) time.Time {
+	return t.UTC()
+}
+
+func sqlNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: sqlTime(*t), Valid: true}
+}
+
+func timePointer(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	value := t.Time.UTC()
+	return &value
+}
+
+// sqlNotFound maps sql.ErrNoRows to the same not found error the MongoDB
+// repositories return.
+func sqlNotFound(err error, notFound *apperrors.Error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound.Wrap(err)
+	}
+
+	return err
+}
+
+// Error codes of a write rejected by a UNIQUE constraint, in Postgres and in
+// SQLite (SQLITE_CONSTRAINT_UNIQUE).
+const (
+	postgresUniqueViolation = "23505"
+	sqliteConstraintUnique  = 2067
+)
+
+// sqlUniqueViolation maps a write rejected by a UNIQUE constraint to the same
+// conflict error the MongoDB repositories return for a unique index.
+func sqlUniqueViolation(err error, conflict *apperrors.Error) error {
+	var postgresError interface{ SQLState() string }
+	if errors.As(err, &postgresError) && postgresError.SQLState() == postgresUniqueViolation {
+		return conflict.Wrap(err)
+	}
+
+	var sqliteError interface{ Code() int }
+	if errors.As(err, &sqliteError) && sqliteError.Code() == sqliteConstraintUnique {
+		return conflict.Wrap(err)
+	}
+
+	return err
+}
+
+// sqlAffected returns notFound when a statement did not change any row.
+func sqlAffected(result sql.Result, notFound *apperrors.Error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// likePattern builds a case-insensitive LIKE pattern matching text anywhere in
+// a value; use it with ESCAPE '\'.
+func likePattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(strings.ToLower(text)) + "%"
+}
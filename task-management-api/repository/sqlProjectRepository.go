@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"task-management-api/domain/entities"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sqlProjectRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLProjectRepository(database *SQLDatabase) entities.ProjectRepository {
+	return &sqlProjectRepository{
+		database: database,
+	}
+}
+
+const sqlProjectColumns = `id, name, owner_id, archived_at, created_at, updated_at, version`
+
+func (pr *sqlProjectRepository) GetProjects(ctx context.Context, userID string, archived bool) ([]*entities.Project, error) {
+	where := "(owner_id = ? OR id IN (SELECT project_id FROM project_members WHERE user_id = ?))"
+	if !archived {
+		where += " AND archived_at IS NULL"
+	}
+
+	projects, err := pr.find(ctx, `SELECT `+sqlProjectColumns+` FROM projects WHERE `+where+` ORDER BY created_at, id`, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if projects == nil {
+		projects = []*entities.Project{}
+	}
+
+	return projects, nil
+}
+
+func (pr *sqlProjectRepository) GetProjectByID(ctx context.Context, id string) (*entities.Project, error) {
+	projects, err := pr.find(ctx, `SELECT `+sqlProjectColumns+` FROM projects WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(projects) == 0 {
+		return nil, sqlNotFound(sql.ErrNoRows, entities.ErrProjectNotFound)
+	}
+
+	return projects[0], nil
+}
+
+func (pr *sqlProjectRepository) CreateProject(ctx context.Context, project entities.Project) error {
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+	id := project.ID.Hex()
+
+	return pr.database.withTx(ctx, func(tx *sqlTx) error {
+		_, err := tx.exec(ctx, `INSERT INTO projects (`+sqlProjectColumns+`) VALUES (?, ?, ?, ?, ?, ?, 1)`,
+			id, project.Name, project.OwnerID, sqlNullTime(project.ArchivedAt), sqlTime(project.CreatedAt), sqlTime(project.UpdatedAt))
+		if err != nil {
+			return err
+		}
+
+		return replaceProjectMembers(ctx, tx, id, project.Members)
+	})
+}
+
+func (pr *sqlProjectRepository) UpdateProject(ctx context.Context, project entities.Project) error {
+	id := project.ID.Hex()
+
+	return pr.database.withTx(ctx, func(tx *sqlTx) error {
+		result, err := tx.exec(ctx, `UPDATE projects SET name = ?, archived_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ?`,
+			project.Name, sqlNullTime(project.ArchivedAt), sqlTime(time.Now()), id, project.Version)
+		if err != nil {
+			return err
+		}
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			var count int
+			if err := tx.queryRow(ctx, `SELECT COUNT(*) FROM projects WHERE id = ?`, id).Scan(&count); err != nil {
+				return err
+			}
+			if count == 0 {
+				return entities.ErrProjectNotFound
+			}
+			return entities.ErrProjectModified
+		}
+
+		return replaceProjectMembers(ctx, tx, id, project.Members)
+	})
+}
+
+func replaceProjectMembers(ctx context.Context, tx *sqlTx, projectID string, members []entities.ProjectMember) error {
+	if _, err := tx.exec(ctx, `DELETE FROM project_members WHERE project_id = ?`, projectID); err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if _, err := tx.exec(ctx, `INSERT INTO project_members (project_id, user_id, role) VALUES (?, ?, ?)`,
+			projectID, member.UserID, member.Role); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// find runs a query selecting sqlProjectColumns and loads the members of the
+// returned projects.
+func (pr *sqlProjectRepository) find(ctx context.Context, query string, args ...interface{}) ([]*entities.Project, error) {
+	rows, err := pr.database.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*entities.Project
+	byID := map[string]*entities.Project{}
+
+	for rows.Next() {
+		var (
+			project    entities.Project
+			id         string
+			archivedAt sql.NullTime
+		)
+
+		if err := rows.Scan(&id, &project.Name, &project.OwnerID, &archivedAt, &project.CreatedAt, &project.UpdatedAt, &project.Version); err != nil {
+			return nil, err
+		}
+
+		project.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		project.ArchivedAt = timePointer(archivedAt)
+		project.CreatedAt = project.CreatedAt.UTC()
+		project.UpdatedAt = project.UpdatedAt.UTC()
+
+		projects = append(projects, &project)
+		byID[id] = &project
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(projects) == 0 {
+		return projects, nil
+	}
+
+	placeholders := make([]string, 0, len(projects))
+	ids := make([]interface{}, 0, len(projects))
+	for id := range byID {
+		placeholders = append(placeholders, "?")
+		ids = append(ids, id)
+	}
+
+	memberRows, err := pr.database.query(ctx,
+		`SELECT project_id, user_id, role FROM project_members WHERE project_id IN (`+strings.Join(placeholders, ", ")+`) ORDER BY user_id`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer memberRows.Close()
+
+	for memberRows.Next() {
+		var (
+			projectID string
+			member    entities.ProjectMember
+		)
+		if err := memberRows.Scan(&projectID, &member.UserID, &member.Role); err != nil {
+			return nil, err
+		}
+		byID[projectID].Members = append(byID[projectID].Members, member)
+	}
+
+	return projects, memberRows.Err()
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"task-management-api/domain/entities"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sqlReminderRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLReminderRepository(database *SQLDatabase) entities.ReminderRepository {
+	return &sqlReminderRepository{
+		database: database,
+	}
+}
+
+const sqlReminderColumns = `id, task_id, reminder_offset, remind_at, claimed_by, claimed_until, attempts, sent_at`
+
+// sqlClaimableReminder matches the reminders that are neither sent, claimed
+// until after now nor out of attempts. Its arguments are now and
+// entities.MaxReminderAttempts.
+const sqlClaimableReminder = `sent_at IS NULL AND (claimed_until IS NULL OR claimed_until <= ?) AND attempts < ?`
+
+// ReplaceReminders leaves alone the reminders a scheduler is sending, which
+// it would otherwise send while their replacements are claimed again.
+func (rr *sqlReminderRepository) ReplaceReminders(ctx context.Context, taskID string, reminders []entities.Reminder, now time.Time) error {
+	now = sqlTime(now)
+	kept, err := rr.find(ctx, `SELECT `+sqlReminderColumns+` FROM reminders
+		WHERE task_id = ? AND (sent_at IS NOT NULL OR claimed_until > ?)`, taskID, now)
+	if err != nil {
+		return err
+	}
+
+	return rr.database.withTx(ctx, func(tx *sqlTx) error {
+		_, err := tx.exec(ctx, `DELETE FROM reminders
+			WHERE task_id = ? AND sent_at IS NULL AND (claimed_until IS NULL OR claimed_until <= ?)`, taskID, now)
+		if err != nil {
+			return err
+		}
+
+		for _, reminder := range newReminders(reminders, derefReminders(kept)) {
+			if reminder.ID.IsZero() {
+				reminder.ID = primitive.NewObjectID()
+			}
+
+			_, err := tx.exec(ctx, `INSERT INTO reminders (`+sqlReminderColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				reminder.ID.Hex(), taskID, reminder.Offset, sqlTime(reminder.RemindAt), reminder.ClaimedBy,
+				sqlNullTime(reminder.ClaimedUntil), reminder.Attempts, sqlNullTime(reminder.SentAt))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func derefReminders(reminders []*entities.Reminder) []entities.Reminder {
+	values := make([]entities.Reminder, 0, len(reminders))
+	for _, reminder := range reminders {
+		values = append(values, *reminder)
+	}
+
+	return values
+}
+
+func (rr *sqlReminderRepository) ClaimDueReminders(ctx context.Context, now time.Time, claimant string, lease time.Duration, limit int) ([]*entities.Reminder, error) {
+	now = sqlTime(now)
+	candidates, err := rr.find(ctx,
+		`SELECT `+sqlReminderColumns+` FROM reminders WHERE remind_at <= ? AND `+sqlClaimableReminder+` ORDER BY remind_at, id LIMIT ?`,
+		now, now, entities.MaxReminderAttempts, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another scheduler may claim a candidate between the query above and
+	// the update below. The update only matches while the reminder is still
+	// claimable, so each one is claimed by a single scheduler.
+	claimedUntil := now.Add(lease)
+	claimed := []*entities.Reminder{}
+	for _, reminder := range candidates {
+		result, err := rr.database.exec(ctx,
+			`UPDATE reminders SET claimed_by = ?, claimed_until = ?, attempts = attempts + 1 WHERE id = ? AND `+sqlClaimableReminder,
+			claimant, claimedUntil, reminder.ID.Hex(), now, entities.MaxReminderAttempts)
+		if err != nil {
+			return nil, err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 1 {
+			reminder.ClaimedBy = claimant
+			reminder.ClaimedUntil = &claimedUntil
+			reminder.Attempts++
+			claimed = append(claimed, reminder)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (rr *sqlReminderRepository) MarkReminderSent(ctx context.Context, id string, sentAt time.Time) error {
+	_, err := rr.database.exec(ctx, `UPDATE reminders SET sent_at = ? WHERE id = ?`, sqlTime(sentAt), id)
+	return err
+}
+
+// find runs a query selecting sqlReminderColumns.
+func (rr *sqlReminderRepository) find(ctx context.Context, query string, args ...interface{}) ([]*entities.Reminder, error) {
+	rows, err := rr.database.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*entities.Reminder
+	for rows.Next() {
+		var (
+			reminder     entities.Reminder
+			id           string
+			claimedUntil sql.NullTime
+			sentAt       sql.NullTime
+		)
+
+		if err := rows.Scan(&id, &reminder.TaskID, &reminder.Offset, &reminder.RemindAt, &reminder.ClaimedBy, &claimedUntil, &reminder.Attempts, &sentAt); err != nil {
+			return nil, err
+		}
+
+		reminder.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		reminder.RemindAt = reminder.RemindAt.UTC()
+		reminder.ClaimedUntil = timePointer(claimedUntil)
+		reminder.SentAt = timePointer(sentAt)
+
+		reminders = append(reminders, &reminder)
+	}
+
+	return reminders, rows.Err()
+}
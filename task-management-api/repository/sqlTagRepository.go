@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"strings"
+	"time"
+)
+
+type sqlTagRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLTagRepository(database *SQLDatabase) entities.TagRepository {
+	return &sqlTagRepository{
+		database: database,
+	}
+}
+
+func (tr *sqlTagRepository) GetTags(ctx context.Context, userID string) ([]*model.TagInfo, error) {
+	rows, err := tr.database.query(ctx, `SELECT task_tags.tag, COUNT(*) FROM task_tags
+		JOIN tasks ON tasks.id = task_tags.task_id
+		WHERE tasks.user_id = ? AND tasks.deleted_at IS NULL
+		GROUP BY task_tags.tag
+		ORDER BY task_tags.tag`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []*model.TagInfo{}
+	for rows.Next() {
+		var tag model.TagInfo
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (tr *sqlTagRepository) RenameTag(ctx context.Context, userID string, name string, newName string) ([]*entities.Task, error) {
+	return tr.updateTaggedTasks(ctx, userID, name, func(tx *sqlTx, placeholders string, ids []interface{}) error {
+		// Tasks already carrying the new tag only lose the old one.
+		_, err := tx.exec(ctx, `INSERT INTO task_tags (task_id, tag)
+			SELECT task_id, ? FROM task_tags
+			WHERE tag = ? AND task_id IN (`+placeholders+`)
+			AND task_id NOT IN (SELECT task_id FROM task_tags WHERE tag = ?)`, append(append([]interface{}{newName, name}, ids...), newName)...)
+		return err
+	})
+}
+
+func (tr *sqlTagRepository) DeleteTag(ctx context.Context, userID string, name string) ([]*entities.Task, error) {
+	return tr.updateTaggedTasks(ctx, userID, name, nil)
+}
+
+// updateTaggedTasks reads the user's tasks carrying tag, then in a single
+// transaction bumps their version, runs change, if any, and removes the tag
+// from them. change gets the placeholders and arguments matching the ids of
+// the tasks read, so that tasks tagged after the read are left alone.
+func (tr *sqlTagRepository) updateTaggedTasks(ctx context.Context, userID string, tag string, change func(tx *sqlTx, placeholders string, ids []interface{}) error) ([]*entities.Task, error) {
+	tasks, err := (&sqlTaskRepository{database: tr.database}).queryTasks(ctx, `SELECT `+sqlTaskColumns+` FROM tasks
+		WHERE user_id = ? AND id IN (SELECT task_id FROM task_tags WHERE tag = ?)
+		ORDER BY created_at, id`, userID, tag)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+
+	ids := make([]interface{}, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID.Hex()
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	err = tr.database.withTx(ctx, func(tx *sqlTx) error {
+		_, err := tx.exec(ctx, `UPDATE tasks SET updated_at = ?, version = version + 1 WHERE id IN (`+placeholders+`)`,
+			append([]interface{}{sqlTime(time.Now())}, ids...)...)
+		if err != nil {
+			return err
+		}
+
+		if change != nil {
+			if err := change(tx, placeholders, ids); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.exec(ctx, `DELETE FROM task_tags WHERE tag = ? AND task_id IN (`+placeholders+`)`, append([]interface{}{tag}, ids...)...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,626 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sqlTaskRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLTaskRepository(database *SQLDatabase) entities.TaskRepository {
+	return &sqlTaskRepository{
+		database: database,
+	}
+}
+
+const sqlTaskColumns = `id, user_id, title, description, status, priority, due_date, created_at, updated_at, completed_at, version, deleted_at, parent_id, assignee_id, project_id, recurrence, series_id, occurrence, reminders`
+
+var sqlTaskSortColumns = map[string]string{
+	entities.TaskSortDueDate:   "due_date",
+	entities.TaskSortPriority:  "priority",
+	entities.TaskSortCreatedAt: "created_at",
+}
+
+func (tr *sqlTaskRepository) GetTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	return tr.listTasks(ctx, userID, query, false)
+}
+
+func (tr *sqlTaskRepository) GetDeletedTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	return tr.listTasks(ctx, userID, query, true)
+}
+
+// listTasks returns one page of the user's tasks, either those they own in the
+// trash or all others they own, collaborate on or are assigned, or one page of
+// a project's tasks.
+func (tr *sqlTaskRepository) listTasks(ctx context.Context, userID string, query model.TaskQuery, deleted bool) (*model.TaskPage, error) {
+	sortKey, descending, err := entities.ParseTaskSort(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	where := []string{"(user_id = ? OR assignee_id = ? OR id IN (SELECT task_id FROM task_collaborators WHERE user_id = ?))", "deleted_at IS NULL"}
+	args := []interface{}{userID, userID, userID}
+	switch {
+	case deleted:
+		where = []string{"user_id = ?", "deleted_at IS NOT NULL"}
+		args = []interface{}{userID}
+	case query.ProjectID != "":
+		where = []string{"project_id = ?", "deleted_at IS NULL"}
+		args = []interface{}{query.ProjectID}
+	}
+
+	if query.AssigneeID != "" {
+		where = append(where, "assignee_id = ?")
+		args = append(args, query.AssigneeID)
+	}
+
+	if query.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, query.Status)
+	}
+
+	if query.Priority != "" {
+		priority, err := entities.ParsePriority(query.Priority)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, "priority = ?")
+		args = append(args, int(priority))
+	}
+
+	if len(query.Tags) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(query.Tags)), ", ")
+		for _, tag := range query.Tags {
+			args = append(args, tag)
+		}
+
+		if query.TagMatch == entities.TagMatchAll {
+			where = append(where, "(SELECT COUNT(DISTINCT tag) FROM task_tags WHERE task_tags.task_id = tasks.id AND task_tags.tag IN ("+placeholders+")) = ?")
+			args = append(args, len(query.Tags))
+		} else {
+			where = append(where, "EXISTS (SELECT 1 FROM task_tags WHERE task_tags.task_id = tasks.id AND task_tags.tag IN ("+placeholders+"))")
+		}
+	}
+
+	if query.DueBefore != nil || query.DueAfter != nil {
+		// Tasks without a due date are stored with the zero time and never
+		// match a due date range.
+		dueAfter := time.Time{}
+		if query.DueAfter != nil {
+			dueAfter = *query.DueAfter
+		}
+		where = append(where, "due_date > ?")
+		args = append(args, sqlTime(dueAfter))
+
+		if query.DueBefore != nil {
+			where = append(where, "due_date < ?")
+			args = append(args, sqlTime(*query.DueBefore))
+		}
+	}
+
+	if query.Text != "" {
+		pattern := likePattern(query.Text)
+		where = append(where, `(LOWER(title) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+
+	column := sqlTaskSortColumns[sortKey]
+	operator, direction := ">", "ASC"
+	if descending {
+		operator, direction = "<", "DESC"
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeTaskCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := parseTaskSortValue(sortKey, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case time.Time:
+			value = sqlTime(v)
+		case entities.Priority:
+			value = int(v)
+		}
+
+		where = append(where, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, operator))
+		args = append(args, value, value, cursor.ID)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = model.DefaultTaskPageSize
+	}
+
+	// One extra task is fetched to find out whether there is a next page.
+	statement := fmt.Sprintf(`SELECT %s FROM tasks WHERE %s ORDER BY %s %s, id %s LIMIT %d`,
+		sqlTaskColumns, strings.Join(where, " AND "), column, direction, direction, limit+1)
+
+	tasks, err := tr.queryTasks(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.TaskPage{
+		Tasks: []*model.TaskInfo{},
+	}
+
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		page.Next = encodeTaskCursor(sortKey, tasks[limit-1])
+	}
+
+	for _, task := range tasks {
+		page.Tasks = append(page.Tasks, task.Info())
+	}
+
+	return page, nil
+}
+
+func (tr *sqlTaskRepository) GetTaskByID(ctx context.Context, id string) (*entities.Task, error) {
+	tasks, err := tr.queryTasks(ctx, `SELECT `+sqlTaskColumns+` FROM tasks WHERE id = ? AND deleted_at IS NULL`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		return nil, sqlNotFound(sql.ErrNoRows, errTaskNotFound)
+	}
+
+	return tasks[0], nil
+}
+
+func (tr *sqlTaskRepository) GetTaskIncludingDeleted(ctx context.Context, id string) (*entities.Task, error) {
+	tasks, err := tr.queryTasks(ctx, `SELECT `+sqlTaskColumns+` FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		return nil, sqlNotFound(sql.ErrNoRows, errTaskNotFound)
+	}
+
+	return tasks[0], nil
+}
+
+func (tr *sqlTaskRepository) UpdateTask(ctx context.Context, id string, updatedTask entities.Task, fields []string) error {
+	set := []string{"updated_at = ?", "version = version + 1"}
+	args := []interface{}{sqlTime(time.Now())}
+	replaceTags, replaceBlockers, replaceCollaborators := false, false, false
+
+	for _, field := range fields {
+		switch field {
+		case entities.TaskFieldTitle:
+			set = append(set, "title = ?")
+			args = append(args, updatedTask.Title)
+		case entities.TaskFieldDescription:
+			set = append(set, "description = ?")
+			args = append(args, updatedTask.Description)
+		case entities.TaskFieldStatus:
+			set = append(set, "status = ?", "completed_at = ?")
+			args = append(args, updatedTask.Status, sqlNullTime(updatedTask.CompletedAt))
+		case entities.TaskFieldPriority:
+			set = append(set, "priority = ?")
+			args = append(args, int(updatedTask.Priority))
+		case entities.TaskFieldTags:
+			replaceTags = true
+		case entities.TaskFieldDueDate:
+			set = append(set, "due_date = ?")
+			args = append(args, sqlTime(updatedTask.DueDate))
+		case entities.TaskFieldParentID:
+			set = append(set, "parent_id = ?")
+			args = append(args, updatedTask.ParentID)
+		case entities.TaskFieldBlockedBy:
+			replaceBlockers = true
+		case entities.TaskFieldCollaborators:
+			replaceCollaborators = true
+		case entities.TaskFieldAssigneeID:
+			set = append(set, "assignee_id = ?")
+			args = append(args, updatedTask.AssigneeID)
+		case entities.TaskFieldProjectID:
+			set = append(set, "project_id = ?")
+			args = append(args, updatedTask.ProjectID)
+		case entities.TaskFieldRecurrence:
+			set = append(set, "recurrence = ?", "series_id = ?", "occurrence = ?")
+			args = append(args, updatedTask.Recurrence, updatedTask.SeriesID, updatedTask.Occurrence)
+		case entities.TaskFieldReminders:
+			set = append(set, "reminders = ?")
+			args = append(args, strings.Join(updatedTask.Reminders, ","))
+		default:
+			return fmt.Errorf("unknown task field %q", field)
+		}
+	}
+	args = append(args, id, updatedTask.Version)
+
+	return tr.database.withTx(ctx, func(tx *sqlTx) error {
+		result, err := tx.exec(ctx, `UPDATE tasks SET `+strings.Join(set, ", ")+` WHERE id = ? AND version = ? AND deleted_at IS NULL`, args...)
+		if err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return unmatchedSQLTaskError(ctx, tx, id)
+		}
+
+		if replaceTags {
+			if err := replaceTaskTags(ctx, tx, id, updatedTask.Tags); err != nil {
+				return err
+			}
+		}
+		if replaceBlockers {
+			if err := replaceTaskBlockers(ctx, tx, id, updatedTask.BlockedBy); err != nil {
+				return err
+			}
+		}
+		if replaceCollaborators {
+			return replaceTaskCollaborators(ctx, tx, id, updatedTask.Collaborators)
+		}
+
+		return nil
+	})
+}
+
+func (tr *sqlTaskRepository) DeleteTask(ctx context.Context, id string, version int64) error {
+	now := sqlTime(time.Now())
+
+	return tr.database.withTx(ctx, func(tx *sqlTx) error {
+		statement := `UPDATE tasks SET deleted_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL`
+		args := []interface{}{now, now, id}
+		if version != entities.AnyTaskVersion {
+			statement += ` AND version = ?`
+			args = append(args, version)
+		}
+
+		result, err := tx.exec(ctx, statement, args...)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return unmatchedSQLTaskError(ctx, tx, id)
+		}
+
+		return nil
+	})
+}
+
+func (tr *sqlTaskRepository) RestoreTask(ctx context.Context, id string, userID string) error {
+	result, err := tr.database.exec(ctx,
+		`UPDATE tasks SET deleted_at = NULL, updated_at = ?, version = version + 1 WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL`,
+		sqlTime(time.Now()), id, userID)
+	if err != nil {
+		return err
+	}
+
+	restored, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if restored == 0 {
+		return errTaskNotInTrash
+	}
+
+	return nil
+}
+
+// PurgeDeletedTasks unlinks the purged tasks from their subtasks and from the
+// tasks they block before deleting them.
+func (tr *sqlTaskRepository) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+
+	err := tr.database.withTx(ctx, func(tx *sqlTx) error {
+		const purgedIDs = `SELECT id FROM tasks WHERE deleted_at < ?`
+
+		_, err := tx.exec(ctx, `DELETE FROM task_tags WHERE task_id IN (`+purgedIDs+`)`, sqlTime(deletedBefore))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(ctx, `DELETE FROM task_blockers WHERE task_id IN (`+purgedIDs+`) OR blocker_id IN (`+purgedIDs+`)`,
+			sqlTime(deletedBefore), sqlTime(deletedBefore))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(ctx, `DELETE FROM task_collaborators WHERE task_id IN (`+purgedIDs+`)`, sqlTime(deletedBefore))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(ctx, `UPDATE tasks SET parent_id = '' WHERE parent_id IN (`+purgedIDs+`)`, sqlTime(deletedBefore))
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.exec(ctx, `DELETE FROM tasks WHERE deleted_at < ?`, sqlTime(deletedBefore))
+		if err != nil {
+			return err
+		}
+
+		purged, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+func (tr *sqlTaskRepository) GetSubtasks(ctx context.Context, parentID string) ([]*entities.Task, error) {
+	tasks, err := tr.queryTasks(ctx, `SELECT `+sqlTaskColumns+` FROM tasks
+		WHERE parent_id = ? AND deleted_at IS NULL
+		ORDER BY created_at, id`, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tasks == nil {
+		tasks = []*entities.Task{}
+	}
+
+	return tasks, nil
+}
+
+func (tr *sqlTaskRepository) GetSeriesTasks(ctx context.Context, seriesID string) ([]*entities.Task, error) {
+	tasks, err := tr.queryTasks(ctx, `SELECT `+sqlTaskColumns+` FROM tasks
+		WHERE series_id = ? AND deleted_at IS NULL
+		ORDER BY occurrence, id`, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tasks == nil {
+		tasks = []*entities.Task{}
+	}
+
+	return tasks, nil
+}
+
+// unmatchedSQLTaskError tells why a conditional write matched no task: either
+// the task is missing or its version moved on.
+func unmatchedSQLTaskError(ctx context.Context, tx *sqlTx, id string) error {
+	var count int
+	if err := tx.queryRow(ctx, `SELECT COUNT(*) FROM tasks WHERE id = ? AND deleted_at IS NULL`, id).Scan(&count); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return errTaskNotFound
+	}
+
+	return entities.ErrTaskModified
+}
+
+func (tr *sqlTaskRepository) CreateTask(ctx context.Context, newTask entities.Task) error {
+	if newTask.ID.IsZero() {
+		newTask.ID = primitive.NewObjectID()
+	}
+
+	now := sqlTime(time.Now())
+	id := newTask.ID.Hex()
+
+	return tr.database.withTx(ctx, func(tx *sqlTx) error {
+		_, err := tx.exec(ctx, `INSERT INTO tasks (`+sqlTaskColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id,
+			newTask.UserID,
+			newTask.Title,
+			newTask.Description,
+			newTask.Status,
+			int(newTask.Priority),
+			sqlTime(newTask.DueDate),
+			now,
+			now,
+			sqlNullTime(newTask.CompletedAt),
+			1,
+			sql.NullTime{},
+			newTask.ParentID,
+			newTask.AssigneeID,
+			newTask.ProjectID,
+			newTask.Recurrence,
+			newTask.SeriesID,
+			newTask.Occurrence,
+			strings.Join(newTask.Reminders, ","),
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := replaceTaskBlockers(ctx, tx, id, newTask.BlockedBy); err != nil {
+			return err
+		}
+		if err := replaceTaskCollaborators(ctx, tx, id, newTask.Collaborators); err != nil {
+			return err
+		}
+
+		return replaceTaskTags(ctx, tx, id, newTask.Tags)
+	})
+}
+
+func replaceTaskTags(ctx context.Context, tx *sqlTx, taskID string, tags []string) error {
+	if _, err := tx.exec(ctx, `DELETE FROM task_tags WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+
+		if _, err := tx.exec(ctx, `INSERT INTO task_tags (task_id, tag) VALUES (?, ?)`, taskID, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func replaceTaskBlockers(ctx context.Context, tx *sqlTx, taskID string, blockerIDs []string) error {
+	if _, err := tx.exec(ctx, `DELETE FROM task_blockers WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+
+	for _, blockerID := range blockerIDs {
+		if _, err := tx.exec(ctx, `INSERT INTO task_blockers (task_id, blocker_id) VALUES (?, ?)`, taskID, blockerID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func replaceTaskCollaborators(ctx context.Context, tx *sqlTx, taskID string, collaborators []entities.Collaborator) error {
+	if _, err := tx.exec(ctx, `DELETE FROM task_collaborators WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+
+	for _, collaborator := range collaborators {
+		if _, err := tx.exec(ctx, `INSERT INTO task_collaborators (task_id, user_id, role) VALUES (?, ?, ?)`,
+			taskID, collaborator.UserID, collaborator.Role); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queryTasks runs a query selecting sqlTaskColumns and loads the tags,
+// blockers and collaborators of the returned tasks.
+func (tr *sqlTaskRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*entities.Task, error) {
+	rows, err := tr.database.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*entities.Task
+	byID := map[string]*entities.Task{}
+
+	for rows.Next() {
+		var (
+			task        entities.Task
+			id          string
+			priority    int
+			completedAt sql.NullTime
+			deletedAt   sql.NullTime
+			reminders   string
+		)
+
+		err := rows.Scan(&id, &task.UserID, &task.Title, &task.Description, &task.Status, &priority,
+			&task.DueDate, &task.CreatedAt, &task.UpdatedAt, &completedAt, &task.Version, &deletedAt, &task.ParentID, &task.AssigneeID, &task.ProjectID,
+			&task.Recurrence, &task.SeriesID, &task.Occurrence, &reminders)
+		if err != nil {
+			return nil, err
+		}
+
+		task.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		task.Priority = entities.Priority(priority)
+		task.DueDate = task.DueDate.UTC()
+		task.CreatedAt = task.CreatedAt.UTC()
+		task.UpdatedAt = task.UpdatedAt.UTC()
+		task.CompletedAt = timePointer(completedAt)
+		task.DeletedAt = timePointer(deletedAt)
+		if reminders != "" {
+			task.Reminders = strings.Split(reminders, ",")
+		}
+
+		tasks = append(tasks, &task)
+		byID[id] = &task
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	placeholders := make([]string, 0, len(tasks))
+	ids := make([]interface{}, 0, len(tasks))
+	for id := range byID {
+		placeholders = append(placeholders, "?")
+		ids = append(ids, id)
+	}
+
+	tagRows, err := tr.database.query(ctx,
+		`SELECT task_id, tag FROM task_tags WHERE task_id IN (`+strings.Join(placeholders, ", ")+`) ORDER BY tag`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+
+	for tagRows.Next() {
+		var taskID, tag string
+		if err := tagRows.Scan(&taskID, &tag); err != nil {
+			return nil, err
+		}
+		byID[taskID].Tags = append(byID[taskID].Tags, tag)
+	}
+	if err := tagRows.Err(); err != nil {
+		return nil, err
+	}
+
+	blockerRows, err := tr.database.query(ctx,
+		`SELECT task_id, blocker_id FROM task_blockers WHERE task_id IN (`+strings.Join(placeholders, ", ")+`) ORDER BY blocker_id`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer blockerRows.Close()
+
+	for blockerRows.Next() {
+		var taskID, blockerID string
+		if err := blockerRows.Scan(&taskID, &blockerID); err != nil {
+			return nil, err
+		}
+		byID[taskID].BlockedBy = append(byID[taskID].BlockedBy, blockerID)
+	}
+	if err := blockerRows.Err(); err != nil {
+		return nil, err
+	}
+
+	collaboratorRows, err := tr.database.query(ctx,
+		`SELECT task_id, user_id, role FROM task_collaborators WHERE task_id IN (`+strings.Join(placeholders, ", ")+`) ORDER BY user_id`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer collaboratorRows.Close()
+
+	for collaboratorRows.Next() {
+		var (
+			taskID       string
+			collaborator entities.Collaborator
+		)
+		if err := collaboratorRows.Scan(&taskID, &collaborator.UserID, &collaborator.Role); err != nil {
+			return nil, err
+		}
+		byID[taskID].Collaborators = append(byID[taskID].Collaborators, collaborator)
+	}
+
+	return tasks, collaboratorRows.Err()
+}
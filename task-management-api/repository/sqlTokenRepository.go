@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"task-management-api/domain/entities"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sqlRefreshTokenRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLRefreshTokenRepository(database *SQLDatabase) entities.RefreshTokenRepository {
+	return &sqlRefreshTokenRepository{
+		database: database,
+	}
+}
+
+const sqlRefreshTokenColumns = `id, user_id, token_hash, family_id, access_token_id, access_token_expires_at, expires_at, created_at, revoked_at`
+
+func (rr *sqlRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token entities.RefreshToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	_, err := rr.database.exec(ctx, `INSERT INTO refresh_tokens (`+sqlRefreshTokenColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		token.ID.Hex(),
+		token.UserID,
+		token.TokenHash,
+		token.FamilyID,
+		token.AccessTokenID,
+		sqlTime(token.AccessTokenExpiresAt),
+		sqlTime(token.ExpiresAt),
+		sqlTime(token.CreatedAt),
+		sqlNullTime(token.RevokedAt),
+	)
+
+	return err
+}
+
+func (rr *sqlRefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	tokens, err := rr.find(ctx, `SELECT `+sqlRefreshTokenColumns+` FROM refresh_tokens WHERE token_hash = ?`, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, sqlNotFound(sql.ErrNoRows, errRefreshTokenNotFound)
+	}
+
+	return tokens[0], nil
+}
+
+func (rr *sqlRefreshTokenRepository) GetRefreshTokenFamily(ctx context.Context, familyID string) ([]*entities.RefreshToken, error) {
+	return rr.find(ctx, `SELECT `+sqlRefreshTokenColumns+` FROM refresh_tokens WHERE family_id = ? ORDER BY created_at`, familyID)
+}
+
+func (rr *sqlRefreshTokenRepository) GetActiveRefreshTokens(ctx context.Context, userID string) ([]*entities.RefreshToken, error) {
+	return rr.find(ctx, `SELECT `+sqlRefreshTokenColumns+` FROM refresh_tokens
+		WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ? ORDER BY created_at`, userID, sqlTime(time.Now()))
+}
+
+// RevokeRefreshToken marks a single token as revoked and reports whether this
+// call revoked it, so concurrent rotations of the same token can be detected.
+func (rr *sqlRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, id string) (bool, error) {
+	result, err := rr.database.exec(ctx, `UPDATE refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		sqlTime(time.Now()), id)
+	if err != nil {
+		return false, err
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return revoked == 1, nil
+}
+
+func (rr *sqlRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	_, err := rr.database.exec(ctx, `UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`,
+		sqlTime(time.Now()), familyID)
+
+	return err
+}
+
+func (rr *sqlRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	_, err := rr.database.exec(ctx, `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`,
+		sqlTime(time.Now()), userID)
+
+	return err
+}
+
+func (rr *sqlRefreshTokenRepository) find(ctx context.Context, query string, args ...interface{}) ([]*entities.RefreshToken, error) {
+	rows, err := rr.database.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*entities.RefreshToken
+	for rows.Next() {
+		var (
+			token     entities.RefreshToken
+			id        string
+			revokedAt sql.NullTime
+		)
+
+		err := rows.Scan(&id, &token.UserID, &token.TokenHash, &token.FamilyID, &token.AccessTokenID,
+			&token.AccessTokenExpiresAt, &token.ExpiresAt, &token.CreatedAt, &revokedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		token.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		token.RevokedAt = timePointer(revokedAt)
+
+		tokens = append(tokens, &token)
+	}
+
+	return tokens, rows.Err()
+}
+
+type sqlRevokedTokenRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLRevokedTokenRepository(database *SQLDatabase) entities.RevokedTokenRepository {
+	return &sqlRevokedTokenRepository{
+		database: database,
+	}
+}
+
+func (rr *sqlRevokedTokenRepository) RevokeToken(ctx context.Context, token entities.RevokedToken) error {
+	_, err := rr.database.exec(ctx, `INSERT INTO revoked_tokens (id, user_id, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, expires_at = excluded.expires_at`,
+		token.ID, token.UserID, sqlTime(token.ExpiresAt))
+
+	return err
+}
+
+func (rr *sqlRevokedTokenRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var id string
+	err := rr.database.queryRow(ctx, `SELECT id FROM revoked_tokens WHERE id = ?`, tokenID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sqlUserRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLUserRepository(database *SQLDatabase) entities.UserRepository {
+	return &sqlUserRepository{
+		database: database,
+	}
+}
+
+const sqlUserColumns = `id, username, password, email, name, bio, role`
+
+func (ur *sqlUserRepository) GetUser(ctx context.Context, param string) ([]*entities.User, error) {
+	pattern := likePattern(param)
+
+	return ur.queryUsers(ctx, `SELECT `+sqlUserColumns+` FROM users
+		WHERE LOWER(username) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'
+		ORDER BY username`, pattern, pattern)
+}
+
+func (ur *sqlUserRepository) GetUserByID(ctx context.Context, id string) (*entities.User, error) {
+	return ur.queryUser(ctx, `SELECT `+sqlUserColumns+` FROM users WHERE id = ?`, id)
+}
+
+func (ur *sqlUserRepository) GetUserByUsername(ctx context.Context, username string) (*entities.User, error) {
+	return ur.queryUser(ctx, `SELECT `+sqlUserColumns+` FROM users WHERE username = ?`, username)
+}
+
+func (ur *sqlUserRepository) UpdateUser(ctx context.Context, id string, updatedUser entities.User, fields []string) error {
+	set := []string{"updated_at = ?"}
+	args := []interface{}{sqlTime(time.Now())}
+
+	for _, field := range fields {
+		var value string
+		switch field {
+		case entities.UserFieldUsername:
+			value = updatedUser.UserName
+		case entities.UserFieldPassword:
+			value = updatedUser.Password
+		case entities.UserFieldEmail:
+			value = updatedUser.Email
+		case entities.UserFieldName:
+			value = updatedUser.Name
+		case entities.UserFieldBio:
+			value = updatedUser.Bio
+		case entities.UserFieldRole:
+			value = updatedUser.Role
+		default:
+			return fmt.Errorf("unknown user field %q", field)
+		}
+
+		// The user field names are also the column names.
+		set = append(set, field+" = ?")
+		args = append(args, value)
+	}
+	args = append(args, id)
+
+	result, err := ur.database.exec(ctx, `UPDATE users SET `+strings.Join(set, ", ")+` WHERE id = ?`, args...)
+	if err != nil {
+		return sqlUniqueViolation(err, entities.ErrUsernameTaken)
+	}
+
+	return sqlAffected(result, errUserNotFound)
+}
+
+func (ur *sqlUserRepository) UpdatePassword(ctx context.Context, id string, hashedPassword string) error {
+	result, err := ur.database.exec(ctx, `UPDATE users SET password = ?, updated_at = ? WHERE id = ?`,
+		hashedPassword, sqlTime(time.Now()), id)
+	if err != nil {
+		return err
+	}
+
+	return sqlAffected(result, errUserNotFound)
+}
+
+func (ur *sqlUserRepository) DeleteUser(ctx context.Context, id string) error {
+	result, err := ur.database.exec(ctx, `DELETE FROM users WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	return sqlAffected(result, errUserNotFound)
+}
+
+func (ur *sqlUserRepository) CreateUser(ctx context.Context, newUser model.UserCreate) (*model.UserInfo, error) {
+	if newUser.ID.IsZero() {
+		newUser.ID = primitive.NewObjectID()
+	}
+
+	now := sqlTime(time.Now())
+	_, err := ur.database.exec(ctx, `INSERT INTO users (id, username, password, email, name, bio, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		newUser.ID.Hex(),
+		newUser.Username,
+		newUser.Password,
+		newUser.Email,
+		newUser.Name,
+		newUser.Bio,
+		newUser.Role,
+		now,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create user: %w", sqlUniqueViolation(err, entities.ErrUsernameTaken))
+	}
+
+	return &model.UserInfo{
+		ID:       newUser.ID.Hex(),
+		Username: newUser.Username,
+		Email:    newUser.Email,
+		Name:     newUser.Name,
+	}, nil
+}
+
+func (ur *sqlUserRepository) queryUser(ctx context.Context, query string, args ...interface{}) (*entities.User, error) {
+	var (
+		user entities.User
+		id   string
+	)
+
+	err := ur.database.queryRow(ctx, query, args...).Scan(&id, &user.UserName, &user.Password, &user.Email, &user.Name, &user.Bio, &user.Role)
+	if err != nil {
+		return nil, sqlNotFound(err, errUserNotFound)
+	}
+
+	user.ID, err = primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (ur *sqlUserRepository) queryUsers(ctx context.Context, query string, args ...interface{}) ([]*entities.User, error) {
+	rows, err := ur.database.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		var (
+			user entities.User
+			id   string
+		)
+
+		if err := rows.Scan(&id, &user.UserName, &user.Password, &user.Email, &user.Name, &user.Bio, &user.Role); err != nil {
+			return nil, err
+		}
+
+		user.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
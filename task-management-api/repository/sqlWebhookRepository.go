@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sqlWebhookRepository struct {
+	database *SQLDatabase
+}
+
+func NewSQLWebhookRepository(database *SQLDatabase) entities.WebhookRepository {
+	return &sqlWebhookRepository{
+		database: database,
+	}
+}
+
+const (
+	sqlWebhookColumns  = `id, user_id, url, secret, created_at`
+	sqlDeliveryColumns = `id, webhook_id, event, payload, status, attempts, next_attempt_at, last_attempt_at, response_status, error, created_at`
+)
+
+func (wr *sqlWebhookRepository) GetWebhooks(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+	return wr.find(ctx, `SELECT `+sqlWebhookColumns+` FROM webhooks WHERE user_id = ? ORDER BY created_at, id`, userID)
+}
+
+func (wr *sqlWebhookRepository) GetEventWebhooks(ctx context.Context, event string, userIDs []string) ([]*entities.Webhook, error) {
+	if len(userIDs) == 0 {
+		return []*entities.Webhook{}, nil
+	}
+
+	args := []interface{}{event}
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+
+	return wr.find(ctx, `SELECT `+sqlWebhookColumns+` FROM webhooks
+		WHERE id IN (SELECT webhook_id FROM webhook_events WHERE event = ?)
+		AND user_id IN (`+strings.TrimSuffix(strings.Repeat("?, ", len(userIDs)), ", ")+`)
+		ORDER BY created_at, id`, args...)
+}
+
+func (wr *sqlWebhookRepository) GetWebhookByID(ctx context.Context, id string) (*entities.Webhook, error) {
+	webhooks, err := wr.find(ctx, `SELECT `+sqlWebhookColumns+` FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(webhooks) == 0 {
+		return nil, sqlNotFound(sql.ErrNoRows, entities.ErrWebhookNotFound)
+	}
+
+	return webhooks[0], nil
+}
+
+func (wr *sqlWebhookRepository) CreateWebhook(ctx context.Context, webhook entities.Webhook) error {
+	if webhook.ID.IsZero() {
+		webhook.ID = primitive.NewObjectID()
+	}
+	id := webhook.ID.Hex()
+
+	return wr.database.withTx(ctx, func(tx *sqlTx) error {
+		_, err := tx.exec(ctx, `INSERT INTO webhooks (`+sqlWebhookColumns+`) VALUES (?, ?, ?, ?, ?)`,
+			id, webhook.UserID, webhook.URL, webhook.Secret, sqlTime(webhook.CreatedAt))
+		if err != nil {
+			return err
+		}
+
+		for _, event := range webhook.Events {
+			if _, err := tx.exec(ctx, `INSERT INTO webhook_events (webhook_id, event) VALUES (?, ?)`, id, event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (wr *sqlWebhookRepository) DeleteWebhook(ctx context.Context, id string) error {
+	return wr.database.withTx(ctx, func(tx *sqlTx) error {
+		if _, err := tx.exec(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := tx.exec(ctx, `DELETE FROM webhook_events WHERE webhook_id = ?`, id); err != nil {
+			return err
+		}
+
+		result, err := tx.exec(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+
+		return sqlAffected(result, entities.ErrWebhookNotFound)
+	})
+}
+
+// find runs a query selecting sqlWebhookColumns and loads the events of the
+// returned webhooks.
+func (wr *sqlWebhookRepository) find(ctx context.Context, query string, args ...interface{}) ([]*entities.Webhook, error) {
+	rows, err := wr.database.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*entities.Webhook{}
+	byID := map[string]*entities.Webhook{}
+
+	for rows.Next() {
+		var (
+			webhook entities.Webhook
+			id      string
+		)
+
+		if err := rows.Scan(&id, &webhook.UserID, &webhook.URL, &webhook.Secret, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		webhook.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		webhook.CreatedAt = webhook.CreatedAt.UTC()
+
+		webhooks = append(webhooks, &webhook)
+		byID[id] = &webhook
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(webhooks) == 0 {
+		return webhooks, nil
+	}
+
+	placeholders := make([]string, 0, len(webhooks))
+	ids := make([]interface{}, 0, len(webhooks))
+	for id := range byID {
+		placeholders = append(placeholders, "?")
+		ids = append(ids, id)
+	}
+
+	eventRows, err := wr.database.query(ctx,
+		`SELECT webhook_id, event FROM webhook_events WHERE webhook_id IN (`+strings.Join(placeholders, ", ")+`) ORDER BY event`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer eventRows.Close()
+
+	for eventRows.Next() {
+		var webhookID, event string
+		if err := eventRows.Scan(&webhookID, &event); err != nil {
+			return nil, err
+		}
+		byID[webhookID].Events = append(byID[webhookID].Events, event)
+	}
+
+	return webhooks, eventRows.Err()
+}
+
+func (wr *sqlWebhookRepository) AddDeliveries(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	return wr.database.withTx(ctx, func(tx *sqlTx) error {
+		for _, delivery := range deliveries {
+			if delivery.ID.IsZero() {
+				delivery.ID = primitive.NewObjectID()
+			}
+
+			_, err := tx.exec(ctx, `INSERT INTO webhook_deliveries (`+sqlDeliveryColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				delivery.ID.Hex(), delivery.WebhookID, delivery.Event, delivery.Payload, delivery.Status, delivery.Attempts,
+				sqlTime(delivery.NextAttemptAt), sqlNullTime(delivery.LastAttemptAt), delivery.ResponseStatus, delivery.Error,
+				sqlTime(delivery.CreatedAt))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (wr *sqlWebhookRepository) GetDeliveries(ctx context.Context, webhookID string, query model.PageQuery) (*model.WebhookDeliveryPage, error) {
+	where, args := "webhook_id = ?", []interface{}{webhookID}
+	if query.Cursor != "" {
+		before, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		where += " AND id < ?"
+		args = append(args, before.Hex())
+	}
+
+	// One extra delivery is fetched to find out whether there is a next page.
+	limit := pageLimit(query)
+	args = append(args, limit+1)
+
+	deliveries, err := wr.findDeliveries(ctx, `SELECT `+sqlDeliveryColumns+` FROM webhook_deliveries WHERE `+where+` ORDER BY id DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.WebhookDeliveryPage{
+		Deliveries: []*model.WebhookDeliveryInfo{},
+	}
+	for _, delivery := range deliveries {
+		page.Deliveries = append(page.Deliveries, delivery.Info())
+	}
+
+	if len(page.Deliveries) > limit {
+		page.Deliveries = page.Deliveries[:limit]
+		page.Next = page.Deliveries[limit-1].ID
+	}
+
+	return page, nil
+}
+
+func (wr *sqlWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	now = sqlTime(now)
+	candidates, err := wr.findDeliveries(ctx,
+		`SELECT `+sqlDeliveryColumns+` FROM webhook_deliveries WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?`,
+		entities.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another dispatcher may claim a candidate between the query above and
+	// the update below. Claiming moves the next attempt past now, so the
+	// update only matches for the first one.
+	nextAttemptAt := now.Add(lease)
+	claimed := []*entities.WebhookDelivery{}
+	for _, delivery := range candidates {
+		result, err := wr.database.exec(ctx,
+			`UPDATE webhook_deliveries SET next_attempt_at = ?, attempts = attempts + 1 WHERE id = ? AND status = ? AND next_attempt_at <= ?`,
+			nextAttemptAt, delivery.ID.Hex(), entities.WebhookDeliveryPending, now)
+		if err != nil {
+			return nil, err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 1 {
+			delivery.NextAttemptAt = nextAttemptAt
+			delivery.Attempts++
+			claimed = append(claimed, delivery)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (wr *sqlWebhookRepository) UpdateDelivery(ctx context.Context, delivery entities.WebhookDelivery) error {
+	_, err := wr.database.exec(ctx,
+		`UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?, response_status = ?, error = ? WHERE id = ?`,
+		delivery.Status, delivery.Attempts, sqlTime(delivery.NextAttemptAt), sqlNullTime(delivery.LastAttemptAt), delivery.ResponseStatus,
+		delivery.Error, delivery.ID.Hex())
+	return err
+}
+
+// findDeliveries runs a query selecting sqlDeliveryColumns.
+func (wr *sqlWebhookRepository) findDeliveries(ctx context.Context, query string, args ...interface{}) ([]*entities.WebhookDelivery, error) {
+	rows, err := wr.database.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*entities.WebhookDelivery
+	for rows.Next() {
+		var (
+			delivery      entities.WebhookDelivery
+			id            string
+			lastAttemptAt sql.NullTime
+		)
+
+		if err := rows.Scan(&id, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status, &delivery.Attempts,
+			&delivery.NextAttemptAt, &lastAttemptAt, &delivery.ResponseStatus, &delivery.Error, &delivery.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		delivery.ID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		delivery.NextAttemptAt = delivery.NextAttemptAt.UTC()
+		delivery.LastAttemptAt = timePointer(lastAttemptAt)
+		delivery.CreatedAt = delivery.CreatedAt.UTC()
+
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
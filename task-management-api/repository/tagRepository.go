@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"task-management-api/mongo"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tagRepository reads and changes the tags stored on the documents of the
+// task collection.
+type tagRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewTagRepository(database mongo.Database, collection string) entities.TagRepository {
+	return &tagRepository{
+		database:   database,
+		collection: collection,
+	}
+}
+
+func (tr *tagRepository) GetTags(ctx context.Context, userID string) ([]*model.TagInfo, error) {
+	pipeline := []bson.D{
+		{{Key: "$match", Value: bson.M{"userid": userID, "deletedAt": nil}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := tr.database.Collection(tr.collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tags := []*model.TagInfo{}
+	for cursor.Next(ctx) {
+		var tag model.TagInfo
+		if err := cursor.Decode(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+
+	return tags, nil
+}
+
+// RenameTag maps the tags array in an update pipeline, so each task is
+// renamed in a single write. Tasks carrying both tags end up with the new one
+// once, in the place it already had.
+func (tr *tagRepository) RenameTag(ctx context.Context, userID string, name string, newName string) ([]*entities.Task, error) {
+	oldTag, newTag := bson.M{"$literal": name}, bson.M{"$literal": newName}
+
+	return tr.updateTaggedTasks(ctx, userID, name, []bson.D{
+		{{Key: "$set", Value: bson.M{
+			"tags": bson.M{"$cond": bson.A{
+				bson.M{"$in": bson.A{newTag, "$tags"}},
+				bson.M{"$filter": bson.M{"input": "$tags", "cond": bson.M{"$ne": bson.A{"$$this", oldTag}}}},
+				bson.M{"$map": bson.M{"input": "$tags", "in": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$$this", oldTag}}, newTag, "$$this"}}}},
+			}},
+			"updatedAt": time.Now(),
+			"version":   bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", int64(0)}}, int64(1)}},
+		}}},
+	})
+}
+
+func (tr *tagRepository) DeleteTag(ctx context.Context, userID string, name string) ([]*entities.Task, error) {
+	return tr.updateTaggedTasks(ctx, userID, name, bson.M{
+		"$pull": bson.M{"tags": name},
+		"$set":  bson.M{"updatedAt": time.Now()},
+		"$inc":  bson.M{"version": int64(1)},
+	})
+}
+
+// updateTaggedTasks reads the user's tasks carrying tag and applies update to
+// them. Tasks tagged after the read are left alone, so that the tasks
+// returned are the ones changed.
+func (tr *tagRepository) updateTaggedTasks(ctx context.Context, userID string, tag string, update interface{}) ([]*entities.Task, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	cursor, err := collection.Find(ctx, bson.M{"userid": userID, "tags": tag})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []*entities.Task
+	for cursor.Next(ctx) {
+		var task entities.Task
+		if err := cursor.Decode(&task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	if _, err := collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "tags": tag}, update); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// taskCursor records the sort value and id of the last task on a page. Tasks
+// are ordered by the sort key and then by id, so the pair identifies exactly
+// where the next page starts.
+type taskCursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func encodeTaskCursor(sortKey string, task *entities.Task) string {
+	cursor := taskCursor{
+		Value: taskSortValue(sortKey, task),
+		ID:    task.ID.Hex(),
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTaskCursor(token string) (*taskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, entities.ErrInvalidTaskCursor
+	}
+
+	var cursor taskCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, entities.ErrInvalidTaskCursor
+	}
+
+	return &cursor, nil
+}
+
+func taskSortValue(sortKey string, task *entities.Task) string {
+	switch sortKey {
+	case entities.TaskSortDueDate:
+		return task.DueDate.UTC().Format(time.RFC3339Nano)
+	case entities.TaskSortPriority:
+		return strconv.Itoa(int(task.Priority))
+	default:
+		return task.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// parseTaskSortValue converts a cursor value back into the type stored in the
+// sort field.
+func parseTaskSortValue(sortKey string, value string) (interface{}, error) {
+	switch sortKey {
+	case entities.TaskSortPriority:
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, entities.ErrInvalidTaskCursor
+		}
+		return entities.Priority(priority), nil
+	default:
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, entities.ErrInvalidTaskCursor
+		}
+		return parsed, nil
+	}
+}
+
+var taskSortFields = map[string]string{
+	entities.TaskSortDueDate:   "dueDate",
+	entities.TaskSortPriority:  "priority",
+	entities.TaskSortCreatedAt: "createdAt",
+}
+
+// taskListFilter builds the Mongo filter for one page of a user's tasks,
+// including the position given by the query's cursor. Tasks in the trash are
+// listed only when deleted is set, and then exclusively and only to their
+// owner; other tasks are also listed to their collaborators and assignee, or
+// to anyone when listing the tasks of a project.
+func taskListFilter(userID string, query model.TaskQuery, sortKey string, descending bool, deleted bool) (bson.M, error) {
+	filter := bson.M{
+		"deletedAt": nil,
+	}
+	var and []bson.M
+	switch {
+	case deleted:
+		filter["userid"] = userID
+		filter["deletedAt"] = bson.M{"$ne": nil}
+	case query.ProjectID != "":
+		filter["projectId"] = query.ProjectID
+	default:
+		and = append(and, bson.M{"$or": []bson.M{
+			{"userid": userID},
+			{"collaborators.userId": userID},
+			{"assigneeId": userID},
+		}})
+	}
+
+	if query.AssigneeID != "" {
+		filter["assigneeId"] = query.AssigneeID
+	}
+
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+
+	if query.Priority != "" {
+		priority, err := entities.ParsePriority(query.Priority)
+		if err != nil {
+			return nil, err
+		}
+		filter["priority"] = priority
+	}
+
+	switch {
+	case len(query.Tags) == 1:
+		filter["tags"] = query.Tags[0]
+	case len(query.Tags) > 1 && query.TagMatch == entities.TagMatchAll:
+		filter["tags"] = bson.M{"$all": query.Tags}
+	case len(query.Tags) > 1:
+		filter["tags"] = bson.M{"$in": query.Tags}
+	}
+
+	if query.DueBefore != nil || query.DueAfter != nil {
+		// Tasks without a due date are stored with the zero time and never
+		// match a due date range.
+		dueDate := bson.M{"$gt": time.Time{}}
+		if query.DueAfter != nil {
+			dueDate["$gt"] = *query.DueAfter
+		}
+		if query.DueBefore != nil {
+			dueDate["$lt"] = *query.DueBefore
+		}
+		filter["dueDate"] = dueDate
+	}
+
+	if query.Text != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query.Text), Options: "i"}
+		and = append(and, bson.M{"$or": []bson.M{
+			{"title": pattern},
+			{"description": pattern},
+		}})
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeTaskCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := parseTaskSortValue(sortKey, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := primitive.ObjectIDFromHex(cursor.ID)
+		if err != nil {
+			return nil, entities.ErrInvalidTaskCursor
+		}
+
+		operator := "$gt"
+		if descending {
+			operator = "$lt"
+		}
+
+		field := taskSortFields[sortKey]
+		and = append(and, bson.M{"$or": []bson.M{
+			{field: bson.M{operator: value}},
+			{field: value, "_id": bson.M{operator: id}},
+		}})
+	}
+
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+
+	return filter, nil
+}
+
+func taskListSort(sortKey string, descending bool) bson.D {
+	direction := 1
+	if descending {
+		direction = -1
+	}
+
+	return bson.D{
+		{Key: taskSortFields[sortKey], Value: direction},
+		{Key: "_id", Value: direction},
+	}
+}
@@ -2,18 +2,25 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/model"
 	"task-management-api/mongo"
-
-	"log"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var errTaskNotFound = entities.ErrTaskNotFound
+
+// errTaskNotInTrash is returned when restoring a task that is not in the
+// user's trash.
+var errTaskNotInTrash = apperrors.NotFound("task not found in trash")
+
 type taskRepository struct {
 	database   mongo.Database
 	collection string
@@ -26,42 +33,99 @@ func NewTaskRepository(database mongo.Database, collection string) entities.Task
 	}
 }
 
-func (tr *taskRepository) GetTasks(ctx context.Context, userID string) ([]*model.TaskInfo, error) {
-    var tasks []*model.TaskInfo
+func (tr *taskRepository) GetTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	return tr.listTasks(ctx, userID, query, false)
+}
 
-	filter := bson.M{
-		"userid": userID,
-	}	
+func (tr *taskRepository) GetDeletedTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	return tr.listTasks(ctx, userID, query, true)
+}
+
+// listTasks returns one page of the user's tasks, either those they own in the
+// trash or all others they own, collaborate on or are assigned, or one page of
+// a project's tasks.
+func (tr *taskRepository) listTasks(ctx context.Context, userID string, query model.TaskQuery, deleted bool) (*model.TaskPage, error) {
+	sortKey, descending, err := entities.ParseTaskSort(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := taskListFilter(userID, query, sortKey, descending, deleted)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = model.DefaultTaskPageSize
+	}
 
-    cursor, err := tr.database.Collection(tr.collection).Find(ctx, filter)
+	// One extra task is fetched to find out whether there is a next page.
+	findOptions := options.Find().
+		SetSort(taskListSort(sortKey, descending)).
+		SetLimit(int64(limit + 1))
+
+    cursor, err := tr.database.Collection(tr.collection).Find(ctx, filter, findOptions)
     if err != nil {
         return nil, err
     }
     defer cursor.Close(ctx)
 
+	var tasks []*entities.Task
     for cursor.Next(ctx) {
         var task entities.Task
         if err := cursor.Decode(&task); err != nil {
             return nil, err
         }
-        tasks = append(tasks, &model.TaskInfo{
-            Title:       task.Title,
-            Description: task.Description,
-            DueDate:     (time.Now()).Add(3 * 24 * time.Hour).Format(time.RFC3339),
-        })
+        tasks = append(tasks, &task)
     }
 
-    return tasks, nil
+	page := &model.TaskPage{
+		Tasks: []*model.TaskInfo{},
+	}
+
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		page.Next = encodeTaskCursor(sortKey, tasks[limit-1])
+	}
+
+	for _, task := range tasks {
+		page.Tasks = append(page.Tasks, task.Info())
+	}
+
+    return page, nil
 }
 
+// CreateTaskIndexes creates the compound indexes used to list a user's tasks
+// in each supported sort order and by the filters on GET /task/, the ones used
+// to find shared, assigned and project tasks, subtasks and the occurrences of
+// a series, and the one used to purge the trash.
+func CreateTaskIndexes(ctx context.Context, database mongo.Database, collection string) error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "dueDate", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "priority", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "tags", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "parentId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "collaborators.userId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "assigneeId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "projectId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "seriesId", Value: 1}, {Key: "occurrence", Value: 1}}},
+		{Keys: bson.D{{Key: "deletedAt", Value: 1}}},
+	}
+
+	_, err := database.Collection(collection).CreateIndexes(ctx, indexes)
+	return err
+}
 
-func (tr *taskRepository) GetTaskByID(ctx context.Context, id string, userID string) (*entities.Task, error) {
+func (tr *taskRepository) GetTaskByID(ctx context.Context, id string) (*entities.Task, error) {
 	objectID, _ := primitive.ObjectIDFromHex(id)
 
 	filter := bson.M{
 		"$and": []bson.M{
 			{"_id": objectID},
-			{"userid": userID},
+			{"deletedAt": nil},
 		},
 	}
 
@@ -69,33 +133,82 @@ func (tr *taskRepository) GetTaskByID(ctx context.Context, id string, userID str
 
     err := tr.database.Collection(tr.collection).FindOne(ctx, filter).Decode(&task)
     if err != nil {
-		log.Println(err)
-        if err == mongo.ErrNoDocuments {
-            return nil, err
+        if errors.Is(err, mongo.ErrNoDocuments) {
+            return nil, errTaskNotFound.Wrap(err)
         }
         return nil, err
     }
 
     return &task, nil
 }
-func (tr *taskRepository) UpdateTask(ctx context.Context, id string, updatedTask entities.Task, userID string) error {
+
+func (tr *taskRepository) GetTaskIncludingDeleted(ctx context.Context, id string) (*entities.Task, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	var task entities.Task
+	err := tr.database.Collection(tr.collection).FindOne(ctx, bson.M{"_id": objectID}).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errTaskNotFound.Wrap(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (tr *taskRepository) UpdateTask(ctx context.Context, id string, updatedTask entities.Task, fields []string) error {
     objectID, err := primitive.ObjectIDFromHex(id)
     if err != nil {
-        log.Fatal(err)
-        return err
+        return errTaskNotFound.Wrap(err)
     }
 
     filter := bson.M{
-        "_id":    objectID,
-        "userid": userID,
+        "_id":       objectID,
+        "version":   versionFilter(updatedTask.Version),
+        "deletedAt": nil,
     }
 
+	set := bson.M{}
+	for _, field := range fields {
+		switch field {
+		case entities.TaskFieldTitle:
+			set["title"] = updatedTask.Title
+		case entities.TaskFieldDescription:
+			set["description"] = updatedTask.Description
+		case entities.TaskFieldStatus:
+			set["status"] = updatedTask.Status
+			set["completedAt"] = updatedTask.CompletedAt
+		case entities.TaskFieldPriority:
+			set["priority"] = updatedTask.Priority
+		case entities.TaskFieldTags:
+			set["tags"] = updatedTask.Tags
+		case entities.TaskFieldDueDate:
+			set["dueDate"] = updatedTask.DueDate
+		case entities.TaskFieldParentID:
+			set["parentId"] = updatedTask.ParentID
+		case entities.TaskFieldBlockedBy:
+			set["blockedBy"] = updatedTask.BlockedBy
+		case entities.TaskFieldCollaborators:
+			set["collaborators"] = updatedTask.Collaborators
+		case entities.TaskFieldAssigneeID:
+			set["assigneeId"] = updatedTask.AssigneeID
+		case entities.TaskFieldProjectID:
+			set["projectId"] = updatedTask.ProjectID
+		case entities.TaskFieldRecurrence:
+			set["recurrence"] = updatedTask.Recurrence
+			set["seriesId"] = updatedTask.SeriesID
+			set["occurrence"] = updatedTask.Occurrence
+		case entities.TaskFieldReminders:
+			set["reminders"] = updatedTask.Reminders
+		default:
+			return fmt.Errorf("unknown task field %q", field)
+		}
+	}
+
 	update := bson.M{
-		"$set": bson.M{
-			"title": updatedTask.Title,
-			"description": updatedTask.Description,
-			"status": updatedTask.Status,
-		},
+		"$set": set,
+		"$inc": bson.M{"version": int64(1)},
 	}
 
     result, err := tr.database.Collection(tr.collection).UpdateOne(ctx, filter, update)
@@ -104,46 +217,181 @@ func (tr *taskRepository) UpdateTask(ctx context.Context, id string, updatedTask
 		return fmt.Errorf("update failed: %w", err)
 	}
 
-	if result.ModifiedCount == 0 {
-		return fmt.Errorf("no documents updated")
+	if result.MatchedCount == 0 {
+		return tr.unmatchedTaskError(ctx, objectID)
 	}
 	
 
     return nil
 }
 
-func (tr *taskRepository) DeleteTask(ctx context.Context, id string, userID string) error{
+func (tr *taskRepository) DeleteTask(ctx context.Context, id string, version int64) error{
 	objectID, err := primitive.ObjectIDFromHex(id)
     if err != nil {
-        log.Fatal(err)
-        return err
+        return errTaskNotFound.Wrap(err)
     }
 
 	filter := bson.M{
-		"$and": []bson.M{
-			{"_id": objectID},
-			{"userid": userID},
-		},
+		"_id":       objectID,
+		"deletedAt": nil,
 	}
-	
-	numDeleted, err := tr.database.Collection(tr.collection).DeleteMany(ctx, filter)
+	if version != entities.AnyTaskVersion {
+		filter["version"] = versionFilter(version)
+	}
+
+	update := bson.M{
+		"$set": bson.M{"deletedAt": time.Now()},
+		"$inc": bson.M{"version": int64(1)},
+	}
+
+	result, err := tr.database.Collection(tr.collection).UpdateOne(ctx, filter, update)
 	if err != nil {
-		log.Fatal(err)
 		return err
 	}
 
-	if numDeleted == 0 {
-		return fmt.Errorf("no documents deleted")
+	if result.MatchedCount == 0 {
+		return tr.unmatchedTaskError(ctx, objectID)
+	}
+
+	return nil
+}
+
+func (tr *taskRepository) RestoreTask(ctx context.Context, id string, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errTaskNotFound.Wrap(err)
+	}
+
+	filter := bson.M{
+		"_id":       objectID,
+		"userid":    userID,
+		"deletedAt": bson.M{"$ne": nil},
+	}
+	update := bson.M{
+		"$set": bson.M{"deletedAt": nil},
+		"$inc": bson.M{"version": int64(1)},
+	}
+
+	result, err := tr.database.Collection(tr.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errTaskNotInTrash
 	}
 
 	return nil
-	
+}
+
+// PurgeDeletedTasks unlinks the purged tasks from their subtasks and from the
+// tasks they block before deleting them.
+func (tr *taskRepository) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	collection := tr.database.Collection(tr.collection)
+	filter := bson.M{"deletedAt": bson.M{"$lt": deletedBefore}}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := bson.A{}
+	for cursor.Next(ctx) {
+		var task entities.Task
+		if err := cursor.Decode(&task); err != nil {
+			return 0, err
+		}
+		ids = append(ids, task.ID.Hex())
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if _, err := collection.UpdateMany(ctx, bson.M{"parentId": bson.M{"$in": ids}}, bson.M{"$set": bson.M{"parentId": ""}}); err != nil {
+		return 0, err
+	}
+	if _, err := collection.UpdateMany(ctx, bson.M{"blockedBy": bson.M{"$in": ids}}, bson.M{"$pull": bson.M{"blockedBy": bson.M{"$in": ids}}}); err != nil {
+		return 0, err
+	}
+
+	return collection.DeleteMany(ctx, filter)
+}
+
+func (tr *taskRepository) GetSubtasks(ctx context.Context, parentID string) ([]*entities.Task, error) {
+	filter := bson.M{"parentId": parentID, "deletedAt": nil}
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := tr.database.Collection(tr.collection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []*entities.Task{}
+	for cursor.Next(ctx) {
+		var task entities.Task
+		if err := cursor.Decode(&task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+
+	return tasks, nil
+}
+
+func (tr *taskRepository) GetSeriesTasks(ctx context.Context, seriesID string) ([]*entities.Task, error) {
+	filter := bson.M{"seriesId": seriesID, "deletedAt": nil}
+	findOptions := options.Find().SetSort(bson.D{{Key: "occurrence", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := tr.database.Collection(tr.collection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []*entities.Task{}
+	for cursor.Next(ctx) {
+		var task entities.Task
+		if err := cursor.Decode(&task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+
+	return tasks, nil
+}
+
+// unmatchedTaskError tells why a conditional write matched no task: either the
+// task is missing or its version moved on.
+func (tr *taskRepository) unmatchedTaskError(ctx context.Context, objectID primitive.ObjectID) error {
+	count, err := tr.database.Collection(tr.collection).CountDocuments(ctx, bson.M{"_id": objectID, "deletedAt": nil})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return errTaskNotFound
+	}
+
+	return entities.ErrTaskModified
+}
+
+// taskVersionFilter matches a task version. Tasks stored before versions were
+// introduced have no version field and are at version 0.
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{nil, int64(0)}}
+	}
+
+	return version
 }
 
 func (tr *taskRepository) CreateTask(ctx context.Context, newTask entities.Task) error {
-	_, err := tr.database.Collection(tr.collection).InsertOne(ctx, newTask)
+	newTask.Version = 1
+
+	_, err := tr.database.Collection(tr.collection).InsertOne(ctx, &newTask)
 	if err != nil {
-		log.Fatal(err)
 		return err
 	}
 	return nil
@@ -3,62 +3,190 @@ package repository_test
 import (
 	"context"
 	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
 	"task-management-api/mongo/mocks"
 	"task-management-api/repository"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 
 func TestGetTasks(t *testing.T) {
-    mockCursor := new(mocks.Cursor)
+    t.Run("first page", func(t *testing.T) {
+        mockCursor := new(mocks.Cursor)
+        mockCollection := new(mocks.Collection)
+        mockDatabase := new(mocks.Database)
+
+        tr := repository.NewTaskRepository(mockDatabase, "tasks")
+
+        ctx := context.TODO()
+        userID := "test-user-id"
+
+        task1 := entities.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", CreatedAt: time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)}
+        task2 := entities.Task{ID: primitive.NewObjectID(), Title: "Task 2", Description: "Description 2", CreatedAt: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)}
+
+        // Tasks shared with the user are listed along with their own.
+        expectedFilter := bson.M{"deletedAt": nil, "$and": []bson.M{
+            {"$or": []bson.M{{"userid": userID}, {"collaborators.userId": userID}, {"assigneeId": userID}}},
+        }}
+        expectedOptions := options.Find().
+            SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+            SetLimit(2)
+
+        mockDatabase.On("Collection", "tasks").Return(mockCollection)
+        mockCollection.On("Find", ctx, expectedFilter, expectedOptions).Return(mockCursor, nil)
+
+        mockCursor.On("Next", ctx).Return(true).Once()
+        mockCursor.On("Decode", mock.Anything).Run(func(args mock.Arguments) {
+            arg := args.Get(0).(*entities.Task)
+            *arg = task1 
+        }).Return(nil).Once()
+
+        mockCursor.On("Next", ctx).Return(true).Once() 
+        mockCursor.On("Decode", mock.Anything).Run(func(args mock.Arguments) {
+            arg := args.Get(0).(*entities.Task)
+            *arg = task2 
+        }).Return(nil).Once()
+
+        mockCursor.On("Next", ctx).Return(false).Once() 
+        mockCursor.On("Close", ctx).Return(nil) 
+
+        result, err := tr.GetTasks(ctx, userID, model.TaskQuery{Limit: 1})
+
+        assert.NoError(t, err)
+        assert.Len(t, result.Tasks, 1)
+        assert.Equal(t, "Task 1", result.Tasks[0].Title)
+        assert.Equal(t, "Description 1", result.Tasks[0].Description)
+        assert.NotEmpty(t, result.Next)
+
+        mockCollection.AssertExpectations(t)
+        mockCursor.AssertExpectations(t)
+
+        t.Run("next page", func(t *testing.T) {
+            mockCursor := new(mocks.Cursor)
+            mockCollection := new(mocks.Collection)
+            mockDatabase := new(mocks.Database)
+
+            tr := repository.NewTaskRepository(mockDatabase, "tasks")
+
+            expectedFilter := bson.M{
+                "deletedAt": nil,
+                "$and": []bson.M{
+                    {"$or": []bson.M{{"userid": userID}, {"collaborators.userId": userID}, {"assigneeId": userID}}},
+                    {"$or": []bson.M{
+                        {"createdAt": bson.M{"$lt": task1.CreatedAt}},
+                        {"createdAt": task1.CreatedAt, "_id": bson.M{"$lt": task1.ID}},
+                    }},
+                },
+            }
+
+            mockDatabase.On("Collection", "tasks").Return(mockCollection)
+            mockCollection.On("Find", ctx, expectedFilter, expectedOptions).Return(mockCursor, nil)
+
+            mockCursor.On("Next", ctx).Return(true).Once()
+            mockCursor.On("Decode", mock.Anything).Run(func(args mock.Arguments) {
+                arg := args.Get(0).(*entities.Task)
+                *arg = task2
+            }).Return(nil).Once()
+            mockCursor.On("Next", ctx).Return(false).Once()
+            mockCursor.On("Close", ctx).Return(nil)
+
+            page, err := tr.GetTasks(ctx, userID, model.TaskQuery{Limit: 1, Cursor: result.Next})
+
+            assert.NoError(t, err)
+            assert.Len(t, page.Tasks, 1)
+            assert.Equal(t, "Task 2", page.Tasks[0].Title)
+            assert.Empty(t, page.Next)
+
+            mockCollection.AssertExpectations(t)
+        })
+    })
+
+    t.Run("filters and sort", func(t *testing.T) {
+        mockCursor := new(mocks.Cursor)
+        mockCollection := new(mocks.Collection)
+        mockDatabase := new(mocks.Database)
+
+        tr := repository.NewTaskRepository(mockDatabase, "tasks")
+
+        ctx := context.TODO()
+        userID := "test-user-id"
+        dueBefore := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+        query := model.TaskQuery{
+            Status:    entities.StatusTodo,
+            Priority:  "high",
+            Tags:      []string{"work"},
+            DueBefore: &dueBefore,
+            Text:      "a.b",
+            Sort:      "due_date",
+            Limit:     10,
+        }
+
+        expectedFilter := bson.M{
+            "deletedAt": nil,
+            "status":   entities.StatusTodo,
+            "priority": entities.PriorityHigh,
+            "tags":     "work",
+            "dueDate":  bson.M{"$gt": time.Time{}, "$lt": dueBefore},
+            "$and": []bson.M{
+                {"$or": []bson.M{{"userid": userID}, {"collaborators.userId": userID}, {"assigneeId": userID}}},
+                {"$or": []bson.M{
+                    {"title": primitive.Regex{Pattern: `a\.b`, Options: "i"}},
+                    {"description": primitive.Regex{Pattern: `a\.b`, Options: "i"}},
+                }},
+            },
+        }
+        expectedOptions := options.Find().
+            SetSort(bson.D{{Key: "dueDate", Value: 1}, {Key: "_id", Value: 1}}).
+            SetLimit(11)
+
+        mockDatabase.On("Collection", "tasks").Return(mockCollection)
+        mockCollection.On("Find", ctx, expectedFilter, expectedOptions).Return(mockCursor, nil)
+        mockCursor.On("Next", ctx).Return(false).Once()
+        mockCursor.On("Close", ctx).Return(nil)
+
+        page, err := tr.GetTasks(ctx, userID, query)
+
+        assert.NoError(t, err)
+        assert.Empty(t, page.Tasks)
+        assert.Empty(t, page.Next)
+
+        mockCollection.AssertExpectations(t)
+    })
+
+    t.Run("invalid cursor", func(t *testing.T) {
+        mockDatabase := new(mocks.Database)
+        tr := repository.NewTaskRepository(mockDatabase, "tasks")
+
+        page, err := tr.GetTasks(context.TODO(), "test-user-id", model.TaskQuery{Cursor: "not a cursor"})
+
+        assert.Nil(t, page)
+        assert.ErrorIs(t, err, entities.ErrInvalidTaskCursor)
+        mockDatabase.AssertNotCalled(t, "Collection", mock.Anything)
+    })
+}
+
+func TestCreateTaskIndexes(t *testing.T) {
     mockCollection := new(mocks.Collection)
     mockDatabase := new(mocks.Database)
 
-    tr := repository.NewTaskRepository(mockDatabase, "tasks")
-
     ctx := context.TODO()
-    userID := "test-user-id"
-
-    task1 := entities.Task{Title: "Task 1", Description: "Description 1"}
-    task2 := entities.Task{Title: "Task 2", Description: "Description 2"}
-
-    expectedFilter := bson.M{"userid": userID}
 
     mockDatabase.On("Collection", "tasks").Return(mockCollection)
-    mockCollection.On("Find", ctx, expectedFilter).Return(mockCursor, nil)
-
-    mockCursor.On("Next", ctx).Return(true).Once()
-    mockCursor.On("Decode", mock.Anything).Run(func(args mock.Arguments) {
-        arg := args.Get(0).(*entities.Task)
-        *arg = task1 
-    }).Return(nil).Once()
+    mockCollection.On("CreateIndexes", ctx, mock.AnythingOfType("[]mongo.IndexModel")).Return([]string{}, nil)
 
-    mockCursor.On("Next", ctx).Return(true).Once() 
-    mockCursor.On("Decode", mock.Anything).Run(func(args mock.Arguments) {
-        arg := args.Get(0).(*entities.Task)
-        *arg = task2 
-    }).Return(nil).Once()
-
-    mockCursor.On("Next", ctx).Return(false).Once() 
-    mockCursor.On("Close", ctx).Return(nil) 
-
-    result, err := tr.GetTasks(ctx, userID)
+    err := repository.CreateTaskIndexes(ctx, mockDatabase, "tasks")
 
     assert.NoError(t, err)
-    assert.Len(t, result, 2)
-    assert.Equal(t, "Task 1", result[0].Title)
-    assert.Equal(t, "Description 1", result[0].Description)
-    assert.Equal(t, "Task 2", result[1].Title)
-    assert.Equal(t, "Description 2", result[1].Description)
-
     mockCollection.AssertExpectations(t)
-    mockCursor.AssertExpectations(t)
 }
 
 func TestGetTaskByID(t *testing.T) {
@@ -69,13 +197,12 @@ func TestGetTaskByID(t *testing.T) {
     
     ctx := context.TODO()
     taskID := "60c72b2f9b1d4c3d88b8e5e6"
-    userID := "12345"
     
     objectID, _ := primitive.ObjectIDFromHex(taskID)
     
     task := entities.Task{Title: "Task 1", Description: "Description 1"}
     
-    expectedFilter := bson.M{"$and": []bson.M{{"_id": objectID}, {"userid": userID}}}
+    expectedFilter := bson.M{"$and": []bson.M{{"_id": objectID}, {"deletedAt": nil}}}
     
     mockDatabase.On("Collection", "tasks").Return(mockCollection)
     
@@ -87,7 +214,7 @@ func TestGetTaskByID(t *testing.T) {
         *arg = task
     }).Return(nil)
     
-    result, err := tr.GetTaskByID(ctx, taskID, userID)
+    result, err := tr.GetTaskByID(ctx, taskID)
     
     assert.NoError(t, err)
     assert.Equal(t, "Task 1", result.Title)
@@ -128,15 +255,22 @@ func TestUpdateTask(t *testing.T) {
 
     ctx := context.TODO()
     taskID := "60c72b2f9b1d4c3d88b8e5e6"
-    userID := "12345"
 
-    task := entities.Task{Title: "Task 1", Status:"Done", Description: "Description 1"}
+    dueDate := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+    task := entities.Task{Title: "Task 1", Status:"done", Description: "Description 1", Priority: entities.PriorityHigh, DueDate: dueDate}
 
     objectID, _ := primitive.ObjectIDFromHex(taskID)
 
-    expectedFilter := bson.M{"_id": objectID, "userid": userID}
+    expectedFilter := bson.M{"_id": objectID, "version": int64(3), "deletedAt": nil}
+    task.Version = 3
 
-    expectedUpdate := bson.M{"$set": bson.M{"title": task.Title, "status": task.Status, "description": task.Description}}
+    // Only the listed fields are written.
+    expectedUpdate := bson.M{"$inc": bson.M{"version": int64(1)}, "$set": bson.M{
+        "title": task.Title,
+        "status": task.Status,
+        "dueDate": task.DueDate,
+        "completedAt": task.CompletedAt,
+    }}
 
     mockDatabase.On("Collection", "tasks").Return(mockCollection)
 
@@ -147,7 +281,8 @@ func TestUpdateTask(t *testing.T) {
 
     mockCollection.On("UpdateOne", ctx, expectedFilter, expectedUpdate).Return(mockUpdateResult, nil).Once()
 
-    err := tr.UpdateTask(ctx, taskID, task, userID)
+    fields := []string{entities.TaskFieldTitle, entities.TaskFieldStatus, entities.TaskFieldDueDate}
+    err := tr.UpdateTask(ctx, taskID, task, fields)
 
     assert.NoError(t, err)
 
@@ -162,23 +297,62 @@ func TestDeleteTask(t *testing.T){
 
     ctx := context.TODO()
     taskID := "60c72b2f9b1d4c3d88b8e5e6"
-    userID := "12345"
 
     objectID, _ := primitive.ObjectIDFromHex(taskID)
 
-    expectedFilter := primitive.M{"$and": []primitive.M{
-        {"_id": objectID},
-        {"userid": userID},
-    }}
+    expectedFilter := bson.M{"_id": objectID, "deletedAt": nil, "version": int64(3)}
+
+    // Deleting moves the task to the trash.
+    isSoftDelete := mock.MatchedBy(func(update bson.M) bool {
+        deletedAt, ok := update["$set"].(bson.M)["deletedAt"].(time.Time)
+        return ok && !deletedAt.IsZero() && update["$inc"].(bson.M)["version"] == int64(1)
+    })
 
     mockDatabase.On("Collection", "tasks").Return(mockCollection)
+    mockCollection.On("UpdateOne", ctx, expectedFilter, isSoftDelete).Return(&mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil)
+
+    err := tr.DeleteTask(ctx, taskID, 3)
+
+    assert.NoError(t, err)
+
+    mockCollection.AssertExpectations(t)
+}
 
-    mockDeleteCount := int64(1)
-    mockCollection.On("DeleteMany", ctx, expectedFilter).Return(mockDeleteCount, nil)
+func TestPurgeDeletedTasks(t *testing.T) {
+    mockCollection := new(mocks.Collection)
+    mockDatabase := new(mocks.Database)
+    mockCursor := new(mocks.Cursor)
 
-    err := tr.DeleteTask(ctx, taskID, userID)
+    tr := repository.NewTaskRepository(mockDatabase, "tasks")
+
+    ctx := context.TODO()
+    deletedBefore := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+    filter := bson.M{"deletedAt": bson.M{"$lt": deletedBefore}}
+    purgedID := primitive.NewObjectID()
+    purgedIDs := bson.A{purgedID.Hex()}
+
+    mockDatabase.On("Collection", "tasks").Return(mockCollection)
+    mockCollection.On("Find", ctx, filter).Return(mockCursor, nil)
+    mockCursor.On("Next", ctx).Return(true).Once()
+    mockCursor.On("Decode", mock.Anything).Run(func(args mock.Arguments) {
+        arg := args.Get(0).(*entities.Task)
+        arg.ID = purgedID
+    }).Return(nil).Once()
+    mockCursor.On("Next", ctx).Return(false).Once()
+    mockCursor.On("Close", ctx).Return(nil)
+
+    // The purged tasks are unlinked from the tasks that remain.
+    mockCollection.On("UpdateMany", ctx, bson.M{"parentId": bson.M{"$in": purgedIDs}},
+        bson.M{"$set": bson.M{"parentId": ""}}).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+    mockCollection.On("UpdateMany", ctx, bson.M{"blockedBy": bson.M{"$in": purgedIDs}},
+        bson.M{"$pull": bson.M{"blockedBy": bson.M{"$in": purgedIDs}}}).Return(&mongo.UpdateResult{}, nil)
+    mockCollection.On("DeleteMany", ctx, filter).Return(int64(1), nil)
+
+    purged, err := tr.PurgeDeletedTasks(ctx, deletedBefore)
 
     assert.NoError(t, err)
+    assert.Equal(t, int64(1), purged)
 
     mockCollection.AssertExpectations(t)
-}
\ No newline at end of file
+    mockCursor.AssertExpectations(t)
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"task-management-api/logging"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/model"
 
@@ -10,8 +13,11 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var errUserNotFound = apperrors.NotFound("user not found")
+
 type userRepository struct {
 	database   mongo.Database
 	collection string
@@ -56,7 +62,7 @@ func (ur *userRepository) GetUserByID(ctx context.Context, id string) (*entities
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, err
+		return nil, errUserNotFound.Wrap(err)
 	}
 
 	filter := bson.M{
@@ -67,30 +73,83 @@ func (ur *userRepository) GetUserByID(ctx context.Context, id string) (*entities
 
 	var user entities.User
 	if err := result.Decode(&user); err != nil {
-		return nil, err
+		return nil, userNotFound(err)
 	}
 
 	return &user, nil
 }
 
-func (ur *userRepository) UpdateUser(ctx context.Context, id string, updatedUser entities.User) error {
+func (ur *userRepository) UpdateUser(ctx context.Context, id string, updatedUser entities.User, fields []string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errUserNotFound.Wrap(err)
+	}
+
+	filter := bson.M{
+		"_id": objectID,
+	}
+
+	set := bson.M{}
+	for _, field := range fields {
+		switch field {
+		case entities.UserFieldUsername:
+			set["username"] = updatedUser.UserName
+		case entities.UserFieldPassword:
+			set["password"] = updatedUser.Password
+		case entities.UserFieldEmail:
+			set["email"] = updatedUser.Email
+		case entities.UserFieldName:
+			set["name"] = updatedUser.Name
+		case entities.UserFieldBio:
+			set["bio"] = updatedUser.Bio
+		case entities.UserFieldRole:
+			set["role"] = updatedUser.Role
+		default:
+			return fmt.Errorf("unknown user field %q", field)
+		}
+	}
+
+	update := bson.M{
+		"$set": set,
+	}
+
+	result, err := ur.database.Collection(ur.collection).UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return entities.ErrUsernameTaken.Wrap(err)
+	}
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return errUserNotFound
+	}
+
+	return nil
+}
+
+func (ur *userRepository) UpdatePassword(ctx context.Context, id string, hashedPassword string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errUserNotFound.Wrap(err)
+	}
 
 	filter := bson.M{
 		"_id": objectID,
 	}
 
 	update := bson.M{
-		"$set": updatedUser,
+		"$set": bson.M{
+			"password": hashedPassword,
+		},
 	}
 
-	_, err = ur.database.Collection(ur.collection).UpdateOne(ctx, filter, update)
+	result, err := ur.database.Collection(ur.collection).UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return errUserNotFound
+	}
 
 	return nil
 }
@@ -98,16 +157,19 @@ func (ur *userRepository) UpdateUser(ctx context.Context, id string, updatedUser
 func (ur *userRepository) DeleteUser(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return errUserNotFound.Wrap(err)
 	}
 
 	filter := bson.M{
 		"_id": objectID,
 	}
-	_, err = ur.database.Collection(ur.collection).DeleteOne(ctx, filter)
+	deleted, err := ur.database.Collection(ur.collection).DeleteOne(ctx, filter)
 	if err != nil {
 		return err
 	}
+	if deleted == 0 {
+		return errUserNotFound
+	}
 
 	return nil
 }
@@ -121,7 +183,7 @@ func (ur *userRepository) GetUserByUsername(ctx context.Context, username string
 	
 	var user entities.User
 	if err := result.Decode(&user); err != nil {
-		return nil, err
+		return nil, userNotFound(err)
 	}
 
 	return &user, nil
@@ -129,11 +191,31 @@ func (ur *userRepository) GetUserByUsername(ctx context.Context, username string
 
 func (ur *userRepository) CreateUser(ctx context.Context, newUser model.UserCreate) (*model.UserInfo, error) {
 	_, err := ur.database.Collection(ur.collection).InsertOne(ctx, &newUser)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, entities.ErrUsernameTaken.Wrap(err)
+	}
 	if err != nil {
 		return nil, err
 	}
-	log.Println("Inside repository")
-	log.Println(err)
+	logging.FromContext(ctx).DebugContext(ctx, "user created", "user_id", newUser.ID.Hex())
 	return nil, nil
 }
 
+
+// userNotFound turns the driver's missing document error into a domain error.
+func userNotFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errUserNotFound.Wrap(err)
+	}
+
+	return err
+}
+
+// CreateUserIndexes creates the unique index on usernames, which login,
+// sharing and project invitations look users up by.
+func CreateUserIndexes(ctx context.Context, database mongo.Database, collection string) error {
+	_, err := database.Collection(collection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	return err
+}
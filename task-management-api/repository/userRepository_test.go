@@ -131,7 +131,10 @@ func TestUpdateUser(t *testing.T)  {
 	}
 
 	update := bson.M{
-		"$set": updatedUser,
+		"$set": bson.M{
+			"username": "newUsername",
+			"password": "1234",
+		},
 	}
 
 	mockDatabase.On("Collection", "user").Return(mockCollection)
@@ -143,7 +146,7 @@ func TestUpdateUser(t *testing.T)  {
 
     mockCollection.On("UpdateOne", ctx, filter, update).Return(mockUpdateResult, nil).Once()
 
-    err := ur.UpdateUser(ctx, userID, updatedUser)
+    err := ur.UpdateUser(ctx, userID, updatedUser, []string{entities.UserFieldUsername, entities.UserFieldPassword})
 
     assert.NoError(t, err)
 
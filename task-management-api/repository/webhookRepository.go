@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"task-management-api/mongo"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type webhookRepository struct {
+	database           mongo.Database
+	collection         string
+	deliveryCollection string
+}
+
+// NewWebhookRepository stores webhooks in collection and their deliveries in
+// deliveryCollection.
+func NewWebhookRepository(database mongo.Database, collection string, deliveryCollection string) entities.WebhookRepository {
+	return &webhookRepository{
+		database:           database,
+		collection:         collection,
+		deliveryCollection: deliveryCollection,
+	}
+}
+
+func (wr *webhookRepository) GetWebhooks(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+	return wr.find(ctx, bson.M{"userId": userID})
+}
+
+func (wr *webhookRepository) GetEventWebhooks(ctx context.Context, event string, userIDs []string) ([]*entities.Webhook, error) {
+	if len(userIDs) == 0 {
+		return []*entities.Webhook{}, nil
+	}
+
+	return wr.find(ctx, bson.M{"events": event, "userId": bson.M{"$in": userIDs}})
+}
+
+func (wr *webhookRepository) find(ctx context.Context, filter bson.M) ([]*entities.Webhook, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := wr.database.Collection(wr.collection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []*entities.Webhook{}
+	for cursor.Next(ctx) {
+		var webhook entities.Webhook
+		if err := cursor.Decode(&webhook); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (wr *webhookRepository) GetWebhookByID(ctx context.Context, id string) (*entities.Webhook, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, entities.ErrWebhookNotFound.Wrap(err)
+	}
+
+	var webhook entities.Webhook
+	err = wr.database.Collection(wr.collection).FindOne(ctx, bson.M{"_id": objectID}).Decode(&webhook)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, entities.ErrWebhookNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (wr *webhookRepository) CreateWebhook(ctx context.Context, webhook entities.Webhook) error {
+	_, err := wr.database.Collection(wr.collection).InsertOne(ctx, &webhook)
+	return err
+}
+
+func (wr *webhookRepository) DeleteWebhook(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.ErrWebhookNotFound.Wrap(err)
+	}
+
+	count, err := wr.database.Collection(wr.collection).DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return entities.ErrWebhookNotFound
+	}
+
+	_, err = wr.database.Collection(wr.deliveryCollection).DeleteMany(ctx, bson.M{"webhookId": id})
+	return err
+}
+
+func (wr *webhookRepository) AddDeliveries(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	collection := wr.database.Collection(wr.deliveryCollection)
+	for _, delivery := range deliveries {
+		if _, err := collection.InsertOne(ctx, &delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (wr *webhookRepository) GetDeliveries(ctx context.Context, webhookID string, query model.PageQuery) (*model.WebhookDeliveryPage, error) {
+	filter := bson.M{"webhookId": webhookID}
+	if query.Cursor != "" {
+		before, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$lt": before}
+	}
+
+	// One extra delivery is fetched to find out whether there is a next page.
+	limit := pageLimit(query)
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limit + 1))
+
+	cursor, err := wr.database.Collection(wr.deliveryCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	page := &model.WebhookDeliveryPage{
+		Deliveries: []*model.WebhookDeliveryInfo{},
+	}
+
+	for cursor.Next(ctx) {
+		var delivery entities.WebhookDelivery
+		if err := cursor.Decode(&delivery); err != nil {
+			return nil, err
+		}
+		page.Deliveries = append(page.Deliveries, delivery.Info())
+	}
+
+	if len(page.Deliveries) > limit {
+		page.Deliveries = page.Deliveries[:limit]
+		page.Next = page.Deliveries[limit-1].ID
+	}
+
+	return page, nil
+}
+
+func (wr *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	collection := wr.database.Collection(wr.deliveryCollection)
+	due := bson.M{
+		"status":        entities.WebhookDeliveryPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, due, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var candidates []*entities.WebhookDelivery
+	for cursor.Next(ctx) {
+		var delivery entities.WebhookDelivery
+		if err := cursor.Decode(&delivery); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, &delivery)
+	}
+
+	// Another dispatcher may claim a candidate between the query above and
+	// the update below. Claiming moves the next attempt past now, so the
+	// update only matches for the first one.
+	nextAttemptAt := now.Add(lease)
+	claimed := []*entities.WebhookDelivery{}
+	for _, delivery := range candidates {
+		filter := bson.M{"_id": delivery.ID}
+		for key, value := range due {
+			filter[key] = value
+		}
+		update := bson.M{
+			"$set": bson.M{"nextAttemptAt": nextAttemptAt},
+			"$inc": bson.M{"attempts": 1},
+		}
+
+		result, err := collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 1 {
+			delivery.NextAttemptAt = nextAttemptAt
+			delivery.Attempts++
+			claimed = append(claimed, delivery)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (wr *webhookRepository) UpdateDelivery(ctx context.Context, delivery entities.WebhookDelivery) error {
+	update := bson.M{"$set": bson.M{
+		"status":         delivery.Status,
+		"attempts":       delivery.Attempts,
+		"nextAttemptAt":  delivery.NextAttemptAt,
+		"lastAttemptAt":  delivery.LastAttemptAt,
+		"responseStatus": delivery.ResponseStatus,
+		"error":          delivery.Error,
+	}}
+
+	_, err := wr.database.Collection(wr.deliveryCollection).UpdateOne(ctx, bson.M{"_id": delivery.ID}, update)
+	return err
+}
+
+// CreateWebhookIndexes creates the indexes used to find the webhooks of a
+// user and of an event, and the deliveries that are due and those of a
+// webhook.
+func CreateWebhookIndexes(ctx context.Context, database mongo.Database, collection string, deliveryCollection string) error {
+	_, err := database.Collection(collection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "events", Value: 1}, {Key: "userId", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Collection(deliveryCollection).CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+		{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
@@ -1,62 +1,192 @@
 package router
 
 import (
+	"log"
 	"task-management-api/config"
 	"task-management-api/controller"
+	"task-management-api/domain/entities"
+	"task-management-api/metrics"
 	"task-management-api/middleware"
+	"task-management-api/notify"
+	"task-management-api/ratelimit"
 	"task-management-api/repository"
 	"task-management-api/usecase"
 	"task-management-api/utils"
 	"time"
 
-	"task-management-api/mongo"
-
 	"github.com/gin-gonic/gin"
 )
 
-func NewAuthRouter(environment *config.Environment, timeout time.Duration, db mongo.Database, r *gin.RouterGroup) {
-	userRepository := repository.NewUserRepository(db, "user")
+func newPasswordHasher(environment *config.Environment) utils.PasswordHasher {
+	passwordHasher, err := utils.NewPasswordHasher((*environment).GetPasswordHasher(), (*environment).GetBcryptCost())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return passwordHasher
+}
+
+// loginFailureRetention is how long failed logins count towards a lockout.
+const loginFailureRetention = time.Hour
+
+func newAuthUsecase(environment *config.Environment, repositories *repository.Repositories) entities.AuthUseCase {
 	tokenUtil := utils.NewTokenUtil(environment)
-	authUsecase :=  usecase.NewAuthUseCase(userRepository, tokenUtil)
+	return usecase.NewAuthUseCase(repositories.Users, repositories.RefreshTokens, repositories.RevokedTokens, tokenUtil, newPasswordHasher(environment), metrics.Default)
+}
+
+func NewAuthRouter(environment *config.Environment, timeout time.Duration, repositories *repository.Repositories, r *gin.RouterGroup, authMiddleware gin.HandlerFunc, loginAttempts entities.LoginAttemptStore) {
+	authUsecase := usecase.NewLoginLockout(newAuthUsecase(environment, repositories), loginAttempts, usecase.DefaultLoginLockoutPolicy)
 	authController := controller.NewAuthController(authUsecase)
 
 	r.POST("/register", authController.Register)
 	r.POST("/login", authController.Login)
+	r.POST("/refresh", authController.Refresh)
+	r.POST("/logout", authMiddleware, authController.Logout)
+	r.POST("/logout-all", authMiddleware, authController.LogoutAll)
 }
 
-func taskRouter(environment *config.Environment, timeout time.Duration, db mongo.Database, r *gin.RouterGroup) {
-	taskRepository := repository.NewTaskRepository(db, "task")
-	taskUseCase := usecase.NewTaskUsecase(taskRepository)
+func adminRouter(environment *config.Environment, timeout time.Duration, repositories *repository.Repositories, r *gin.RouterGroup) {
+	authController := controller.NewAuthController(newAuthUsecase(environment, repositories))
+
+	r.POST("/users", authController.AdminRegister)
+}
+
+func taskRouter(environment *config.Environment, timeout time.Duration, repositories *repository.Repositories, r *gin.RouterGroup) {
+	taskUseCase := usecase.NewTaskUsecase(repositories.Tasks, repositories.Activity, repositories.Users, repositories.Projects, repositories.Reminders, repositories.Webhooks)
 	taskController := controller.NewTaskController(*environment, taskUseCase)
+	commentController := controller.NewCommentController(usecase.NewCommentUsecase(repositories.Comments, repositories.Tasks, repositories.Projects))
 
 	r.GET("/", taskController.GetTasks)
 	r.POST("/", taskController.CreateTask)
+	r.GET("/trash", taskController.GetTrash)
+	r.GET("/assigned", taskController.GetAssignedTasks)
+	r.GET("/series/:seriesId", taskController.GetTaskSeries)
+	r.PATCH("/series/:seriesId", taskController.UpdateTaskSeries)
+	r.POST("/series/:seriesId/stop", taskController.StopTaskSeries)
 	r.GET("/:id", taskController.GetTaskByID)
 	r.PATCH("/:id", taskController.UpdateTask)
 	r.DELETE("/:id", taskController.DeleteTask)
+	r.POST("/:id/restore", taskController.RestoreTask)
+	r.PUT("/:id/parent/:parentId", taskController.SetTaskParent)
+	r.DELETE("/:id/parent", taskController.RemoveTaskParent)
+	r.PUT("/:id/blocked_by/:blockerId", taskController.AddTaskBlocker)
+	r.DELETE("/:id/blocked_by/:blockerId", taskController.RemoveTaskBlocker)
+	r.PUT("/:id/collaborators/:username", taskController.ShareTask)
+	r.DELETE("/:id/collaborators/:username", taskController.UnshareTask)
+	r.PUT("/:id/assignee/:username", taskController.SetTaskAssignee)
+	r.DELETE("/:id/assignee", taskController.RemoveTaskAssignee)
+	r.GET("/:id/activity", taskController.GetTaskActivity)
+	r.GET("/:id/comments", commentController.GetComments)
+	r.POST("/:id/comments", commentController.CreateComment)
+	r.PATCH("/:id/comments/:commentId", commentController.UpdateComment)
+	r.DELETE("/:id/comments/:commentId", commentController.DeleteComment)
 }
 
+// projectRouter checks the role of the user in the project in the path of
+// every route taking one, see middleware.RequireProjectRole.
+func projectRouter(environment *config.Environment, timeout time.Duration, repositories *repository.Repositories, r *gin.RouterGroup) {
+	taskUseCase := usecase.NewTaskUsecase(repositories.Tasks, repositories.Activity, repositories.Users, repositories.Projects, repositories.Reminders, repositories.Webhooks)
+	projectController := controller.NewProjectController(usecase.NewProjectUsecase(repositories.Projects, repositories.Users), taskUseCase)
 
-func userRouter(environment *config.Environment, timeout time.Duration, db mongo.Database, r *gin.RouterGroup) {
+	viewer := middleware.RequireProjectRole(repositories.Projects, entities.ProjectRoleViewer)
+	editor := middleware.RequireProjectRole(repositories.Projects, entities.ProjectRoleEditor)
+	owner := middleware.RequireProjectRole(repositories.Projects, entities.ProjectRoleOwner)
+
+	r.GET("/", projectController.GetProjects)
+	r.POST("/", projectController.CreateProject)
+	r.GET("/:id", viewer, projectController.GetProjectByID)
+	r.PATCH("/:id", owner, projectController.RenameProject)
+	r.POST("/:id/archive", owner, projectController.ArchiveProject)
+	r.POST("/:id/unarchive", owner, projectController.UnarchiveProject)
+	r.PUT("/:id/members/:username", owner, projectController.AddProjectMember)
+	r.DELETE("/:id/members/:username", owner, projectController.RemoveProjectMember)
+	r.GET("/:id/tasks", viewer, projectController.GetProjectTasks)
+	r.POST("/:id/tasks", editor, projectController.CreateProjectTask)
+	r.PUT("/:id/tasks/:taskId", editor, projectController.MoveTask)
+	r.DELETE("/:id/tasks/:taskId", editor, projectController.RemoveProjectTask)
+}
+
+func tagRouter(environment *config.Environment, timeout time.Duration, repositories *repository.Repositories, r *gin.RouterGroup) {
+	tagController := controller.NewTagController(usecase.NewTagUsecase(repositories.Tags, repositories.Activity, repositories.Projects, repositories.Webhooks))
+
+	r.GET("/", tagController.GetTags)
+	r.PATCH("/:name", tagController.RenameTag)
+	r.DELETE("/:name", tagController.DeleteTag)
+}
 
-	userRepository := repository.NewUserRepository(db, "user")
-	userUseCase := usecase.NewUserUsecase(userRepository)
+func webhookRouter(environment *config.Environment, timeout time.Duration, repositories *repository.Repositories, r *gin.RouterGroup) {
+	webhookController := controller.NewWebhookController(usecase.NewWebhookUsecase(repositories.Webhooks, notify.NewWebhookSender(nil)))
+
+	r.GET("/", webhookController.GetWebhooks)
+	r.POST("/", webhookController.CreateWebhook)
+	r.GET("/:id", webhookController.GetWebhookByID)
+	r.DELETE("/:id", webhookController.DeleteWebhook)
+	r.GET("/:id/deliveries", webhookController.GetDeliveries)
+	r.POST("/:id/test", webhookController.TestWebhook)
+}
+
+func userRouter(environment *config.Environment, timeout time.Duration, repositories *repository.Repositories, r *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+
+	userUseCase := usecase.NewUserUsecase(repositories.Users, newPasswordHasher(environment))
 	userController := controller.NewUserController(*environment, userUseCase)
 
 	r.GET("/", userController.GetUsers)
 	r.GET("/:id", userController.GetUserByID)
-	r.PATCH("/:id", userController.UpdateUser).Use(middleware.AuthMiddleware())
-	r.DELETE("/:id", userController.DeleteUser).Use(middleware.AuthMiddleware())
+	r.PATCH("/:id", authMiddleware, userController.UpdateUser)
+	r.DELETE("/:id", authMiddleware, middleware.RequireRole(entities.RoleAdmin), userController.DeleteUser)
 }
 
-func NewRouter(environment config.Environment, timeout time.Duration, db mongo.Database, r *gin.Engine) {
+func NewRouter(environment config.Environment, timeout time.Duration, repositories *repository.Repositories, r *gin.Engine) error {
+	// gin trusts X-Forwarded-For from any peer by default, which would let
+	// clients pick the IP address they are rate limited by.
+	if err := r.SetTrustedProxies(environment.GetTrustedProxies()); err != nil {
+		return err
+	}
+
+	r.Use(middleware.RequestID(), middleware.RequestLogger(), middleware.Metrics(metrics.Default))
+	r.Use(middleware.ErrorHandler())
+	r.Use(middleware.CORS(environment.GetCorsAllowedOrigins()))
+
+	healthController := controller.NewHealthController(repositories)
+	r.GET("/healthz", healthController.Liveness)
+	r.GET("/readyz", healthController.Readiness)
+	r.GET("/metrics", gin.WrapH(metrics.Default.Handler()))
+
+	authMiddleware := middleware.AuthMiddleware([]byte(environment.GetJwtKey()), repositories.RevokedTokens)
+
+	// Rate limits and failed logins are tracked in memory, so every instance
+	// of the service enforces its own limits.
+	rateLimits := ratelimit.NewMemoryStore(nil)
+	loginAttempts := ratelimit.NewMemoryLoginAttemptStore(loginFailureRetention, nil)
+
 	authRouter := r.Group("/auth")
-	NewAuthRouter(&environment, timeout, db, authRouter)
+	authRouter.Use(middleware.RateLimit(rateLimits, "auth", environment.GetAuthRateLimit(), middleware.ByClientIP))
+	NewAuthRouter(&environment, timeout, repositories, authRouter, authMiddleware, loginAttempts)
 
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(authMiddleware, middleware.RequireRole(entities.RoleAdmin))
+	adminRouter(&environment, timeout, repositories, adminGroup)
+
+	// Tasks, projects, tags and webhooks share TASK_RATE_LIMIT, each with a
+	// bucket of its own so that heavy use of one does not lock out the others.
 	taskGroup := r.Group("/task")
-	taskGroup.Use(middleware.AuthMiddleware())
-	taskRouter(&environment, timeout, db, taskGroup)
+	taskGroup.Use(authMiddleware, middleware.RateLimit(rateLimits, "task", environment.GetTaskRateLimit(), middleware.ByUserID))
+	taskRouter(&environment, timeout, repositories, taskGroup)
+
+	projectGroup := r.Group("/projects")
+	projectGroup.Use(authMiddleware, middleware.RateLimit(rateLimits, "project", environment.GetTaskRateLimit(), middleware.ByUserID))
+	projectRouter(&environment, timeout, repositories, projectGroup)
+
+	tagGroup := r.Group("/tags")
+	tagGroup.Use(authMiddleware, middleware.RateLimit(rateLimits, "tag", environment.GetTaskRateLimit(), middleware.ByUserID))
+	tagRouter(&environment, timeout, repositories, tagGroup)
+
+	webhookGroup := r.Group("/webhooks")
+	webhookGroup.Use(authMiddleware, middleware.RateLimit(rateLimits, "webhook", environment.GetTaskRateLimit(), middleware.ByUserID))
+	webhookRouter(&environment, timeout, repositories, webhookGroup)
 
 	userGroup := r.Group("/")
-	userRouter(&environment, timeout, db, userGroup)
+	userRouter(&environment, timeout, repositories, userGroup, authMiddleware)
+
+	return nil
 }
@@ -0,0 +1,82 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-management-api/config"
+	"task-management-api/mongo"
+	"task-management-api/repository"
+	"task-management-api/router"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(t *testing.T, trustedProxies string) (*gin.Engine, *repository.Repositories) {
+	t.Setenv("JWT_KEY", "0123456789abcdef0123456789abcdef")
+	t.Setenv("DB_DRIVER", "memory")
+	t.Setenv("AUTH_RATE_LIMIT", "1/m")
+	t.Setenv("TRUSTED_PROXIES", trustedProxies)
+
+	env, err := config.Load(nil)
+	require.NoError(t, err)
+
+	repositories, err := repository.NewMongoRepositories(context.TODO(), mongo.NewMemoryClient().Database("test"))
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	require.NoError(t, router.NewRouter(env, 5*time.Second, repositories, engine))
+
+	return engine, repositories
+}
+
+// login posts to /auth/login from the peer 192.0.2.1, as httptest does, with
+// the given X-Forwarded-For header.
+func login(engine *gin.Engine, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w.Code
+}
+
+func TestAuthRateLimitClientIP(t *testing.T) {
+	t.Run("spoofed forwarded for", func(t *testing.T) {
+		engine, _ := newTestRouter(t, "")
+
+		assert.NotEqual(t, http.StatusTooManyRequests, login(engine, "203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, login(engine, "203.0.113.2"))
+	})
+
+	t.Run("trusted proxy", func(t *testing.T) {
+		engine, _ := newTestRouter(t, "192.0.2.0/24")
+
+		assert.NotEqual(t, http.StatusTooManyRequests, login(engine, "203.0.113.1"))
+		assert.NotEqual(t, http.StatusTooManyRequests, login(engine, "203.0.113.2"))
+		assert.Equal(t, http.StatusTooManyRequests, login(engine, "203.0.113.1"))
+	})
+}
+
+func TestRegisterStoresProfile(t *testing.T) {
+	engine, repositories := newTestRouter(t, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(
+		`{"username": "alice", "password": "correct horse battery", "email": "alice@example.com", "name": "Alice Liddell", "bio": "Curious"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	user, err := repositories.Users.GetUserByUsername(context.TODO(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Liddell", user.Name)
+	assert.Equal(t, "Curious", user.Bio)
+}
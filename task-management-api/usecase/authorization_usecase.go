@@ -3,72 +3,295 @@ package usecase
 import (
 	"context"
 	"errors"
+	"time"
 
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/model"
-	"task-management-api/mongo"
+	"task-management-api/logging"
 	"task-management-api/utils"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	errInvalidCredentials  = apperrors.Unauthorized("invalid username or password")
+	errInvalidRefreshToken = apperrors.Unauthorized("invalid refresh token")
+	errRefreshTokenReused  = apperrors.Unauthorized("refresh token reuse detected")
+	errRefreshTokenExpired = apperrors.Unauthorized("refresh token expired")
+	errInvalidUserData     = apperrors.Validation("invalid user data",
+		apperrors.FieldError{Field: "username", Message: "is required"},
+		apperrors.FieldError{Field: "password", Message: "is required"})
+	errInvalidRole   = apperrors.Validation("invalid role", apperrors.FieldError{Field: "role", Message: "must be user or admin"})
+	errAdminRequired = apperrors.Forbidden("admin role required")
+)
+
 type authUseCase struct {
-	userRepository entities.UserRepository
-	utils          utils.Utils
-    context        context.Context
+	userRepository         entities.UserRepository
+	refreshTokenRepository entities.RefreshTokenRepository
+	revokedTokenRepository entities.RevokedTokenRepository
+	utils                  utils.Utils
+	passwordHasher         utils.PasswordHasher
+	loginMetrics           entities.LoginMetrics
 }
 
-func NewAuthUseCase(userRepo entities.UserRepository, utils utils.Utils) entities.AuthUseCase {
+func NewAuthUseCase(userRepo entities.UserRepository, refreshTokenRepo entities.RefreshTokenRepository, revokedTokenRepo entities.RevokedTokenRepository, utils utils.Utils, passwordHasher utils.PasswordHasher, loginMetrics entities.LoginMetrics) entities.AuthUseCase {
 	return &authUseCase{
-		userRepository: userRepo,
-		utils: utils,
-        context:       context.TODO(),
+		userRepository:         userRepo,
+		refreshTokenRepository: refreshTokenRepo,
+		revokedTokenRepository: revokedTokenRepo,
+		utils:                  utils,
+		passwordHasher:         passwordHasher,
+		loginMetrics:           loginMetrics,
+	}
+}
+
+func (uc *authUseCase) Login(ctx context.Context, userLogin *model.UserLogin) (*entities.TokenPair, error) {
+	user, err := uc.userRepository.GetUserByUsername(ctx, userLogin.Username)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			uc.loginMetrics.RecordLogin(false)
+			return nil, errInvalidCredentials
+		}
+		return nil, err
+	}
+
+	valid, err := uc.passwordHasher.Verify(user.Password, userLogin.Password)
+	if err != nil || !valid {
+		uc.loginMetrics.RecordLogin(false)
+		return nil, errInvalidCredentials
+	}
+
+	if uc.passwordHasher.NeedsRehash(user.Password) {
+		uc.rehashPassword(ctx, user, userLogin.Password)
+	}
+
+	familyID, err := utils.GenerateOpaqueToken(16)
+	if err != nil {
+		return nil, errors.New("token Generation Failed")
+	}
+
+	tokens, err := uc.issueTokens(ctx, user, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.loginMetrics.RecordLogin(true)
+	return tokens, nil
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new
+// access/refresh pair from the same family is issued. Presenting a token that
+// was already rotated is treated as theft and revokes the whole family.
+func (uc *authUseCase) Refresh(ctx context.Context, refreshToken string) (*entities.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, errInvalidRefreshToken
+	}
+
+	token, err := uc.refreshTokenRepository.GetRefreshTokenByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if token.RevokedAt != nil {
+		uc.revokeFamily(ctx, token.FamilyID)
+		return nil, errRefreshTokenReused
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, errRefreshTokenExpired
+	}
+
+	revoked, err := uc.refreshTokenRepository.RevokeRefreshToken(ctx, token.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		uc.revokeFamily(ctx, token.FamilyID)
+		return nil, errRefreshTokenReused
+	}
+
+	// The access token issued alongside the rotated refresh token is replaced
+	// by the new one.
+	if err := uc.revokeAccessToken(ctx, token.UserID, token.AccessTokenID, token.AccessTokenExpiresAt); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepository.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return uc.issueTokens(ctx, user, token.FamilyID)
+}
+
+// Logout revokes the current access token and, when given, the session the
+// refresh token belongs to.
+func (uc *authUseCase) Logout(ctx context.Context, currUser entities.AuthenticatedUser, refreshToken string) error {
+	if err := uc.revokeAccessToken(ctx, currUser.UserID, currUser.TokenID, currUser.TokenExpiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	token, err := uc.refreshTokenRepository.GetRefreshTokenByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil || token.UserID != currUser.UserID {
+		return nil
+	}
+
+	return uc.revokeFamily(ctx, token.FamilyID)
+}
+
+// LogoutAll ends every session of the user: all refresh tokens are revoked
+// together with the access tokens that were issued alongside them.
+func (uc *authUseCase) LogoutAll(ctx context.Context, currUser entities.AuthenticatedUser) error {
+	if err := uc.revokeAccessToken(ctx, currUser.UserID, currUser.TokenID, currUser.TokenExpiresAt); err != nil {
+		return err
 	}
+
+	tokens, err := uc.refreshTokenRepository.GetActiveRefreshTokens(ctx, currUser.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if err := uc.revokeAccessToken(ctx, token.UserID, token.AccessTokenID, token.AccessTokenExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	return uc.refreshTokenRepository.RevokeUserRefreshTokens(ctx, currUser.UserID)
 }
 
-func (uc *authUseCase) Login(userLogin *model.UserLogin) (string, error) {
-	user, err := uc.userRepository.GetUserByUsername(uc.context, userLogin.Username)
+func (uc *authUseCase) issueTokens(ctx context.Context, user *entities.User, familyID string) (*entities.TokenPair, error) {
+	now := time.Now()
 
+	tokenID, err := utils.GenerateOpaqueToken(16)
 	if err != nil {
-		return "", errors.New("user Not Found")
+		return nil, errors.New("token Generation Failed")
 	}
-	if user.Password != userLogin.Password {
-		return "", errors.New("invalid Password")
+
+	authenticatedUser := entities.AuthenticatedUser{
+		UserID:         user.ID.Hex(),
+		Username:       user.UserName,
+		Role:           roleOrDefault(user.Role),
+		TokenID:        tokenID,
+		TokenExpiresAt: now.Add(AccessTokenTTL),
 	}
 
-	token, err := uc.utils.GenerateToken(user.ID.Hex())
+	accessToken, err := uc.utils.GenerateToken(authenticatedUser)
 	if err != nil {
-		return "", errors.New("token Generation Failed")
+		return nil, errors.New("token Generation Failed")
+	}
+
+	refreshToken, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, errors.New("token Generation Failed")
+	}
+
+	err = uc.refreshTokenRepository.CreateRefreshToken(ctx, entities.RefreshToken{
+		UserID:               authenticatedUser.UserID,
+		TokenHash:            utils.HashToken(refreshToken),
+		FamilyID:             familyID,
+		AccessTokenID:        tokenID,
+		AccessTokenExpiresAt: authenticatedUser.TokenExpiresAt,
+		ExpiresAt:            now.Add(RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, errors.New("token Generation Failed")
+	}
+
+	return &entities.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (uc *authUseCase) revokeFamily(ctx context.Context, familyID string) error {
+	tokens, err := uc.refreshTokenRepository.GetRefreshTokenFamily(ctx, familyID)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "revoking token family failed", "family_id", familyID, "error", err)
+		return err
+	}
+
+	for _, token := range tokens {
+		if err := uc.revokeAccessToken(ctx, token.UserID, token.AccessTokenID, token.AccessTokenExpiresAt); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "revoking token family failed", "family_id", familyID, "error", err)
+			return err
+		}
 	}
 
-	return token, nil
+	if err := uc.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "revoking token family failed", "family_id", familyID, "error", err)
+		return err
+	}
+
+	return nil
 }
 
-func (uc *authUseCase) Register(userCreate *model.UserCreate) (*model.UserInfo, error) {
+func (uc *authUseCase) revokeAccessToken(ctx context.Context, userID string, tokenID string, expiresAt time.Time) error {
+	if tokenID == "" || time.Now().After(expiresAt) {
+		return nil
+	}
+
+	return uc.revokedTokenRepository.RevokeToken(ctx, entities.RevokedToken{
+		ID:        tokenID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (uc *authUseCase) Register(ctx context.Context, userCreate *model.UserCreate) (*model.UserInfo, error) {
+	return uc.register(ctx, userCreate, entities.RoleUser)
+}
+
+func (uc *authUseCase) register(ctx context.Context, userCreate *model.UserCreate, role string) (*model.UserInfo, error) {
 	if userCreate == nil || userCreate.Username == "" || userCreate.Password == "" {
-		return nil, errors.New("invalid user data")
+		return nil, errInvalidUserData
 	}
 
-	existingUser, err := uc.userRepository.GetUserByUsername(uc.context, userCreate.Username)
+	existingUser, err := uc.userRepository.GetUserByUsername(ctx, userCreate.Username)
 	if err != nil {
-		if !errors.Is(err, mongo.ErrNoDocuments) {
+		if !errors.Is(err, apperrors.ErrNotFound) {
 			return nil, err
 		}
 	} else {
 		if existingUser != nil && existingUser.UserName != "" {
-			return nil, errors.New("username already exists")
+			return nil, entities.ErrUsernameTaken
 		}
 	}
 
+	hashedPassword, err := uc.passwordHasher.Hash(userCreate.Password)
+	if err != nil {
+		return nil, errors.New("user Creation Unseccssfull")
+	}
+
 	newUser := &model.UserCreate{
 		ID:       primitive.NewObjectID(),
 		Username: userCreate.Username,
-		Password: userCreate.Password,
+		Password: hashedPassword,
 		Email:    userCreate.Email,
+		Name:     userCreate.Name,
 		Bio:      userCreate.Bio,
+		Role:     role,
 	}
 
-	userInfo, err := uc.userRepository.CreateUser(uc.context, *newUser)
+	userInfo, err := uc.userRepository.CreateUser(ctx, *newUser)
 	if err != nil {
 		return nil, errors.New("user Creation Unseccssfull")
 	}
@@ -76,10 +299,45 @@ func (uc *authUseCase) Register(userCreate *model.UserCreate) (*model.UserInfo,
 	return userInfo, nil
 }
 
-func (uc *authUseCase) AdminRegister(currUser entities.AuthenticatedUser, userCreate *model.UserCreate, param any) (*model.UserInfo, error) {
-	if currUser.Role != "ADMIN" {
-		return nil, errors.New("Unauthorized")
+func (uc *authUseCase) AdminRegister(ctx context.Context, currUser entities.AuthenticatedUser, userCreate *model.UserCreate, param any) (*model.UserInfo, error) {
+	if currUser.Role != entities.RoleAdmin {
+		return nil, errAdminRequired
 	}
 
-	return uc.Register(userCreate)
+	if userCreate == nil {
+		return nil, errInvalidUserData
+	}
+
+	switch userCreate.Role {
+	case "":
+		return uc.register(ctx, userCreate, entities.RoleUser)
+	case entities.RoleUser, entities.RoleAdmin:
+		return uc.register(ctx, userCreate, userCreate.Role)
+	default:
+		return nil, errInvalidRole
+	}
+}
+
+// roleOrDefault treats users stored before roles were introduced as regular users.
+func roleOrDefault(role string) string {
+	if role == "" {
+		return entities.RoleUser
+	}
+
+	return role
+}
+
+// rehashPassword upgrades a stored hash after a successful login when the
+// configured algorithm or cost parameters have changed. Failures are only
+// logged since the user has already been authenticated.
+func (uc *authUseCase) rehashPassword(ctx context.Context, user *entities.User, password string) {
+	hashedPassword, err := uc.passwordHasher.Hash(password)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "rehashing password failed", "user_id", user.ID.Hex(), "error", err)
+		return
+	}
+
+	if err := uc.userRepository.UpdatePassword(ctx, user.ID.Hex(), hashedPassword); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "rehashing password failed", "user_id", user.ID.Hex(), "error", err)
+	}
 }
@@ -1,17 +1,21 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/mocks"
 	"task-management-api/domain/model"
-	"task-management-api/mongo"
 	"task-management-api/usecase"
+	"task-management-api/utils"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestRegister(t *testing.T) {
@@ -19,19 +23,27 @@ func TestRegister(t *testing.T) {
     t.Run("successful registration", func(t *testing.T) {
 		mockUserRepository := mocks.NewUserRepository(t)
 		mockUtils := mocks.NewUtils(t)
-		uc := usecase.NewAuthUseCase(mockUserRepository, mockUtils)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
 
         userCreate := &model.UserCreate{
 			Username: "testuser",
             Password: "password",
             Email:    "test@example.com",
+            Name:     "Test User",
             Bio:      "A test user",
         }
 
-        mockUserRepository.On("GetUserByUsername", mock.Anything, userCreate.Username).Return(nil, mongo.ErrNoDocuments)
-        mockUserRepository.On("CreateUser", mock.Anything, mock.Anything).Return(&model.UserInfo{ID: primitive.NewObjectID().Hex(), Username: userCreate.Username}, nil)
+        mockUserRepository.On("GetUserByUsername", mock.Anything, userCreate.Username).Return(nil, apperrors.NotFound("user not found"))
+        mockPasswordHasher.On("Hash", userCreate.Password).Return("hashedPassword", nil)
+        mockUserRepository.On("CreateUser", mock.Anything, mock.MatchedBy(func(user model.UserCreate) bool {
+            return user.Password == "hashedPassword" && user.Name == "Test User" && user.Bio == "A test user"
+        })).Return(&model.UserInfo{ID: primitive.NewObjectID().Hex(), Username: userCreate.Username}, nil)
 
-        userInfo, err := uc.Register(userCreate)
+        userInfo, err := uc.Register(context.TODO(), userCreate)
 
         assert.NoError(t, err)
         assert.NotNil(t, userInfo)
@@ -42,7 +54,11 @@ func TestRegister(t *testing.T) {
     t.Run("user already exists", func(t *testing.T) {
 		mockUserRepository := mocks.NewUserRepository(t)
 		mockUtils := mocks.NewUtils(t)
-		uc := usecase.NewAuthUseCase(mockUserRepository, mockUtils)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
 
         userCreate := &model.UserCreate{
             Username: "testuser",
@@ -54,10 +70,11 @@ func TestRegister(t *testing.T) {
         existingUser := &entities.User{ID: primitive.NewObjectID(), UserName: userCreate.Username}
         mockUserRepository.On("GetUserByUsername", mock.Anything, userCreate.Username).Return(existingUser, nil)
 
-        userInfo, err := uc.Register(userCreate)
+        userInfo, err := uc.Register(context.TODO(), userCreate)
 
         assert.Error(t, err)
         assert.Nil(t, userInfo)
+        assert.ErrorIs(t, err, apperrors.ErrConflict)
         assert.Equal(t, "username already exists", err.Error())
         mockUserRepository.AssertExpectations(t)
     })
@@ -65,24 +82,33 @@ func TestRegister(t *testing.T) {
     t.Run("invalid user data", func(t *testing.T) {
 		mockUserRepository := mocks.NewUserRepository(t)
 		mockUtils := mocks.NewUtils(t)
-		uc := usecase.NewAuthUseCase(mockUserRepository, mockUtils)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
 
         userCreate := &model.UserCreate{
             Username: "",
             Password: "",
         }
 
-        userInfo, err := uc.Register(userCreate)
+        userInfo, err := uc.Register(context.TODO(), userCreate)
 
         assert.Error(t, err)
         assert.Nil(t, userInfo)
+        assert.ErrorIs(t, err, apperrors.ErrValidation)
         assert.Equal(t, "invalid user data", err.Error())
     })
 
     t.Run("repository error", func(t *testing.T) {
 		mockUserRepository := mocks.NewUserRepository(t)
 		mockUtils := mocks.NewUtils(t)
-		uc := usecase.NewAuthUseCase(mockUserRepository, mockUtils)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
 
         userCreate := &model.UserCreate{
             Username: "testuser",
@@ -93,7 +119,7 @@ func TestRegister(t *testing.T) {
 
         mockUserRepository.On("GetUserByUsername", mock.Anything, userCreate.Username).Return(nil, errors.New("repository error"))
 
-        userInfo, err := uc.Register(userCreate)
+        userInfo, err := uc.Register(context.TODO(), userCreate)
 
         assert.Error(t, err)
         assert.Nil(t, userInfo)
@@ -106,7 +132,11 @@ func TestLogin(t *testing.T) {
     t.Run("successful login", func(t *testing.T) {
 		mockUserRepository := mocks.NewUserRepository(t)
 		mockUtils := mocks.NewUtils(t)
-		uc := usecase.NewAuthUseCase(mockUserRepository, mockUtils)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
 
 		
         userLogin := &model.UserLogin{
@@ -117,42 +147,62 @@ func TestLogin(t *testing.T) {
         user := &entities.User{
             ID:       primitive.NewObjectID(),
             UserName: userLogin.Username,
-			Password: userLogin.Password,
+			Password: "hashedPassword",
         }
 
         mockUserRepository.On("GetUserByUsername", mock.Anything, userLogin.Username).Return(user, nil)
-        mockUtils.On("GenerateToken", mock.AnythingOfType("string")).Return("mockToken", nil)
-        token, err := uc.Login(userLogin)
+        mockPasswordHasher.On("Verify", user.Password, userLogin.Password).Return(true, nil)
+        mockPasswordHasher.On("NeedsRehash", user.Password).Return(false)
+        mockUtils.On("GenerateToken", mock.AnythingOfType("entities.AuthenticatedUser")).Return("mockToken", nil)
+        mockRefreshTokenRepository.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(token entities.RefreshToken) bool {
+            return token.UserID == user.ID.Hex() && token.TokenHash != "" && token.FamilyID != "" && token.AccessTokenID != ""
+        })).Return(nil)
+        mockLoginMetrics.On("RecordLogin", true).Once()
+
+        token, err := uc.Login(context.TODO(), userLogin)
 
         assert.NoError(t, err)
-        assert.Equal(t, "mockToken", token)
+        assert.Equal(t, "mockToken", token.AccessToken)
+        assert.NotEmpty(t, token.RefreshToken)
+        assert.Equal(t, "Bearer", token.TokenType)
         mockUserRepository.AssertExpectations(t)
     })
 
     t.Run("user not found", func(t *testing.T) {
 		mockUserRepository := mocks.NewUserRepository(t)
 		mockUtils := mocks.NewUtils(t)
-		uc := usecase.NewAuthUseCase(mockUserRepository, mockUtils)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
 
         userLogin := &model.UserLogin{
             Username: "nonexistentuser",
             Password: "password",
         }
 
-        mockUserRepository.On("GetUserByUsername", mock.Anything, userLogin.Username).Return(nil, errors.New("user Not Found"))
+        mockUserRepository.On("GetUserByUsername", mock.Anything, userLogin.Username).Return(nil, apperrors.NotFound("user not found"))
 
-        token, err := uc.Login(userLogin)
+        mockLoginMetrics.On("RecordLogin", false).Once()
+
+        token, err := uc.Login(context.TODO(), userLogin)
 
         assert.Error(t, err)
-        assert.Equal(t, "", token)
-        assert.Equal(t, "user Not Found", err.Error())
+        assert.Nil(t, token)
+        assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+        assert.Equal(t, "invalid username or password", err.Error())
         mockUserRepository.AssertExpectations(t)
     })
 
     t.Run("invalid password", func(t *testing.T) {
 		mockUserRepository := mocks.NewUserRepository(t)
 		mockUtils := mocks.NewUtils(t)
-		uc := usecase.NewAuthUseCase(mockUserRepository, mockUtils)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
 
         userLogin := &model.UserLogin{
             Username: "testuser",
@@ -162,22 +212,31 @@ func TestLogin(t *testing.T) {
         user := &entities.User{
             ID:       primitive.NewObjectID(),
             UserName: userLogin.Username,
+            Password: "hashedPassword",
         }
 
         mockUserRepository.On("GetUserByUsername", mock.Anything, userLogin.Username).Return(user, nil)
+        mockPasswordHasher.On("Verify", user.Password, userLogin.Password).Return(false, nil)
+
+        mockLoginMetrics.On("RecordLogin", false).Once()
 
-        token, err := uc.Login(userLogin)
+        token, err := uc.Login(context.TODO(), userLogin)
 
         assert.Error(t, err)
-        assert.Equal(t, "", token)
-        assert.Equal(t, "invalid Password", err.Error())
+        assert.Nil(t, token)
+        assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+        assert.Equal(t, "invalid username or password", err.Error())
         mockUserRepository.AssertExpectations(t)
     })
 
 	t.Run("token generation failed", func(t *testing.T) {
 		mockUserRepository := mocks.NewUserRepository(t)
 		mockUtils := mocks.NewUtils(t)
-		uc := usecase.NewAuthUseCase(mockUserRepository, mockUtils)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
 
 		userLogin := &model.UserLogin{
 			Username: "testuser",
@@ -187,18 +246,333 @@ func TestLogin(t *testing.T) {
 		user := &entities.User{
 			ID:       primitive.NewObjectID(),
 			UserName: userLogin.Username,
-			Password: userLogin.Password,
+			Password: "hashedPassword",
 		}
 	
 		mockUserRepository.On("GetUserByUsername", mock.Anything, userLogin.Username).Return(user, nil)
-		mockUtils.On("GenerateToken", mock.AnythingOfType("string")).Return("", errors.New("token Generation Failed"))
+		mockPasswordHasher.On("Verify", user.Password, userLogin.Password).Return(true, nil)
+		mockPasswordHasher.On("NeedsRehash", user.Password).Return(false)
+		mockUtils.On("GenerateToken", mock.AnythingOfType("entities.AuthenticatedUser")).Return("", errors.New("token Generation Failed"))
 	
-		token, err := uc.Login(userLogin)
+		token, err := uc.Login(context.TODO(), userLogin)
 	
 		assert.Error(t, err)
-		assert.Equal(t, "", token)
+		assert.Nil(t, token)
 		assert.Equal(t, "token Generation Failed", err.Error())
 		mockUserRepository.AssertExpectations(t)
 		mockUtils.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+	t.Run("plaintext password rejected", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUtils := mocks.NewUtils(t)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, utils.NewBcryptHasher(bcrypt.MinCost), mockLoginMetrics)
+
+		userLogin := &model.UserLogin{
+			Username: "testuser",
+			Password: "password",
+		}
+
+		user := &entities.User{
+			ID:       primitive.NewObjectID(),
+			UserName: userLogin.Username,
+			Password: userLogin.Password,
+		}
+
+		mockUserRepository.On("GetUserByUsername", mock.Anything, userLogin.Username).Return(user, nil)
+
+		mockLoginMetrics.On("RecordLogin", false).Once()
+
+		token, err := uc.Login(context.TODO(), userLogin)
+
+		assert.Error(t, err)
+		assert.Nil(t, token)
+		assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+	})
+
+	t.Run("rehash on login", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUtils := mocks.NewUtils(t)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
+
+		userLogin := &model.UserLogin{
+			Username: "testuser",
+			Password: "password",
+		}
+
+		user := &entities.User{
+			ID:       primitive.NewObjectID(),
+			UserName: userLogin.Username,
+			Password: "oldHash",
+		}
+
+		mockUserRepository.On("GetUserByUsername", mock.Anything, userLogin.Username).Return(user, nil)
+		mockPasswordHasher.On("Verify", user.Password, userLogin.Password).Return(true, nil)
+		mockPasswordHasher.On("NeedsRehash", user.Password).Return(true)
+		mockPasswordHasher.On("Hash", userLogin.Password).Return("newHash", nil)
+		mockUserRepository.On("UpdatePassword", mock.Anything, user.ID.Hex(), "newHash").Return(nil)
+		mockUtils.On("GenerateToken", mock.MatchedBy(func(authenticatedUser entities.AuthenticatedUser) bool {
+			return authenticatedUser.UserID == user.ID.Hex() && authenticatedUser.Role == entities.RoleUser
+		})).Return("mockToken", nil)
+		mockRefreshTokenRepository.On("CreateRefreshToken", mock.Anything, mock.Anything).Return(nil)
+
+		mockLoginMetrics.On("RecordLogin", true).Once()
+
+		token, err := uc.Login(context.TODO(), userLogin)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "mockToken", token.AccessToken)
+	})
+}
+
+func TestMigratePlaintextPasswords(t *testing.T) {
+	mockUserRepository := mocks.NewUserRepository(t)
+	mockPasswordHasher := mocks.NewPasswordHasher(t)
+
+	plaintextUser := &entities.User{ID: primitive.NewObjectID(), UserName: "legacy", Password: "secret"}
+	hashedUser := &entities.User{ID: primitive.NewObjectID(), UserName: "modern", Password: "$2a$10$abcdefghijklmnopqrstuv"}
+
+	mockUserRepository.On("GetUser", mock.Anything, "").Return([]*entities.User{plaintextUser, hashedUser}, nil)
+	mockPasswordHasher.On("Hash", "secret").Return("hashedSecret", nil)
+	mockUserRepository.On("UpdatePassword", mock.Anything, plaintextUser.ID.Hex(), "hashedSecret").Return(nil)
+
+	migrated, err := usecase.MigratePlaintextPasswords(context.TODO(), mockUserRepository, mockPasswordHasher)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, migrated)
+}
+
+func TestAdminRegister(t *testing.T) {
+	userCreate := &model.UserCreate{
+		Username: "newadmin",
+		Password: "password",
+		Role:     entities.RoleAdmin,
+	}
+
+	t.Run("admin can create admins", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUtils := mocks.NewUtils(t)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
+
+		mockUserRepository.On("GetUserByUsername", mock.Anything, userCreate.Username).Return(nil, apperrors.NotFound("user not found"))
+		mockPasswordHasher.On("Hash", userCreate.Password).Return("hashedPassword", nil)
+		mockUserRepository.On("CreateUser", mock.Anything, mock.MatchedBy(func(user model.UserCreate) bool {
+			return user.Role == entities.RoleAdmin
+		})).Return(&model.UserInfo{Username: userCreate.Username}, nil)
+
+		userInfo, err := uc.AdminRegister(context.TODO(), entities.AuthenticatedUser{Role: entities.RoleAdmin}, userCreate, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, userCreate.Username, userInfo.Username)
+	})
+
+	t.Run("non admin is rejected", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUtils := mocks.NewUtils(t)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
+
+		userInfo, err := uc.AdminRegister(context.TODO(), entities.AuthenticatedUser{Role: entities.RoleUser}, userCreate, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, userInfo)
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUtils := mocks.NewUtils(t)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
+
+		userInfo, err := uc.AdminRegister(context.TODO(), entities.AuthenticatedUser{Role: entities.RoleAdmin}, &model.UserCreate{Username: "user", Password: "password", Role: "ROOT"}, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, userInfo)
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+		assert.Equal(t, "invalid role", err.Error())
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	newUseCase := func(t *testing.T) (entities.AuthUseCase, *mocks.UserRepository, *mocks.RefreshTokenRepository, *mocks.RevokedTokenRepository, *mocks.Utils) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUtils := mocks.NewUtils(t)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
+		return uc, mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils
+	}
+
+	user := &entities.User{ID: primitive.NewObjectID(), UserName: "testuser", Role: entities.RoleUser}
+
+	t.Run("rotates the refresh token", func(t *testing.T) {
+		uc, mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils := newUseCase(t)
+
+		stored := &entities.RefreshToken{
+			ID:                   primitive.NewObjectID(),
+			UserID:               user.ID.Hex(),
+			FamilyID:             "family",
+			AccessTokenID:        "old-jti",
+			AccessTokenExpiresAt: time.Now().Add(time.Minute),
+			ExpiresAt:            time.Now().Add(time.Hour),
+		}
+
+		mockRefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, utils.HashToken("refresh")).Return(stored, nil)
+		mockRefreshTokenRepository.On("RevokeRefreshToken", mock.Anything, stored.ID.Hex()).Return(true, nil)
+		mockRevokedTokenRepository.On("RevokeToken", mock.Anything, mock.MatchedBy(func(token entities.RevokedToken) bool {
+			return token.ID == "old-jti"
+		})).Return(nil)
+		mockUserRepository.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+		mockUtils.On("GenerateToken", mock.AnythingOfType("entities.AuthenticatedUser")).Return("newAccessToken", nil)
+		mockRefreshTokenRepository.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(token entities.RefreshToken) bool {
+			return token.FamilyID == "family" && token.TokenHash != utils.HashToken("refresh")
+		})).Return(nil)
+
+		tokens, err := uc.Refresh(context.TODO(), "refresh")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "newAccessToken", tokens.AccessToken)
+		assert.NotEqual(t, "refresh", tokens.RefreshToken)
+	})
+
+	t.Run("reuse revokes the family", func(t *testing.T) {
+		uc, _, mockRefreshTokenRepository, mockRevokedTokenRepository, _ := newUseCase(t)
+
+		revokedAt := time.Now().Add(-time.Minute)
+		stored := &entities.RefreshToken{
+			ID:        primitive.NewObjectID(),
+			UserID:    user.ID.Hex(),
+			FamilyID:  "family",
+			ExpiresAt: time.Now().Add(time.Hour),
+			RevokedAt: &revokedAt,
+		}
+		latest := &entities.RefreshToken{
+			ID:                   primitive.NewObjectID(),
+			UserID:               user.ID.Hex(),
+			FamilyID:             "family",
+			AccessTokenID:        "latest-jti",
+			AccessTokenExpiresAt: time.Now().Add(time.Minute),
+			ExpiresAt:            time.Now().Add(time.Hour),
+		}
+
+		mockRefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, utils.HashToken("refresh")).Return(stored, nil)
+		mockRefreshTokenRepository.On("GetRefreshTokenFamily", mock.Anything, "family").Return([]*entities.RefreshToken{stored, latest}, nil)
+		mockRevokedTokenRepository.On("RevokeToken", mock.Anything, mock.MatchedBy(func(token entities.RevokedToken) bool {
+			return token.ID == "latest-jti"
+		})).Return(nil)
+		mockRefreshTokenRepository.On("RevokeRefreshTokenFamily", mock.Anything, "family").Return(nil)
+
+		tokens, err := uc.Refresh(context.TODO(), "refresh")
+
+		assert.Error(t, err)
+		assert.Nil(t, tokens)
+		assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+		assert.Equal(t, "refresh token reuse detected", err.Error())
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		uc, _, mockRefreshTokenRepository, _, _ := newUseCase(t)
+
+		stored := &entities.RefreshToken{
+			ID:        primitive.NewObjectID(),
+			UserID:    user.ID.Hex(),
+			FamilyID:  "family",
+			ExpiresAt: time.Now().Add(-time.Hour),
+		}
+
+		mockRefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, utils.HashToken("refresh")).Return(stored, nil)
+
+		tokens, err := uc.Refresh(context.TODO(), "refresh")
+
+		assert.Error(t, err)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		uc, _, mockRefreshTokenRepository, _, _ := newUseCase(t)
+
+		mockRefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, utils.HashToken("refresh")).Return(nil, apperrors.NotFound("refresh token not found"))
+
+		tokens, err := uc.Refresh(context.TODO(), "refresh")
+
+		assert.Error(t, err)
+		assert.Nil(t, tokens)
+		assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+		assert.Equal(t, "invalid refresh token", err.Error())
+	})
+}
+
+func TestLogout(t *testing.T) {
+	currUser := entities.AuthenticatedUser{
+		UserID:         primitive.NewObjectID().Hex(),
+		TokenID:        "current-jti",
+		TokenExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	t.Run("logout revokes access token and session", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUtils := mocks.NewUtils(t)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
+
+		stored := &entities.RefreshToken{ID: primitive.NewObjectID(), UserID: currUser.UserID, FamilyID: "family"}
+
+		mockRevokedTokenRepository.On("RevokeToken", mock.Anything, entities.RevokedToken{ID: currUser.TokenID, UserID: currUser.UserID, ExpiresAt: currUser.TokenExpiresAt}).Return(nil)
+		mockRefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, utils.HashToken("refresh")).Return(stored, nil)
+		mockRefreshTokenRepository.On("GetRefreshTokenFamily", mock.Anything, "family").Return([]*entities.RefreshToken{stored}, nil)
+		mockRefreshTokenRepository.On("RevokeRefreshTokenFamily", mock.Anything, "family").Return(nil)
+
+		err := uc.Logout(context.TODO(), currUser, "refresh")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("logout all revokes every session", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUtils := mocks.NewUtils(t)
+		mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+		mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+		mockLoginMetrics := mocks.NewLoginMetrics(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+		uc := usecase.NewAuthUseCase(mockUserRepository, mockRefreshTokenRepository, mockRevokedTokenRepository, mockUtils, mockPasswordHasher, mockLoginMetrics)
+
+		other := &entities.RefreshToken{
+			ID:                   primitive.NewObjectID(),
+			UserID:               currUser.UserID,
+			AccessTokenID:        "other-jti",
+			AccessTokenExpiresAt: time.Now().Add(time.Minute),
+		}
+
+		mockRevokedTokenRepository.On("RevokeToken", mock.Anything, mock.MatchedBy(func(token entities.RevokedToken) bool {
+			return token.ID == "current-jti" || token.ID == "other-jti"
+		})).Return(nil).Twice()
+		mockRefreshTokenRepository.On("GetActiveRefreshTokens", mock.Anything, currUser.UserID).Return([]*entities.RefreshToken{other}, nil)
+		mockRefreshTokenRepository.On("RevokeUserRefreshTokens", mock.Anything, currUser.UserID).Return(nil)
+
+		err := uc.LogoutAll(context.TODO(), currUser)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CommentUsecase struct {
+	CommentRepository entities.CommentRepository
+	TaskRepository    entities.TaskRepository
+	ProjectRepository entities.ProjectRepository
+	contextTimeout    time.Duration
+}
+
+func NewCommentUsecase(commentRepository entities.CommentRepository, taskRepository entities.TaskRepository, projectRepository entities.ProjectRepository) entities.CommentUsecase {
+	return &CommentUsecase{
+		CommentRepository: commentRepository,
+		TaskRepository:    taskRepository,
+		ProjectRepository: projectRepository,
+		contextTimeout:    3 * time.Second,
+	}
+}
+
+func (uc *CommentUsecase) GetComments(ctx context.Context, taskID string, userID string, query model.PageQuery) (*model.CommentPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, taskID, userID, entities.TaskRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.CommentRepository.GetComments(ctx, taskID, normalizePageQuery(query))
+}
+
+func (uc *CommentUsecase) CreateComment(ctx context.Context, taskID string, userID string, body string) (*model.CommentInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, taskID, userID, entities.TaskRoleViewer); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment := entities.Comment{
+		ID:        primitive.NewObjectID(),
+		TaskID:    taskID,
+		AuthorID:  userID,
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := uc.CommentRepository.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	return comment.Info(), nil
+}
+
+func (uc *CommentUsecase) UpdateComment(ctx context.Context, taskID string, id string, userID string, body string) (*model.CommentInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	comment, err := uc.authoredComment(ctx, taskID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Body = body
+	if err := uc.CommentRepository.UpdateComment(ctx, *comment); err != nil {
+		return nil, err
+	}
+
+	comment.UpdatedAt = time.Now()
+	return comment.Info(), nil
+}
+
+func (uc *CommentUsecase) DeleteComment(ctx context.Context, taskID string, id string, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.authoredComment(ctx, taskID, id, userID); err != nil {
+		return err
+	}
+
+	return uc.CommentRepository.DeleteComment(ctx, taskID, id)
+}
+
+// authoredComment returns a comment on a task the user can read, failing with
+// ErrCommentNotAuthor unless the user wrote it. Authors keep their comments
+// when their role on the task changes, as long as they can still read it.
+func (uc *CommentUsecase) authoredComment(ctx context.Context, taskID string, id string, userID string) (*entities.Comment, error) {
+	if _, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, taskID, userID, entities.TaskRoleViewer); err != nil {
+		return nil, err
+	}
+
+	comment, err := uc.CommentRepository.GetCommentByID(ctx, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.AuthorID != userID {
+		return nil, entities.ErrCommentNotAuthor
+	}
+
+	return comment, nil
+}
@@ -0,0 +1,183 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/domain/model"
+	"task-management-api/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetComments(t *testing.T) {
+	taskID := "testTaskID"
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		page := &model.CommentPage{Comments: []*model.CommentInfo{{Body: "First"}}, Next: "next"}
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+
+		mockCommentRepository := mocks.NewCommentRepository(t)
+		mockCommentRepository.On("GetComments", mock.Anything, taskID, model.PageQuery{Cursor: "c", Limit: model.DefaultTaskPageSize}).Return(page, nil).Once()
+
+		cuc := usecase.NewCommentUsecase(mockCommentRepository, mockTaskRepository, new(mocks.ProjectRepository))
+
+		result, err := cuc.GetComments(context.TODO(), taskID, userID, model.PageQuery{Cursor: "c"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, page, result)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		notFound := apperrors.NotFound("task not found")
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(nil, notFound).Once()
+
+		cuc := usecase.NewCommentUsecase(mocks.NewCommentRepository(t), mockTaskRepository, new(mocks.ProjectRepository))
+
+		_, err := cuc.GetComments(context.TODO(), taskID, userID, model.PageQuery{})
+
+		assert.ErrorIs(t, err, notFound)
+	})
+}
+
+func TestCreateComment(t *testing.T) {
+	taskID := "testTaskID"
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+
+		mockCommentRepository := mocks.NewCommentRepository(t)
+		mockCommentRepository.On("CreateComment", mock.Anything, mock.MatchedBy(func(comment entities.Comment) bool {
+			return !comment.ID.IsZero() && comment.TaskID == taskID && comment.AuthorID == userID && comment.Body == "Hello"
+		})).Return(nil).Once()
+
+		cuc := usecase.NewCommentUsecase(mockCommentRepository, mockTaskRepository, new(mocks.ProjectRepository))
+
+		comment, err := cuc.CreateComment(context.TODO(), taskID, userID, "Hello")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, comment.ID)
+		assert.Equal(t, userID, comment.AuthorID)
+		assert.False(t, comment.CreatedAt.IsZero())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		expectedErr := errors.New("creation error")
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+
+		mockCommentRepository := mocks.NewCommentRepository(t)
+		mockCommentRepository.On("CreateComment", mock.Anything, mock.Anything).Return(expectedErr).Once()
+
+		cuc := usecase.NewCommentUsecase(mockCommentRepository, mockTaskRepository, new(mocks.ProjectRepository))
+
+		_, err := cuc.CreateComment(context.TODO(), taskID, userID, "Hello")
+
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+func TestUpdateComment(t *testing.T) {
+	taskID := "testTaskID"
+	userID := "testUserID"
+	commentID := primitive.NewObjectID()
+	comment := &entities.Comment{ID: commentID, TaskID: taskID, AuthorID: userID, Body: "Hello"}
+	// The other user can read the task but did not write the comment.
+	sharedTask := &entities.Task{UserID: userID, Collaborators: []entities.Collaborator{{UserID: "otherUserID", Role: entities.TaskRoleEditor}}}
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+
+		updated := *comment
+		updated.Body = "Edited"
+
+		mockCommentRepository := mocks.NewCommentRepository(t)
+		mockCommentRepository.On("GetCommentByID", mock.Anything, taskID, commentID.Hex()).Return(comment, nil).Once()
+		mockCommentRepository.On("UpdateComment", mock.Anything, updated).Return(nil).Once()
+
+		cuc := usecase.NewCommentUsecase(mockCommentRepository, mockTaskRepository, new(mocks.ProjectRepository))
+
+		result, err := cuc.UpdateComment(context.TODO(), taskID, commentID.Hex(), userID, "Edited")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Edited", result.Body)
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(sharedTask, nil).Once()
+
+		mockCommentRepository := mocks.NewCommentRepository(t)
+		mockCommentRepository.On("GetCommentByID", mock.Anything, taskID, commentID.Hex()).Return(comment, nil).Once()
+
+		cuc := usecase.NewCommentUsecase(mockCommentRepository, mockTaskRepository, new(mocks.ProjectRepository))
+
+		_, err := cuc.UpdateComment(context.TODO(), taskID, commentID.Hex(), "otherUserID", "Edited")
+
+		assert.ErrorIs(t, err, entities.ErrCommentNotAuthor)
+	})
+
+	t.Run("comment not found", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+
+		mockCommentRepository := mocks.NewCommentRepository(t)
+		mockCommentRepository.On("GetCommentByID", mock.Anything, taskID, "missing").Return(nil, entities.ErrCommentNotFound).Once()
+
+		cuc := usecase.NewCommentUsecase(mockCommentRepository, mockTaskRepository, new(mocks.ProjectRepository))
+
+		_, err := cuc.UpdateComment(context.TODO(), taskID, "missing", userID, "Edited")
+
+		assert.ErrorIs(t, err, entities.ErrCommentNotFound)
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	taskID := "testTaskID"
+	userID := "testUserID"
+	commentID := primitive.NewObjectID()
+	comment := &entities.Comment{ID: commentID, TaskID: taskID, AuthorID: userID, Body: "Hello"}
+	// The other user can read the task but did not write the comment.
+	sharedTask := &entities.Task{UserID: userID, Collaborators: []entities.Collaborator{{UserID: "otherUserID", Role: entities.TaskRoleEditor}}}
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+
+		mockCommentRepository := mocks.NewCommentRepository(t)
+		mockCommentRepository.On("GetCommentByID", mock.Anything, taskID, commentID.Hex()).Return(comment, nil).Once()
+		mockCommentRepository.On("DeleteComment", mock.Anything, taskID, commentID.Hex()).Return(nil).Once()
+
+		cuc := usecase.NewCommentUsecase(mockCommentRepository, mockTaskRepository, new(mocks.ProjectRepository))
+
+		assert.NoError(t, cuc.DeleteComment(context.TODO(), taskID, commentID.Hex(), userID))
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(sharedTask, nil).Once()
+
+		mockCommentRepository := mocks.NewCommentRepository(t)
+		mockCommentRepository.On("GetCommentByID", mock.Anything, taskID, commentID.Hex()).Return(comment, nil).Once()
+
+		cuc := usecase.NewCommentUsecase(mockCommentRepository, mockTaskRepository, new(mocks.ProjectRepository))
+
+		err := cuc.DeleteComment(context.TODO(), taskID, commentID.Hex(), "otherUserID")
+
+		assert.ErrorIs(t, err, entities.ErrCommentNotAuthor)
+	})
+}
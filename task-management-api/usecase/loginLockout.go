@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"task-management-api/logging"
+)
+
+// LoginLockoutPolicy locks a username out once Threshold consecutive logins
+// failed. The first lockout lasts BaseLockout and every further failure
+// doubles it, up to MaxLockout.
+type LoginLockoutPolicy struct {
+	Threshold   int
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+var DefaultLoginLockoutPolicy = LoginLockoutPolicy{
+	Threshold:   5,
+	BaseLockout: 30 * time.Second,
+	MaxLockout:  15 * time.Minute,
+}
+
+// LockedUntil returns when the lockout earned by failures ends, or the zero
+// time when there is none.
+func (p LoginLockoutPolicy) LockedUntil(failures entities.LoginFailures) time.Time {
+	if failures.Count < p.Threshold {
+		return time.Time{}
+	}
+
+	lockout := p.MaxLockout
+	if doublings := failures.Count - p.Threshold; doublings < 32 && p.BaseLockout<<doublings < p.MaxLockout {
+		lockout = p.BaseLockout << doublings
+	}
+
+	return failures.LastFailure.Add(lockout)
+}
+
+type loginLockoutUseCase struct {
+	entities.AuthUseCase
+	loginAttempts entities.LoginAttemptStore
+	policy        LoginLockoutPolicy
+}
+
+// NewLoginLockout protects the logins of authUseCase against password
+// guessing: usernames with too many failed logins are locked out according to
+// policy, whether or not the right password is then given.
+func NewLoginLockout(authUseCase entities.AuthUseCase, loginAttempts entities.LoginAttemptStore, policy LoginLockoutPolicy) entities.AuthUseCase {
+	return &loginLockoutUseCase{
+		AuthUseCase:   authUseCase,
+		loginAttempts: loginAttempts,
+		policy:        policy,
+	}
+}
+
+func (uc *loginLockoutUseCase) Login(ctx context.Context, userLogin *model.UserLogin) (*entities.TokenPair, error) {
+	logger := logging.FromContext(ctx)
+
+	// The attempt is counted as failed before the password is checked, and
+	// forgotten again when the login succeeds.
+	failures, lockedUntil, recordErr := uc.loginAttempts.RecordAttempt(ctx, userLogin.Username, uc.policy.LockedUntil)
+	if recordErr != nil {
+		logger.ErrorContext(ctx, "recording login attempt failed", "error", recordErr)
+	} else if !lockedUntil.IsZero() {
+		return nil, apperrors.RateLimited("too many failed logins, try again later", time.Until(lockedUntil))
+	}
+
+	tokens, err := uc.AuthUseCase.Login(ctx, userLogin)
+	switch {
+	case err == nil:
+		if err := uc.loginAttempts.ResetFailures(ctx, userLogin.Username); err != nil {
+			logger.ErrorContext(ctx, "resetting failed logins failed", "error", err)
+		}
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		if recordErr == nil && failures.Count == uc.policy.Threshold {
+			logger.WarnContext(ctx, "username locked out after failed logins", "username", userLogin.Username, "failures", failures.Count)
+		}
+	}
+
+	return tokens, err
+}
@@ -0,0 +1,139 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/domain/model"
+	"task-management-api/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginLockoutPolicy(t *testing.T) {
+	policy := usecase.LoginLockoutPolicy{Threshold: 3, BaseLockout: time.Minute, MaxLockout: 10 * time.Minute}
+	lastFailure := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		failures int
+		lockout  time.Duration
+	}{
+		{failures: 2, lockout: 0},
+		{failures: 3, lockout: time.Minute},
+		{failures: 4, lockout: 2 * time.Minute},
+		{failures: 5, lockout: 4 * time.Minute},
+		{failures: 7, lockout: 10 * time.Minute},
+		{failures: 100, lockout: 10 * time.Minute},
+	}
+
+	for _, test := range tests {
+		lockedUntil := policy.LockedUntil(entities.LoginFailures{Count: test.failures, LastFailure: lastFailure})
+		if test.lockout == 0 {
+			assert.True(t, lockedUntil.IsZero(), "failures: %d", test.failures)
+			continue
+		}
+		assert.Equal(t, lastFailure.Add(test.lockout), lockedUntil, "failures: %d", test.failures)
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	policy := usecase.LoginLockoutPolicy{Threshold: 3, BaseLockout: time.Minute, MaxLockout: 10 * time.Minute}
+	userLogin := &model.UserLogin{Username: "alice", Password: "password"}
+
+	t.Run("successful login resets failures", func(t *testing.T) {
+		mockAuthUseCase := mocks.NewAuthUseCase(t)
+		mockLoginAttempts := mocks.NewLoginAttemptStore(t)
+		uc := usecase.NewLoginLockout(mockAuthUseCase, mockLoginAttempts, policy)
+
+		tokens := &entities.TokenPair{AccessToken: "access"}
+		mockLoginAttempts.On("RecordAttempt", mock.Anything, "alice", mock.Anything).Return(entities.LoginFailures{Count: 3, LastFailure: time.Now()}, time.Time{}, nil).Once()
+		mockAuthUseCase.On("Login", mock.Anything, userLogin).Return(tokens, nil).Once()
+		mockLoginAttempts.On("ResetFailures", mock.Anything, "alice").Return(nil).Once()
+
+		result, err := uc.Login(context.TODO(), userLogin)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tokens, result)
+	})
+
+	t.Run("invalid credentials stay recorded", func(t *testing.T) {
+		mockAuthUseCase := mocks.NewAuthUseCase(t)
+		mockLoginAttempts := mocks.NewLoginAttemptStore(t)
+		uc := usecase.NewLoginLockout(mockAuthUseCase, mockLoginAttempts, policy)
+
+		invalidCredentials := apperrors.Unauthorized("invalid username or password")
+		mockLoginAttempts.On("RecordAttempt", mock.Anything, "alice", mock.Anything).Return(entities.LoginFailures{Count: 1, LastFailure: time.Now()}, time.Time{}, nil).Once()
+		mockAuthUseCase.On("Login", mock.Anything, userLogin).Return(nil, invalidCredentials).Once()
+
+		result, err := uc.Login(context.TODO(), userLogin)
+
+		assert.Nil(t, result)
+		assert.Equal(t, invalidCredentials, err)
+	})
+
+	t.Run("other errors do not reset failures", func(t *testing.T) {
+		mockAuthUseCase := mocks.NewAuthUseCase(t)
+		mockLoginAttempts := mocks.NewLoginAttemptStore(t)
+		uc := usecase.NewLoginLockout(mockAuthUseCase, mockLoginAttempts, policy)
+
+		mockLoginAttempts.On("RecordAttempt", mock.Anything, "alice", mock.Anything).Return(entities.LoginFailures{Count: 1, LastFailure: time.Now()}, time.Time{}, nil).Once()
+		mockAuthUseCase.On("Login", mock.Anything, userLogin).Return(nil, errors.New("connection refused")).Once()
+
+		_, err := uc.Login(context.TODO(), userLogin)
+
+		assert.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("locked out username", func(t *testing.T) {
+		mockAuthUseCase := mocks.NewAuthUseCase(t)
+		mockLoginAttempts := mocks.NewLoginAttemptStore(t)
+		uc := usecase.NewLoginLockout(mockAuthUseCase, mockLoginAttempts, policy)
+
+		lockedUntil := time.Now().Add(2 * time.Minute)
+		mockLoginAttempts.On("RecordAttempt", mock.Anything, "alice", mock.Anything).Return(entities.LoginFailures{Count: 4}, lockedUntil, nil).Once()
+
+		result, err := uc.Login(context.TODO(), userLogin)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, apperrors.ErrRateLimited)
+		appErr, ok := apperrors.As(err)
+		assert.True(t, ok)
+		assert.InDelta(t, (2 * time.Minute).Seconds(), appErr.RetryAfter.Seconds(), 1)
+	})
+
+	t.Run("lockout is checked with the policy", func(t *testing.T) {
+		mockAuthUseCase := mocks.NewAuthUseCase(t)
+		mockLoginAttempts := mocks.NewLoginAttemptStore(t)
+		uc := usecase.NewLoginLockout(mockAuthUseCase, mockLoginAttempts, policy)
+
+		tokens := &entities.TokenPair{AccessToken: "access"}
+		lastFailure := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+		mockLoginAttempts.On("RecordAttempt", mock.Anything, "alice", mock.MatchedBy(func(lockedUntil func(entities.LoginFailures) time.Time) bool {
+			return lockedUntil(entities.LoginFailures{Count: 3, LastFailure: lastFailure}).Equal(lastFailure.Add(time.Minute))
+		})).Return(entities.LoginFailures{Count: 4, LastFailure: time.Now()}, time.Time{}, nil).Once()
+		mockAuthUseCase.On("Login", mock.Anything, userLogin).Return(tokens, nil).Once()
+		mockLoginAttempts.On("ResetFailures", mock.Anything, "alice").Return(nil).Once()
+
+		_, err := uc.Login(context.TODO(), userLogin)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("other methods are passed through", func(t *testing.T) {
+		mockAuthUseCase := mocks.NewAuthUseCase(t)
+		mockLoginAttempts := mocks.NewLoginAttemptStore(t)
+		uc := usecase.NewLoginLockout(mockAuthUseCase, mockLoginAttempts, policy)
+
+		mockAuthUseCase.On("Refresh", mock.Anything, "refresh").Return(nil, nil).Once()
+
+		_, err := uc.Refresh(context.TODO(), "refresh")
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"task-management-api/domain/entities"
+	"task-management-api/utils"
+)
+
+// MigratePlaintextPasswords hashes every stored password that is not already
+// in a supported hash format and returns the number of upgraded users.
+func MigratePlaintextPasswords(ctx context.Context, userRepository entities.UserRepository, passwordHasher utils.PasswordHasher) (int, error) {
+	// An empty search parameter matches every user.
+	users, err := userRepository.GetUser(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, user := range users {
+		if user.Password == "" || utils.IsHashedPassword(user.Password) {
+			continue
+		}
+
+		hashedPassword, err := passwordHasher.Hash(user.Password)
+		if err != nil {
+			return migrated, fmt.Errorf("hashing password for user %s: %w", user.ID.Hex(), err)
+		}
+
+		if err := userRepository.UpdatePassword(ctx, user.ID.Hex(), hashedPassword); err != nil {
+			return migrated, fmt.Errorf("updating password for user %s: %w", user.ID.Hex(), err)
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProjectUsecase struct {
+	ProjectRepository entities.ProjectRepository
+	UserRepository    entities.UserRepository
+	contextTimeout    time.Duration
+}
+
+func NewProjectUsecase(projectRepository entities.ProjectRepository, userRepository entities.UserRepository) entities.ProjectUsecase {
+	return &ProjectUsecase{
+		ProjectRepository: projectRepository,
+		UserRepository:    userRepository,
+		contextTimeout:    3 * time.Second,
+	}
+}
+
+func (uc *ProjectUsecase) GetProjects(ctx context.Context, userID string, archived bool) ([]*model.ProjectInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	projects, err := uc.ProjectRepository.GetProjects(ctx, userID, archived)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []*model.ProjectInfo{}
+	for _, project := range projects {
+		infos = append(infos, project.Info())
+	}
+
+	return infos, nil
+}
+
+func (uc *ProjectUsecase) GetProjectByID(ctx context.Context, id string) (*model.ProjectInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	project, err := uc.ProjectRepository.GetProjectByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return project.Info(), nil
+}
+
+func (uc *ProjectUsecase) CreateProject(ctx context.Context, name string, userID string) (*model.ProjectInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	project := entities.Project{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		OwnerID:   userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := uc.ProjectRepository.CreateProject(ctx, project); err != nil {
+		return nil, err
+	}
+
+	return project.Info(), nil
+}
+
+func (uc *ProjectUsecase) RenameProject(ctx context.Context, id string, name string) (*model.ProjectInfo, error) {
+	return uc.updateProject(ctx, id, func(ctx context.Context, project *entities.Project) error {
+		project.Name = name
+		return nil
+	})
+}
+
+func (uc *ProjectUsecase) ArchiveProject(ctx context.Context, id string, archived bool) (*model.ProjectInfo, error) {
+	return uc.updateProject(ctx, id, func(ctx context.Context, project *entities.Project) error {
+		switch {
+		case archived && project.ArchivedAt == nil:
+			archivedAt := time.Now().UTC()
+			project.ArchivedAt = &archivedAt
+		case !archived:
+			project.ArchivedAt = nil
+		}
+		return nil
+	})
+}
+
+func (uc *ProjectUsecase) AddProjectMember(ctx context.Context, id string, username string, role string) (*model.ProjectInfo, error) {
+	if !entities.IsValidProjectMemberRole(role) {
+		return nil, entities.ErrInvalidProjectRole
+	}
+
+	return uc.updateProject(ctx, id, func(ctx context.Context, project *entities.Project) error {
+		user, err := uc.UserRepository.GetUserByUsername(ctx, username)
+		if err != nil {
+			return err
+		}
+		memberID := user.ID.Hex()
+
+		if memberID == project.OwnerID {
+			return entities.ErrAddProjectOwner
+		}
+
+		// Members are kept in order of their IDs, as the SQL backend reads
+		// them back.
+		members := []entities.ProjectMember{{UserID: memberID, Role: role}}
+		for _, member := range project.Members {
+			if member.UserID != memberID {
+				members = append(members, member)
+			}
+		}
+		sort.Slice(members, func(i, j int) bool {
+			return members[i].UserID < members[j].UserID
+		})
+
+		project.Members = members
+		return nil
+	})
+}
+
+func (uc *ProjectUsecase) RemoveProjectMember(ctx context.Context, id string, username string) (*model.ProjectInfo, error) {
+	return uc.updateProject(ctx, id, func(ctx context.Context, project *entities.Project) error {
+		user, err := uc.UserRepository.GetUserByUsername(ctx, username)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return entities.ErrProjectMemberMissing
+		}
+		if err != nil {
+			return err
+		}
+		memberID := user.ID.Hex()
+
+		var members []entities.ProjectMember
+		for _, member := range project.Members {
+			if member.UserID != memberID {
+				members = append(members, member)
+			}
+		}
+		if len(members) == len(project.Members) {
+			return entities.ErrProjectMemberMissing
+		}
+
+		project.Members = members
+		return nil
+	})
+}
+
+// projectUpdateAttempts is how many times updateProject reads a project again
+// when another update wrote it in the meantime.
+const projectUpdateAttempts = 3
+
+// updateProject reads a project, applies change to it and writes it back,
+// starting over with a fresh copy when another update wrote the project
+// first, so that concurrent changes do not overwrite each other.
+func (uc *ProjectUsecase) updateProject(ctx context.Context, id string, change func(ctx context.Context, project *entities.Project) error) (*model.ProjectInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		project, err := uc.ProjectRepository.GetProjectByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := change(ctx, project); err != nil {
+			return nil, err
+		}
+
+		err = uc.ProjectRepository.UpdateProject(ctx, *project)
+		if errors.Is(err, entities.ErrProjectModified) && attempt < projectUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		project.UpdatedAt = time.Now().UTC()
+		project.Version++
+		return project.Info(), nil
+	}
+}
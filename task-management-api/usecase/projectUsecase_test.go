@@ -0,0 +1,198 @@
+package usecase_test
+
+import (
+	"context"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateProject(t *testing.T) {
+	mockProjectRepository := mocks.NewProjectRepository(t)
+	mockProjectRepository.On("CreateProject", mock.Anything, mock.MatchedBy(func(project entities.Project) bool {
+		return !project.ID.IsZero() && project.Name == "Launch" && project.OwnerID == "ownerID"
+	})).Return(nil).Once()
+
+	puc := usecase.NewProjectUsecase(mockProjectRepository, mocks.NewUserRepository(t))
+
+	project, err := puc.CreateProject(context.TODO(), "Launch", "ownerID")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Launch", project.Name)
+	assert.Equal(t, "ownerID", project.OwnerID)
+	assert.NotNil(t, project.Members)
+}
+
+func TestGetProjects(t *testing.T) {
+	mockProjectRepository := mocks.NewProjectRepository(t)
+	mockProjectRepository.On("GetProjects", mock.Anything, "userID", true).Return([]*entities.Project{{Name: "Launch"}}, nil).Once()
+
+	puc := usecase.NewProjectUsecase(mockProjectRepository, mocks.NewUserRepository(t))
+
+	projects, err := puc.GetProjects(context.TODO(), "userID", true)
+
+	assert.NoError(t, err)
+	assert.Len(t, projects, 1)
+	assert.Equal(t, "Launch", projects[0].Name)
+}
+
+func TestArchiveProject(t *testing.T) {
+	projectID := "testProjectID"
+
+	t.Run("archive", func(t *testing.T) {
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{Name: "Launch"}, nil).Once()
+		mockProjectRepository.On("UpdateProject", mock.Anything, mock.MatchedBy(func(project entities.Project) bool {
+			return project.ArchivedAt != nil
+		})).Return(nil).Once()
+
+		puc := usecase.NewProjectUsecase(mockProjectRepository, mocks.NewUserRepository(t))
+
+		project, err := puc.ArchiveProject(context.TODO(), projectID, true)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, project.ArchivedAt)
+	})
+
+	t.Run("unarchive", func(t *testing.T) {
+		archivedAt := time.Now()
+
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{ArchivedAt: &archivedAt}, nil).Once()
+		mockProjectRepository.On("UpdateProject", mock.Anything, entities.Project{}).Return(nil).Once()
+
+		puc := usecase.NewProjectUsecase(mockProjectRepository, mocks.NewUserRepository(t))
+
+		project, err := puc.ArchiveProject(context.TODO(), projectID, false)
+
+		assert.NoError(t, err)
+		assert.Nil(t, project.ArchivedAt)
+	})
+}
+
+func TestAddProjectMember(t *testing.T) {
+	projectID := "testProjectID"
+	ownerID := primitive.NewObjectID().Hex()
+	bob := &entities.User{ID: primitive.NewObjectID(), UserName: "bob"}
+	existing := entities.ProjectMember{UserID: "000000000000000000000000", Role: entities.ProjectRoleViewer}
+
+	t.Run("success", func(t *testing.T) {
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{OwnerID: ownerID, Members: []entities.ProjectMember{existing}}, nil).Once()
+		mockProjectRepository.On("UpdateProject", mock.Anything, entities.Project{OwnerID: ownerID,
+			Members: []entities.ProjectMember{existing, {UserID: bob.ID.Hex(), Role: entities.ProjectRoleEditor}}}).Return(nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		puc := usecase.NewProjectUsecase(mockProjectRepository, mockUserRepository)
+
+		project, err := puc.AddProjectMember(context.TODO(), projectID, "bob", entities.ProjectRoleEditor)
+
+		assert.NoError(t, err)
+		assert.Len(t, project.Members, 2)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		puc := usecase.NewProjectUsecase(mocks.NewProjectRepository(t), mocks.NewUserRepository(t))
+
+		_, err := puc.AddProjectMember(context.TODO(), projectID, "bob", entities.ProjectRoleOwner)
+
+		assert.ErrorIs(t, err, entities.ErrInvalidProjectRole)
+	})
+
+	t.Run("add the owner", func(t *testing.T) {
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{OwnerID: bob.ID.Hex()}, nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		puc := usecase.NewProjectUsecase(mockProjectRepository, mockUserRepository)
+
+		_, err := puc.AddProjectMember(context.TODO(), projectID, "bob", entities.ProjectRoleViewer)
+
+		assert.ErrorIs(t, err, entities.ErrAddProjectOwner)
+	})
+
+	t.Run("concurrent update", func(t *testing.T) {
+		carol := entities.ProjectMember{UserID: "ffffffffffffffffffffffff", Role: entities.ProjectRoleViewer}
+
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{OwnerID: ownerID, Version: 1}, nil).Once()
+		mockProjectRepository.On("UpdateProject", mock.Anything, mock.MatchedBy(func(project entities.Project) bool {
+			return project.Version == 1
+		})).Return(entities.ErrProjectModified).Once()
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{OwnerID: ownerID, Version: 2,
+			Members: []entities.ProjectMember{carol}}, nil).Once()
+		mockProjectRepository.On("UpdateProject", mock.Anything, entities.Project{OwnerID: ownerID, Version: 2,
+			Members: []entities.ProjectMember{{UserID: bob.ID.Hex(), Role: entities.ProjectRoleEditor}, carol}}).Return(nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Twice()
+
+		puc := usecase.NewProjectUsecase(mockProjectRepository, mockUserRepository)
+
+		project, err := puc.AddProjectMember(context.TODO(), projectID, "bob", entities.ProjectRoleEditor)
+
+		assert.NoError(t, err)
+		assert.Len(t, project.Members, 2)
+	})
+
+	t.Run("keeps conflicting", func(t *testing.T) {
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{OwnerID: ownerID}, nil).Times(3)
+		mockProjectRepository.On("UpdateProject", mock.Anything, mock.Anything).Return(entities.ErrProjectModified).Times(3)
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Times(3)
+
+		puc := usecase.NewProjectUsecase(mockProjectRepository, mockUserRepository)
+
+		_, err := puc.AddProjectMember(context.TODO(), projectID, "bob", entities.ProjectRoleEditor)
+
+		assert.ErrorIs(t, err, entities.ErrProjectModified)
+	})
+}
+
+func TestRemoveProjectMember(t *testing.T) {
+	projectID := "testProjectID"
+	bob := &entities.User{ID: primitive.NewObjectID(), UserName: "bob"}
+
+	t.Run("success", func(t *testing.T) {
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{
+			Members: []entities.ProjectMember{{UserID: bob.ID.Hex(), Role: entities.ProjectRoleEditor}}}, nil).Once()
+		mockProjectRepository.On("UpdateProject", mock.Anything, entities.Project{}).Return(nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		puc := usecase.NewProjectUsecase(mockProjectRepository, mockUserRepository)
+
+		project, err := puc.RemoveProjectMember(context.TODO(), projectID, "bob")
+
+		assert.NoError(t, err)
+		assert.Empty(t, project.Members)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{}, nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		puc := usecase.NewProjectUsecase(mockProjectRepository, mockUserRepository)
+
+		_, err := puc.RemoveProjectMember(context.TODO(), projectID, "bob")
+
+		assert.ErrorIs(t, err, entities.ErrProjectMemberMissing)
+	})
+}
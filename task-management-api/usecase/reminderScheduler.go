@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// reminderBatchSize is the most reminders claimed at once.
+	reminderBatchSize = 50
+	// reminderClaimLease is how long a claimed reminder is left to the
+	// scheduler that claimed it. Reminders it neither sent nor gave up on by
+	// then are claimed again, by any scheduler.
+	reminderClaimLease = 5 * time.Minute
+)
+
+// ReminderScheduler sends the reminders of tasks when they are due. Every
+// replica of the service may run one: reminders are claimed in the database
+// before they are sent, so each is sent by a single scheduler.
+type ReminderScheduler struct {
+	reminders entities.ReminderRepository
+	tasks     entities.TaskRepository
+	users     entities.UserRepository
+	notifier  entities.Notifier
+	claimant  string
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// NewReminderScheduler returns a scheduler looking for due reminders every
+// interval and sending them with notifier. Time is read from now, or from
+// time.Now when now is nil.
+func NewReminderScheduler(reminders entities.ReminderRepository, tasks entities.TaskRepository, users entities.UserRepository, notifier entities.Notifier, interval time.Duration, now func() time.Time) *ReminderScheduler {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &ReminderScheduler{
+		reminders: reminders,
+		tasks:     tasks,
+		users:     users,
+		notifier:  notifier,
+		claimant:  primitive.NewObjectID().Hex(),
+		interval:  interval,
+		now:       now,
+	}
+}
+
+// SendDue claims the reminders that are due and sends them, and returns how
+// many were sent. Reminders whose task was deleted, done, rescheduled or is
+// already overdue are marked sent without notifying anyone. A reminder that
+// fails to send is tried again once its claim has expired.
+func (rs *ReminderScheduler) SendDue(ctx context.Context) (int, error) {
+	now := rs.now()
+
+	reminders, err := rs.reminders.ClaimDueReminders(ctx, now, rs.claimant, reminderClaimLease, reminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		notified, err := rs.send(ctx, reminder, now)
+		if err != nil {
+			slog.ErrorContext(ctx, "Sending a reminder failed", "reminder_id", reminder.ID.Hex(), "task_id", reminder.TaskID, "attempts", reminder.Attempts, "error", err)
+			continue
+		}
+
+		if err := rs.reminders.MarkReminderSent(ctx, reminder.ID.Hex(), now); err != nil {
+			slog.ErrorContext(ctx, "Marking a reminder sent failed", "reminder_id", reminder.ID.Hex(), "task_id", reminder.TaskID, "error", err)
+			continue
+		}
+		if notified {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// send notifies the assignee of the task of a reminder, or its owner when it
+// is not assigned. It returns false when the reminder is stale.
+func (rs *ReminderScheduler) send(ctx context.Context, reminder *entities.Reminder, now time.Time) (bool, error) {
+	task, err := rs.tasks.GetTaskByID(ctx, reminder.TaskID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	offset, err := entities.ParseReminderOffset(reminder.Offset)
+	if err != nil || task.Status == entities.StatusDone || !task.DueDate.After(now) || !task.DueDate.Add(-offset).Equal(reminder.RemindAt) {
+		return false, nil
+	}
+
+	recipientID := task.AssigneeID
+	if recipientID == "" {
+		recipientID = task.UserID
+	}
+	user, err := rs.users.GetUserByID(ctx, recipientID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	dueDate := task.DueDate.UTC()
+	notification := entities.Notification{
+		Event:    entities.NotificationTaskReminder,
+		UserID:   recipientID,
+		Username: user.UserName,
+		Email:    user.Email,
+		TaskID:   reminder.TaskID,
+		Title:    task.Title,
+		DueDate:  dueDate,
+		Subject:  "Reminder: " + task.Title,
+		Message:  fmt.Sprintf("%q is due on %s.", task.Title, dueDate.Format("Mon, 02 Jan 2006 15:04 MST")),
+	}
+
+	return true, rs.notifier.Notify(ctx, notification)
+}
+
+// Run sends the due reminders right away and then every interval until ctx is
+// done. Failures are logged and retried at the next interval.
+func (rs *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := rs.SendDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Sending reminders failed", "error", err)
+		} else if sent > 0 {
+			slog.InfoContext(ctx, "Sent reminders", "count", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReminderScheduler(t *testing.T) {
+	now := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	ownerID := primitive.NewObjectID()
+	assigneeID := primitive.NewObjectID()
+	newTask := func() *entities.Task {
+		return &entities.Task{ID: primitive.NewObjectID(), UserID: ownerID.Hex(), Title: "Write report", Status: entities.StatusTodo,
+			DueDate: now.Add(time.Hour), Reminders: []string{"PT1H"}}
+	}
+	reminderFor := func(task *entities.Task) *entities.Reminder {
+		return &entities.Reminder{ID: primitive.NewObjectID(), TaskID: task.ID.Hex(), Offset: "PT1H", RemindAt: now, Attempts: 1}
+	}
+
+	t.Run("notifies the owner of the task", func(t *testing.T) {
+		task := newTask()
+		reminder := reminderFor(task)
+
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ClaimDueReminders", mock.Anything, now, mock.AnythingOfType("string"), mock.Anything, mock.Anything).Return([]*entities.Reminder{reminder}, nil).Once()
+		mockReminderRepository.On("MarkReminderSent", mock.Anything, reminder.ID.Hex(), now).Return(nil).Once()
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByID", mock.Anything, ownerID.Hex()).Return(&entities.User{ID: ownerID, UserName: "alice", Email: "alice@example.com"}, nil).Once()
+		mockNotifier := mocks.NewNotifier(t)
+		mockNotifier.On("Notify", mock.Anything, entities.Notification{
+			Event:    entities.NotificationTaskReminder,
+			UserID:   ownerID.Hex(),
+			Username: "alice",
+			Email:    "alice@example.com",
+			TaskID:   task.ID.Hex(),
+			Title:    "Write report",
+			DueDate:  now.Add(time.Hour),
+			Subject:  "Reminder: Write report",
+			Message:  `"Write report" is due on Mon, 30 Sep 2024 13:00 UTC.`,
+		}).Return(nil).Once()
+
+		scheduler := usecase.NewReminderScheduler(mockReminderRepository, mockTaskRepository, mockUserRepository, mockNotifier, time.Minute, clock)
+
+		sent, err := scheduler.SendDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+	})
+
+	t.Run("notifies the assignee instead of the owner", func(t *testing.T) {
+		task := newTask()
+		task.AssigneeID = assigneeID.Hex()
+		reminder := reminderFor(task)
+
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ClaimDueReminders", mock.Anything, now, mock.Anything, mock.Anything, mock.Anything).Return([]*entities.Reminder{reminder}, nil).Once()
+		mockReminderRepository.On("MarkReminderSent", mock.Anything, reminder.ID.Hex(), now).Return(nil).Once()
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByID", mock.Anything, assigneeID.Hex()).Return(&entities.User{ID: assigneeID, UserName: "bob"}, nil).Once()
+		mockNotifier := mocks.NewNotifier(t)
+		mockNotifier.On("Notify", mock.Anything, mock.MatchedBy(func(notification entities.Notification) bool {
+			return notification.UserID == assigneeID.Hex() && notification.Username == "bob"
+		})).Return(nil).Once()
+
+		scheduler := usecase.NewReminderScheduler(mockReminderRepository, mockTaskRepository, mockUserRepository, mockNotifier, time.Minute, clock)
+
+		sent, err := scheduler.SendDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+	})
+
+	stale := map[string]func(task *entities.Task){
+		"done task":        func(task *entities.Task) { task.Status = entities.StatusDone },
+		"rescheduled task": func(task *entities.Task) { task.DueDate = task.DueDate.Add(24 * time.Hour) },
+		"overdue task":     func(task *entities.Task) { task.DueDate = now.Add(-time.Minute) },
+	}
+	for name, change := range stale {
+		t.Run("marks reminders of a "+name+" sent without notifying", func(t *testing.T) {
+			task := newTask()
+			reminder := reminderFor(task)
+			change(task)
+
+			mockReminderRepository := mocks.NewReminderRepository(t)
+			mockReminderRepository.On("ClaimDueReminders", mock.Anything, now, mock.Anything, mock.Anything, mock.Anything).Return([]*entities.Reminder{reminder}, nil).Once()
+			mockReminderRepository.On("MarkReminderSent", mock.Anything, reminder.ID.Hex(), now).Return(nil).Once()
+			mockTaskRepository := mocks.NewTaskRepository(t)
+			mockTaskRepository.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+
+			scheduler := usecase.NewReminderScheduler(mockReminderRepository, mockTaskRepository, mocks.NewUserRepository(t), mocks.NewNotifier(t), time.Minute, clock)
+
+			sent, err := scheduler.SendDue(context.TODO())
+
+			assert.NoError(t, err)
+			assert.Equal(t, 0, sent)
+		})
+	}
+
+	t.Run("marks reminders of a deleted task sent", func(t *testing.T) {
+		task := newTask()
+		reminder := reminderFor(task)
+
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ClaimDueReminders", mock.Anything, now, mock.Anything, mock.Anything, mock.Anything).Return([]*entities.Reminder{reminder}, nil).Once()
+		mockReminderRepository.On("MarkReminderSent", mock.Anything, reminder.ID.Hex(), now).Return(nil).Once()
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(nil, entities.ErrTaskNotFound).Once()
+
+		scheduler := usecase.NewReminderScheduler(mockReminderRepository, mockTaskRepository, mocks.NewUserRepository(t), mocks.NewNotifier(t), time.Minute, clock)
+
+		sent, err := scheduler.SendDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("leaves failed reminders to be claimed again", func(t *testing.T) {
+		task := newTask()
+		reminder := reminderFor(task)
+
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ClaimDueReminders", mock.Anything, now, mock.Anything, mock.Anything, mock.Anything).Return([]*entities.Reminder{reminder}, nil).Once()
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByID", mock.Anything, ownerID.Hex()).Return(&entities.User{ID: ownerID, UserName: "alice"}, nil).Once()
+		mockNotifier := mocks.NewNotifier(t)
+		mockNotifier.On("Notify", mock.Anything, mock.Anything).Return(errors.New("smtp down")).Once()
+
+		scheduler := usecase.NewReminderScheduler(mockReminderRepository, mockTaskRepository, mockUserRepository, mockNotifier, time.Minute, clock)
+
+		sent, err := scheduler.SendDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("schedulers claim as different claimants", func(t *testing.T) {
+		var claimants []string
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ClaimDueReminders", mock.Anything, now, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			claimants = append(claimants, args.String(2))
+		}).Return([]*entities.Reminder{}, nil).Twice()
+
+		for i := 0; i < 2; i++ {
+			scheduler := usecase.NewReminderScheduler(mockReminderRepository, mocks.NewTaskRepository(t), mocks.NewUserRepository(t), mocks.NewNotifier(t), time.Minute, clock)
+			_, err := scheduler.SendDue(context.TODO())
+			require.NoError(t, err)
+		}
+
+		require.Len(t, claimants, 2)
+		assert.NotEqual(t, claimants[0], claimants[1])
+	})
+
+	t.Run("runs until cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ClaimDueReminders", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database down")).Once()
+		mockReminderRepository.On("ClaimDueReminders", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+			cancel()
+		}).Return([]*entities.Reminder{}, nil).Once()
+
+		scheduler := usecase.NewReminderScheduler(mockReminderRepository, mocks.NewTaskRepository(t), mocks.NewUserRepository(t), mocks.NewNotifier(t), time.Millisecond, clock)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			scheduler.Run(ctx)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("scheduler did not stop")
+		}
+	})
+}
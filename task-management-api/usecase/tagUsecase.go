@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+	"unicode/utf8"
+)
+
+// TagUsecase renames and deletes tags. The tasks it changes are reported
+// through tasks, the way task updates are.
+type TagUsecase struct {
+	TagRepository  entities.TagRepository
+	tasks          *TaskUsecase
+	contextTimeout time.Duration
+}
+
+func NewTagUsecase(tagRepository entities.TagRepository, activityRepository entities.ActivityRepository, projectRepository entities.ProjectRepository, webhookRepository entities.WebhookRepository) entities.TagUsecase {
+	return &TagUsecase{
+		TagRepository: tagRepository,
+		tasks: &TaskUsecase{
+			ActivityRepository: activityRepository,
+			ProjectRepository:  projectRepository,
+			WebhookRepository:  webhookRepository,
+		},
+		contextTimeout: 3 * time.Second,
+	}
+}
+
+func (uc *TagUsecase) GetTags(ctx context.Context, userID string) ([]*model.TagInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.TagRepository.GetTags(ctx, userID)
+}
+
+// RenameTag renames a tag on every task carrying it. Renaming a tag to itself
+// changes nothing.
+func (uc *TagUsecase) RenameTag(ctx context.Context, userID string, name string, newName string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if !isValidTagName(newName) {
+		return 0, entities.ErrInvalidTagName
+	}
+	if newName == name {
+		return 0, nil
+	}
+
+	tasks, err := uc.TagRepository.RenameTag(ctx, userID, name, newName)
+	if err != nil {
+		return 0, err
+	}
+	if len(tasks) == 0 {
+		return 0, entities.ErrTagNotFound
+	}
+
+	uc.publishTagChanges(ctx, tasks, userID, func(tags []string) []string {
+		renamed := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if tag == name {
+				if slices.Contains(tags, newName) {
+					continue
+				}
+				tag = newName
+			}
+			renamed = append(renamed, tag)
+		}
+		return renamed
+	})
+
+	return int64(len(tasks)), nil
+}
+
+func (uc *TagUsecase) DeleteTag(ctx context.Context, userID string, name string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	tasks, err := uc.TagRepository.DeleteTag(ctx, userID, name)
+	if err != nil {
+		return 0, err
+	}
+	if len(tasks) == 0 {
+		return 0, entities.ErrTagNotFound
+	}
+
+	uc.publishTagChanges(ctx, tasks, userID, func(tags []string) []string {
+		return slices.DeleteFunc(slices.Clone(tags), func(tag string) bool { return tag == name })
+	})
+
+	return int64(len(tasks)), nil
+}
+
+// publishTagChanges records an update of the tags of each task changed, as
+// retag changes them, in the activity of the task and sends it to the
+// webhooks of its readers as task.updated.
+func (uc *TagUsecase) publishTagChanges(ctx context.Context, tasks []*entities.Task, userID string, retag func(tags []string) []string) {
+	for _, task := range tasks {
+		changedTask := *task
+		changedTask.Tags = retag(task.Tags)
+		changes := uc.tasks.recordUpdate(ctx, task.ID.Hex(), *task, changedTask, []string{entities.TaskFieldTags}, userID)
+
+		changedTask.UpdatedAt = time.Now()
+		changedTask.Version++
+		uc.tasks.publishUpdate(ctx, *task, changedTask, changes, userID)
+	}
+}
+
+func isValidTagName(name string) bool {
+	return strings.TrimSpace(name) != "" && utf8.RuneCountInString(name) <= entities.MaxTagLength
+}
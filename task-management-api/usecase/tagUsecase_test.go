@@ -0,0 +1,170 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/domain/model"
+	"task-management-api/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetTags(t *testing.T) {
+	mockTagRepository := new(mocks.TagRepository)
+	expectedTags := []*model.TagInfo{{Name: "home", Count: 1}, {Name: "work", Count: 3}}
+
+	mockTagRepository.On("GetTags", mock.Anything, "testUserID").Return(expectedTags, nil).Once()
+
+	tuc := usecase.NewTagUsecase(mockTagRepository, ignoreActivity(), new(mocks.ProjectRepository), noWebhooks())
+
+	tags, err := tuc.GetTags(context.TODO(), "testUserID")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTags, tags)
+	mockTagRepository.AssertExpectations(t)
+}
+
+func TestRenameTag(t *testing.T) {
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		mockTagRepository := new(mocks.TagRepository)
+		mockTagRepository.On("RenameTag", mock.Anything, userID, "work", "office").Return([]*entities.Task{
+			{ID: primitive.NewObjectID(), UserID: userID, Tags: []string{"home", "work"}, Version: 1},
+			{ID: primitive.NewObjectID(), UserID: userID, Tags: []string{"office", "work"}, Version: 4},
+		}, nil).Once()
+
+		var activities []entities.Activity
+		mockActivityRepository := new(mocks.ActivityRepository)
+		mockActivityRepository.On("AddActivity", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			activities = append(activities, args.Get(1).(entities.Activity))
+		}).Return(nil).Twice()
+
+		webhook := &entities.Webhook{ID: primitive.NewObjectID(), UserID: userID}
+		var payloads []model.WebhookPayload
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetEventWebhooks", mock.Anything, entities.EventTaskUpdated, []string{userID}).Return([]*entities.Webhook{webhook}, nil).Twice()
+		mockWebhookRepository.On("AddDeliveries", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			for _, delivery := range args.Get(1).([]entities.WebhookDelivery) {
+				var payload model.WebhookPayload
+				require.NoError(t, json.Unmarshal([]byte(delivery.Payload), &payload))
+				payloads = append(payloads, payload)
+			}
+		}).Return(nil).Twice()
+
+		tuc := usecase.NewTagUsecase(mockTagRepository, mockActivityRepository, new(mocks.ProjectRepository), mockWebhookRepository)
+
+		renamed, err := tuc.RenameTag(context.TODO(), userID, "work", "office")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), renamed)
+		require.Len(t, activities, 2)
+		for _, activity := range activities {
+			assert.Equal(t, userID, activity.ActorID)
+			assert.Equal(t, entities.ActivityUpdated, activity.Action)
+		}
+		assert.Equal(t, []entities.FieldChange{{Field: "tags", From: `["home","work"]`, To: `["home","office"]`}}, activities[0].Changes)
+		assert.Equal(t, []entities.FieldChange{{Field: "tags", From: `["office","work"]`, To: `["office"]`}}, activities[1].Changes)
+		require.Len(t, payloads, 2)
+		assert.Equal(t, entities.EventTaskUpdated, payloads[0].Event)
+		assert.Equal(t, []string{"home", "office"}, payloads[0].Task.Tags)
+		assert.Equal(t, int64(2), payloads[0].Task.Version)
+		assert.Equal(t, json.RawMessage(`["home","office"]`), payloads[0].Changes[0].To)
+		assert.Equal(t, int64(5), payloads[1].Task.Version)
+		mockTagRepository.AssertExpectations(t)
+		mockActivityRepository.AssertExpectations(t)
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		mockTagRepository := new(mocks.TagRepository)
+		tuc := usecase.NewTagUsecase(mockTagRepository, ignoreActivity(), new(mocks.ProjectRepository), noWebhooks())
+
+		renamed, err := tuc.RenameTag(context.TODO(), userID, "work", "work")
+
+		assert.NoError(t, err)
+		assert.Zero(t, renamed)
+		mockTagRepository.AssertNotCalled(t, "RenameTag")
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		for _, name := range []string{"", "  ", strings.Repeat("x", entities.MaxTagLength+1)} {
+			mockTagRepository := new(mocks.TagRepository)
+			tuc := usecase.NewTagUsecase(mockTagRepository, ignoreActivity(), new(mocks.ProjectRepository), noWebhooks())
+
+			_, err := tuc.RenameTag(context.TODO(), userID, "work", name)
+
+			assert.ErrorIs(t, err, entities.ErrInvalidTagName)
+			mockTagRepository.AssertNotCalled(t, "RenameTag")
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockTagRepository := new(mocks.TagRepository)
+		mockTagRepository.On("RenameTag", mock.Anything, userID, "missing", "office").Return([]*entities.Task{}, nil).Once()
+
+		tuc := usecase.NewTagUsecase(mockTagRepository, ignoreActivity(), new(mocks.ProjectRepository), noWebhooks())
+
+		_, err := tuc.RenameTag(context.TODO(), userID, "missing", "office")
+
+		assert.ErrorIs(t, err, entities.ErrTagNotFound)
+		mockTagRepository.AssertExpectations(t)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockTagRepository := new(mocks.TagRepository)
+		expectedErr := errors.New("repository error")
+		mockTagRepository.On("RenameTag", mock.Anything, userID, "work", "office").Return(nil, expectedErr).Once()
+
+		tuc := usecase.NewTagUsecase(mockTagRepository, ignoreActivity(), new(mocks.ProjectRepository), noWebhooks())
+
+		_, err := tuc.RenameTag(context.TODO(), userID, "work", "office")
+
+		assert.Equal(t, expectedErr, err)
+		mockTagRepository.AssertExpectations(t)
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		taskID := primitive.NewObjectID()
+		mockTagRepository := new(mocks.TagRepository)
+		mockTagRepository.On("DeleteTag", mock.Anything, userID, "work").Return([]*entities.Task{{ID: taskID, Tags: []string{"home", "work"}}}, nil).Once()
+
+		mockActivityRepository := new(mocks.ActivityRepository)
+		mockActivityRepository.On("AddActivity", mock.Anything, mock.MatchedBy(func(activity entities.Activity) bool {
+			return activity.TaskID == taskID.Hex() && activity.ActorID == userID && activity.Action == entities.ActivityUpdated &&
+				assert.ObjectsAreEqual([]entities.FieldChange{{Field: "tags", From: `["home","work"]`, To: `["home"]`}}, activity.Changes)
+		})).Return(nil).Once()
+
+		tuc := usecase.NewTagUsecase(mockTagRepository, mockActivityRepository, new(mocks.ProjectRepository), noWebhooks())
+
+		deleted, err := tuc.DeleteTag(context.TODO(), userID, "work")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		mockTagRepository.AssertExpectations(t)
+		mockActivityRepository.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockTagRepository := new(mocks.TagRepository)
+		mockTagRepository.On("DeleteTag", mock.Anything, userID, "missing").Return([]*entities.Task{}, nil).Once()
+
+		tuc := usecase.NewTagUsecase(mockTagRepository, ignoreActivity(), new(mocks.ProjectRepository), noWebhooks())
+
+		_, err := tuc.DeleteTag(context.TODO(), userID, "missing")
+
+		assert.ErrorIs(t, err, entities.ErrTagNotFound)
+		mockTagRepository.AssertExpectations(t)
+	})
+}
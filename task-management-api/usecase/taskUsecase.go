@@ -2,92 +2,1288 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/model"
+	"task-management-api/logging"
 
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type TaskUsecase struct {
-	TaskRepository entities.TaskRepository
-	contextTimeout time.Duration
+	TaskRepository     entities.TaskRepository
+	ActivityRepository entities.ActivityRepository
+	UserRepository     entities.UserRepository
+	ProjectRepository  entities.ProjectRepository
+	ReminderRepository entities.ReminderRepository
+	WebhookRepository  entities.WebhookRepository
+	contextTimeout     time.Duration
 }
 
-func NewTaskUsecase(taskRepository entities.TaskRepository) entities.TaskUsecase {
+func NewTaskUsecase(taskRepository entities.TaskRepository, activityRepository entities.ActivityRepository, userRepository entities.UserRepository, projectRepository entities.ProjectRepository, reminderRepository entities.ReminderRepository, webhookRepository entities.WebhookRepository) entities.TaskUsecase {
 	return &TaskUsecase{
-		TaskRepository: taskRepository,
-		contextTimeout: 3 * time.Second,
+		TaskRepository:     taskRepository,
+		ActivityRepository: activityRepository,
+		UserRepository:     userRepository,
+		ProjectRepository:  projectRepository,
+		ReminderRepository: reminderRepository,
+		WebhookRepository:  webhookRepository,
+		contextTimeout:     3 * time.Second,
 	}
 }
 
-func (uc *TaskUsecase) GetTasks(userID string) ([]*model.TaskInfo, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (uc *TaskUsecase) GetTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+    ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
     defer cancel()
 
-    tasks, err := uc.TaskRepository.GetTasks(ctx, userID)
+	query, err := normalizeTaskQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+    page, err := uc.TaskRepository.GetTasks(ctx, userID, query)
     if err != nil {
         return nil, err
     }
 
-    var taskInfos []*model.TaskInfo
-    for _, task := range tasks {
-        taskInfos = append(taskInfos, &model.TaskInfo{
-            Title:       task.Title,
-            Description: task.Description,
-            DueDate:     (time.Now()).Add(3 * 24 * time.Hour).Format(time.RFC3339),
-        })
-    }
+    return page, nil
+}
+
+func (uc *TaskUsecase) GetAssignedTasks(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	query, err := normalizeTaskQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	query.AssigneeID = userID
+
+	return uc.TaskRepository.GetTasks(ctx, userID, query)
+}
+
+func (uc *TaskUsecase) GetProjectTasks(ctx context.Context, projectID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	query, err := normalizeTaskQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	query.ProjectID = projectID
+
+	return uc.TaskRepository.GetTasks(ctx, "", query)
+}
+
+func (uc *TaskUsecase) GetTrash(ctx context.Context, userID string, query model.TaskQuery) (*model.TaskPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	query, err := normalizeTaskQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.TaskRepository.GetDeletedTasks(ctx, userID, query)
+}
+
+// normalizeTaskQuery validates the filters and sort order of a task listing
+// and clamps its page size.
+func normalizeTaskQuery(query model.TaskQuery) (model.TaskQuery, error) {
+	if query.Status != "" && !entities.IsValidTaskStatus(query.Status) {
+		return query, entities.ErrInvalidStatus
+	}
+	if _, err := entities.ParsePriority(query.Priority); err != nil {
+		return query, err
+	}
+	if _, _, err := entities.ParseTaskSort(query.Sort); err != nil {
+		return query, err
+	}
+
+	switch query.TagMatch {
+	case "":
+		query.TagMatch = entities.TagMatchAny
+	case entities.TagMatchAny, entities.TagMatchAll:
+	default:
+		return query, entities.ErrInvalidTagMatch
+	}
+	query.Tags = uniqueTags(query.Tags)
 
-    return taskInfos, nil
+	if query.Limit <= 0 {
+		query.Limit = model.DefaultTaskPageSize
+	}
+	if query.Limit > model.MaxTaskPageSize {
+		query.Limit = model.MaxTaskPageSize
+	}
+
+	return query, nil
 }
 
 
-func (uc *TaskUsecase) GetTaskByID(id string, userID string) (*model.TaskInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+// uniqueTags drops repeated tags, keeping the first occurrence of each.
+func uniqueTags(tags []string) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			unique = append(unique, tag)
+		}
+	}
+
+	return unique
+}
+
+func (uc *TaskUsecase) GetTaskByID(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	taskEntity, err := uc.TaskRepository.GetTaskByID(ctx, id, userID)
+	taskEntity, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return taskEntity.Info(), nil
+}
+
+// accessTask reads a task the user has at least the given role on. Tasks the
+// user has no role on are reported as missing, so that users cannot find out
+// which tasks exist.
+func accessTask(ctx context.Context, taskRepository entities.TaskRepository, projectRepository entities.ProjectRepository, id string, userID string, role string) (*entities.Task, error) {
+	task, err := taskRepository.GetTaskByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	taskInfo := &model.TaskInfo{
-		Title:       taskEntity.Title,
-		Description: taskEntity.Description,
+	userRole, err := taskRoleOf(ctx, projectRepository, task, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == "" {
+		return nil, entities.ErrTaskNotFound
+	}
+	if !entities.RoleAllows(userRole, role) {
+		return nil, entities.ErrTaskForbidden
+	}
+
+	return task, nil
+}
+
+// taskRoleOf returns the role of a user on a task, including the one they get
+// as a member of the task's project. The project is only read when it could
+// raise the role.
+func taskRoleOf(ctx context.Context, projectRepository entities.ProjectRepository, task *entities.Task, userID string) (string, error) {
+	role := task.RoleOf(userID)
+	if task.ProjectID == "" || entities.RoleAllows(role, entities.TaskRoleEditor) {
+		return role, nil
+	}
+
+	project, err := projectRepository.GetProjectByID(ctx, task.ProjectID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return role, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return entities.HigherRole(role, project.TaskRoleOf(userID)), nil
+}
+
+// taskReaders returns the users who can read a task: its owner,
+// collaborators and assignee, and the owner and members of its project.
+func taskReaders(ctx context.Context, projectRepository entities.ProjectRepository, task *entities.Task) ([]string, error) {
+	readers := []string{task.UserID}
+	for _, collaborator := range task.Collaborators {
+		readers = append(readers, collaborator.UserID)
+	}
+	if task.AssigneeID != "" {
+		readers = append(readers, task.AssigneeID)
+	}
+
+	if task.ProjectID != "" {
+		project, err := projectRepository.GetProjectByID(ctx, task.ProjectID)
+		if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			readers = append(readers, project.OwnerID)
+			for _, member := range project.Members {
+				readers = append(readers, member.UserID)
+			}
+		}
+	}
+
+	slices.Sort(readers)
+	return slices.Compact(readers), nil
+}
+
+func (uc *TaskUsecase) UpdateTask(ctx context.Context, id string, patch model.TaskPatch, userID string, version int64) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	currentTask, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	// The repository checks the version again when writing, which catches
+	// updates made since the task was read here.
+	if version == entities.AnyTaskVersion {
+		version = currentTask.Version
+	} else if version != currentTask.Version {
+		return nil, entities.ErrTaskModified
+	}
+
+	updatedTask, fields, err := applyTaskPatch(*currentTask, patch)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return currentTask.Info(), nil
+	}
+
+	if updatedTask.Status != currentTask.Status && !entities.CanTransitionTask(currentTask.Status, updatedTask.Status) {
+		return nil, entities.ErrInvalidStatusTransition
+	}
+
+	if updatedTask.Status == entities.StatusDone && currentTask.Status != entities.StatusDone {
+		if err := uc.checkBlockersDone(ctx, currentTask); err != nil {
+			return nil, err
+		}
+
+		completedAt := time.Now()
+		updatedTask.CompletedAt = &completedAt
+	} else if updatedTask.Status != entities.StatusDone {
+		updatedTask.CompletedAt = nil
+	}
+
+	err = uc.TaskRepository.UpdateTask(ctx, id, updatedTask, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := uc.recordUpdate(ctx, id, *currentTask, updatedTask, fields, userID)
+	uc.rescheduleReminders(ctx, *currentTask, updatedTask, fields)
+
+	updatedTask.UpdatedAt = time.Now()
+	updatedTask.Version++
+	uc.publishUpdate(ctx, *currentTask, updatedTask, changes, userID)
+
+	// The task is already done, so a failure is logged instead of failing the
+	// request. Reopening and completing the task again retries.
+	if updatedTask.Status == entities.StatusDone && currentTask.Status != entities.StatusDone && updatedTask.Recurrence != "" {
+		if err := uc.createNextOccurrence(ctx, updatedTask, userID); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "creating the next occurrence failed", "task_id", id, "series_id", updatedTask.SeriesID, "error", err)
+		}
+	}
+
+	return updatedTask.Info(), nil
+}
+
+// createNextOccurrence creates the occurrence following task in its series,
+// unless the rule has ended. Only the latest occurrence spawns the next one,
+// so that completing an earlier occurrence again does not fork the series.
+func (uc *TaskUsecase) createNextOccurrence(ctx context.Context, task entities.Task, userID string) error {
+	recurrence, err := entities.ParseRecurrence(task.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	dueDate, ok := recurrence.Next(task.DueDate, task.Occurrence)
+	if !ok {
+		return nil
+	}
+
+	occurrences, err := uc.TaskRepository.GetSeriesTasks(ctx, task.SeriesID)
+	if err != nil {
+		return err
+	}
+	for _, occurrence := range occurrences {
+		if occurrence.Occurrence > task.Occurrence {
+			return nil
+		}
+	}
+
+	now := time.Now()
+	next := task
+	next.ID = primitive.NewObjectID()
+	next.Status = entities.StatusTodo
+	next.CompletedAt = nil
+	next.DueDate = dueDate
+	next.Occurrence = task.Occurrence + 1
+	next.BlockedBy = nil
+	next.DeletedAt = nil
+	next.Version = 0
+	next.CreatedAt = now
+	next.UpdatedAt = now
+
+	if err := uc.TaskRepository.CreateTask(ctx, next); err != nil {
+		return err
+	}
+
+	uc.recordActivity(ctx, entities.Activity{TaskID: next.ID.Hex(), ActorID: userID, Action: entities.ActivityCreated})
+	if len(next.Reminders) > 0 {
+		uc.scheduleReminders(ctx, next)
+	}
+	uc.publishEvent(ctx, entities.EventTaskCreated, next, nil, userID)
+	return nil
+}
+
+// normalizeRecurrence validates a recurrence rule and returns its canonical
+// form. An empty rule stays empty.
+func normalizeRecurrence(rule string) (string, error) {
+	if rule == "" {
+		return "", nil
+	}
+
+	recurrence, err := entities.ParseRecurrence(rule)
+	if err != nil {
+		return "", err
+	}
+
+	return recurrence.String(), nil
+}
+
+// applyTaskPatch merges a JSON merge patch into task and validates the
+// resulting task. It also returns the fields the patch touched.
+func applyTaskPatch(task entities.Task, patch model.TaskPatch) (entities.Task, []string, error) {
+	var fields []string
+	touch := func(field string, set bool) {
+		if set {
+			fields = append(fields, field)
+		}
+	}
+
+	task.Title = patch.Title.Merge(task.Title)
+	touch(entities.TaskFieldTitle, patch.Title.Set)
+	task.Description = patch.Description.Merge(task.Description)
+	touch(entities.TaskFieldDescription, patch.Description.Set)
+	task.Status = patch.Status.Merge(task.Status)
+	touch(entities.TaskFieldStatus, patch.Status.Set)
+	task.Tags = patch.Tags.Merge(task.Tags)
+	touch(entities.TaskFieldTags, patch.Tags.Set)
+	task.DueDate = patch.DueDate.Merge(task.DueDate)
+	touch(entities.TaskFieldDueDate, patch.DueDate.Set)
+
+	if patch.Recurrence.Set {
+		recurrence, err := normalizeRecurrence(patch.Recurrence.Merge(""))
+		if err != nil {
+			return task, nil, err
+		}
+		task.Recurrence = recurrence
+		touch(entities.TaskFieldRecurrence, true)
+
+		// A task given its first rule starts a series. A task whose rule is
+		// removed stays in its series.
+		if recurrence != "" && task.SeriesID == "" {
+			task.SeriesID = task.ID.Hex()
+			task.Occurrence = 1
+		}
+	}
+
+	if patch.Reminders.Set {
+		reminders, err := entities.NormalizeReminders(patch.Reminders.Merge(nil))
+		if err != nil {
+			return task, nil, err
+		}
+		task.Reminders = reminders
+		touch(entities.TaskFieldReminders, true)
 	}
 
-	return taskInfo, nil
+	if patch.Priority.Set {
+		priority, err := entities.ParsePriority(patch.Priority.Merge(""))
+		if err != nil {
+			return task, nil, err
+		}
+		task.Priority = priority
+		touch(entities.TaskFieldPriority, true)
+	}
+
+	// Removing a member that every task needs leaves an invalid task.
+	if task.Title == "" {
+		return task, nil, entities.ErrTaskTitleRequired
+	}
+	if patch.Status.Set && !entities.IsValidTaskStatus(task.Status) {
+		return task, nil, entities.ErrInvalidStatus
+	}
+	if patch.Priority.Set && !task.Priority.IsValid() {
+		return task, nil, entities.ErrInvalidPriority
+	}
+	if task.Recurrence != "" && task.DueDate.IsZero() {
+		return task, nil, entities.ErrRecurrenceDueDate
+	}
+
+	return task, fields, nil
 }
 
-func (uc *TaskUsecase) UpdateTask(id string, updatedTask entities.Task, userID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (uc *TaskUsecase) DeleteTask(ctx context.Context, id string, userID string, version int64) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	err := uc.TaskRepository.UpdateTask(ctx, id, updatedTask, userID)
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleOwner)
 	if err != nil {
 		return err
 	}
+
+	err = uc.TaskRepository.DeleteTask(ctx, id, version)
+	if err != nil {
+		return err
+	}
+
+	uc.recordActivity(ctx, entities.Activity{TaskID: id, ActorID: userID, Action: entities.ActivityDeleted})
+
+	// Tasks in the trash are not reminded of; RestoreTask schedules their
+	// reminders again.
+	if len(task.Reminders) > 0 {
+		trashedTask := *task
+		trashedTask.Reminders = nil
+		uc.scheduleReminders(ctx, trashedTask)
+	}
+
+	deletedAt := time.Now()
+	task.DeletedAt = &deletedAt
+	uc.publishEvent(ctx, entities.EventTaskDeleted, *task, nil, userID)
 	return nil
 }
 
-func (uc *TaskUsecase) DeleteTask(id string, userID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (uc *TaskUsecase) RestoreTask(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	err := uc.TaskRepository.DeleteTask(ctx, id, userID)
+	if err := uc.TaskRepository.RestoreTask(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	uc.recordActivity(ctx, entities.Activity{TaskID: id, ActorID: userID, Action: entities.ActivityRestored})
+
+	task, err := uc.TaskRepository.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(task.Reminders) > 0 {
+		uc.scheduleReminders(ctx, *task)
+	}
+	uc.publishEvent(ctx, entities.EventTaskUpdated, *task, nil, userID)
+
+	return task.Info(), nil
+}
+
+func (uc *TaskUsecase) GetTaskTree(ctx context.Context, id string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.taskTree(ctx, id, task, userID, map[string]bool{})
+}
+
+// taskTree nests the subtasks of task below it and rolls their progress up.
+// Links are kept free of cycles, so the recursion ends at the leaves; seen
+// holds the tasks above, and a task met again is left out rather than nested
+// without end. Subtasks can be shared apart from their parent, so those the
+// user cannot read are left out of the tree and of the progress.
+func (uc *TaskUsecase) taskTree(ctx context.Context, id string, task *entities.Task, userID string, seen map[string]bool) (*model.TaskInfo, error) {
+	info := task.Info()
+	seen[id] = true
+	defer delete(seen, id)
+
+	subtasks, err := uc.TaskRepository.GetSubtasks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	readable := make([]*entities.Task, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		role, err := taskRoleOf(ctx, uc.ProjectRepository, subtask, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role != "" {
+			readable = append(readable, subtask)
+		}
+	}
+
+	progress := 0
+	if len(readable) == 0 && task.Status == entities.StatusDone {
+		progress = 100
+	}
+
+	if len(readable) > 0 {
+		total := 0
+		for _, subtask := range readable {
+			if seen[subtask.ID.Hex()] {
+				continue
+			}
+
+			subtaskInfo, err := uc.taskTree(ctx, subtask.ID.Hex(), subtask, userID, seen)
+			if err != nil {
+				return nil, err
+			}
+
+			info.Subtasks = append(info.Subtasks, subtaskInfo)
+			total += *subtaskInfo.Progress
+		}
+		progress = total / len(readable)
+	}
+
+	info.Progress = &progress
+	return info, nil
+}
+
+func (uc *TaskUsecase) SetTaskParent(ctx context.Context, id string, parentID string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != "" {
+		if err := uc.checkNotAncestor(ctx, id, parentID, task.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if task.ParentID == parentID {
+		return task.Info(), nil
+	}
+
+	linkedTask := *task
+	linkedTask.ParentID = parentID
+	return uc.updateTaskFields(ctx, id, *task, linkedTask, []string{entities.TaskFieldParentID}, userID)
+}
+
+// checkNotAncestor walks up from parentID and fails with ErrTaskCycle if it
+// meets the task with the given id, which cannot become a subtask of its own
+// subtask. The parent must belong to the owner of the task and be outside the
+// trash, but ancestors in the trash are followed: restoring them brings their
+// links back.
+func (uc *TaskUsecase) checkNotAncestor(ctx context.Context, id string, parentID string, ownerID string) error {
+	if parentID == id {
+		return entities.ErrTaskCycle
+	}
+
+	parent, err := uc.TaskRepository.GetTaskByID(ctx, parentID)
+	if err == nil && parent.UserID != ownerID {
+		err = entities.ErrTaskNotFound
+	}
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return entities.ErrTaskParentNotFound
+	}
 	if err != nil {
 		return err
 	}
+
+	seen := map[string]bool{parentID: true}
+	for ancestorID := parent.ParentID; ancestorID != "" && !seen[ancestorID]; {
+		if ancestorID == id {
+			return entities.ErrTaskCycle
+		}
+		seen[ancestorID] = true
+
+		ancestor, err := uc.TaskRepository.GetTaskIncludingDeleted(ctx, ancestorID)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ancestorID = ancestor.ParentID
+	}
+
+	return nil
+}
+
+func (uc *TaskUsecase) AddTaskBlocker(ctx context.Context, id string, blockerID string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if blockerID == id {
+		return nil, entities.ErrTaskCycle
+	}
+	for _, existing := range task.BlockedBy {
+		if existing == blockerID {
+			return task.Info(), nil
+		}
+	}
+
+	// Like the parent, blockers must belong to the owner of the task.
+	blocker, err := uc.TaskRepository.GetTaskByID(ctx, blockerID)
+	if err == nil && blocker.UserID != task.UserID {
+		err = entities.ErrTaskNotFound
+	}
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, entities.ErrTaskBlockerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkNotBlockedBy(ctx, blocker, id); err != nil {
+		return nil, err
+	}
+
+	linkedTask := *task
+	linkedTask.BlockedBy = append(append([]string{}, task.BlockedBy...), blockerID)
+	sort.Strings(linkedTask.BlockedBy)
+
+	return uc.updateTaskFields(ctx, id, *task, linkedTask, []string{entities.TaskFieldBlockedBy}, userID)
+}
+
+// checkNotBlockedBy follows the blockers of blocker and fails with
+// ErrTaskCycle if it meets the task with the given id, which would end up
+// waiting on itself. Blockers in the trash are followed too, as restoring them
+// brings their links back.
+func (uc *TaskUsecase) checkNotBlockedBy(ctx context.Context, blocker *entities.Task, id string) error {
+	seen := map[string]bool{}
+	pending := [][]string{blocker.BlockedBy}
+
+	for len(pending) > 0 {
+		blockerIDs := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		for _, blockerID := range blockerIDs {
+			if blockerID == id {
+				return entities.ErrTaskCycle
+			}
+			if seen[blockerID] {
+				continue
+			}
+			seen[blockerID] = true
+
+			next, err := uc.TaskRepository.GetTaskIncludingDeleted(ctx, blockerID)
+			if errors.Is(err, apperrors.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			pending = append(pending, next.BlockedBy)
+		}
+	}
+
+	return nil
+}
+
+func (uc *TaskUsecase) RemoveTaskBlocker(ctx context.Context, id string, blockerID string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockedBy []string
+	for _, existing := range task.BlockedBy {
+		if existing != blockerID {
+			blockedBy = append(blockedBy, existing)
+		}
+	}
+	if len(blockedBy) == len(task.BlockedBy) {
+		return nil, entities.ErrTaskBlockerNotFound
+	}
+
+	linkedTask := *task
+	linkedTask.BlockedBy = blockedBy
+	return uc.updateTaskFields(ctx, id, *task, linkedTask, []string{entities.TaskFieldBlockedBy}, userID)
+}
+
+// updateTaskFields writes the given fields of changedTask, a changed copy of
+// task, at the version task was read at.
+func (uc *TaskUsecase) updateTaskFields(ctx context.Context, id string, task entities.Task, changedTask entities.Task, fields []string, userID string) (*model.TaskInfo, error) {
+	if err := uc.TaskRepository.UpdateTask(ctx, id, changedTask, fields); err != nil {
+		return nil, err
+	}
+
+	changes := uc.recordUpdate(ctx, id, task, changedTask, fields, userID)
+	uc.rescheduleReminders(ctx, task, changedTask, fields)
+
+	changedTask.UpdatedAt = time.Now()
+	changedTask.Version++
+	uc.publishUpdate(ctx, task, changedTask, changes, userID)
+
+	return changedTask.Info(), nil
+}
+
+// checkBlockersDone fails with ErrTaskBlocked while a task blocking task is
+// still open. Blockers in the trash no longer block.
+func (uc *TaskUsecase) checkBlockersDone(ctx context.Context, task *entities.Task) error {
+	for _, blockerID := range task.BlockedBy {
+		blocker, err := uc.TaskRepository.GetTaskByID(ctx, blockerID)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if blocker.Status != entities.StatusDone {
+			return entities.ErrTaskBlocked
+		}
+	}
+
 	return nil
 }
 
-func (uc *TaskUsecase) CreateTask(newTask entities.Task) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (uc *TaskUsecase) CreateTask(ctx context.Context, newTask entities.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	err := uc.TaskRepository.CreateTask(ctx, newTask)
+	if newTask.Status == "" {
+		newTask.Status = entities.StatusTodo
+	}
+	if !entities.IsValidTaskStatus(newTask.Status) {
+		return entities.ErrInvalidStatus
+	}
+
+	if newTask.Priority == 0 {
+		newTask.Priority = entities.PriorityMedium
+	}
+	if !newTask.Priority.IsValid() {
+		return entities.ErrInvalidPriority
+	}
+
+	newTask.CompletedAt = nil
+	if newTask.Status == entities.StatusDone {
+		completedAt := time.Now()
+		newTask.CompletedAt = &completedAt
+	}
+
+	recurrence, err := normalizeRecurrence(newTask.Recurrence)
+	if err != nil {
+		return err
+	}
+	if recurrence != "" && newTask.DueDate.IsZero() {
+		return entities.ErrRecurrenceDueDate
+	}
+	newTask.Recurrence = recurrence
+
+	newTask.Reminders, err = entities.NormalizeReminders(newTask.Reminders)
 	if err != nil {
 		return err
 	}
+
+	if newTask.ProjectID != "" {
+		if _, err := uc.activeProject(ctx, newTask.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	// The ID is chosen here so the activity log and the series can refer to
+	// the task.
+	if newTask.ID.IsZero() {
+		newTask.ID = primitive.NewObjectID()
+	}
+	newTask.SeriesID, newTask.Occurrence = "", 0
+	if newTask.Recurrence != "" {
+		newTask.SeriesID, newTask.Occurrence = newTask.ID.Hex(), 1
+	}
+
+	err = uc.TaskRepository.CreateTask(ctx, newTask)
+	if err != nil {
+		return err
+	}
+
+	uc.recordActivity(ctx, entities.Activity{TaskID: newTask.ID.Hex(), ActorID: newTask.UserID, Action: entities.ActivityCreated})
+	if len(newTask.Reminders) > 0 {
+		uc.scheduleReminders(ctx, newTask)
+	}
+	// The repository stamps the stored task; the event carries the same
+	// moment, give or take the write.
+	created := newTask
+	created.CreatedAt = time.Now()
+	created.UpdatedAt = created.CreatedAt
+	uc.publishEvent(ctx, entities.EventTaskCreated, created, nil, newTask.UserID)
 	return nil
 }
+
+func (uc *TaskUsecase) GetTaskActivity(ctx context.Context, id string, userID string, query model.PageQuery) (*model.ActivityPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.ActivityRepository.GetActivity(ctx, id, normalizePageQuery(query))
+}
+
+func (uc *TaskUsecase) ShareTask(ctx context.Context, id string, username string, role string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if !entities.IsValidCollaboratorRole(role) {
+		return nil, entities.ErrInvalidTaskRole
+	}
+
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.UserRepository.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	collaboratorID := user.ID.Hex()
+
+	if collaboratorID == task.UserID {
+		return nil, entities.ErrShareWithOwner
+	}
+	if task.RoleOf(collaboratorID) == role {
+		return task.Info(), nil
+	}
+
+	// Collaborators are kept in order of their IDs, as the SQL backend reads
+	// them back.
+	sharedTask := *task
+	sharedTask.Collaborators = []entities.Collaborator{{UserID: collaboratorID, Role: role}}
+	for _, collaborator := range task.Collaborators {
+		if collaborator.UserID != collaboratorID {
+			sharedTask.Collaborators = append(sharedTask.Collaborators, collaborator)
+		}
+	}
+	sort.Slice(sharedTask.Collaborators, func(i, j int) bool {
+		return sharedTask.Collaborators[i].UserID < sharedTask.Collaborators[j].UserID
+	})
+
+	return uc.updateTaskFields(ctx, id, *task, sharedTask, []string{entities.TaskFieldCollaborators}, userID)
+}
+
+func (uc *TaskUsecase) UnshareTask(ctx context.Context, id string, username string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.UserRepository.GetUserByUsername(ctx, username)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, entities.ErrCollaboratorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	collaboratorID := user.ID.Hex()
+
+	var collaborators []entities.Collaborator
+	for _, collaborator := range task.Collaborators {
+		if collaborator.UserID != collaboratorID {
+			collaborators = append(collaborators, collaborator)
+		}
+	}
+	if len(collaborators) == len(task.Collaborators) {
+		return nil, entities.ErrCollaboratorNotFound
+	}
+
+	unsharedTask := *task
+	unsharedTask.Collaborators = collaborators
+	fields := []string{entities.TaskFieldCollaborators}
+	if task.AssigneeID == collaboratorID {
+		unsharedTask.AssigneeID = ""
+		fields = append(fields, entities.TaskFieldAssigneeID)
+	}
+
+	return uc.updateTaskFields(ctx, id, *task, unsharedTask, fields, userID)
+}
+
+func (uc *TaskUsecase) SetTaskAssignee(ctx context.Context, id string, username string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	assigneeID := ""
+	if username != "" {
+		user, err := uc.UserRepository.GetUserByUsername(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+
+		assigneeID = user.ID.Hex()
+		role, err := taskRoleOf(ctx, uc.ProjectRepository, task, assigneeID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, entities.ErrAssigneeCannotAccess
+		}
+	}
+
+	if task.AssigneeID == assigneeID {
+		return task.Info(), nil
+	}
+
+	assignedTask := *task
+	assignedTask.AssigneeID = assigneeID
+	return uc.updateTaskFields(ctx, id, *task, assignedTask, []string{entities.TaskFieldAssigneeID}, userID)
+}
+
+func (uc *TaskUsecase) MoveTask(ctx context.Context, id string, projectID string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	// Moving a task shares it with the members of the project, so only its
+	// owner can do so.
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.activeProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if task.ProjectID == projectID {
+		return task.Info(), nil
+	}
+	if err := uc.checkLeavingProject(ctx, task); err != nil {
+		return nil, err
+	}
+
+	movedTask := *task
+	movedTask.ProjectID = projectID
+	return uc.updateTaskFields(ctx, id, *task, movedTask, []string{entities.TaskFieldProjectID}, userID)
+}
+
+func (uc *TaskUsecase) RemoveTaskFromProject(ctx context.Context, id string, projectID string, userID string) (*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	task, err := accessTask(ctx, uc.TaskRepository, uc.ProjectRepository, id, userID, entities.TaskRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.ProjectID != projectID {
+		return nil, entities.ErrTaskNotInProject
+	}
+	if err := uc.checkLeavingProject(ctx, task); err != nil {
+		return nil, err
+	}
+
+	removedTask := *task
+	removedTask.ProjectID = ""
+	return uc.updateTaskFields(ctx, id, *task, removedTask, []string{entities.TaskFieldProjectID}, userID)
+}
+
+func (uc *TaskUsecase) GetTaskSeries(ctx context.Context, seriesID string, userID string) ([]*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	occurrences, err := uc.seriesTasks(ctx, seriesID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []*model.TaskInfo{}
+	for _, occurrence := range occurrences {
+		infos = append(infos, occurrence.Info())
+	}
+
+	return infos, nil
+}
+
+func (uc *TaskUsecase) UpdateTaskSeries(ctx context.Context, seriesID string, patch model.SeriesPatch, userID string) ([]*model.TaskInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	occurrences, err := uc.seriesTasks(ctx, seriesID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Done occurrences are history and keep their values. A series whose
+	// occurrences are all done can still be changed through its latest one.
+	var open []*entities.Task
+	for _, occurrence := range occurrences {
+		if occurrence.Status != entities.StatusDone {
+			open = append(open, occurrence)
+		}
+	}
+	if len(open) == 0 {
+		open = occurrences[len(occurrences)-1:]
+	}
+
+	taskPatch := model.TaskPatch{
+		Title:       patch.Title,
+		Description: patch.Description,
+		Priority:    patch.Priority,
+		Tags:        patch.Tags,
+		Recurrence:  patch.Recurrence,
+		Reminders:   patch.Reminders,
+	}
+
+	// Every occurrence is checked before any is written, so that a rejected
+	// patch changes none of them.
+	updated := make([]entities.Task, len(open))
+	fields := make([][]string, len(open))
+	for i, occurrence := range open {
+		role, err := taskRoleOf(ctx, uc.ProjectRepository, occurrence, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !entities.RoleAllows(role, entities.TaskRoleEditor) {
+			return nil, entities.ErrTaskForbidden
+		}
+
+		updated[i], fields[i], err = applyTaskPatch(*occurrence, taskPatch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	infos := []*model.TaskInfo{}
+	for i, occurrence := range open {
+		if len(fields[i]) == 0 {
+			infos = append(infos, occurrence.Info())
+			continue
+		}
+
+		info, err := uc.updateTaskFields(ctx, occurrence.ID.Hex(), *occurrence, updated[i], fields[i], userID)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// seriesTasks reads the occurrences of a series the user can read. A series
+// without any is reported as missing.
+func (uc *TaskUsecase) seriesTasks(ctx context.Context, seriesID string, userID string) ([]*entities.Task, error) {
+	occurrences, err := uc.TaskRepository.GetSeriesTasks(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	var readable []*entities.Task
+	for _, occurrence := range occurrences {
+		role, err := taskRoleOf(ctx, uc.ProjectRepository, occurrence, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role != "" {
+			readable = append(readable, occurrence)
+		}
+	}
+
+	if len(readable) == 0 {
+		return nil, entities.ErrSeriesNotFound
+	}
+
+	return readable, nil
+}
+
+// activeProject reads a project tasks can be added to.
+func (uc *TaskUsecase) activeProject(ctx context.Context, projectID string) (*entities.Project, error) {
+	project, err := uc.ProjectRepository.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.ArchivedAt != nil {
+		return nil, entities.ErrProjectArchived
+	}
+
+	return project, nil
+}
+
+// checkLeavingProject fails with ErrProjectArchived when the project a task is
+// in is archived.
+func (uc *TaskUsecase) checkLeavingProject(ctx context.Context, task *entities.Task) error {
+	if task.ProjectID == "" {
+		return nil
+	}
+
+	_, err := uc.activeProject(ctx, task.ProjectID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// normalizePageQuery clamps the page size of a list of comments or activity.
+func normalizePageQuery(query model.PageQuery) model.PageQuery {
+	if query.Limit <= 0 {
+		query.Limit = model.DefaultTaskPageSize
+	}
+	if query.Limit > model.MaxTaskPageSize {
+		query.Limit = model.MaxTaskPageSize
+	}
+
+	return query
+}
+
+// recordUpdate records the fields an update changed in the activity log, and
+// returns them. An update that changed nothing is not recorded.
+func (uc *TaskUsecase) recordUpdate(ctx context.Context, id string, before entities.Task, after entities.Task, fields []string, userID string) []entities.FieldChange {
+	action := entities.ActivityUpdated
+	for _, field := range fields {
+		if field == entities.TaskFieldStatus && before.Status != after.Status {
+			action = entities.ActivityStatusChanged
+			fields = append(append([]string{}, fields...), "completed_at")
+			break
+		}
+	}
+
+	changes := taskChanges(before, after, fields)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	uc.recordActivity(ctx, entities.Activity{TaskID: id, ActorID: userID, Action: action, Changes: changes})
+	return changes
+}
+
+// rescheduleReminders schedules the reminders of a task again after an update
+// that changed when they are due. Tasks that had and still have no reminders
+// are left alone.
+func (uc *TaskUsecase) rescheduleReminders(ctx context.Context, before entities.Task, after entities.Task, fields []string) {
+	if len(before.Reminders) == 0 && len(after.Reminders) == 0 {
+		return
+	}
+
+	for _, field := range fields {
+		switch field {
+		case entities.TaskFieldStatus, entities.TaskFieldDueDate, entities.TaskFieldReminders:
+			uc.scheduleReminders(ctx, after)
+			return
+		}
+	}
+}
+
+// scheduleReminders replaces the pending reminders of a task with those it
+// needs now, see entities.TaskReminders. The task has already been written, so
+// a failure is logged instead of failing the request.
+func (uc *TaskUsecase) scheduleReminders(ctx context.Context, task entities.Task) {
+	now := time.Now()
+	reminders := entities.TaskReminders(task, now)
+
+	if err := uc.ReminderRepository.ReplaceReminders(ctx, task.ID.Hex(), reminders, now); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "scheduling task reminders failed", "task_id", task.ID.Hex(), "error", err)
+	}
+}
+
+// publishUpdate sends task.updated for an update that changed the task, and
+// task.completed when it marked the task done.
+func (uc *TaskUsecase) publishUpdate(ctx context.Context, before entities.Task, after entities.Task, changes []entities.FieldChange, userID string) {
+	if len(changes) == 0 {
+		return
+	}
+
+	uc.publishEvent(ctx, entities.EventTaskUpdated, after, changes, userID)
+	if after.Status == entities.StatusDone && before.Status != entities.StatusDone {
+		uc.publishEvent(ctx, entities.EventTaskCompleted, after, nil, userID)
+	}
+}
+
+// publishEvent queues the delivery of a task event to the webhooks subscribed
+// to it whose users can read the task. The change has already been made, so a
+// failure is logged instead of failing the request.
+func (uc *TaskUsecase) publishEvent(ctx context.Context, event string, task entities.Task, changes []entities.FieldChange, userID string) {
+	if err := uc.queueEvent(ctx, event, task, changes, userID); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "queueing webhook deliveries failed", "task_id", task.ID.Hex(), "event", event, "error", err)
+	}
+}
+
+func (uc *TaskUsecase) queueEvent(ctx context.Context, event string, task entities.Task, changes []entities.FieldChange, userID string) error {
+	readers, err := taskReaders(ctx, uc.ProjectRepository, &task)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := uc.WebhookRepository.GetEventWebhooks(ctx, event, readers)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	deliveries, err := newWebhookDeliveries(webhooks, model.WebhookPayload{
+		ID:        primitive.NewObjectID().Hex(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		ActorID:   userID,
+		Task:      task.Info(),
+		Changes:   entities.FieldChangesInfo(changes),
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.WebhookRepository.AddDeliveries(ctx, deliveries)
+}
+
+// recordActivity appends to the activity log. The change it records has
+// already been made, so a failure is logged instead of failing the request.
+func (uc *TaskUsecase) recordActivity(ctx context.Context, activity entities.Activity) {
+	activity.CreatedAt = time.Now()
+
+	if err := uc.ActivityRepository.AddActivity(ctx, activity); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "recording task activity failed", "task_id", activity.TaskID, "action", activity.Action, "error", err)
+	}
+}
+
+// taskChanges compares the given fields of a task before and after an update,
+// encoded as in task responses, and returns those that differ.
+func taskChanges(before entities.Task, after entities.Task, fields []string) []entities.FieldChange {
+	beforeFields, afterFields := taskFieldsJSON(before), taskFieldsJSON(after)
+
+	var changes []entities.FieldChange
+	for _, field := range fields {
+		if beforeFields[field] != afterFields[field] {
+			changes = append(changes, entities.FieldChange{Field: field, From: beforeFields[field], To: afterFields[field]})
+		}
+	}
+
+	return changes
+}
+
+// taskFieldsJSON encodes each field of the response representation of a
+// task. Fields left out of the response are null.
+func taskFieldsJSON(task entities.Task) map[string]string {
+	encoded, _ := json.Marshal(task.Info())
+
+	var members map[string]json.RawMessage
+	json.Unmarshal(encoded, &members)
+
+	fields := map[string]string{
+		entities.TaskFieldParentID:      "null",
+		entities.TaskFieldBlockedBy:     "null",
+		entities.TaskFieldCollaborators: "null",
+		entities.TaskFieldAssigneeID:    "null",
+		entities.TaskFieldProjectID:     "null",
+		entities.TaskFieldRecurrence:    "null",
+		entities.TaskFieldReminders:     "null",
+	}
+	for field, value := range members {
+		fields[field] = string(value)
+	}
+
+	return fields
+}
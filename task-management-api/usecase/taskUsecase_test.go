@@ -1,19 +1,93 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
+	"sort"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/mocks"
 	"task-management-api/domain/model"
+	"task-management-api/logging"
 	"task-management-api/usecase"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ignoreActivity accepts any activity, for tests that do not check the
+// activity log.
+func ignoreActivity() *mocks.ActivityRepository {
+	activity := new(mocks.ActivityRepository)
+	activity.On("AddActivity", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return activity
+}
+
+// noWebhooks has no webhook subscribed to any event, for tests that do not
+// check webhook deliveries.
+func noWebhooks() *mocks.WebhookRepository {
+	webhooks := new(mocks.WebhookRepository)
+	webhooks.On("GetEventWebhooks", mock.Anything, mock.Anything, mock.Anything).Return([]*entities.Webhook{}, nil).Maybe()
+	return webhooks
+}
+
+// taskUsecaseOption replaces one of the repositories newTaskUsecase passes to
+// the usecase.
+type taskUsecaseOption func(*taskUsecaseRepositories)
+
+type taskUsecaseRepositories struct {
+	activity  entities.ActivityRepository
+	users     entities.UserRepository
+	projects  entities.ProjectRepository
+	reminders entities.ReminderRepository
+	webhooks  entities.WebhookRepository
+}
+
+func withActivity(activity entities.ActivityRepository) taskUsecaseOption {
+	return func(r *taskUsecaseRepositories) { r.activity = activity }
+}
+
+func withUsers(users entities.UserRepository) taskUsecaseOption {
+	return func(r *taskUsecaseRepositories) { r.users = users }
+}
+
+func withProjects(projects entities.ProjectRepository) taskUsecaseOption {
+	return func(r *taskUsecaseRepositories) { r.projects = projects }
+}
+
+func withReminders(reminders entities.ReminderRepository) taskUsecaseOption {
+	return func(r *taskUsecaseRepositories) { r.reminders = reminders }
+}
+
+func withWebhooks(webhooks entities.WebhookRepository) taskUsecaseOption {
+	return func(r *taskUsecaseRepositories) { r.webhooks = webhooks }
+}
+
+// newTaskUsecase returns a task usecase over tasks that ignores activity and
+// has no webhooks. Its user, project and reminder repositories expect no
+// calls unless opts replace them.
+func newTaskUsecase(t *testing.T, tasks entities.TaskRepository, opts ...taskUsecaseOption) entities.TaskUsecase {
+	repositories := taskUsecaseRepositories{
+		activity:  ignoreActivity(),
+		users:     mocks.NewUserRepository(t),
+		projects:  mocks.NewProjectRepository(t),
+		reminders: mocks.NewReminderRepository(t),
+		webhooks:  noWebhooks(),
+	}
+	for _, opt := range opts {
+		opt(&repositories)
+	}
+
+	return usecase.NewTaskUsecase(tasks, repositories.activity, repositories.users, repositories.projects, repositories.reminders, repositories.webhooks)
+}
+
 func TestGetTasks(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockTaskRepository := new(mocks.TaskRepository)
@@ -34,34 +108,81 @@ func TestGetTasks(t *testing.T) {
 			},
 		}
 
-		mockTaskRepository.On("GetTasks", mock.Anything, userID).Return(expectedTaskInfos, nil).Once()
+		expectedQuery := model.TaskQuery{TagMatch: entities.TagMatchAny, Limit: model.DefaultTaskPageSize}
+		mockTaskRepository.On("GetTasks", mock.Anything, userID, expectedQuery).Return(&model.TaskPage{Tasks: expectedTaskInfos, Next: "next"}, nil).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		tuc := newTaskUsecase(t, mockTaskRepository)
 
-		tasks, err := tuc.GetTasks(userID)
+		page, err := tuc.GetTasks(context.TODO(), userID, model.TaskQuery{})
 
 		assert.NoError(t, err)
-		assert.Equal(t, len(expectedTaskInfos), len(tasks))
+		assert.Equal(t, len(expectedTaskInfos), len(page.Tasks))
+		assert.Equal(t, "next", page.Next)
 
-		for i := range tasks {
-			assert.Equal(t, expectedTaskInfos[i].Title, tasks[i].Title)
-			assert.Equal(t, expectedTaskInfos[i].Description, tasks[i].Description)
+		for i := range page.Tasks {
+			assert.Equal(t, expectedTaskInfos[i].Title, page.Tasks[i].Title)
+			assert.Equal(t, expectedTaskInfos[i].Description, page.Tasks[i].Description)
 		}
 
 		mockTaskRepository.AssertExpectations(t)
 	})
 
+	t.Run("limit is capped", func(t *testing.T) {
+		mockTaskRepository := new(mocks.TaskRepository)
+
+		userID := "testUserID"
+		query := model.TaskQuery{Status: entities.StatusDone, Priority: "high", Sort: "-priority", Tags: []string{"work", "home", "work"}, TagMatch: entities.TagMatchAll, Limit: 1000}
+
+		expectedQuery := query
+		expectedQuery.Tags = []string{"work", "home"}
+		expectedQuery.Limit = model.MaxTaskPageSize
+		mockTaskRepository.On("GetTasks", mock.Anything, userID, expectedQuery).Return(&model.TaskPage{}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.GetTasks(context.TODO(), userID, query)
+
+		assert.NoError(t, err)
+		mockTaskRepository.AssertExpectations(t)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			query model.TaskQuery
+			err   error
+		}{
+			{name: "status", query: model.TaskQuery{Status: "archived"}, err: entities.ErrInvalidStatus},
+			{name: "priority", query: model.TaskQuery{Priority: "urgent"}, err: entities.ErrInvalidPriority},
+			{name: "sort", query: model.TaskQuery{Sort: "title"}, err: entities.ErrInvalidTaskSort},
+			{name: "tag match", query: model.TaskQuery{Tags: []string{"work"}, TagMatch: "some"}, err: entities.ErrInvalidTagMatch},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockTaskRepository := new(mocks.TaskRepository)
+				tuc := newTaskUsecase(t, mockTaskRepository)
+
+				page, err := tuc.GetTasks(context.TODO(), "testUserID", tt.query)
+
+				assert.Nil(t, page)
+				assert.ErrorIs(t, err, tt.err)
+				mockTaskRepository.AssertNotCalled(t, "GetTasks")
+			})
+		}
+	})
+
 	t.Run("error", func(t *testing.T) {
 		mockTaskRepository := new(mocks.TaskRepository)
 
 		userID := "testUserID"
 		expectedErr := errors.New("repository error")
 
-		mockTaskRepository.On("GetTasks", mock.Anything, userID).Return(nil, expectedErr).Once()
+		mockTaskRepository.On("GetTasks", mock.Anything, userID, mock.AnythingOfType("model.TaskQuery")).Return(nil, expectedErr).Once()
 
-		u := usecase.NewTaskUsecase(mockTaskRepository)
+		u := newTaskUsecase(t, mockTaskRepository)
 
-		tasks, err := u.GetTasks(userID)
+		tasks, err := u.GetTasks(context.TODO(), userID, model.TaskQuery{})
 
 		assert.Nil(t, tasks)
 		assert.Error(t, err)
@@ -78,6 +199,7 @@ func TestGetTaskByID(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockTaskEntity := &entities.Task{
+			UserID:      userID,
 			Title:       "Sample Task",
 			Description: "Sample Description",
 		}
@@ -86,11 +208,11 @@ func TestGetTaskByID(t *testing.T) {
 			Description: "Sample Description",
 		}
 
-		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID, userID).Return(mockTaskEntity, nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(mockTaskEntity, nil).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		tuc := newTaskUsecase(t, mockTaskRepository)
 
-		taskInfo, err := tuc.GetTaskByID(taskID, userID)
+		taskInfo, err := tuc.GetTaskByID(context.TODO(), taskID, userID)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTaskInfo.Title, taskInfo.Title)
@@ -103,11 +225,11 @@ func TestGetTaskByID(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		expectedErr := errors.New("repository error")
 
-		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID, userID).Return(nil, expectedErr).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(nil, expectedErr).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		tuc := newTaskUsecase(t, mockTaskRepository)
 
-		taskInfo, err := tuc.GetTaskByID(taskID, userID)
+		taskInfo, err := tuc.GetTaskByID(context.TODO(), taskID, userID)
 
 		assert.Nil(t, taskInfo)
 		assert.Error(t, err)
@@ -117,42 +239,221 @@ func TestGetTaskByID(t *testing.T) {
 	})
 }
 
-func TestUpdateTask(t *testing.T) {
-	mockTaskRepository := new(mocks.TaskRepository)
+func taskPatch(t *testing.T, body string) model.TaskPatch {
+	var patch model.TaskPatch
+	require.NoError(t, json.Unmarshal([]byte(body), &patch))
+	return patch
+}
 
+func TestUpdateTask(t *testing.T) {
 	taskID := "testTaskID"
 	userID := "testUserID"
-	updatedTask := entities.Task{
-		Title:       "Updated Task",
-		Description: "Updated Description",
+
+	dueDate := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	currentTask := &entities.Task{
+		UserID:      userID,
+		Title:       "Task",
+		Description: "Description",
+		Status:      entities.StatusTodo,
+		Priority:    entities.PriorityMedium,
+		Tags:        []string{"work"},
+		DueDate:     dueDate,
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, updatedTask, userID).Return(nil).Once()
+		mockTaskRepository := new(mocks.TaskRepository)
+
+		expectedTask := *currentTask
+		expectedTask.Title = "Updated Task"
+		expectedTask.Description = "Updated Description"
+
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(currentTask, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, expectedTask,
+			[]string{entities.TaskFieldTitle, entities.TaskFieldDescription}).Return(nil).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		tuc := newTaskUsecase(t, mockTaskRepository)
 
-		err := tuc.UpdateTask(taskID, updatedTask, userID)
+		task, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Updated Task", "description": "Updated Description"}`), userID, 0)
 
 		assert.NoError(t, err)
+		assert.Equal(t, "Updated Task", task.Title)
+		assert.Equal(t, []string{"work"}, task.Tags)
+		assert.Equal(t, int64(1), task.Version)
 
 		mockTaskRepository.AssertExpectations(t)
 	})
 
+	t.Run("null members are cleared", func(t *testing.T) {
+		mockTaskRepository := new(mocks.TaskRepository)
+
+		expectedTask := *currentTask
+		expectedTask.Description = ""
+		expectedTask.Tags = nil
+		expectedTask.DueDate = time.Time{}
+
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(currentTask, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, expectedTask,
+			[]string{entities.TaskFieldDescription, entities.TaskFieldTags, entities.TaskFieldDueDate}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"description": null, "tags": null, "due_date": null}`), userID, 0)
+
+		assert.NoError(t, err)
+		assert.Empty(t, task.Description)
+		assert.Nil(t, task.DueDate)
+
+		mockTaskRepository.AssertExpectations(t)
+	})
+
+	t.Run("invalid resulting task", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			patch       string
+			expectedErr error
+		}{
+			{name: "title removed", patch: `{"title": null}`, expectedErr: entities.ErrTaskTitleRequired},
+			{name: "empty title", patch: `{"title": ""}`, expectedErr: entities.ErrTaskTitleRequired},
+			{name: "status removed", patch: `{"status": null}`, expectedErr: entities.ErrInvalidStatus},
+			{name: "priority removed", patch: `{"priority": null}`, expectedErr: entities.ErrInvalidPriority},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				mockTaskRepository := mocks.NewTaskRepository(t)
+				mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(currentTask, nil).Once()
+
+				tuc := newTaskUsecase(t, mockTaskRepository)
+
+				_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, test.patch), userID, 0)
+
+				assert.ErrorIs(t, err, test.expectedErr)
+			})
+		}
+	})
+
+	t.Run("empty patch", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(currentTask, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{}`), userID, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), task.Version)
+	})
+
 	t.Run("error", func(t *testing.T) {
+		mockTaskRepository := new(mocks.TaskRepository)
 		expectedErr := errors.New("update error")
 
-		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, updatedTask, userID).Return(expectedErr).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(currentTask, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, mock.AnythingOfType("entities.Task"), mock.Anything).Return(expectedErr).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		tuc := newTaskUsecase(t, mockTaskRepository)
 
-		err := tuc.UpdateTask(taskID, updatedTask, userID)
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Updated Task"}`), userID, 0)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 
 		mockTaskRepository.AssertExpectations(t)
 	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockTaskRepository := new(mocks.TaskRepository)
+
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(nil, apperrors.NotFound("task not found")).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Updated Task"}`), userID, 0)
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		mockTaskRepository.AssertExpectations(t)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockTaskRepository := new(mocks.TaskRepository)
+
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID, Title: "Task", Version: 4}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Updated Task"}`), userID, 3)
+
+		assert.ErrorIs(t, err, apperrors.ErrPreconditionFailed)
+
+		mockTaskRepository.AssertExpectations(t)
+	})
+
+	t.Run("any version updates the version read", func(t *testing.T) {
+		mockTaskRepository := new(mocks.TaskRepository)
+
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID, Title: "Task", Status: entities.StatusTodo, Version: 4}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, mock.MatchedBy(func(task entities.Task) bool {
+			return task.Version == 4
+		}), mock.Anything).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Updated Task"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), task.Version)
+
+		mockTaskRepository.AssertExpectations(t)
+	})
+
+	t.Run("status workflow", func(t *testing.T) {
+		completedAt := time.Now().Add(-time.Hour)
+
+		tests := []struct {
+			name          string
+			from          string
+			to            string
+			expectedErr   error
+			wantCompleted bool
+		}{
+			{name: "start", from: entities.StatusTodo, to: entities.StatusInProgress},
+			{name: "complete", from: entities.StatusInProgress, to: entities.StatusDone, wantCompleted: true},
+			{name: "reopen", from: entities.StatusDone, to: entities.StatusTodo},
+			{name: "skip in progress", from: entities.StatusTodo, to: entities.StatusDone, expectedErr: entities.ErrInvalidStatusTransition},
+			{name: "unknown status", from: entities.StatusTodo, to: "archived", expectedErr: entities.ErrInvalidStatus},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				mockTaskRepository := new(mocks.TaskRepository)
+
+				current := &entities.Task{UserID: userID, Title: "Task", Status: test.from, Priority: entities.PriorityLow}
+				if test.from == entities.StatusDone {
+					current.CompletedAt = &completedAt
+				}
+
+				mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(current, nil).Once()
+				if test.expectedErr == nil {
+					mockTaskRepository.On("UpdateTask", mock.Anything, taskID, mock.MatchedBy(func(task entities.Task) bool {
+						return task.Status == test.to && (task.CompletedAt != nil) == test.wantCompleted
+					}), []string{entities.TaskFieldStatus}).Return(nil).Once()
+				}
+
+				tuc := newTaskUsecase(t, mockTaskRepository)
+
+				_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"status": "`+test.to+`"}`), userID, 0)
+
+				if test.expectedErr != nil {
+					assert.ErrorIs(t, err, test.expectedErr)
+				} else {
+					assert.NoError(t, err)
+				}
+
+				mockTaskRepository.AssertExpectations(t)
+			})
+		}
+	})
 }
 
 func TestDeleteTask(t *testing.T) {
@@ -162,11 +463,12 @@ func TestDeleteTask(t *testing.T) {
 	userID := "testUserID"
 
 	t.Run("success", func(t *testing.T) {
-		mockTaskRepository.On("DeleteTask", mock.Anything, taskID, userID).Return(nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+		mockTaskRepository.On("DeleteTask", mock.Anything, taskID, int64(2)).Return(nil).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		tuc := newTaskUsecase(t, mockTaskRepository)
 
-		err := tuc.DeleteTask(taskID, userID)
+		err := tuc.DeleteTask(context.TODO(), taskID, userID, 2)
 
 		assert.NoError(t, err)
 
@@ -176,11 +478,12 @@ func TestDeleteTask(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		expectedErr := errors.New("delete error")
 
-		mockTaskRepository.On("DeleteTask", mock.Anything, taskID, userID).Return(expectedErr).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+		mockTaskRepository.On("DeleteTask", mock.Anything, taskID, int64(2)).Return(expectedErr).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		tuc := newTaskUsecase(t, mockTaskRepository)
 
-		err := tuc.DeleteTask(taskID, userID)
+		err := tuc.DeleteTask(context.TODO(), taskID, userID, 2)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -189,6 +492,64 @@ func TestDeleteTask(t *testing.T) {
 	})
 }
 
+func TestGetTrash(t *testing.T) {
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		page := &model.TaskPage{Tasks: []*model.TaskInfo{{ID: "1", Title: "Deleted"}}}
+
+		mockTaskRepository.On("GetDeletedTasks", mock.Anything, userID, model.TaskQuery{TagMatch: entities.TagMatchAny, Limit: model.DefaultTaskPageSize}).Return(page, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		result, err := tuc.GetTrash(context.TODO(), userID, model.TaskQuery{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, page, result)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		tuc := newTaskUsecase(t, mocks.NewTaskRepository(t))
+
+		_, err := tuc.GetTrash(context.TODO(), userID, model.TaskQuery{Sort: "title"})
+
+		assert.ErrorIs(t, err, entities.ErrInvalidTaskSort)
+	})
+}
+
+func TestRestoreTask(t *testing.T) {
+	taskID := "testTaskID"
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+
+		mockTaskRepository.On("RestoreTask", mock.Anything, taskID, userID).Return(nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID, Title: "Restored", Version: 3}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.RestoreTask(context.TODO(), taskID, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Restored", task.Title)
+		assert.Equal(t, int64(3), task.Version)
+	})
+
+	t.Run("not in trash", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+
+		mockTaskRepository.On("RestoreTask", mock.Anything, taskID, userID).Return(apperrors.NotFound("task not found in trash")).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.RestoreTask(context.TODO(), taskID, userID)
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
 func TestCreateTask(t *testing.T) {
 	mockTaskRepository := new(mocks.TaskRepository)
 
@@ -197,12 +558,30 @@ func TestCreateTask(t *testing.T) {
 		Description: "New Task Description",
 	}
 
+	storedTask := newTask
+	storedTask.Status = entities.StatusTodo
+	storedTask.Priority = entities.PriorityMedium
+
+	// The ID is generated by the usecase so the activity log can refer to it.
+	isStoredTask := mock.MatchedBy(func(task entities.Task) bool {
+		if task.ID.IsZero() {
+			return false
+		}
+		task.ID = primitive.NilObjectID
+		return reflect.DeepEqual(task, storedTask)
+	})
+
 	t.Run("success", func(t *testing.T) {
-		mockTaskRepository.On("CreateTask", mock.Anything, newTask).Return(nil).Once()
+		mockTaskRepository.On("CreateTask", mock.Anything, isStoredTask).Return(nil).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		mockActivityRepository := mocks.NewActivityRepository(t)
+		mockActivityRepository.On("AddActivity", mock.Anything, mock.MatchedBy(func(activity entities.Activity) bool {
+			return activity.Action == entities.ActivityCreated && activity.TaskID != ""
+		})).Return(nil).Once()
 
-		err := tuc.CreateTask(newTask)
+		tuc := newTaskUsecase(t, mockTaskRepository, withActivity(mockActivityRepository))
+
+		err := tuc.CreateTask(context.TODO(), newTask)
 
 		assert.NoError(t, err)
 
@@ -212,18 +591,1306 @@ func TestCreateTask(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		expectedErr := errors.New("creation error")
 
-		mockTaskRepository.On("CreateTask", mock.Anything, newTask).Return(expectedErr).Once()
+		mockTaskRepository.On("CreateTask", mock.Anything, isStoredTask).Return(expectedErr).Once()
 
-		tuc := usecase.NewTaskUsecase(mockTaskRepository)
+		tuc := newTaskUsecase(t, mockTaskRepository)
 
-		err := tuc.CreateTask(newTask)
+		err := tuc.CreateTask(context.TODO(), newTask)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 
 		mockTaskRepository.AssertExpectations(t)
 	})
+
+	t.Run("invalid priority", func(t *testing.T) {
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		err := tuc.CreateTask(context.TODO(), entities.Task{Title: "New Task", Priority: entities.Priority(7)})
+
+		assert.ErrorIs(t, err, entities.ErrInvalidPriority)
+	})
+}
+
+func TestUpdateTaskBlocked(t *testing.T) {
+	userID := "testUserID"
+	blockerID := primitive.NewObjectID().Hex()
+	task := &entities.Task{UserID: userID, Title: "Task", Status: entities.StatusInProgress, Version: 2, BlockedBy: []string{blockerID}}
+
+	t.Run("open blocker", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "task").Return(task, nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, blockerID).Return(&entities.Task{UserID: userID, Status: entities.StatusTodo}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), "task", taskPatch(t, `{"status": "done"}`), userID, 2)
+
+		assert.ErrorIs(t, err, entities.ErrTaskBlocked)
+	})
+
+	t.Run("blocker done or deleted", func(t *testing.T) {
+		for _, blocker := range []struct {
+			task *entities.Task
+			err  error
+		}{
+			{task: &entities.Task{UserID: userID, Status: entities.StatusDone}},
+			{err: apperrors.NotFound("task not found")},
+		} {
+			mockTaskRepository := mocks.NewTaskRepository(t)
+			mockTaskRepository.On("GetTaskByID", mock.Anything, "task").Return(task, nil).Once()
+			mockTaskRepository.On("GetTaskByID", mock.Anything, blockerID).Return(blocker.task, blocker.err).Once()
+			mockTaskRepository.On("UpdateTask", mock.Anything, "task", mock.AnythingOfType("entities.Task"),
+				[]string{entities.TaskFieldStatus}).Return(nil).Once()
+
+			tuc := newTaskUsecase(t, mockTaskRepository)
+
+			updated, err := tuc.UpdateTask(context.TODO(), "task", taskPatch(t, `{"status": "done"}`), userID, 2)
+
+			assert.NoError(t, err)
+			assert.Equal(t, entities.StatusDone, updated.Status)
+		}
+	})
+}
+
+func TestGetTaskTree(t *testing.T) {
+	userID := "testUserID"
+	rootID := primitive.NewObjectID()
+	childID := primitive.NewObjectID()
+	grandchildIDs := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+
+	mockTaskRepository := mocks.NewTaskRepository(t)
+	mockTaskRepository.On("GetTaskByID", mock.Anything, rootID.Hex()).Return(&entities.Task{UserID: userID, ID: rootID, Title: "Root"}, nil).Once()
+	mockTaskRepository.On("GetSubtasks", mock.Anything, rootID.Hex()).Return([]*entities.Task{
+		{UserID: userID, ID: childID, Title: "Child", ParentID: rootID.Hex()},
+		{UserID: userID, ID: primitive.NewObjectID(), Title: "Done child", Status: entities.StatusDone, ParentID: rootID.Hex()},
+	}, nil).Once()
+	mockTaskRepository.On("GetSubtasks", mock.Anything, childID.Hex()).Return([]*entities.Task{
+		{UserID: userID, ID: grandchildIDs[0], Status: entities.StatusDone},
+		{UserID: userID, ID: grandchildIDs[1], Status: entities.StatusInProgress},
+		{UserID: userID, ID: grandchildIDs[2], Status: entities.StatusDone},
+	}, nil).Once()
+	mockTaskRepository.On("GetSubtasks", mock.Anything, mock.Anything).Return([]*entities.Task{}, nil)
+
+	tuc := newTaskUsecase(t, mockTaskRepository)
+
+	tree, err := tuc.GetTaskTree(context.TODO(), rootID.Hex(), userID)
+
+	require.NoError(t, err)
+	require.Len(t, tree.Subtasks, 2)
+	require.Len(t, tree.Subtasks[0].Subtasks, 3)
+	assert.Equal(t, 66, *tree.Subtasks[0].Progress)
+	assert.Equal(t, 100, *tree.Subtasks[1].Progress)
+	assert.Equal(t, 0, *tree.Subtasks[0].Subtasks[1].Progress)
+	assert.Empty(t, tree.Subtasks[1].Subtasks)
+	assert.Equal(t, 83, *tree.Progress)
+}
+
+func TestGetTaskTreeSharedParent(t *testing.T) {
+	ownerID := "ownerID"
+	userID := "testUserID"
+	rootID := primitive.NewObjectID()
+	sharedID := primitive.NewObjectID()
+
+	// The root is shared with the user, only one of its subtasks is.
+	mockTaskRepository := mocks.NewTaskRepository(t)
+	mockTaskRepository.On("GetTaskByID", mock.Anything, rootID.Hex()).Return(&entities.Task{
+		UserID: ownerID, ID: rootID, Collaborators: []entities.Collaborator{{UserID: userID, Role: entities.TaskRoleViewer}},
+	}, nil).Once()
+	mockTaskRepository.On("GetSubtasks", mock.Anything, rootID.Hex()).Return([]*entities.Task{
+		{UserID: ownerID, ID: primitive.NewObjectID(), Title: "Private", ParentID: rootID.Hex()},
+		{UserID: ownerID, ID: sharedID, Title: "Shared", Status: entities.StatusDone, ParentID: rootID.Hex(), AssigneeID: userID},
+	}, nil).Once()
+	mockTaskRepository.On("GetSubtasks", mock.Anything, sharedID.Hex()).Return([]*entities.Task{}, nil).Once()
+
+	tuc := newTaskUsecase(t, mockTaskRepository)
+
+	tree, err := tuc.GetTaskTree(context.TODO(), rootID.Hex(), userID)
+
+	require.NoError(t, err)
+	require.Len(t, tree.Subtasks, 1)
+	assert.Equal(t, "Shared", tree.Subtasks[0].Title)
+	assert.Equal(t, 100, *tree.Progress)
+}
+
+func TestGetTaskTreeLoop(t *testing.T) {
+	userID := "testUserID"
+	rootID := primitive.NewObjectID()
+	childID := primitive.NewObjectID()
+
+	// Links stored before cycles through the trash were caught can still
+	// loop; the tree stops where a task repeats.
+	mockTaskRepository := mocks.NewTaskRepository(t)
+	mockTaskRepository.On("GetTaskByID", mock.Anything, rootID.Hex()).Return(&entities.Task{UserID: userID, ID: rootID, ParentID: childID.Hex()}, nil).Once()
+	mockTaskRepository.On("GetSubtasks", mock.Anything, rootID.Hex()).Return([]*entities.Task{{UserID: userID, ID: childID, ParentID: rootID.Hex()}}, nil).Once()
+	mockTaskRepository.On("GetSubtasks", mock.Anything, childID.Hex()).Return([]*entities.Task{{UserID: userID, ID: rootID, ParentID: childID.Hex()}}, nil).Once()
+
+	tuc := newTaskUsecase(t, mockTaskRepository)
+
+	tree, err := tuc.GetTaskTree(context.TODO(), rootID.Hex(), userID)
+
+	require.NoError(t, err)
+	require.Len(t, tree.Subtasks, 1)
+	assert.Empty(t, tree.Subtasks[0].Subtasks)
+}
+
+func TestSetTaskParent(t *testing.T) {
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "child").Return(&entities.Task{UserID: userID, Title: "Child", Version: 1}, nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "parent").Return(&entities.Task{UserID: userID, Title: "Parent", ParentID: "root"}, nil).Once()
+		mockTaskRepository.On("GetTaskIncludingDeleted", mock.Anything, "root").Return(&entities.Task{UserID: userID, Title: "Root"}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, "child", entities.Task{UserID: userID, Title: "Child", Version: 1, ParentID: "parent"},
+			[]string{entities.TaskFieldParentID}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.SetTaskParent(context.TODO(), "child", "parent", userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "parent", task.ParentID)
+		assert.Equal(t, int64(2), task.Version)
+	})
+
+	t.Run("unlink", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "child").Return(&entities.Task{UserID: userID, ParentID: "parent", Version: 1}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, "child", entities.Task{UserID: userID, Version: 1},
+			[]string{entities.TaskFieldParentID}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.SetTaskParent(context.TODO(), "child", "", userID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, task.ParentID)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "root").Return(&entities.Task{UserID: userID}, nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "grandchild").Return(&entities.Task{UserID: userID, ParentID: "child"}, nil).Once()
+		mockTaskRepository.On("GetTaskIncludingDeleted", mock.Anything, "child").Return(&entities.Task{UserID: userID, ParentID: "root"}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.SetTaskParent(context.TODO(), "root", "grandchild", userID)
+		assert.ErrorIs(t, err, entities.ErrTaskCycle)
+
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "root").Return(&entities.Task{UserID: userID}, nil).Once()
+
+		_, err = tuc.SetTaskParent(context.TODO(), "root", "root", userID)
+		assert.ErrorIs(t, err, entities.ErrTaskCycle)
+	})
+
+	t.Run("cycle through the trash", func(t *testing.T) {
+		// y is a subtask of the trashed b, itself a subtask of x: restoring b
+		// would close the loop.
+		deletedAt := time.Now()
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "x").Return(&entities.Task{UserID: userID}, nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "y").Return(&entities.Task{UserID: userID, ParentID: "b"}, nil).Once()
+		mockTaskRepository.On("GetTaskIncludingDeleted", mock.Anything, "b").Return(&entities.Task{UserID: userID, ParentID: "x", DeletedAt: &deletedAt}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.SetTaskParent(context.TODO(), "x", "y", userID)
+
+		assert.ErrorIs(t, err, entities.ErrTaskCycle)
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "child").Return(&entities.Task{UserID: userID}, nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "missing").Return(nil, apperrors.NotFound("task not found")).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.SetTaskParent(context.TODO(), "child", "missing", userID)
+
+		assert.Equal(t, entities.ErrTaskParentNotFound, err)
+	})
+}
+
+func TestAddTaskBlocker(t *testing.T) {
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "task").Return(&entities.Task{UserID: userID, BlockedBy: []string{"c"}, Version: 1}, nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "a").Return(&entities.Task{UserID: userID, BlockedBy: []string{"b"}}, nil).Once()
+		mockTaskRepository.On("GetTaskIncludingDeleted", mock.Anything, "b").Return(nil, apperrors.NotFound("task not found")).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, "task", entities.Task{UserID: userID, BlockedBy: []string{"a", "c"}, Version: 1},
+			[]string{entities.TaskFieldBlockedBy}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.AddTaskBlocker(context.TODO(), "task", "a", userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "c"}, task.BlockedBy)
+	})
+
+	t.Run("already blocked", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "task").Return(&entities.Task{UserID: userID, BlockedBy: []string{"a"}, Version: 1}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.AddTaskBlocker(context.TODO(), "task", "a", userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), task.Version)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "task").Return(&entities.Task{UserID: userID}, nil)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "a").Return(&entities.Task{UserID: userID, BlockedBy: []string{"b"}}, nil).Once()
+		deletedAt := time.Now()
+		mockTaskRepository.On("GetTaskIncludingDeleted", mock.Anything, "b").Return(&entities.Task{UserID: userID, BlockedBy: []string{"task"}, DeletedAt: &deletedAt}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.AddTaskBlocker(context.TODO(), "task", "a", userID)
+		assert.ErrorIs(t, err, entities.ErrTaskCycle)
+
+		_, err = tuc.AddTaskBlocker(context.TODO(), "task", "task", userID)
+		assert.ErrorIs(t, err, entities.ErrTaskCycle)
+	})
+
+	t.Run("blocker not found", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "task").Return(&entities.Task{UserID: userID}, nil).Once()
+		mockTaskRepository.On("GetTaskByID", mock.Anything, "missing").Return(nil, apperrors.NotFound("task not found")).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.AddTaskBlocker(context.TODO(), "task", "missing", userID)
+
+		assert.Equal(t, entities.ErrTaskBlockerNotFound, err)
+	})
+}
+
+func TestRemoveTaskBlocker(t *testing.T) {
+	userID := "testUserID"
+
+	mockTaskRepository := mocks.NewTaskRepository(t)
+	mockTaskRepository.On("GetTaskByID", mock.Anything, "task").Return(&entities.Task{UserID: userID, BlockedBy: []string{"a", "b"}, Version: 3}, nil)
+	mockTaskRepository.On("UpdateTask", mock.Anything, "task", entities.Task{UserID: userID, BlockedBy: []string{"b"}, Version: 3},
+		[]string{entities.TaskFieldBlockedBy}).Return(nil).Once()
+
+	tuc := newTaskUsecase(t, mockTaskRepository)
+
+	task, err := tuc.RemoveTaskBlocker(context.TODO(), "task", "a", userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, task.BlockedBy)
+
+	_, err = tuc.RemoveTaskBlocker(context.TODO(), "task", "c", userID)
+	assert.Equal(t, entities.ErrTaskBlockerNotFound, err)
 }
 
+func TestUpdateTaskActivity(t *testing.T) {
+	taskID := "testTaskID"
+	userID := "testUserID"
+	currentTask := &entities.Task{UserID: userID, Title: "Task", Status: entities.StatusTodo, Priority: entities.PriorityMedium}
+
+	t.Run("status change", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(currentTask, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, mock.Anything, mock.Anything).Return(nil).Once()
+
+		mockActivityRepository := mocks.NewActivityRepository(t)
+		mockActivityRepository.On("AddActivity", mock.Anything, mock.MatchedBy(func(activity entities.Activity) bool {
+			return activity.TaskID == taskID && activity.ActorID == userID &&
+				activity.Action == entities.ActivityStatusChanged &&
+				assert.ObjectsAreEqual([]entities.FieldChange{
+					{Field: "status", From: `"todo"`, To: `"in_progress"`},
+					{Field: "title", From: `"Task"`, To: `"Renamed"`},
+				}, sortedChanges(activity.Changes))
+		})).Return(nil).Once()
 
+		tuc := newTaskUsecase(t, mockTaskRepository, withActivity(mockActivityRepository))
 
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Renamed", "status": "in_progress"}`), userID, 0)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("unchanged fields are not recorded", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(currentTask, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, mock.Anything, mock.Anything).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withActivity(mocks.NewActivityRepository(t)))
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Task"}`), userID, 0)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed write is logged", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(currentTask, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, mock.Anything, mock.Anything).Return(nil).Once()
+
+		mockActivityRepository := mocks.NewActivityRepository(t)
+		mockActivityRepository.On("AddActivity", mock.Anything, mock.Anything).Return(errors.New("write error")).Once()
+
+		var logs bytes.Buffer
+		ctx := logging.WithLogger(context.TODO(), logging.New(&logs, "info"))
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withActivity(mockActivityRepository))
+
+		task, err := tuc.UpdateTask(ctx, taskID, taskPatch(t, `{"title": "Renamed"}`), userID, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Renamed", task.Title)
+		assert.Contains(t, logs.String(), "recording task activity failed")
+		assert.Contains(t, logs.String(), "write error")
+	})
+}
+
+func sortedChanges(changes []entities.FieldChange) []entities.FieldChange {
+	sorted := append([]entities.FieldChange{}, changes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Field < sorted[j].Field })
+	return sorted
+}
+
+func TestGetTaskActivity(t *testing.T) {
+	taskID := "testTaskID"
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		page := &model.ActivityPage{Activity: []*model.ActivityInfo{{Action: entities.ActivityCreated}}}
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: userID}, nil).Once()
+
+		mockActivityRepository := mocks.NewActivityRepository(t)
+		mockActivityRepository.On("GetActivity", mock.Anything, taskID, model.PageQuery{Limit: model.MaxTaskPageSize}).Return(page, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withActivity(mockActivityRepository))
+
+		result, err := tuc.GetTaskActivity(context.TODO(), taskID, userID, model.PageQuery{Limit: 1000})
+
+		assert.NoError(t, err)
+		assert.Equal(t, page, result)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		notFound := apperrors.NotFound("task not found")
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(nil, notFound).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withActivity(mocks.NewActivityRepository(t)))
+
+		_, err := tuc.GetTaskActivity(context.TODO(), taskID, userID, model.PageQuery{})
+
+		assert.ErrorIs(t, err, notFound)
+	})
+}
+
+func TestTaskAccess(t *testing.T) {
+	taskID := "testTaskID"
+	ownerID := "ownerID"
+	task := &entities.Task{UserID: ownerID, Title: "Task", Status: entities.StatusTodo, Priority: entities.PriorityMedium,
+		Collaborators: []entities.Collaborator{{UserID: "viewerID", Role: entities.TaskRoleViewer}, {UserID: "editorID", Role: entities.TaskRoleEditor}}}
+
+	t.Run("viewer can read", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		info, err := tuc.GetTaskByID(context.TODO(), taskID, "viewerID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ownerID, info.OwnerID)
+	})
+
+	t.Run("viewer cannot edit", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Renamed"}`), "viewerID", 0)
+
+		assert.ErrorIs(t, err, entities.ErrTaskForbidden)
+	})
+
+	t.Run("editor can edit", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, mock.Anything, []string{entities.TaskFieldTitle}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Renamed"}`), "editorID", 0)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("editor cannot delete", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		err := tuc.DeleteTask(context.TODO(), taskID, "editorID", 0)
+
+		assert.ErrorIs(t, err, entities.ErrTaskForbidden)
+	})
+
+	t.Run("other users do not see the task", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.GetTaskByID(context.TODO(), taskID, "strangerID")
+
+		assert.ErrorIs(t, err, entities.ErrTaskNotFound)
+	})
+}
+
+func TestShareTask(t *testing.T) {
+	taskID := "testTaskID"
+	ownerID := primitive.NewObjectID().Hex()
+	bob := &entities.User{ID: primitive.NewObjectID(), UserName: "bob"}
+	existing := entities.Collaborator{UserID: "000000000000000000000000", Role: entities.TaskRoleViewer}
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID, Version: 1, Collaborators: []entities.Collaborator{existing}}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, entities.Task{UserID: ownerID, Version: 1,
+			Collaborators: []entities.Collaborator{existing, {UserID: bob.ID.Hex(), Role: entities.TaskRoleEditor}}},
+			[]string{entities.TaskFieldCollaborators}).Return(nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withUsers(mockUserRepository))
+
+		task, err := tuc.ShareTask(context.TODO(), taskID, "bob", entities.TaskRoleEditor, ownerID)
+
+		assert.NoError(t, err)
+		assert.Len(t, task.Collaborators, 2)
+		assert.Equal(t, int64(2), task.Version)
+	})
+
+	t.Run("role changed", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID,
+			Collaborators: []entities.Collaborator{{UserID: bob.ID.Hex(), Role: entities.TaskRoleEditor}}}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, entities.Task{UserID: ownerID,
+			Collaborators: []entities.Collaborator{{UserID: bob.ID.Hex(), Role: entities.TaskRoleViewer}}},
+			[]string{entities.TaskFieldCollaborators}).Return(nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withUsers(mockUserRepository))
+
+		_, err := tuc.ShareTask(context.TODO(), taskID, "bob", entities.TaskRoleViewer, ownerID)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		tuc := newTaskUsecase(t, mocks.NewTaskRepository(t))
+
+		_, err := tuc.ShareTask(context.TODO(), taskID, "bob", entities.TaskRoleOwner, ownerID)
+
+		assert.ErrorIs(t, err, entities.ErrInvalidTaskRole)
+	})
+
+	t.Run("only the owner can share", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID,
+			Collaborators: []entities.Collaborator{{UserID: "editorID", Role: entities.TaskRoleEditor}}}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.ShareTask(context.TODO(), taskID, "bob", entities.TaskRoleViewer, "editorID")
+
+		assert.ErrorIs(t, err, entities.ErrTaskForbidden)
+	})
+
+	t.Run("share with the owner", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: bob.ID.Hex()}, nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withUsers(mockUserRepository))
+
+		_, err := tuc.ShareTask(context.TODO(), taskID, "bob", entities.TaskRoleViewer, bob.ID.Hex())
+
+		assert.ErrorIs(t, err, entities.ErrShareWithOwner)
+	})
+}
+
+func TestUnshareTask(t *testing.T) {
+	taskID := "testTaskID"
+	ownerID := primitive.NewObjectID().Hex()
+	bob := &entities.User{ID: primitive.NewObjectID(), UserName: "bob"}
+
+	t.Run("assignee is cleared", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID, AssigneeID: bob.ID.Hex(),
+			Collaborators: []entities.Collaborator{{UserID: bob.ID.Hex(), Role: entities.TaskRoleEditor}}}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, entities.Task{UserID: ownerID},
+			[]string{entities.TaskFieldCollaborators, entities.TaskFieldAssigneeID}).Return(nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withUsers(mockUserRepository))
+
+		task, err := tuc.UnshareTask(context.TODO(), taskID, "bob", ownerID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, task.Collaborators)
+		assert.Empty(t, task.AssigneeID)
+	})
+
+	t.Run("not a collaborator", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID}, nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withUsers(mockUserRepository))
+
+		_, err := tuc.UnshareTask(context.TODO(), taskID, "bob", ownerID)
+
+		assert.ErrorIs(t, err, entities.ErrCollaboratorNotFound)
+	})
+}
+
+func TestSetTaskAssignee(t *testing.T) {
+	taskID := "testTaskID"
+	ownerID := primitive.NewObjectID().Hex()
+	bob := &entities.User{ID: primitive.NewObjectID(), UserName: "bob"}
+
+	t.Run("success", func(t *testing.T) {
+		collaborators := []entities.Collaborator{{UserID: bob.ID.Hex(), Role: entities.TaskRoleViewer}}
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID, Collaborators: collaborators}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, entities.Task{UserID: ownerID, Collaborators: collaborators, AssigneeID: bob.ID.Hex()},
+			[]string{entities.TaskFieldAssigneeID}).Return(nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withUsers(mockUserRepository))
+
+		task, err := tuc.SetTaskAssignee(context.TODO(), taskID, "bob", ownerID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, bob.ID.Hex(), task.AssigneeID)
+	})
+
+	t.Run("task not shared with the assignee", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID}, nil).Once()
+
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withUsers(mockUserRepository))
+
+		_, err := tuc.SetTaskAssignee(context.TODO(), taskID, "bob", ownerID)
+
+		assert.ErrorIs(t, err, entities.ErrAssigneeCannotAccess)
+	})
+
+	t.Run("unassign", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID, AssigneeID: ownerID}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, entities.Task{UserID: ownerID},
+			[]string{entities.TaskFieldAssigneeID}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		task, err := tuc.SetTaskAssignee(context.TODO(), taskID, "", ownerID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, task.AssigneeID)
+	})
+}
+
+func TestGetAssignedTasks(t *testing.T) {
+	userID := "testUserID"
+	page := &model.TaskPage{Tasks: []*model.TaskInfo{{ID: "1", Title: "Assigned"}}}
+
+	mockTaskRepository := mocks.NewTaskRepository(t)
+	mockTaskRepository.On("GetTasks", mock.Anything, userID, model.TaskQuery{TagMatch: entities.TagMatchAny, Limit: model.DefaultTaskPageSize, AssigneeID: userID}).Return(page, nil).Once()
+
+	tuc := newTaskUsecase(t, mockTaskRepository)
+
+	result, err := tuc.GetAssignedTasks(context.TODO(), userID, model.TaskQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+}
+
+func TestProjectTaskAccess(t *testing.T) {
+	taskID := "testTaskID"
+	projectID := "testProjectID"
+	project := &entities.Project{OwnerID: "projectOwnerID", Members: []entities.ProjectMember{
+		{UserID: "editorID", Role: entities.ProjectRoleEditor},
+		{UserID: "viewerID", Role: entities.ProjectRoleViewer},
+	}}
+	task := &entities.Task{UserID: "ownerID", ProjectID: projectID, Title: "Task", Status: entities.StatusTodo, Priority: entities.PriorityMedium}
+
+	t.Run("project editor can edit", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, mock.Anything, []string{entities.TaskFieldTitle}).Return(nil).Once()
+
+		// The project is read again for the readers of the update.
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(project, nil).Twice()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withProjects(mockProjectRepository))
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Renamed"}`), "editorID", 0)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("project owner cannot delete", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(project, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withProjects(mockProjectRepository))
+
+		err := tuc.DeleteTask(context.TODO(), taskID, "projectOwnerID", 0)
+
+		assert.ErrorIs(t, err, entities.ErrTaskForbidden)
+	})
+
+	t.Run("project viewer cannot edit", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(project, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withProjects(mockProjectRepository))
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID, taskPatch(t, `{"title": "Renamed"}`), "viewerID", 0)
+
+		assert.ErrorIs(t, err, entities.ErrTaskForbidden)
+	})
+
+	t.Run("task of a deleted project", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(task, nil).Once()
+
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(nil, entities.ErrProjectNotFound).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withProjects(mockProjectRepository))
+
+		_, err := tuc.GetTaskByID(context.TODO(), taskID, "editorID")
+
+		assert.ErrorIs(t, err, entities.ErrTaskNotFound)
+	})
+}
+
+func TestGetProjectTasks(t *testing.T) {
+	projectID := "testProjectID"
+	page := &model.TaskPage{Tasks: []*model.TaskInfo{{ID: "1", Title: "In project", ProjectID: projectID}}}
+
+	mockTaskRepository := mocks.NewTaskRepository(t)
+	mockTaskRepository.On("GetTasks", mock.Anything, "", model.TaskQuery{ProjectID: projectID, Status: entities.StatusTodo, TagMatch: entities.TagMatchAny, Limit: model.DefaultTaskPageSize}).Return(page, nil).Once()
+
+	tuc := newTaskUsecase(t, mockTaskRepository)
+
+	result, err := tuc.GetProjectTasks(context.TODO(), projectID, model.TaskQuery{Status: entities.StatusTodo})
+
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+}
+
+func TestCreateProjectTask(t *testing.T) {
+	projectID := "testProjectID"
+
+	t.Run("archived project", func(t *testing.T) {
+		archivedAt := time.Now()
+
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{ArchivedAt: &archivedAt}, nil).Once()
+
+		tuc := newTaskUsecase(t, mocks.NewTaskRepository(t), withProjects(mockProjectRepository))
+
+		err := tuc.CreateTask(context.TODO(), entities.Task{UserID: "userID", Title: "Task", ProjectID: projectID})
+
+		assert.ErrorIs(t, err, entities.ErrProjectArchived)
+	})
+}
+
+func TestMoveTask(t *testing.T) {
+	taskID := "testTaskID"
+	ownerID := "ownerID"
+	projectID := "testProjectID"
+
+	t.Run("success", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID, Version: 3}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, entities.Task{UserID: ownerID, Version: 3, ProjectID: projectID},
+			[]string{entities.TaskFieldProjectID}).Return(nil).Once()
+
+		// The project is read again for the readers of the update.
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{OwnerID: ownerID}, nil).Twice()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withProjects(mockProjectRepository))
+
+		task, err := tuc.MoveTask(context.TODO(), taskID, projectID, ownerID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, projectID, task.ProjectID)
+		assert.Equal(t, int64(4), task.Version)
+	})
+
+	t.Run("out of an archived project", func(t *testing.T) {
+		archivedAt := time.Now()
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID, ProjectID: "archivedID"}, nil).Once()
+
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(&entities.Project{OwnerID: ownerID}, nil).Once()
+		mockProjectRepository.On("GetProjectByID", mock.Anything, "archivedID").Return(&entities.Project{OwnerID: ownerID, ArchivedAt: &archivedAt}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withProjects(mockProjectRepository))
+
+		_, err := tuc.MoveTask(context.TODO(), taskID, projectID, ownerID)
+
+		assert.ErrorIs(t, err, entities.ErrProjectArchived)
+	})
+
+	t.Run("only the owner can move", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID,
+			Collaborators: []entities.Collaborator{{UserID: "editorID", Role: entities.TaskRoleEditor}}}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.MoveTask(context.TODO(), taskID, projectID, "editorID")
+
+		assert.ErrorIs(t, err, entities.ErrTaskForbidden)
+	})
+}
+
+func TestRemoveTaskFromProject(t *testing.T) {
+	taskID := "testTaskID"
+	ownerID := "ownerID"
+	projectID := "testProjectID"
+	project := &entities.Project{OwnerID: "projectOwnerID", Members: []entities.ProjectMember{{UserID: "editorID", Role: entities.ProjectRoleEditor}}}
+
+	t.Run("project editor removes a task", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID, ProjectID: projectID}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID, entities.Task{UserID: ownerID},
+			[]string{entities.TaskFieldProjectID}).Return(nil).Once()
+
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID).Return(project, nil).Twice()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withProjects(mockProjectRepository))
+
+		task, err := tuc.RemoveTaskFromProject(context.TODO(), taskID, projectID, "editorID")
+
+		assert.NoError(t, err)
+		assert.Empty(t, task.ProjectID)
+	})
+
+	t.Run("task in another project", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID).Return(&entities.Task{UserID: ownerID, ProjectID: "otherID"}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.RemoveTaskFromProject(context.TODO(), taskID, projectID, ownerID)
+
+		assert.ErrorIs(t, err, entities.ErrTaskNotInProject)
+	})
+}
+
+func TestCreateRecurringTask(t *testing.T) {
+	dueDate := time.Date(2030, time.January, 7, 9, 0, 0, 0, time.UTC)
+
+	t.Run("starts a series", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("CreateTask", mock.Anything, mock.MatchedBy(func(task entities.Task) bool {
+			return task.Recurrence == "FREQ=WEEKLY;BYDAY=MO,TH" && task.SeriesID == task.ID.Hex() && task.Occurrence == 1
+		})).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		err := tuc.CreateTask(context.TODO(), entities.Task{UserID: "userID", Title: "Gym", DueDate: dueDate, Recurrence: "freq=weekly;byday=th,mo"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		tuc := newTaskUsecase(t, mocks.NewTaskRepository(t))
+
+		err := tuc.CreateTask(context.TODO(), entities.Task{UserID: "userID", Title: "Gym", DueDate: dueDate, Recurrence: "FREQ=HOURLY"})
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+
+	t.Run("without a due date", func(t *testing.T) {
+		tuc := newTaskUsecase(t, mocks.NewTaskRepository(t))
+
+		err := tuc.CreateTask(context.TODO(), entities.Task{UserID: "userID", Title: "Gym", Recurrence: "FREQ=DAILY"})
+
+		assert.ErrorIs(t, err, entities.ErrRecurrenceDueDate)
+	})
+}
+
+func TestCompleteRecurringTask(t *testing.T) {
+	taskID := primitive.NewObjectID()
+	userID := "testUserID"
+	dueDate := time.Date(2030, time.January, 7, 9, 0, 0, 0, time.UTC)
+
+	recurring := func(rule string, occurrence int) *entities.Task {
+		return &entities.Task{ID: taskID, UserID: userID, Title: "Gym", Status: entities.StatusInProgress, Priority: entities.PriorityHigh,
+			Tags: []string{"health"}, DueDate: dueDate, BlockedBy: []string{}, Version: 4,
+			Recurrence: rule, SeriesID: "seriesID", Occurrence: occurrence}
+	}
+
+	t.Run("spawns the next occurrence", func(t *testing.T) {
+		task := recurring("FREQ=WEEKLY;BYDAY=MO,TH", 2)
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, []string{entities.TaskFieldStatus}).Return(nil).Once()
+		mockTaskRepository.On("GetSeriesTasks", mock.Anything, "seriesID").Return([]*entities.Task{task}, nil).Once()
+		mockTaskRepository.On("CreateTask", mock.Anything, mock.MatchedBy(func(next entities.Task) bool {
+			return next.ID != taskID && next.Status == entities.StatusTodo && next.CompletedAt == nil &&
+				next.DueDate.Equal(dueDate.AddDate(0, 0, 3)) && next.Occurrence == 3 && next.SeriesID == "seriesID" &&
+				next.Recurrence == task.Recurrence && next.Priority == entities.PriorityHigh && next.BlockedBy == nil && next.Version == 0
+		})).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		info, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"status": "done"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.StatusDone, info.Status)
+	})
+
+	t.Run("a later occurrence exists", func(t *testing.T) {
+		task := recurring("FREQ=DAILY", 1)
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, []string{entities.TaskFieldStatus}).Return(nil).Once()
+		mockTaskRepository.On("GetSeriesTasks", mock.Anything, "seriesID").Return([]*entities.Task{task, {Occurrence: 2}}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"status": "done"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("the series has ended", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(recurring("FREQ=DAILY;COUNT=3", 3), nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, []string{entities.TaskFieldStatus}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"status": "done"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("a failed spawn does not fail the update", func(t *testing.T) {
+		task := recurring("FREQ=DAILY", 1)
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, []string{entities.TaskFieldStatus}).Return(nil).Once()
+		mockTaskRepository.On("GetSeriesTasks", mock.Anything, "seriesID").Return(nil, errors.New("database down")).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		info, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"status": "done"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.StatusDone, info.Status)
+	})
+
+	t.Run("setting a rule starts a series", func(t *testing.T) {
+		task := &entities.Task{ID: taskID, UserID: userID, Title: "Gym", Status: entities.StatusTodo, DueDate: dueDate}
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.MatchedBy(func(updated entities.Task) bool {
+			return updated.Recurrence == "FREQ=MONTHLY;BYMONTHDAY=-1" && updated.SeriesID == taskID.Hex() && updated.Occurrence == 1
+		}), []string{entities.TaskFieldRecurrence}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		info, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"recurrence": "FREQ=MONTHLY;BYMONTHDAY=-1"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+		assert.Equal(t, taskID.Hex(), info.SeriesID)
+	})
+
+	t.Run("clearing the due date of a recurring task", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(recurring("FREQ=DAILY", 1), nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"due_date": null}`), userID, entities.AnyTaskVersion)
+
+		assert.ErrorIs(t, err, entities.ErrRecurrenceDueDate)
+	})
+}
+
+func TestTaskSeries(t *testing.T) {
+	seriesID := "seriesID"
+	ownerID := "ownerID"
+	done := &entities.Task{ID: primitive.NewObjectID(), UserID: ownerID, Title: "Gym", Status: entities.StatusDone, SeriesID: seriesID, Occurrence: 1,
+		Recurrence: "FREQ=DAILY", DueDate: time.Date(2030, time.January, 7, 9, 0, 0, 0, time.UTC),
+		Collaborators: []entities.Collaborator{{UserID: "viewerID", Role: entities.TaskRoleViewer}}}
+	open := &entities.Task{ID: primitive.NewObjectID(), UserID: ownerID, Title: "Gym", Status: entities.StatusTodo, SeriesID: seriesID, Occurrence: 2,
+		Recurrence: "FREQ=DAILY", DueDate: time.Date(2030, time.January, 8, 9, 0, 0, 0, time.UTC),
+		Collaborators: []entities.Collaborator{{UserID: "viewerID", Role: entities.TaskRoleViewer}}}
+
+	t.Run("list", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetSeriesTasks", mock.Anything, seriesID).Return([]*entities.Task{done, open}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		tasks, err := tuc.GetTaskSeries(context.TODO(), seriesID, "viewerID")
+
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 2)
+	})
+
+	t.Run("hidden from other users", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetSeriesTasks", mock.Anything, seriesID).Return([]*entities.Task{done, open}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.GetTaskSeries(context.TODO(), seriesID, "strangerID")
+
+		assert.ErrorIs(t, err, entities.ErrSeriesNotFound)
+	})
+
+	t.Run("edit the open occurrences", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetSeriesTasks", mock.Anything, seriesID).Return([]*entities.Task{done, open}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, open.ID.Hex(), mock.MatchedBy(func(updated entities.Task) bool {
+			return updated.Title == "Swim" && updated.Recurrence == "FREQ=WEEKLY"
+		}), []string{entities.TaskFieldTitle, entities.TaskFieldRecurrence}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		var patch model.SeriesPatch
+		require.NoError(t, json.Unmarshal([]byte(`{"title": "Swim", "recurrence": "FREQ=WEEKLY"}`), &patch))
+
+		tasks, err := tuc.UpdateTaskSeries(context.TODO(), seriesID, patch, ownerID)
+
+		assert.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "Swim", tasks[0].Title)
+	})
+
+	t.Run("stop a finished series", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetSeriesTasks", mock.Anything, seriesID).Return([]*entities.Task{done}, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, done.ID.Hex(), mock.MatchedBy(func(updated entities.Task) bool {
+			return updated.Recurrence == "" && updated.SeriesID == seriesID
+		}), []string{entities.TaskFieldRecurrence}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		tasks, err := tuc.UpdateTaskSeries(context.TODO(), seriesID, model.SeriesPatch{Recurrence: model.Patch[string]{Set: true, Null: true}}, ownerID)
+
+		assert.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Empty(t, tasks[0].Recurrence)
+	})
+
+	t.Run("viewers cannot edit", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetSeriesTasks", mock.Anything, seriesID).Return([]*entities.Task{done, open}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTaskSeries(context.TODO(), seriesID, model.SeriesPatch{Title: model.Patch[string]{Set: true, Value: "Swim"}}, "viewerID")
+
+		assert.ErrorIs(t, err, entities.ErrTaskForbidden)
+	})
+}
+
+func TestTaskReminders(t *testing.T) {
+	taskID := primitive.NewObjectID()
+	userID := "testUserID"
+	dueDate := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+
+	remindedTask := func() *entities.Task {
+		return &entities.Task{ID: taskID, UserID: userID, Title: "Write report", Status: entities.StatusTodo, Priority: entities.PriorityMedium,
+			DueDate: dueDate, Reminders: []string{"P1D", "PT1H"}, Version: 1}
+	}
+	offsets := func(reminders []entities.Reminder) []string {
+		var offsets []string
+		for _, reminder := range reminders {
+			offsets = append(offsets, reminder.Offset)
+		}
+		return offsets
+	}
+
+	t.Run("creating a task schedules its reminders", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("CreateTask", mock.Anything, mock.MatchedBy(func(task entities.Task) bool {
+			return reflect.DeepEqual(task.Reminders, []string{"P1D", "PT1H"})
+		})).Return(nil).Once()
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ReplaceReminders", mock.Anything, mock.Anything, mock.MatchedBy(func(reminders []entities.Reminder) bool {
+			return reflect.DeepEqual(offsets(reminders), []string{"P1D", "PT1H"}) &&
+				reminders[0].RemindAt.Equal(dueDate.Add(-24*time.Hour)) && reminders[1].RemindAt.Equal(dueDate.Add(-time.Hour))
+		}), mock.Anything).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withReminders(mockReminderRepository))
+
+		err := tuc.CreateTask(context.TODO(), entities.Task{UserID: userID, Title: "Write report", DueDate: dueDate, Reminders: []string{"PT60M", "P1D"}})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid offset", func(t *testing.T) {
+		tuc := newTaskUsecase(t, mocks.NewTaskRepository(t))
+
+		err := tuc.CreateTask(context.TODO(), entities.Task{UserID: userID, Title: "Write report", DueDate: dueDate, Reminders: []string{"1 day"}})
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+
+	t.Run("moving the due date reschedules them", func(t *testing.T) {
+		newDueDate := dueDate.Add(24 * time.Hour)
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(remindedTask(), nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, []string{entities.TaskFieldDueDate}).Return(nil).Once()
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ReplaceReminders", mock.Anything, taskID.Hex(), mock.MatchedBy(func(reminders []entities.Reminder) bool {
+			return len(reminders) == 2 && reminders[0].RemindAt.Equal(newDueDate.Add(-24*time.Hour))
+		}), mock.Anything).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withReminders(mockReminderRepository))
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"due_date": "`+newDueDate.Format(time.RFC3339)+`"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("completing the task cancels them", func(t *testing.T) {
+		task := remindedTask()
+		task.Status = entities.StatusInProgress
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, []string{entities.TaskFieldStatus}).Return(nil).Once()
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ReplaceReminders", mock.Anything, taskID.Hex(), []entities.Reminder(nil), mock.Anything).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withReminders(mockReminderRepository))
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"status": "done"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("changing the offsets", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(remindedTask(), nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.MatchedBy(func(task entities.Task) bool {
+			return reflect.DeepEqual(task.Reminders, []string{"P1W", "PT2H"})
+		}), []string{entities.TaskFieldReminders}).Return(nil).Once()
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ReplaceReminders", mock.Anything, taskID.Hex(), mock.MatchedBy(func(reminders []entities.Reminder) bool {
+			// A week before the due date has already passed.
+			return reflect.DeepEqual(offsets(reminders), []string{"PT2H"})
+		}), mock.Anything).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withReminders(mockReminderRepository))
+
+		info, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"reminders": ["PT2H", "P7D"]}`), userID, entities.AnyTaskVersion)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P1W", "PT2H"}, info.Reminders)
+	})
+
+	t.Run("other changes leave them alone", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(remindedTask(), nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, []string{entities.TaskFieldTitle}).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository)
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"title": "Write the report"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("deleting the task cancels them", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(remindedTask(), nil).Once()
+		mockTaskRepository.On("DeleteTask", mock.Anything, taskID.Hex(), int64(1)).Return(nil).Once()
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ReplaceReminders", mock.Anything, taskID.Hex(), []entities.Reminder(nil), mock.Anything).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withReminders(mockReminderRepository))
+
+		err := tuc.DeleteTask(context.TODO(), taskID.Hex(), userID, 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("a scheduling failure does not fail the update", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(remindedTask(), nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, []string{entities.TaskFieldReminders}).Return(nil).Once()
+		mockReminderRepository := mocks.NewReminderRepository(t)
+		mockReminderRepository.On("ReplaceReminders", mock.Anything, taskID.Hex(), mock.Anything, mock.Anything).Return(errors.New("database down")).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withReminders(mockReminderRepository))
+
+		info, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"reminders": null}`), userID, entities.AnyTaskVersion)
+
+		require.NoError(t, err)
+		assert.Empty(t, info.Reminders)
+	})
+}
+
+func TestTaskWebhookEvents(t *testing.T) {
+	taskID := primitive.NewObjectID()
+	userID := "testUserID"
+	ownerWebhook := &entities.Webhook{ID: primitive.NewObjectID(), UserID: userID}
+
+	payloads := func(deliveries []entities.WebhookDelivery) []model.WebhookPayload {
+		var payloads []model.WebhookPayload
+		for _, delivery := range deliveries {
+			var payload model.WebhookPayload
+			if json.Unmarshal([]byte(delivery.Payload), &payload) == nil {
+				payloads = append(payloads, payload)
+			}
+		}
+		return payloads
+	}
+
+	t.Run("creating a task notifies the webhooks of its readers", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("CreateTask", mock.Anything, mock.Anything).Return(nil).Once()
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetEventWebhooks", mock.Anything, entities.EventTaskCreated, []string{userID}).
+			Return([]*entities.Webhook{ownerWebhook}, nil).Once()
+		mockWebhookRepository.On("AddDeliveries", mock.Anything, mock.MatchedBy(func(deliveries []entities.WebhookDelivery) bool {
+			payloads := payloads(deliveries)
+			return len(deliveries) == 1 && deliveries[0].WebhookID == ownerWebhook.ID.Hex() &&
+				deliveries[0].Status == entities.WebhookDeliveryPending && len(payloads) == 1 &&
+				payloads[0].Event == entities.EventTaskCreated && payloads[0].ActorID == userID && payloads[0].Task.Title == "Write report"
+		})).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withWebhooks(mockWebhookRepository))
+
+		err := tuc.CreateTask(context.TODO(), entities.Task{UserID: userID, Title: "Write report"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("only the webhooks of readers are read", func(t *testing.T) {
+		projectID := primitive.NewObjectID()
+		task := &entities.Task{
+			ID: taskID, UserID: userID, Title: "Write report", ProjectID: projectID.Hex(), AssigneeID: "assigneeID",
+			Collaborators: []entities.Collaborator{{UserID: "editorID", Role: entities.TaskRoleEditor}},
+		}
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, mock.Anything).Return(nil).Once()
+		mockProjectRepository := mocks.NewProjectRepository(t)
+		mockProjectRepository.On("GetProjectByID", mock.Anything, projectID.Hex()).Return(&entities.Project{
+			ID: projectID, OwnerID: "projectOwnerID", Members: []entities.ProjectMember{{UserID: "memberID", Role: entities.ProjectRoleViewer}, {UserID: "editorID", Role: entities.ProjectRoleEditor}},
+		}, nil).Once()
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetEventWebhooks", mock.Anything, entities.EventTaskUpdated,
+			[]string{"assigneeID", "editorID", "memberID", "projectOwnerID", userID}).Return([]*entities.Webhook{}, nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withProjects(mockProjectRepository), withWebhooks(mockWebhookRepository))
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"title": "Write final report"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("completing a task sends updated and completed", func(t *testing.T) {
+		task := &entities.Task{ID: taskID, UserID: userID, Title: "Write report", Status: entities.StatusInProgress, Priority: entities.PriorityMedium}
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(task, nil).Once()
+		mockTaskRepository.On("UpdateTask", mock.Anything, taskID.Hex(), mock.Anything, mock.Anything).Return(nil).Once()
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetEventWebhooks", mock.Anything, entities.EventTaskUpdated, []string{userID}).Return([]*entities.Webhook{ownerWebhook}, nil).Once()
+		mockWebhookRepository.On("GetEventWebhooks", mock.Anything, entities.EventTaskCompleted, []string{userID}).Return([]*entities.Webhook{ownerWebhook}, nil).Once()
+		mockWebhookRepository.On("AddDeliveries", mock.Anything, mock.MatchedBy(func(deliveries []entities.WebhookDelivery) bool {
+			payloads := payloads(deliveries)
+			return len(payloads) == 1 && payloads[0].Event == entities.EventTaskUpdated && len(payloads[0].Changes) > 0 &&
+				assert.ObjectsAreEqual(model.FieldChange{Field: "status", From: json.RawMessage(`"in_progress"`), To: json.RawMessage(`"done"`)}, payloads[0].Changes[0])
+		})).Return(nil).Once()
+		mockWebhookRepository.On("AddDeliveries", mock.Anything, mock.MatchedBy(func(deliveries []entities.WebhookDelivery) bool {
+			payloads := payloads(deliveries)
+			return len(payloads) == 1 && payloads[0].Event == entities.EventTaskCompleted && payloads[0].Task.Status == entities.StatusDone
+		})).Return(nil).Once()
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withWebhooks(mockWebhookRepository))
+
+		_, err := tuc.UpdateTask(context.TODO(), taskID.Hex(), taskPatch(t, `{"status": "done"}`), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed queueing is logged", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("GetTaskByID", mock.Anything, taskID.Hex()).Return(&entities.Task{ID: taskID, UserID: userID}, nil).Once()
+		mockTaskRepository.On("DeleteTask", mock.Anything, taskID.Hex(), entities.AnyTaskVersion).Return(nil).Once()
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetEventWebhooks", mock.Anything, entities.EventTaskDeleted, []string{userID}).Return(nil, errors.New("read error")).Once()
+
+		var logs bytes.Buffer
+		ctx := logging.WithLogger(context.TODO(), logging.New(&logs, "info"))
+
+		tuc := newTaskUsecase(t, mockTaskRepository, withWebhooks(mockWebhookRepository))
+
+		err := tuc.DeleteTask(ctx, taskID.Hex(), userID, entities.AnyTaskVersion)
+
+		assert.NoError(t, err)
+		assert.Contains(t, logs.String(), "queueing webhook deliveries failed")
+		assert.Contains(t, logs.String(), "read error")
+	})
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"task-management-api/domain/entities"
+	"time"
+)
+
+// TrashPurger permanently removes tasks once they have been in the trash for
+// longer than the retention period.
+type TrashPurger struct {
+	tasks     entities.TaskRepository
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// NewTrashPurger returns a purger checking the trash every interval. Time is
+// read from now, or from time.Now when now is nil.
+func NewTrashPurger(tasks entities.TaskRepository, retention time.Duration, interval time.Duration, now func() time.Time) *TrashPurger {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &TrashPurger{
+		tasks:     tasks,
+		retention: retention,
+		interval:  interval,
+		now:       now,
+	}
+}
+
+// Purge removes the tasks deleted more than the retention period ago and
+// returns how many there were.
+func (tp *TrashPurger) Purge(ctx context.Context) (int64, error) {
+	return tp.tasks.PurgeDeletedTasks(ctx, tp.now().Add(-tp.retention))
+}
+
+// Run purges the trash right away and then every interval until ctx is done.
+// Failures are logged and retried at the next interval.
+func (tp *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(tp.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := tp.Purge(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Purging the trash failed", "error", err)
+		} else if purged > 0 {
+			slog.InfoContext(ctx, "Purged tasks from the trash", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"task-management-api/domain/mocks"
+	"task-management-api/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTrashPurger(t *testing.T) {
+	now := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("purges tasks past the retention period", func(t *testing.T) {
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("PurgeDeletedTasks", mock.Anything, now.Add(-48*time.Hour)).Return(int64(3), nil).Once()
+
+		purger := usecase.NewTrashPurger(mockTaskRepository, 48*time.Hour, time.Hour, clock)
+
+		purged, err := purger.Purge(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+
+	t.Run("runs until cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		mockTaskRepository := mocks.NewTaskRepository(t)
+		mockTaskRepository.On("PurgeDeletedTasks", mock.Anything, mock.Anything).Return(int64(0), errors.New("database down")).Once()
+		mockTaskRepository.On("PurgeDeletedTasks", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+			cancel()
+		}).Return(int64(1), nil).Once()
+
+		purger := usecase.NewTrashPurger(mockTaskRepository, time.Hour, time.Millisecond, clock)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			purger.Run(ctx)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("purger did not stop")
+		}
+	})
+}
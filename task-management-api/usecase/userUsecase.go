@@ -2,17 +2,22 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/model"
+	"task-management-api/utils"
 )
 
 type UserUsecase struct {
 	userRepository entities.UserRepository
+	passwordHasher utils.PasswordHasher
 }
 
-func NewUserUsecase(userRepository entities.UserRepository) entities.UserUsecase {
+func NewUserUsecase(userRepository entities.UserRepository, passwordHasher utils.PasswordHasher) entities.UserUsecase {
     return &UserUsecase{
         userRepository: userRepository,
+        passwordHasher: passwordHasher,
     }
 }
 
@@ -34,12 +39,73 @@ func (uc *UserUsecase) GetUserByID(ctx context.Context, id string) (*entities.Us
 }
 
 
-func (uc *UserUsecase) UpdateUser(ctx context.Context, id string, updatedUser entities.User) error {
-	err := uc.userRepository.UpdateUser(context.Background(), id, updatedUser)
+// UpdateUser applies patch to the account id, which must be the account of
+// currUser unless currUser is an administrator. The role of a user is never
+// changed here, and a new username must not belong to another account.
+func (uc *UserUsecase) UpdateUser(ctx context.Context, currUser entities.AuthenticatedUser, id string, patch model.UserPatch) (*entities.User, error) {
+	if currUser.UserID != id && currUser.Role != entities.RoleAdmin {
+		return nil, entities.ErrUserForbidden
+	}
+
+	user, err := uc.userRepository.GetUserByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	var fields []string
+	touch := func(field string, set bool) {
+		if set {
+			fields = append(fields, field)
+		}
+	}
+
+	previousUsername := user.UserName
+	user.UserName = patch.Username.Merge(user.UserName)
+	touch(entities.UserFieldUsername, patch.Username.Set)
+	user.Email = patch.Email.Merge(user.Email)
+	touch(entities.UserFieldEmail, patch.Email.Set)
+	user.Name = patch.Name.Merge(user.Name)
+	touch(entities.UserFieldName, patch.Name.Set)
+	user.Bio = patch.Bio.Merge(user.Bio)
+	touch(entities.UserFieldBio, patch.Bio.Set)
+
+	// Removing a member that every user needs leaves an invalid user.
+	if user.UserName == "" {
+		return nil, entities.ErrUsernameRequired
+	}
+
+	if patch.Username.Set && user.UserName != previousUsername {
+		owner, err := uc.userRepository.GetUserByUsername(ctx, user.UserName)
+		if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return nil, err
+		}
+		if err == nil && owner.ID != user.ID {
+			return nil, entities.ErrUsernameTaken
+		}
+	}
+
+	if patch.Password.Set {
+		if patch.Password.Merge("") == "" {
+			return nil, entities.ErrPasswordRequired
+		}
+
+		hashedPassword, err := uc.passwordHasher.Hash(patch.Password.Value)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = hashedPassword
+		touch(entities.UserFieldPassword, true)
+	}
+
+	if len(fields) == 0 {
+		return user, nil
+	}
+
+	err = uc.userRepository.UpdateUser(ctx, id, *user, fields)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 func (uc *UserUsecase) DeleteUser(ctx context.Context, id string) error {
@@ -51,7 +117,13 @@ func (uc *UserUsecase) DeleteUser(ctx context.Context, id string) error {
 }
 
 func (uc *UserUsecase) CreateUser(ctx context.Context, newUser model.UserCreate) error {
-	_, err := uc.userRepository.CreateUser(ctx, newUser)
+	hashedPassword, err := uc.passwordHasher.Hash(newUser.Password)
+	if err != nil {
+		return err
+	}
+	newUser.Password = hashedPassword
+
+	_, err = uc.userRepository.CreateUser(ctx, newUser)
 	if err != nil {
 		return err
 	}
@@ -2,7 +2,9 @@ package usecase_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"task-management-api/domain/apperrors"
 	"task-management-api/domain/entities"
 	"task-management-api/domain/mocks"
 	"task-management-api/domain/model"
@@ -11,11 +13,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestGetUsers(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
+	mockPasswordHasher := new(mocks.PasswordHasher)
 	ctx := context.TODO()
 	param := "testParam"
 
@@ -27,7 +31,7 @@ func TestGetUsers(t *testing.T) {
 
 		mockUserRepository.On("GetUser", mock.Anything, param).Return(mockUsers, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
 
 		users, err := u.GetUsers(ctx, param)
 
@@ -42,7 +46,7 @@ func TestGetUsers(t *testing.T) {
 
 		mockUserRepository.On("GetUser", mock.Anything, param).Return(nil, expectedErr).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
 
 		users, err := u.GetUsers(ctx, param)
 
@@ -56,6 +60,7 @@ func TestGetUsers(t *testing.T) {
 
 func TestGetUserByID(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
+	mockPasswordHasher := new(mocks.PasswordHasher)
 	userID := primitive.NewObjectID()
 
 	t.Run("success", func(t *testing.T) {
@@ -67,7 +72,7 @@ func TestGetUserByID(t *testing.T) {
 
 		mockUserRepository.On("GetUserByID", mock.Anything, userID.Hex()).Return(mockUser, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
 
 		user, err := u.GetUserByID(context.Background(), userID.Hex())
 
@@ -82,7 +87,7 @@ func TestGetUserByID(t *testing.T) {
 
 		mockUserRepository.On("GetUserByID", mock.Anything, userID.Hex()).Return(nil, expectedErr).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
 
 		user, err := u.GetUserByID(context.Background(), userID.Hex())
 
@@ -95,53 +100,136 @@ func TestGetUserByID(t *testing.T) {
 }
 
 func TestUpdateUser(t *testing.T) {
-	mockUserRepository := new(mocks.UserRepository)
-	ctx := context.Background()
 	userID := primitive.NewObjectID()
 
-	updatedUser := entities.User{
-		ID:       userID,
-		UserName: "updatedUser",
-		Password: "123",
+	currentUser := func() *entities.User {
+		return &entities.User{ID: userID, UserName: "user", Password: "old-hash", Email: "user@example.com", Role: entities.RoleUser}
+	}
+
+	owner := entities.AuthenticatedUser{UserID: userID.Hex(), Username: "user", Role: entities.RoleUser}
+
+	userPatch := func(t *testing.T, body string) model.UserPatch {
+		var patch model.UserPatch
+		require.NoError(t, json.Unmarshal([]byte(body), &patch))
+		return patch
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockUserRepository.On("UpdateUser", ctx, userID.Hex(), updatedUser).Return(nil).Once()
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockPasswordHasher := mocks.NewPasswordHasher(t)
+
+		expectedUser := currentUser()
+		expectedUser.UserName = "updatedUser"
+		expectedUser.Password = "hashed123"
+		expectedUser.Email = ""
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		mockUserRepository.On("GetUserByID", mock.Anything, userID.Hex()).Return(currentUser(), nil).Once()
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "updatedUser").Return(nil, apperrors.NotFound("user not found")).Once()
+		mockPasswordHasher.On("Hash", "password123").Return("hashed123", nil)
+		mockUserRepository.On("UpdateUser", mock.Anything, userID.Hex(), *expectedUser,
+			[]string{entities.UserFieldUsername, entities.UserFieldEmail, entities.UserFieldPassword}).Return(nil).Once()
 
-		err := u.UpdateUser(context.Background(), userID.Hex(), updatedUser)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
+
+		user, err := u.UpdateUser(context.Background(), owner, userID.Hex(), userPatch(t, `{"username": "updatedUser", "password": "password123", "email": null}`))
 
 		assert.NoError(t, err)
+		assert.Equal(t, expectedUser, user)
+	})
 
-		mockUserRepository.AssertExpectations(t)
+	t.Run("username taken", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByID", mock.Anything, userID.Hex()).Return(currentUser(), nil).Once()
+		mockUserRepository.On("GetUserByUsername", mock.Anything, "bob").Return(&entities.User{ID: primitive.NewObjectID(), UserName: "bob"}, nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepository, mocks.NewPasswordHasher(t))
+
+		_, err := u.UpdateUser(context.Background(), owner, userID.Hex(), userPatch(t, `{"username": "bob"}`))
+
+		assert.ErrorIs(t, err, entities.ErrUsernameTaken)
+	})
+
+	t.Run("same username", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		mockUserRepository.On("GetUserByID", mock.Anything, userID.Hex()).Return(currentUser(), nil).Once()
+		mockUserRepository.On("UpdateUser", mock.Anything, userID.Hex(), *currentUser(), []string{entities.UserFieldUsername}).Return(nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepository, mocks.NewPasswordHasher(t))
+
+		_, err := u.UpdateUser(context.Background(), owner, userID.Hex(), userPatch(t, `{"username": "user"}`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid resulting user", func(t *testing.T) {
+		for patch, expectedErr := range map[string]error{
+			`{"username": null}`: entities.ErrUsernameRequired,
+			`{"password": null}`: entities.ErrPasswordRequired,
+			`{"password": ""}`:   entities.ErrPasswordRequired,
+		} {
+			mockUserRepository := mocks.NewUserRepository(t)
+			mockUserRepository.On("GetUserByID", mock.Anything, userID.Hex()).Return(currentUser(), nil).Once()
+
+			u := usecase.NewUserUsecase(mockUserRepository, mocks.NewPasswordHasher(t))
+
+			_, err := u.UpdateUser(context.Background(), owner, userID.Hex(), userPatch(t, patch))
+
+			assert.ErrorIs(t, err, expectedErr, patch)
+		}
 	})
 
 	t.Run("error", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
 		expectedErr := errors.New("repository error")
 
-		mockUserRepository.On("UpdateUser", ctx, userID.Hex(), updatedUser).Return(expectedErr).Once()
+		mockUserRepository.On("GetUserByID", mock.Anything, userID.Hex()).Return(currentUser(), nil).Once()
+		mockUserRepository.On("UpdateUser", mock.Anything, userID.Hex(), mock.Anything, mock.Anything).Return(expectedErr).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mocks.NewPasswordHasher(t))
 
-		err := u.UpdateUser(context.Background(), userID.Hex(), updatedUser)
+		_, err := u.UpdateUser(context.Background(), owner, userID.Hex(), userPatch(t, `{"name": "User"}`))
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("other user", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		other := entities.AuthenticatedUser{UserID: primitive.NewObjectID().Hex(), Username: "other", Role: entities.RoleUser}
 
-		mockUserRepository.AssertExpectations(t)
+		u := usecase.NewUserUsecase(mockUserRepository, mocks.NewPasswordHasher(t))
+
+		_, err := u.UpdateUser(context.Background(), other, userID.Hex(), userPatch(t, `{"password": "password123"}`))
+
+		assert.ErrorIs(t, err, entities.ErrUserForbidden)
+	})
+
+	t.Run("administrator", func(t *testing.T) {
+		mockUserRepository := mocks.NewUserRepository(t)
+		admin := entities.AuthenticatedUser{UserID: primitive.NewObjectID().Hex(), Username: "admin", Role: entities.RoleAdmin}
+
+		mockUserRepository.On("GetUserByID", mock.Anything, userID.Hex()).Return(currentUser(), nil).Once()
+		mockUserRepository.On("UpdateUser", mock.Anything, userID.Hex(), mock.Anything, []string{entities.UserFieldName}).Return(nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepository, mocks.NewPasswordHasher(t))
+
+		user, err := u.UpdateUser(context.Background(), admin, userID.Hex(), userPatch(t, `{"name": "User"}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "User", user.Name)
+		assert.Equal(t, entities.RoleUser, user.Role)
 	})
 }
 
 func TestDeleteUser(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
+	mockPasswordHasher := new(mocks.PasswordHasher)
 	ctx := context.TODO()
 	userID := "testUserID"
 
 	t.Run("success", func(t *testing.T) {
 		mockUserRepository.On("DeleteUser", ctx, userID).Return(nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
 
 		err := u.DeleteUser(context.TODO(), userID)
 
@@ -155,7 +243,7 @@ func TestDeleteUser(t *testing.T) {
 
 		mockUserRepository.On("DeleteUser", ctx, userID).Return(expectedErr).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
 
 		err := u.DeleteUser(context.TODO(), userID)
 
@@ -168,6 +256,7 @@ func TestDeleteUser(t *testing.T) {
 
 func TestCreateUser(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
+	mockPasswordHasher := new(mocks.PasswordHasher)
 	ctx := context.TODO()
 	userID := primitive.NewObjectID()
 
@@ -180,6 +269,11 @@ func TestCreateUser(t *testing.T) {
 		Bio: "Software engineer with a passion for open-source projects and tech innovations.",
 	}
 
+	hashedUser := newUser
+	hashedUser.Password = "hashedP@ssw0rd123"
+
+	mockPasswordHasher.On("Hash", newUser.Password).Return(hashedUser.Password, nil)
+
 	newUserInfo := &model.UserInfo{
 
 		Username: newUser.Username,
@@ -189,9 +283,9 @@ func TestCreateUser(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockUserRepository.On("CreateUser", ctx, newUser).Return(newUserInfo, nil).Once()
+		mockUserRepository.On("CreateUser", ctx, hashedUser).Return(newUserInfo, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
 
 		err := u.CreateUser(context.TODO(), newUser)
 
@@ -203,9 +297,9 @@ func TestCreateUser(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		expectedErr := errors.New("repository error")
 
-		mockUserRepository.On("CreateUser", ctx, newUser).Return(nil, expectedErr).Once()
+		mockUserRepository.On("CreateUser", ctx, hashedUser).Return(nil, expectedErr).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepository)
+		u := usecase.NewUserUsecase(mockUserRepository, mockPasswordHasher)
 
 		err := u.CreateUser(context.TODO(), newUser)
 
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"time"
+)
+
+const (
+	// webhookBatchSize is the most deliveries claimed, and sent in parallel,
+	// at once.
+	webhookBatchSize = 20
+	// webhookClaimLease is how long a claimed delivery is left to the
+	// dispatcher that claimed it. It outlasts the timeout of a send, so a
+	// delivery is only claimed again when that dispatcher stopped.
+	webhookClaimLease = time.Minute
+)
+
+// WebhookDispatcher sends the pending webhook deliveries. Every replica of
+// the service may run one: deliveries are claimed in the database before they
+// are sent, so each attempt is made by a single dispatcher.
+type WebhookDispatcher struct {
+	webhooks entities.WebhookRepository
+	sender   entities.WebhookSender
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewWebhookDispatcher returns a dispatcher looking for due deliveries every
+// interval and sending them with sender. Time is read from now, or from
+// time.Now when now is nil.
+func NewWebhookDispatcher(webhooks entities.WebhookRepository, sender entities.WebhookSender, interval time.Duration, now func() time.Time) *WebhookDispatcher {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &WebhookDispatcher{
+		webhooks: webhooks,
+		sender:   sender,
+		interval: interval,
+		now:      now,
+	}
+}
+
+// DeliverDue claims the deliveries that are due, sends them and records the
+// outcome of each, and returns how many succeeded. Failed deliveries are
+// tried again with exponential backoff, see entities.WebhookRetryDelay.
+func (wd *WebhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := wd.webhooks.ClaimDueDeliveries(ctx, wd.now(), webhookClaimLease, webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *entities.WebhookDelivery) {
+			defer wg.Done()
+
+			if wd.deliver(ctx, delivery) {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return succeeded, nil
+}
+
+// deliver makes one attempt at a claimed delivery and reports whether it
+// succeeded. Deliveries of deleted webhooks were deleted with them and are
+// dropped.
+func (wd *WebhookDispatcher) deliver(ctx context.Context, delivery *entities.WebhookDelivery) bool {
+	webhook, err := wd.webhooks.GetWebhookByID(ctx, delivery.WebhookID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Reading a webhook failed", "webhook_id", delivery.WebhookID, "delivery_id", delivery.ID.Hex(), "error", err)
+		return false
+	}
+
+	status, sendErr := wd.sender.Send(ctx, *webhook, *delivery)
+	delivery.RecordAttempt(wd.now(), status, sendErr)
+	if sendErr != nil {
+		slog.WarnContext(ctx, "Webhook delivery failed", "webhook_id", delivery.WebhookID, "delivery_id", delivery.ID.Hex(), "event", delivery.Event,
+			"attempts", delivery.Attempts, "status", delivery.Status, "error", sendErr)
+	}
+
+	if err := wd.webhooks.UpdateDelivery(ctx, *delivery); err != nil {
+		slog.ErrorContext(ctx, "Recording a webhook delivery failed", "webhook_id", delivery.WebhookID, "delivery_id", delivery.ID.Hex(), "error", err)
+	}
+
+	return sendErr == nil
+}
+
+// Run sends the due deliveries right away and then every interval until ctx
+// is done. Failures are logged and retried at the next interval.
+func (wd *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(wd.interval)
+	defer ticker.Stop()
+
+	for {
+		delivered, err := wd.DeliverDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Sending webhook deliveries failed", "error", err)
+		} else if delivered > 0 {
+			slog.InfoContext(ctx, "Sent webhook deliveries", "count", delivered)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
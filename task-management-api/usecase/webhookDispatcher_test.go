@@ -0,0 +1,134 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/notify"
+	"task-management-api/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWebhookDispatcher(t *testing.T) {
+	now := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	newWebhook := func(url string) *entities.Webhook {
+		return &entities.Webhook{ID: primitive.NewObjectID(), UserID: "testUserID", URL: url, Events: []string{entities.EventTaskCreated},
+			Secret: "whsec_test"}
+	}
+	deliveryFor := func(webhook *entities.Webhook, attempts int) *entities.WebhookDelivery {
+		return &entities.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID.Hex(), Event: entities.EventTaskCreated,
+			Payload: `{"id":"e1","event":"task.created"}`, Status: entities.WebhookDeliveryPending, Attempts: attempts, NextAttemptAt: now}
+	}
+
+	t.Run("sends signed deliveries", func(t *testing.T) {
+		received := make(chan *http.Request, 1)
+		bodies := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- r
+			bodies <- string(body)
+		}))
+		defer server.Close()
+
+		webhook := newWebhook(server.URL)
+		delivery := deliveryFor(webhook, 1)
+
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("ClaimDueDeliveries", mock.Anything, now, mock.Anything, mock.Anything).Return([]*entities.WebhookDelivery{delivery}, nil).Once()
+		mockWebhookRepository.On("GetWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil).Once()
+		mockWebhookRepository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated entities.WebhookDelivery) bool {
+			return updated.ID == delivery.ID && updated.Status == entities.WebhookDeliverySucceeded && updated.ResponseStatus == http.StatusOK
+		})).Return(nil).Once()
+
+		dispatcher := usecase.NewWebhookDispatcher(mockWebhookRepository, notify.NewWebhookSender(server.Client()), time.Minute, clock)
+
+		delivered, err := dispatcher.DeliverDue(context.TODO())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+
+		request, body := <-received, <-bodies
+		assert.Equal(t, delivery.Payload, body)
+		assert.Equal(t, delivery.ID.Hex(), request.Header.Get(notify.HeaderWebhookDelivery))
+		timestamp, err := strconv.ParseInt(request.Header.Get(notify.HeaderWebhookTimestamp), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, notify.SignWebhook(webhook.Secret, timestamp, []byte(body)), request.Header.Get(notify.HeaderWebhookSignature))
+	})
+
+	t.Run("schedules a retry when the receiver fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		webhook := newWebhook(server.URL)
+		delivery := deliveryFor(webhook, 2)
+
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("ClaimDueDeliveries", mock.Anything, now, mock.Anything, mock.Anything).Return([]*entities.WebhookDelivery{delivery}, nil).Once()
+		mockWebhookRepository.On("GetWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil).Once()
+		mockWebhookRepository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated entities.WebhookDelivery) bool {
+			return updated.Status == entities.WebhookDeliveryPending && updated.ResponseStatus == http.StatusServiceUnavailable &&
+				updated.NextAttemptAt.Equal(now.Add(time.Minute)) && updated.Error == "webhook answered 503 Service Unavailable"
+		})).Return(nil).Once()
+
+		dispatcher := usecase.NewWebhookDispatcher(mockWebhookRepository, notify.NewWebhookSender(server.Client()), time.Minute, clock)
+
+		delivered, err := dispatcher.DeliverDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Zero(t, delivered)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		webhook := newWebhook("https://hooks.example.com/tasks")
+		delivery := deliveryFor(webhook, entities.MaxWebhookAttempts)
+
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("ClaimDueDeliveries", mock.Anything, now, mock.Anything, mock.Anything).Return([]*entities.WebhookDelivery{delivery}, nil).Once()
+		mockWebhookRepository.On("GetWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil).Once()
+		mockWebhookRepository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated entities.WebhookDelivery) bool {
+			return updated.Status == entities.WebhookDeliveryFailed
+		})).Return(nil).Once()
+		mockWebhookSender := mocks.NewWebhookSender(t)
+		mockWebhookSender.On("Send", mock.Anything, *webhook, mock.Anything).Return(http.StatusInternalServerError, assert.AnError).Once()
+
+		dispatcher := usecase.NewWebhookDispatcher(mockWebhookRepository, mockWebhookSender, time.Minute, clock)
+
+		delivered, err := dispatcher.DeliverDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Zero(t, delivered)
+	})
+
+	t.Run("drops deliveries of deleted webhooks", func(t *testing.T) {
+		webhook := newWebhook("https://hooks.example.com/tasks")
+		delivery := deliveryFor(webhook, 1)
+
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("ClaimDueDeliveries", mock.Anything, now, mock.Anything, mock.Anything).Return([]*entities.WebhookDelivery{delivery}, nil).Once()
+		mockWebhookRepository.On("GetWebhookByID", mock.Anything, webhook.ID.Hex()).Return(nil, entities.ErrWebhookNotFound).Once()
+		mockWebhookSender := mocks.NewWebhookSender(t)
+
+		dispatcher := usecase.NewWebhookDispatcher(mockWebhookRepository, mockWebhookSender, time.Minute, clock)
+
+		delivered, err := dispatcher.DeliverDue(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Zero(t, delivered)
+		mockWebhookSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+		mockWebhookRepository.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+	})
+}
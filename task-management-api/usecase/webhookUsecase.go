@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookUsecase struct {
+	WebhookRepository entities.WebhookRepository
+	WebhookSender     entities.WebhookSender
+	contextTimeout    time.Duration
+}
+
+func NewWebhookUsecase(webhookRepository entities.WebhookRepository, webhookSender entities.WebhookSender) entities.WebhookUsecase {
+	return &WebhookUsecase{
+		WebhookRepository: webhookRepository,
+		WebhookSender:     webhookSender,
+		contextTimeout:    3 * time.Second,
+	}
+}
+
+func (uc *WebhookUsecase) GetWebhooks(ctx context.Context, userID string) ([]*model.WebhookInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	webhooks, err := uc.WebhookRepository.GetWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []*model.WebhookInfo{}
+	for _, webhook := range webhooks {
+		infos = append(infos, webhook.Info())
+	}
+
+	return infos, nil
+}
+
+func (uc *WebhookUsecase) GetWebhookByID(ctx context.Context, id string, userID string) (*model.WebhookInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	webhook, err := uc.ownWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook.Info(), nil
+}
+
+// CreateWebhook subscribes a new webhook to events, which are stored sorted
+// and without duplicates. Its secret is generated here.
+func (uc *WebhookUsecase) CreateWebhook(ctx context.Context, url string, events []string, userID string) (*model.WebhookInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if !entities.IsValidWebhookURL(url) {
+		return nil, entities.ErrInvalidWebhookURL
+	}
+
+	if err := uc.WebhookSender.CheckURL(ctx, url); err != nil {
+		return nil, entities.ErrWebhookURLNotPublic.Wrap(err)
+	}
+
+	subscribed := map[string]bool{}
+	for _, event := range events {
+		if !entities.IsValidWebhookEvent(event) {
+			return nil, entities.ErrInvalidWebhookEvent
+		}
+		subscribed[event] = true
+	}
+	if len(subscribed) == 0 {
+		return nil, entities.ErrInvalidWebhookEvent
+	}
+
+	webhooks, err := uc.WebhookRepository.GetWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) >= entities.MaxWebhooks {
+		return nil, entities.ErrTooManyWebhooks
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := entities.Webhook{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		URL:       url,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	for event := range subscribed {
+		webhook.Events = append(webhook.Events, event)
+	}
+	sort.Strings(webhook.Events)
+
+	if err := uc.WebhookRepository.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	info := webhook.Info()
+	info.Secret = webhook.Secret
+	return info, nil
+}
+
+// newWebhookSecret returns 32 random bytes, hex encoded with a prefix that
+// makes leaked secrets easy to recognize.
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+func (uc *WebhookUsecase) DeleteWebhook(ctx context.Context, id string, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.ownWebhook(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return uc.WebhookRepository.DeleteWebhook(ctx, id)
+}
+
+func (uc *WebhookUsecase) GetDeliveries(ctx context.Context, id string, userID string, query model.PageQuery) (*model.WebhookDeliveryPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.ownWebhook(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	return uc.WebhookRepository.GetDeliveries(ctx, id, normalizePageQuery(query))
+}
+
+// TestWebhook waits for the receiver, which the sender gives up on after its
+// own timeout, rather than for contextTimeout.
+func (uc *WebhookUsecase) TestWebhook(ctx context.Context, id string, userID string) (*model.WebhookDeliveryInfo, error) {
+	lookupCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	webhook, err := uc.ownWebhook(lookupCtx, id, userID)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	deliveries, err := newWebhookDeliveries([]*entities.Webhook{webhook}, model.WebhookPayload{
+		ID:        primitive.NewObjectID().Hex(),
+		Event:     entities.EventWebhookTest,
+		CreatedAt: now,
+		ActorID:   userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	delivery := deliveries[0]
+
+	delivery.Attempts = 1
+	status, sendErr := uc.WebhookSender.Send(ctx, *webhook, delivery)
+	delivery.RecordAttempt(time.Now().UTC(), status, sendErr)
+	// Test deliveries are not retried.
+	if delivery.Status == entities.WebhookDeliveryPending {
+		delivery.Status = entities.WebhookDeliveryFailed
+	}
+
+	ctx, cancel = context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if err := uc.WebhookRepository.AddDeliveries(ctx, []entities.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+
+	return delivery.Info(), nil
+}
+
+// ownWebhook reads a webhook of the user. Webhooks of other users are
+// reported as missing.
+func (uc *WebhookUsecase) ownWebhook(ctx context.Context, id string, userID string) (*entities.Webhook, error) {
+	webhook, err := uc.WebhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.UserID != userID {
+		return nil, entities.ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+// newWebhookDeliveries returns the pending deliveries of payload to each of
+// webhooks, due right away.
+func newWebhookDeliveries(webhooks []*entities.Webhook, payload model.WebhookPayload) ([]entities.WebhookDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]entities.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, entities.WebhookDelivery{
+			ID:            primitive.NewObjectID(),
+			WebhookID:     webhook.ID.Hex(),
+			Event:         payload.Event,
+			Payload:       string(body),
+			Status:        entities.WebhookDeliveryPending,
+			NextAttemptAt: payload.CreatedAt,
+			CreatedAt:     payload.CreatedAt,
+		})
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,175 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"task-management-api/domain/apperrors"
+	"task-management-api/domain/entities"
+	"task-management-api/domain/mocks"
+	"task-management-api/domain/model"
+	"task-management-api/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	userID := "testUserID"
+
+	t.Run("success", func(t *testing.T) {
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetWebhooks", mock.Anything, userID).Return([]*entities.Webhook{}, nil).Once()
+		mockWebhookRepository.On("CreateWebhook", mock.Anything, mock.MatchedBy(func(webhook entities.Webhook) bool {
+			return webhook.UserID == userID && webhook.URL == "https://hooks.example.com/tasks" &&
+				strings.Join(webhook.Events, ",") == "task.completed,task.created" && strings.HasPrefix(webhook.Secret, "whsec_")
+		})).Return(nil).Once()
+		mockWebhookSender := mocks.NewWebhookSender(t)
+		mockWebhookSender.On("CheckURL", mock.Anything, "https://hooks.example.com/tasks").Return(nil).Once()
+
+		wuc := usecase.NewWebhookUsecase(mockWebhookRepository, mockWebhookSender)
+
+		webhook, err := wuc.CreateWebhook(context.TODO(), "https://hooks.example.com/tasks",
+			[]string{entities.EventTaskCreated, entities.EventTaskCompleted, entities.EventTaskCreated}, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{entities.EventTaskCompleted, entities.EventTaskCreated}, webhook.Events)
+		assert.Len(t, webhook.Secret, len("whsec_")+64)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		wuc := usecase.NewWebhookUsecase(mocks.NewWebhookRepository(t), mocks.NewWebhookSender(t))
+
+		_, err := wuc.CreateWebhook(context.TODO(), "ftp://hooks.example.com", []string{entities.EventTaskCreated}, userID)
+
+		assert.ErrorIs(t, err, entities.ErrInvalidWebhookURL)
+	})
+
+	t.Run("internal address", func(t *testing.T) {
+		mockWebhookSender := mocks.NewWebhookSender(t)
+		mockWebhookSender.On("CheckURL", mock.Anything, "http://169.254.169.254/latest").Return(errors.New("webhook address 169.254.169.254 is not public")).Once()
+
+		wuc := usecase.NewWebhookUsecase(mocks.NewWebhookRepository(t), mockWebhookSender)
+
+		_, err := wuc.CreateWebhook(context.TODO(), "http://169.254.169.254/latest", []string{entities.EventTaskCreated}, userID)
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+		assert.ErrorContains(t, err, "is not public")
+	})
+
+	t.Run("invalid event", func(t *testing.T) {
+		mockWebhookSender := mocks.NewWebhookSender(t)
+		mockWebhookSender.On("CheckURL", mock.Anything, mock.Anything).Return(nil).Once()
+		wuc := usecase.NewWebhookUsecase(mocks.NewWebhookRepository(t), mockWebhookSender)
+
+		_, err := wuc.CreateWebhook(context.TODO(), "https://hooks.example.com/tasks", []string{entities.EventWebhookTest}, userID)
+
+		assert.ErrorIs(t, err, entities.ErrInvalidWebhookEvent)
+	})
+
+	t.Run("too many webhooks", func(t *testing.T) {
+		webhooks := make([]*entities.Webhook, entities.MaxWebhooks)
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetWebhooks", mock.Anything, userID).Return(webhooks, nil).Once()
+		mockWebhookSender := mocks.NewWebhookSender(t)
+		mockWebhookSender.On("CheckURL", mock.Anything, mock.Anything).Return(nil).Once()
+
+		wuc := usecase.NewWebhookUsecase(mockWebhookRepository, mockWebhookSender)
+
+		_, err := wuc.CreateWebhook(context.TODO(), "https://hooks.example.com/tasks", []string{entities.EventTaskCreated}, userID)
+
+		assert.ErrorIs(t, err, entities.ErrTooManyWebhooks)
+	})
+}
+
+func TestGetWebhooksHidesSecrets(t *testing.T) {
+	webhook := &entities.Webhook{ID: primitive.NewObjectID(), UserID: "testUserID", URL: "https://hooks.example.com/tasks",
+		Events: []string{entities.EventTaskCreated}, Secret: "whsec_test"}
+	mockWebhookRepository := mocks.NewWebhookRepository(t)
+	mockWebhookRepository.On("GetWebhooks", mock.Anything, "testUserID").Return([]*entities.Webhook{webhook}, nil).Once()
+
+	wuc := usecase.NewWebhookUsecase(mockWebhookRepository, mocks.NewWebhookSender(t))
+
+	webhooks, err := wuc.GetWebhooks(context.TODO(), "testUserID")
+
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, webhook.URL, webhooks[0].URL)
+	assert.Empty(t, webhooks[0].Secret)
+}
+
+func TestDeleteWebhookOfOtherUser(t *testing.T) {
+	webhook := &entities.Webhook{ID: primitive.NewObjectID(), UserID: "otherUserID"}
+	mockWebhookRepository := mocks.NewWebhookRepository(t)
+	mockWebhookRepository.On("GetWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil).Once()
+
+	wuc := usecase.NewWebhookUsecase(mockWebhookRepository, mocks.NewWebhookSender(t))
+
+	err := wuc.DeleteWebhook(context.TODO(), webhook.ID.Hex(), "testUserID")
+
+	assert.ErrorIs(t, err, entities.ErrWebhookNotFound)
+	mockWebhookRepository.AssertNotCalled(t, "DeleteWebhook", mock.Anything, mock.Anything)
+}
+
+func TestTestWebhook(t *testing.T) {
+	webhook := &entities.Webhook{ID: primitive.NewObjectID(), UserID: "testUserID", URL: "https://hooks.example.com/tasks",
+		Events: []string{entities.EventTaskCreated}, Secret: "whsec_test"}
+
+	t.Run("succeeded", func(t *testing.T) {
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil).Once()
+		mockWebhookRepository.On("AddDeliveries", mock.Anything, mock.MatchedBy(func(deliveries []entities.WebhookDelivery) bool {
+			return len(deliveries) == 1 && deliveries[0].Status == entities.WebhookDeliverySucceeded && deliveries[0].Attempts == 1
+		})).Return(nil).Once()
+		mockWebhookSender := mocks.NewWebhookSender(t)
+		mockWebhookSender.On("Send", mock.Anything, *webhook, mock.MatchedBy(func(delivery entities.WebhookDelivery) bool {
+			return delivery.WebhookID == webhook.ID.Hex() && delivery.Event == entities.EventWebhookTest
+		})).Return(204, nil).Once()
+
+		wuc := usecase.NewWebhookUsecase(mockWebhookRepository, mockWebhookSender)
+
+		delivery, err := wuc.TestWebhook(context.TODO(), webhook.ID.Hex(), "testUserID")
+
+		require.NoError(t, err)
+		assert.Equal(t, entities.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 204, delivery.ResponseStatus)
+
+		var payload model.WebhookPayload
+		require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+		assert.Equal(t, entities.EventWebhookTest, payload.Event)
+		assert.Equal(t, "testUserID", payload.ActorID)
+		assert.Nil(t, payload.Task)
+	})
+
+	t.Run("failed without retries", func(t *testing.T) {
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil).Once()
+		mockWebhookRepository.On("AddDeliveries", mock.Anything, mock.Anything).Return(nil).Once()
+		mockWebhookSender := mocks.NewWebhookSender(t)
+		mockWebhookSender.On("Send", mock.Anything, *webhook, mock.Anything).Return(0, errors.New("connection refused")).Once()
+
+		wuc := usecase.NewWebhookUsecase(mockWebhookRepository, mockWebhookSender)
+
+		delivery, err := wuc.TestWebhook(context.TODO(), webhook.ID.Hex(), "testUserID")
+
+		require.NoError(t, err)
+		assert.Equal(t, entities.WebhookDeliveryFailed, delivery.Status)
+		assert.Equal(t, "connection refused", delivery.Error)
+		assert.Nil(t, delivery.NextAttemptAt)
+	})
+
+	t.Run("webhook of another user", func(t *testing.T) {
+		mockWebhookRepository := mocks.NewWebhookRepository(t)
+		mockWebhookRepository.On("GetWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil).Once()
+
+		wuc := usecase.NewWebhookUsecase(mockWebhookRepository, mocks.NewWebhookSender(t))
+
+		_, err := wuc.TestWebhook(context.TODO(), webhook.ID.Hex(), "otherUserID")
+
+		assert.ErrorIs(t, err, entities.ErrWebhookNotFound)
+	})
+}
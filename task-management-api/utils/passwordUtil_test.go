@@ -0,0 +1,67 @@
+package utils_test
+
+import (
+	"task-management-api/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHasher(t *testing.T) {
+	lowMemoryParams := utils.Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	hashers := map[string]utils.PasswordHasher{
+		"bcrypt":   utils.NewBcryptHasher(bcrypt.MinCost),
+		"argon2id": utils.NewArgon2idHasher(lowMemoryParams),
+	}
+
+	for name, hasher := range hashers {
+		t.Run(name, func(t *testing.T) {
+			hashed, err := hasher.Hash("password")
+			assert.NoError(t, err)
+			assert.NotEqual(t, "password", hashed)
+			assert.True(t, utils.IsHashedPassword(hashed))
+
+			valid, err := hasher.Verify(hashed, "password")
+			assert.NoError(t, err)
+			assert.True(t, valid)
+
+			valid, err = hasher.Verify(hashed, "wrongpassword")
+			assert.NoError(t, err)
+			assert.False(t, valid)
+
+			assert.False(t, hasher.NeedsRehash(hashed))
+		})
+	}
+
+	t.Run("verifies hashes from other algorithms", func(t *testing.T) {
+		hashed, err := hashers["bcrypt"].Hash("password")
+		assert.NoError(t, err)
+
+		valid, err := hashers["argon2id"].Verify(hashed, "password")
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		assert.True(t, hashers["argon2id"].NeedsRehash(hashed))
+	})
+
+	t.Run("needs rehash when cost changes", func(t *testing.T) {
+		hashed, err := hashers["bcrypt"].Hash("password")
+		assert.NoError(t, err)
+
+		assert.True(t, utils.NewBcryptHasher(bcrypt.MinCost+1).NeedsRehash(hashed))
+
+		lowMemoryParams.Iterations++
+		argonHashed, err := hashers["argon2id"].Hash("password")
+		assert.NoError(t, err)
+		assert.True(t, utils.NewArgon2idHasher(lowMemoryParams).NeedsRehash(argonHashed))
+	})
+
+	t.Run("plaintext is rejected", func(t *testing.T) {
+		assert.False(t, utils.IsHashedPassword("password"))
+
+		valid, err := hashers["bcrypt"].Verify("password", "password")
+		assert.ErrorIs(t, err, utils.ErrUnknownHashFormat)
+		assert.False(t, valid)
+	})
+}
@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"task-management-api/config"
 	"task-management-api/domain/entities"
 	"time"
@@ -9,25 +13,34 @@ import (
 )
 
 type Utils interface {
-	GenerateToken(userID string) (string, error)
+	GenerateToken(user entities.AuthenticatedUser) (string, error)
 }
 
 type TokenUtil struct {
 	environment *config.Environment
 }
 
-func (t *TokenUtil) GenerateToken(userID string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateToken signs an access token for the user. The token ID and expiry
+// are taken from user.TokenID and user.TokenExpiresAt, falling back to a 24h
+// lifetime when no expiry is given.
+func (t *TokenUtil) GenerateToken(user entities.AuthenticatedUser) (string, error) {
+	expirationTime := user.TokenExpiresAt
+	if expirationTime.IsZero() {
+		expirationTime = time.Now().Add(24 * time.Hour)
+	}
 
 	claims := &entities.Claims{
-		UserID: userID,
+		UserID:   user.UserID,
+		Username: user.Username,
+		Role:     user.Role,
 		StandardClaims: jwt.StandardClaims{
+			Id:        user.TokenID,
 			ExpiresAt: expirationTime.Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
 	}
 
-	var jwtKey = config.GetJwtKey()
+	var jwtKey = []byte((*t.environment).GetJwtKey())
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -45,6 +58,20 @@ func NewTokenUtil(environment *config.Environment) *TokenUtil {
 	}
 }
 
-func GetJwtKey() []byte {
-	return config.GetJwtKey()
+// GenerateOpaqueToken returns a random URL safe token suitable for refresh
+// tokens and token IDs.
+func GenerateOpaqueToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken returns the hex encoded SHA-256 digest under which opaque tokens
+// are stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }